@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+func diffCommand(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	quiet := fs.Bool("q", false, "only print the number of different entries")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: chunks-inspect diff [options] <chunk file> <chunk file>")
+		fmt.Fprintln(fs.Output(), "Compares the headers and the entries of two chunks, exits with 1 if they differ.")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	a, entriesA, err := loadChunkEntries(fs.Arg(0))
+	if err != nil {
+		log.Printf("%s: %v", fs.Arg(0), err)
+		return 2
+	}
+	b, entriesB, err := loadChunkEntries(fs.Arg(1))
+	if err != nil {
+		log.Printf("%s: %v", fs.Arg(1), err)
+		return 2
+	}
+
+	fmt.Println("---", a.name)
+	fmt.Println("+++", b.name)
+
+	differ := false
+	diffField := func(name string, valueA, valueB interface{}) {
+		if va, vb := fmt.Sprint(valueA), fmt.Sprint(valueB); va != vb {
+			differ = true
+			fmt.Printf("-%s: %s\n+%s: %s\n", name, va, name, vb)
+		}
+	}
+	diffField("UserID", a.header.UserID, b.header.UserID)
+	diffField("Labels", a.header.Metric, b.header.Metric)
+	diffField("From", a.header.From.Time().In(timezone).Format(format), b.header.From.Time().In(timezone).Format(format))
+	diffField("Through", a.header.Through.Time().In(timezone).Format(format), b.header.Through.Time().In(timezone).Format(format))
+	diffField("Format", a.chunk.format, b.chunk.format)
+	diffField("Encoding", a.chunk.encoding, b.chunk.encoding)
+
+	onlyA, onlyB, common := diffEntries(entriesA, entriesB)
+	if !*quiet {
+		printDiffEntries(onlyA, onlyB)
+	}
+	fmt.Printf("%d entries only in %s, %d entries only in %s, %d entries in both\n", len(onlyA), a.name, len(onlyB), b.name, common)
+
+	if differ || len(onlyA) > 0 || len(onlyB) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// loadChunkEntries loads the chunk file and returns the entries of its valid blocks, sorted by timestamp.
+func loadChunkEntries(filename string) (*chunkFile, []LokiEntry, error) {
+	f, err := loadChunkFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := f.chunk.decodable(); err != nil {
+		return nil, nil, err
+	}
+	var entries []LokiEntry
+	for ix, b := range f.chunk.blocks {
+		if err := b.corruption(); err != nil {
+			log.Printf("%s: skipping block %d: %v", filename, ix, err)
+			continue
+		}
+		entries = append(entries, b.entries...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].timestamp < entries[j].timestamp })
+	return f, entries, nil
+}
+
+// diffEntries returns the entries only in a, only in b, and the number of entries in both.
+// The entries are compared by timestamp, then as a multiset of lines with their metadata,
+// because the order of the entries with the same timestamp isn't meaningful.
+func diffEntries(a, b []LokiEntry) (onlyA, onlyB []LokiEntry, common int) {
+	for len(a) > 0 || len(b) > 0 {
+		var ts int64
+		switch {
+		case len(a) == 0:
+			ts = b[0].timestamp
+		case len(b) == 0:
+			ts = a[0].timestamp
+		case a[0].timestamp < b[0].timestamp:
+			ts = a[0].timestamp
+		default:
+			ts = b[0].timestamp
+		}
+
+		var groupA, groupB []LokiEntry
+		groupA, a = splitTimestamp(a, ts)
+		groupB, b = splitTimestamp(b, ts)
+
+		countsA, countsB := map[string]int{}, map[string]int{}
+		for _, e := range groupA {
+			countsA[entryKey(e)]++
+		}
+		for _, e := range groupB {
+			countsB[entryKey(e)]++
+		}
+		for _, e := range groupA {
+			if k := entryKey(e); countsB[k] > 0 {
+				countsB[k]--
+			} else {
+				onlyA = append(onlyA, e)
+			}
+		}
+		for _, e := range groupB {
+			if k := entryKey(e); countsA[k] > 0 {
+				countsA[k]--
+				common++
+			} else {
+				onlyB = append(onlyB, e)
+			}
+		}
+	}
+	return onlyA, onlyB, common
+}
+
+// splitTimestamp splits the sorted entries after the ones with the timestamp.
+func splitTimestamp(entries []LokiEntry, ts int64) ([]LokiEntry, []LokiEntry) {
+	i := 0
+	for i < len(entries) && entries[i].timestamp == ts {
+		i++
+	}
+	return entries[:i], entries[i:]
+}
+
+func entryKey(e LokiEntry) string {
+	return e.line + "\x00" + e.metadata.String()
+}
+
+func printDiffEntries(onlyA, onlyB []LokiEntry) {
+	for len(onlyA) > 0 || len(onlyB) > 0 {
+		if len(onlyB) == 0 || (len(onlyA) > 0 && onlyA[0].timestamp <= onlyB[0].timestamp) {
+			printDiffEntry("-", onlyA[0])
+			onlyA = onlyA[1:]
+		} else {
+			printDiffEntry("+", onlyB[0])
+			onlyB = onlyB[1:]
+		}
+	}
+}
+
+func printDiffEntry(prefix string, e LokiEntry) {
+	ts := time.Unix(0, e.timestamp).In(timezone).Format(format)
+	if len(e.metadata) > 0 {
+		fmt.Printf("%s%v\t%s\t%s\n", prefix, ts, e.metadata, strings.TrimSpace(e.line))
+		return
+	}
+	fmt.Printf("%s%v\t%s\n", prefix, ts, strings.TrimSpace(e.line))
+}
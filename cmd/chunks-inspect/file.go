@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// chunkFile is a chunk file loaded in memory.
+type chunkFile struct {
+	name   string
+	data   []byte
+	header *ChunkHeader
+	chunk  *LokiChunk
+}
+
+func loadChunkFile(filename string) (*chunkFile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	h, err := DecodeHeader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	start := int(h.MetadataLength) + 4
+	if start+int(h.DataLength) > len(data) {
+		return nil, fmt.Errorf("data length %d larger than the file", h.DataLength)
+	}
+	c, err := parseLokiChunkData(data[start : start+int(h.DataLength)])
+	if err != nil {
+		return nil, err
+	}
+	return &chunkFile{name: filename, data: data, header: h, chunk: c}, nil
+}
+
+// storedChecksum returns the checksum of the chunk in its key, when the file is named after its key.
+func (f *chunkFile) storedChecksum() (uint32, bool) {
+	parts := strings.Split(filepath.Base(f.name), ":")
+	if len(parts) != 4 {
+		return 0, false
+	}
+	checksum, err := strconv.ParseUint(parts[3], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(checksum), true
+}
+
+// encodeChunkFile encodes the chunk file with the chunk data, keeping the chunk metadata of the file.
+// It returns the file content and its checksum.
+func encodeChunkFile(f *chunkFile, data []byte) ([]byte, uint32) {
+	buf := make([]byte, 0, int(f.header.MetadataLength)+4+len(data))
+	buf = append(buf, f.data[:f.header.MetadataLength]...)
+	var dataLength [4]byte
+	binary.BigEndian.PutUint32(dataLength[:], uint32(len(data)))
+	buf = append(buf, dataLength[:]...)
+	buf = append(buf, data...)
+	return buf, crc32.Checksum(buf, castagnoliTable)
+}
+
+// chunkFilename returns the name of a chunk file with the checksum, named after the chunk key
+// like the chunks of the filesystem object store.
+func chunkFilename(h *ChunkHeader, checksum uint32) string {
+	return fmt.Sprintf("%x:%x:%x:%x", h.Fingerprint, int64(h.From), int64(h.Through), checksum)
+}
+
+// listFiles returns the files of the paths, walking the directories.
+func listFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// forEachFile calls fn on each file, on at most parallelism files at once.
+func forEachFile(files []string, parallelism int, fn func(filename string)) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	ch := make(chan string)
+	wg := sync.WaitGroup{}
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range ch {
+				fn(f)
+			}
+		}()
+	}
+	for _, f := range files {
+		ch <- f
+	}
+	close(ch)
+	wg.Wait()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -13,6 +14,16 @@ import (
 	"github.com/pierrec/lz4"
 )
 
+const lokiMagicNumber = 0x012EE56A
+
+// Chunk formats, see chunkFormatV* in pkg/chunkenc.
+const (
+	chunkFormatV1 = 1 // gzip blocks
+	chunkFormatV2 = 2 // adds a byte for the encoding of the blocks
+	chunkFormatV3 = 3 // adds the ID of the zstd dictionary
+	chunkFormatV4 = 4 // adds the structured metadata of the entries
+)
+
 type Encoding struct {
 	code     int
 	name     string
@@ -33,24 +44,45 @@ func init() {
 }
 
 var (
-	encNone   = Encoding{code: 0, name: "none", readerFn: func(reader io.Reader) (io.Reader, error) { return reader, nil }}
-	encGZIP   = Encoding{code: 1, name: "gzip", readerFn: func(reader io.Reader) (io.Reader, error) { return gzip.NewReader(reader) }}
-	encDumb   = Encoding{code: 2, name: "dumb", readerFn: func(reader io.Reader) (io.Reader, error) { return reader, nil }}
-	encLZ4    = Encoding{code: 3, name: "lz4", readerFn: func(reader io.Reader) (io.Reader, error) { return lz4.NewReader(reader), nil }}
-	encSnappy = Encoding{code: 4, name: "snappy", readerFn: func(reader io.Reader) (io.Reader, error) { return snappy.NewReader(reader), nil }}
-
-	Encodings = []Encoding{encNone, encGZIP, encDumb, encLZ4, encSnappy}
+	lz4ReaderFn = func(reader io.Reader) (io.Reader, error) { return lz4.NewReader(reader), nil }
+
+	encNone     = Encoding{code: 0, name: "none", readerFn: func(reader io.Reader) (io.Reader, error) { return reader, nil }}
+	encGZIP     = Encoding{code: 1, name: "gzip", readerFn: func(reader io.Reader) (io.Reader, error) { return gzip.NewReader(reader) }}
+	encDumb     = Encoding{code: 2, name: "dumb", readerFn: func(reader io.Reader) (io.Reader, error) { return reader, nil }}
+	encLZ4      = Encoding{code: 3, name: "lz4", readerFn: lz4ReaderFn}
+	encSnappy   = Encoding{code: 4, name: "snappy", readerFn: func(reader io.Reader) (io.Reader, error) { return snappy.NewReader(reader), nil }}
+	encLZ4_256k = Encoding{code: 5, name: "lz4-256k", readerFn: lz4ReaderFn}
+	encLZ4_1M   = Encoding{code: 6, name: "lz4-1M", readerFn: lz4ReaderFn}
+	encLZ4_4M   = Encoding{code: 7, name: "lz4-4M", readerFn: lz4ReaderFn}
+	// zstd blocks can't be decompressed by this tool, only their checksums are verified.
+	encZstd = Encoding{code: 8, name: "zstd"}
+
+	Encodings = []Encoding{encNone, encGZIP, encDumb, encLZ4, encSnappy, encLZ4_256k, encLZ4_1M, encLZ4_4M, encZstd}
 )
 
 type LokiChunk struct {
+	format   byte
 	encoding Encoding
+	dictID   uint64 // ID of the zstd dictionary of the blocks, 0 for none
 
+	header []byte // magic number, format, encoding and dictionary, as stored in chunk file
 	blocks []LokiBlock
 
 	metadataChecksum         uint32
 	computedMetadataChecksum uint32
 }
 
+// decodable returns an error when the blocks of the chunk can't be decompressed by this tool.
+func (c *LokiChunk) decodable() error {
+	if c.encoding.readerFn == nil {
+		return fmt.Errorf("unsupported encoding %s", c.encoding)
+	}
+	if c.dictID != 0 {
+		return fmt.Errorf("unsupported dictionary %d", c.dictID)
+	}
+	return nil
+}
+
 type LokiBlock struct {
 	numEntries uint64 // number of log lines in this block
 	minT       int64  // minimum timestamp, unix nanoseconds
@@ -65,11 +97,36 @@ type LokiBlock struct {
 	entries          []LokiEntry
 	storedChecksum   uint32
 	computedChecksum uint32
+
+	// error reading or decoding the block, nil if it is valid.
+	err error
+}
+
+// corruption returns why the block is corrupted, or nil if it is valid.
+func (b *LokiBlock) corruption() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.storedChecksum != b.computedChecksum {
+		return fmt.Errorf("checksum %08x, computed %08x", b.storedChecksum, b.computedChecksum)
+	}
+	if b.originalData == nil {
+		// not decoded, see LokiChunk.decodable.
+		return nil
+	}
+	if uint64(len(b.entries)) != b.numEntries {
+		return fmt.Errorf("%d entries, expected %d", len(b.entries), b.numEntries)
+	}
+	if len(b.entries) > 0 && (b.entries[0].timestamp != b.minT || b.entries[len(b.entries)-1].timestamp != b.maxT) {
+		return fmt.Errorf("entries from %d to %d, expected from %d to %d", b.entries[0].timestamp, b.entries[len(b.entries)-1].timestamp, b.minT, b.maxT)
+	}
+	return nil
 }
 
 type LokiEntry struct {
 	timestamp int64
 	line      string
+	metadata  Labels // structured metadata, chunk format v4 only
 }
 
 func parseLokiChunk(chunkHeader *ChunkHeader, r io.Reader) (*LokiChunk, error) {
@@ -78,24 +135,51 @@ func parseLokiChunk(chunkHeader *ChunkHeader, r io.Reader) (*LokiChunk, error) {
 	if _, err := io.ReadFull(r, data); err != nil {
 		return nil, fmt.Errorf("failed to read rawData for Loki chunk into memory: %w", err)
 	}
+	return parseLokiChunkData(data)
+}
 
-	if num := binary.BigEndian.Uint32(data[0:4]); num != 0x012EE56A {
+func parseLokiChunkData(data []byte) (*LokiChunk, error) {
+	if len(data) < 6+4+8 {
+		return nil, fmt.Errorf("chunk too short: %d bytes", len(data))
+	}
+	if num := binary.BigEndian.Uint32(data[0:4]); num != lokiMagicNumber {
 		return nil, fmt.Errorf("invalid magic number: %0x", num)
 	}
 
-	compression, err := getCompression(data[4], data[5])
+	format := data[4]
+	compression, err := getCompression(format, data[5])
 	if err != nil {
 		return nil, fmt.Errorf("failed to read compression: %w", err)
 	}
 
-	// return &LokiChunk{encoding: compression}, nil
+	lokiChunk := &LokiChunk{
+		format:   format,
+		encoding: compression,
+	}
+
+	headerLength := 6
+	switch format {
+	case chunkFormatV1:
+		headerLength = 5
+	case chunkFormatV3, chunkFormatV4:
+		dictID, n := binary.Uvarint(data[6:])
+		if n <= 0 {
+			return nil, fmt.Errorf("failed to read dictionary")
+		}
+		lokiChunk.dictID = dictID
+		headerLength += n
+	}
+	lokiChunk.header = data[:headerLength]
 
 	metasOffset := binary.BigEndian.Uint64(data[len(data)-8:])
+	if metasOffset < uint64(headerLength) || metasOffset > uint64(len(data)-(8+4)) {
+		return nil, fmt.Errorf("invalid blocks metadata offset: %d", metasOffset)
+	}
 
 	metadata := data[metasOffset : len(data)-(8+4)]
 
-	metaChecksum := binary.BigEndian.Uint32(data[len(data)-12 : len(data)-8])
-	computedMetaChecksum := crc32.Checksum(metadata, castagnoliTable)
+	lokiChunk.metadataChecksum = binary.BigEndian.Uint32(data[len(data)-12 : len(data)-8])
+	lokiChunk.computedMetadataChecksum = crc32.Checksum(metadata, castagnoliTable)
 
 	blocks, n := binary.Uvarint(metadata)
 	if n <= 0 {
@@ -103,12 +187,7 @@ func parseLokiChunk(chunkHeader *ChunkHeader, r io.Reader) (*LokiChunk, error) {
 	}
 	metadata = metadata[n:]
 
-	lokiChunk := &LokiChunk{
-		encoding:                 compression,
-		metadataChecksum:         metaChecksum,
-		computedMetadataChecksum: computedMetaChecksum,
-	}
-
+	decodable := lokiChunk.decodable() == nil
 	for ix := 0; ix < int(blocks); ix++ {
 		block := LokiBlock{}
 		block.numEntries, metadata, err = readUvarint(err, metadata)
@@ -119,20 +198,28 @@ func parseLokiChunk(chunkHeader *ChunkHeader, r io.Reader) (*LokiChunk, error) {
 		dataLength, metadata, err = readUvarint(err, metadata)
 
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to read metadata of block %d: %w", ix, err)
+		}
+
+		if block.dataOffset < uint64(headerLength) || block.dataOffset+dataLength+4 > metasOffset {
+			block.err = fmt.Errorf("block out of bounds, position: %d, length: %d", block.dataOffset, dataLength)
+			lokiChunk.blocks = append(lokiChunk.blocks, block)
+			continue
 		}
 
 		block.rawData = data[block.dataOffset : block.dataOffset+dataLength]
 		block.storedChecksum = binary.BigEndian.Uint32(data[block.dataOffset+dataLength : block.dataOffset+dataLength+4])
 		block.computedChecksum = crc32.Checksum(block.rawData, castagnoliTable)
-		block.originalData, block.entries, err = parseLokiBlock(compression, block.rawData)
+		if decodable {
+			block.originalData, block.entries, block.err = parseLokiBlock(compression, format, block.rawData)
+		}
 		lokiChunk.blocks = append(lokiChunk.blocks, block)
 	}
 
 	return lokiChunk, nil
 }
 
-func parseLokiBlock(compression Encoding, data []byte) ([]byte, []LokiEntry, error) {
+func parseLokiBlock(compression Encoding, format byte, data []byte) ([]byte, []LokiEntry, error) {
 	r, err := compression.readerFn(bytes.NewReader(data))
 	if err != nil {
 		return nil, nil, err
@@ -152,24 +239,68 @@ func parseLokiBlock(compression Encoding, data []byte) ([]byte, []LokiEntry, err
 		timestamp, decompressed, err = readVarint(err, decompressed)
 		lineLength, decompressed, err = readUvarint(err, decompressed)
 		if err != nil {
-			return origDecompressed, nil, err
+			return origDecompressed, entries, err
+		}
+
+		hasMetadata := false
+		if format >= chunkFormatV4 {
+			// the lowest bit of the line length flags the entries with structured metadata.
+			hasMetadata = lineLength&1 == 1
+			lineLength >>= 1
 		}
 
-		if len(decompressed) < int(lineLength) {
-			return origDecompressed, nil, fmt.Errorf("not enough line data, need %d, got %d", lineLength, len(decompressed))
+		if uint64(len(decompressed)) < lineLength {
+			return origDecompressed, entries, fmt.Errorf("not enough line data, need %d, got %d", lineLength, len(decompressed))
 		}
 
-		entries = append(entries, LokiEntry{
+		entry := LokiEntry{
 			timestamp: timestamp,
 			line:      string(decompressed[0:lineLength]),
-		})
-
+		}
 		decompressed = decompressed[lineLength:]
+
+		if hasMetadata {
+			entry.metadata, decompressed, err = readMetadata(decompressed)
+			if err != nil {
+				return origDecompressed, entries, err
+			}
+		}
+
+		entries = append(entries, entry)
 	}
 
 	return origDecompressed, entries, nil
 }
 
+func readMetadata(buf []byte) (Labels, []byte, error) {
+	count, buf, err := readUvarint(nil, buf)
+	if err != nil {
+		return nil, buf, fmt.Errorf("failed to read structured metadata: %w", err)
+	}
+	metadata := make(Labels, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var name, value string
+		name, buf, err = readString(err, buf)
+		value, buf, err = readString(err, buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("failed to read structured metadata: %w", err)
+		}
+		metadata = append(metadata, Label{Name: name, Value: value})
+	}
+	return metadata, buf, nil
+}
+
+func readString(prevErr error, buf []byte) (string, []byte, error) {
+	length, buf, err := readUvarint(prevErr, buf)
+	if err != nil {
+		return "", buf, err
+	}
+	if uint64(len(buf)) < length {
+		return "", nil, fmt.Errorf("not enough data, need %d, got %d", length, len(buf))
+	}
+	return string(buf[:length]), buf[length:], nil
+}
+
 func readVarint(prevErr error, buf []byte) (int64, []byte, error) {
 	if prevErr != nil {
 		return 0, buf, prevErr
@@ -195,11 +326,11 @@ func readUvarint(prevErr error, buf []byte) (uint64, []byte, error) {
 }
 
 func getCompression(format byte, code byte) (Encoding, error) {
-	if format == 1 {
+	if format == chunkFormatV1 {
 		return encGZIP, nil
 	}
 
-	if format == 2 {
+	if format >= chunkFormatV2 && format <= chunkFormatV4 {
 		for _, e := range Encodings {
 			if e.code == int(code) {
 				return e, nil
@@ -211,3 +342,42 @@ func getCompression(format byte, code byte) (Encoding, error) {
 
 	return encNone, fmt.Errorf("unknown format: %d", format)
 }
+
+// encodeLokiChunk encodes the chunk data with the blocks, as the Bytes method of the chunkenc.MemChunk.
+func encodeLokiChunk(c *LokiChunk, blocks []LokiBlock) ([]byte, error) {
+	if len(blocks) == 0 {
+		return nil, errors.New("no blocks")
+	}
+	buf := bytes.NewBuffer(nil)
+	buf.Write(c.header)
+
+	var (
+		meta    []byte
+		scratch [binary.MaxVarintLen64]byte
+	)
+	putUvarint := func(v uint64) { meta = append(meta, scratch[:binary.PutUvarint(scratch[:], v)]...) }
+	putVarint := func(v int64) { meta = append(meta, scratch[:binary.PutVarint(scratch[:], v)]...) }
+
+	putUvarint(uint64(len(blocks)))
+	for _, b := range blocks {
+		offset := buf.Len()
+		buf.Write(b.rawData)
+		var checksum [4]byte
+		binary.BigEndian.PutUint32(checksum[:], crc32.Checksum(b.rawData, castagnoliTable))
+		buf.Write(checksum[:])
+
+		putUvarint(b.numEntries)
+		putVarint(b.minT)
+		putVarint(b.maxT)
+		putUvarint(uint64(offset))
+		putUvarint(uint64(len(b.rawData)))
+	}
+
+	metasOffset := buf.Len()
+	buf.Write(meta)
+	var trailer [12]byte
+	binary.BigEndian.PutUint32(trailer[:4], crc32.Checksum(meta, castagnoliTable))
+	binary.BigEndian.PutUint64(trailer[4:], uint64(metasOffset))
+	buf.Write(trailer[:])
+	return buf.Bytes(), nil
+}
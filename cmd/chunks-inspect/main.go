@@ -15,10 +15,29 @@ const format = "2006-01-02 15:04:05.000000 MST"
 
 var timezone = time.UTC
 
+var commands = map[string]func(args []string) int{
+	"verify": verifyCommand,
+	"repair": repairCommand,
+	"diff":   diffCommand,
+	"stats":  statsCommand,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
 	blocks := flag.Bool("b", false, "print block details")
 	lines := flag.Bool("l", false, "print log lines")
 	storeBlocks := flag.Bool("s", false, "store blocks, using input filename, and appending block index to it")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s [options] <chunk file>...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s verify|repair|diff|stats -h\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	for _, f := range flag.Args() {
@@ -66,7 +85,11 @@ func printFile(filename string, blockDetails, printLines, storeBlocks bool) {
 		return
 	}
 
+	fmt.Printf("Format: v%d\n", lokiChunk.format)
 	fmt.Println("Encoding:", lokiChunk.encoding)
+	if err := lokiChunk.decodable(); err != nil {
+		fmt.Println("Blocks can't be decoded:", err)
+	}
 	fmt.Print("Blocks Metadata Checksum: ", fmt.Sprintf("%08x", lokiChunk.metadataChecksum))
 	if lokiChunk.metadataChecksum == lokiChunk.computedMetadataChecksum {
 		fmt.Println(" OK")
@@ -102,12 +125,19 @@ func printFile(filename string, blockDetails, printLines, storeBlocks bool) {
 				time.Unix(0, b.minT).In(timezone).Format(format), time.Unix(0, b.maxT).In(timezone).Format(format),
 				cksum)
 			fmt.Printf("Block %4d: digest compressed: %02x, original: %02x\n", ix, sha256.Sum256(b.rawData), sha256.Sum256(b.originalData))
+			if err := b.corruption(); err != nil {
+				fmt.Printf("Block %4d: corrupted: %v\n", ix, err)
+			}
 		}
 
 		totalSize += len(b.originalData)
 
 		if printLines {
 			for _, l := range b.entries {
+				if len(l.metadata) > 0 {
+					fmt.Printf("%v\t%s\t%s\n", time.Unix(0, l.timestamp).In(timezone).Format(format), l.metadata, strings.TrimSpace(l.line))
+					continue
+				}
 				fmt.Printf("%v\t%s\n", time.Unix(0, l.timestamp).In(timezone).Format(format), strings.TrimSpace(l.line))
 			}
 		}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+)
+
+func repairCommand(args []string) int {
+	fs := flag.NewFlagSet("repair", flag.ExitOnError)
+	output := fs.String("o", ".", "directory to write the repaired chunks to")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: chunks-inspect repair [options] <chunk file or directory>...")
+		fmt.Fprintln(fs.Output(), "Drops the corrupted blocks of the chunks, and writes the chunks made of the valid blocks left.")
+		fmt.Fprintln(fs.Output(), "The chunks named after their key are written with the key of the repaired chunk, the others with a .repaired suffix.")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	files, err := listFiles(fs.Args())
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	failed := 0
+	for _, filename := range files {
+		if err := repairFile(filename, *output); err != nil {
+			fmt.Printf("%s: %v\n", filename, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func repairFile(filename, output string) error {
+	f, err := loadChunkFile(filename)
+	if err != nil {
+		return err
+	}
+	if len(verifyChunk(f)) == 0 {
+		fmt.Printf("%s: nothing to repair\n", filename)
+		return nil
+	}
+
+	var (
+		valid          []LokiBlock
+		droppedEntries uint64
+	)
+	for _, b := range f.chunk.blocks {
+		if b.corruption() != nil {
+			droppedEntries += b.numEntries
+			continue
+		}
+		valid = append(valid, b)
+	}
+	if len(valid) == 0 {
+		return fmt.Errorf("no valid block left, can't repair")
+	}
+
+	data, err := encodeLokiChunk(f.chunk, valid)
+	if err != nil {
+		return err
+	}
+	content, checksum := encodeChunkFile(f, data)
+
+	name := filepath.Base(filename) + ".repaired"
+	if _, ok := f.storedChecksum(); ok {
+		name = chunkFilename(f.header, checksum)
+	}
+	path := filepath.Join(output, name)
+	if abs, err := filepath.Abs(path); err == nil {
+		if in, err := filepath.Abs(filename); err == nil && in == abs {
+			return fmt.Errorf("refusing to overwrite %s", filename)
+		}
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	// the repaired chunk is verified, as it would be by Loki.
+	repaired, err := loadChunkFile(path)
+	if err != nil {
+		return fmt.Errorf("repaired chunk %s unreadable: %w", path, err)
+	}
+	if problems := verifyChunk(repaired); len(problems) > 0 {
+		return fmt.Errorf("repaired chunk %s corrupted: %v", path, problems)
+	}
+
+	fmt.Printf("%s: dropped %d of %d block(s) (%d entries), written to %s\n", filename, len(f.chunk.blocks)-len(valid), len(f.chunk.blocks), droppedEntries, path)
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+func statsCommand(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	parallelism := fs.Int("p", runtime.NumCPU(), "number of chunks read in parallel")
+	blockSize := fs.Int("block-size", 256*1024, "block size the chunks were cut with, in bytes, see -ingester.chunks-block-size")
+	targetSize := fs.Int("target-size", 0, "target size the chunks were cut with, in bytes, see -ingester.chunk-target-size")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: chunks-inspect stats [options] <chunk file or directory>...")
+		fmt.Fprintln(fs.Output(), "Aggregates the formats, encodings, entries, sizes and utilization of the chunks.")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *blockSize <= 0 {
+		log.Println("block size must be positive")
+		return 2
+	}
+
+	files, err := listFiles(fs.Args())
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	var mtx sync.Mutex
+	s := newChunkStats(*blockSize, *targetSize)
+	forEachFile(files, *parallelism, func(filename string) {
+		f, err := loadChunkFile(filename)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		if err != nil {
+			log.Printf("%s: %v", filename, err)
+			s.unreadable++
+			return
+		}
+		s.add(f)
+	})
+	s.print()
+	return 0
+}
+
+const utilizationBuckets = 10
+
+type chunkStats struct {
+	blockSize, targetSize int
+
+	chunks, unreadable, corrupted int
+	formats                       map[byte]int
+	encodings                     map[string]int
+
+	blocks                             int
+	entries, minEntries, maxEntries    uint64
+	fileSize, storedSize, originalSize int64
+
+	// utilization of the decoded chunks, and of their full blocks.
+	decoded           int
+	utilization       float64
+	utilizationCounts [utilizationBuckets + 1]int
+	fullBlocks        int
+	blockUtilization  float64
+}
+
+func newChunkStats(blockSize, targetSize int) *chunkStats {
+	return &chunkStats{
+		blockSize:  blockSize,
+		targetSize: targetSize,
+		formats:    map[byte]int{},
+		encodings:  map[string]int{},
+	}
+}
+
+func (s *chunkStats) add(f *chunkFile) {
+	c := f.chunk
+	s.chunks++
+	s.formats[c.format]++
+	s.encodings[c.encoding.name]++
+	s.fileSize += int64(len(f.data))
+	if len(verifyChunk(f)) > 0 {
+		s.corrupted++
+	}
+
+	var entries uint64
+	var stored, original int
+	for _, b := range c.blocks {
+		entries += b.numEntries
+		stored += len(b.rawData)
+		original += len(b.originalData)
+	}
+	s.blocks += len(c.blocks)
+	s.entries += entries
+	if s.chunks == 1 || entries < s.minEntries {
+		s.minEntries = entries
+	}
+	if entries > s.maxEntries {
+		s.maxEntries = entries
+	}
+	s.storedSize += int64(stored)
+
+	if c.decodable() != nil {
+		return
+	}
+	s.decoded++
+	s.originalSize += int64(original)
+
+	// as chunkenc.MemChunk.Utilization.
+	var utilization float64
+	if s.targetSize != 0 {
+		utilization = float64(stored) / float64(s.targetSize)
+	} else {
+		utilization = float64(original) / float64(10*s.blockSize)
+	}
+	s.utilization += utilization
+	bucket := int(utilization * utilizationBuckets)
+	if bucket > utilizationBuckets {
+		bucket = utilizationBuckets
+	}
+	s.utilizationCounts[bucket]++
+
+	// the last block is cut when the chunk is flushed, it isn't full.
+	for i := 0; i < len(c.blocks)-1; i++ {
+		s.fullBlocks++
+		s.blockUtilization += float64(len(c.blocks[i].originalData)) / float64(s.blockSize)
+	}
+}
+
+func (s *chunkStats) print() {
+	fmt.Printf("Chunks: %d (%d unreadable, %d corrupted)\n", s.chunks, s.unreadable, s.corrupted)
+	if s.chunks == 0 {
+		return
+	}
+
+	formats := make([]int, 0, len(s.formats))
+	for f := range s.formats {
+		formats = append(formats, int(f))
+	}
+	sort.Ints(formats)
+	fmt.Print("Formats:")
+	for _, f := range formats {
+		fmt.Printf(" v%d: %d", f, s.formats[byte(f)])
+	}
+	fmt.Println()
+
+	encodings := make([]string, 0, len(s.encodings))
+	for e := range s.encodings {
+		encodings = append(encodings, e)
+	}
+	sort.Strings(encodings)
+	fmt.Print("Encodings:")
+	for _, e := range encodings {
+		fmt.Printf(" %s: %d", e, s.encodings[e])
+	}
+	fmt.Println()
+
+	fmt.Printf("Blocks: %d (%.1f per chunk)\n", s.blocks, float64(s.blocks)/float64(s.chunks))
+	fmt.Printf("Entries: %d (per chunk min: %d, avg: %.1f, max: %d)\n", s.entries, s.minEntries, float64(s.entries)/float64(s.chunks), s.maxEntries)
+	fmt.Printf("File size: %d, stored blocks size: %d\n", s.fileSize, s.storedSize)
+
+	if s.decoded == 0 {
+		fmt.Println("No chunk decoded, original size and utilization unknown")
+		return
+	}
+	if s.decoded < s.chunks {
+		fmt.Printf("Decoded %d of %d chunk(s), the following only covers the decoded chunks\n", s.decoded, s.chunks)
+	}
+	fmt.Printf("Original size: %d, ratio: %0.3g\n", s.originalSize, float64(s.originalSize)/float64(s.fileSize))
+	if s.fullBlocks > 0 {
+		fmt.Printf("Block utilization: %.2f (block size: %d, last block of the chunks excluded)\n", s.blockUtilization/float64(s.fullBlocks), s.blockSize)
+	}
+	fmt.Printf("Chunk utilization: %.2f (block size: %d, target size: %d)\n", s.utilization/float64(s.decoded), s.blockSize, s.targetSize)
+	for i, n := range s.utilizationCounts {
+		if i == utilizationBuckets {
+			fmt.Printf("\t >= %d%%: %d\n", i*100/utilizationBuckets, n)
+			continue
+		}
+		fmt.Printf("\t%3d-%d%%: %d\n", i*100/utilizationBuckets, (i+1)*100/utilizationBuckets, n)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"runtime"
+	"sync"
+)
+
+func verifyCommand(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	parallelism := fs.Int("p", runtime.NumCPU(), "number of chunks verified in parallel")
+	verbose := fs.Bool("v", false, "print the valid chunks too")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: chunks-inspect verify [options] <chunk file or directory>...")
+		fmt.Fprintln(fs.Output(), "Verifies the checksums and the entries of the blocks of the chunks, exits with 1 if any is corrupted.")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	files, err := listFiles(fs.Args())
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	var (
+		mtx                          sync.Mutex
+		valid, corrupted, unreadable int
+	)
+	forEachFile(files, *parallelism, func(filename string) {
+		f, err := loadChunkFile(filename)
+		var problems []string
+		if err == nil {
+			problems = verifyChunk(f)
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		switch {
+		case err != nil:
+			unreadable++
+			fmt.Printf("%s: UNREADABLE: %v\n", filename, err)
+		case len(problems) > 0:
+			corrupted++
+			fmt.Printf("%s: CORRUPTED\n", filename)
+			for _, p := range problems {
+				fmt.Println("\t", p)
+			}
+		default:
+			valid++
+			if !*verbose {
+				return
+			}
+			if err := f.chunk.decodable(); err != nil {
+				fmt.Printf("%s: OK, checksums only: %v\n", filename, err)
+			} else {
+				fmt.Printf("%s: OK\n", filename)
+			}
+		}
+	})
+
+	fmt.Printf("Verified %d chunk(s): %d OK, %d corrupted, %d unreadable\n", len(files), valid, corrupted, unreadable)
+	if corrupted+unreadable > 0 {
+		return 1
+	}
+	return 0
+}
+
+// verifyChunk returns the corruptions found in the chunk file.
+func verifyChunk(f *chunkFile) []string {
+	var problems []string
+	if checksum, ok := f.storedChecksum(); ok {
+		if computed := crc32.Checksum(f.data, castagnoliTable); computed != checksum {
+			problems = append(problems, fmt.Sprintf("file checksum %08x, computed %08x", checksum, computed))
+		}
+	}
+	c := f.chunk
+	if c.metadataChecksum != c.computedMetadataChecksum {
+		problems = append(problems, fmt.Sprintf("blocks metadata checksum %08x, computed %08x", c.metadataChecksum, c.computedMetadataChecksum))
+	}
+	for ix := range c.blocks {
+		if err := c.blocks[ix].corruption(); err != nil {
+			problems = append(problems, fmt.Sprintf("block %d: %v", ix, err))
+		}
+	}
+	return problems
+}
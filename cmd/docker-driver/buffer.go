@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	// bufferRoot is the folder of the buffers of the containers, kept across restarts of the plugin.
+	bufferRoot = "/var/lib/loki-docker-driver/buffer"
+
+	segmentSuffix = ".seg"
+	positionFile  = "position"
+
+	// a buffer is made of this many segments, the oldest being dropped when the buffer is full.
+	segmentsPerBuffer = 10
+	// maximum size of a record, larger lengths are considered as corrupted.
+	maxRecordSize = 16 << 20
+)
+
+// buffer is an on-disk buffer of the entries of a container, written before they are pushed
+// to Loki so that they survive the outages of Loki and the restarts of the plugin.
+//
+// The entries are appended as records to segment files, and read from the position of the oldest
+// entry not pushed yet. The position is committed once the entries read are pushed, which removes
+// the segments fully pushed. When the buffer is larger than its maximum size, its oldest segments
+// are dropped.
+type buffer struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+	logger      log.Logger
+
+	mtx      sync.Mutex
+	segments []segment // oldest first, the last one being written.
+	current  *os.File
+	position position
+
+	// written is notified when entries are written.
+	written chan struct{}
+}
+
+type segment struct {
+	index int64
+	size  int64
+}
+
+// position is the position of the next record to read.
+type position struct {
+	segment int64
+	offset  int64
+}
+
+// newBuffer opens the buffer of the folder, creating it if it doesn't exist.
+func newBuffer(dir string, maxSize int64, logger log.Logger) (*buffer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "error creating buffer dir")
+	}
+	b := &buffer{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: maxSize / segmentsPerBuffer,
+		logger:      logger,
+		written:     make(chan struct{}, 1),
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), segmentSuffix) {
+			continue
+		}
+		index, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		b.segments = append(b.segments, segment{index: index, size: f.Size()})
+	}
+	sort.Slice(b.segments, func(i, j int) bool { return b.segments[i].index < b.segments[j].index })
+
+	// the entries are written to a new segment, the last one may end with a partial record.
+	next := int64(0)
+	if len(b.segments) > 0 {
+		next = b.segments[len(b.segments)-1].index + 1
+	}
+	if err := b.openSegment(next); err != nil {
+		return nil, err
+	}
+
+	b.position = position{segment: b.segments[0].index}
+	if content, err := ioutil.ReadFile(filepath.Join(dir, positionFile)); err == nil {
+		var p position
+		if _, err := fmt.Sscanf(string(content), "%d %d", &p.segment, &p.offset); err == nil && p.segment >= b.position.segment {
+			b.position = p
+		}
+	}
+	if len(b.segments) > 1 {
+		level.Info(logger).Log("msg", "resuming buffered entries", "dir", dir, "bytes", b.unread())
+	}
+	return b, nil
+}
+
+func (b *buffer) segmentPath(index int64) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%020d%s", index, segmentSuffix))
+}
+
+func (b *buffer) openSegment(index int64) error {
+	f, err := os.OpenFile(b.segmentPath(index), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "error creating buffer segment")
+	}
+	if b.current != nil {
+		if err := b.current.Close(); err != nil {
+			level.Warn(b.logger).Log("msg", "error closing buffer segment", "err", err)
+		}
+	}
+	b.current = f
+	b.segments = append(b.segments, segment{index: index})
+	return nil
+}
+
+// Handle implements api.EntryHandler, writing the entry to the buffer.
+func (b *buffer) Handle(ls model.LabelSet, t time.Time, line string) error {
+	stream := logproto.Stream{
+		Labels:  ls.String(),
+		Entries: []logproto.Entry{{Timestamp: t, Line: line}},
+	}
+	rec, err := stream.Marshal()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4+len(rec))
+	binary.BigEndian.PutUint32(buf, uint32(len(rec)))
+	copy(buf[4:], rec)
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.segments[len(b.segments)-1].size >= b.segmentSize {
+		if err := b.openSegment(b.segments[len(b.segments)-1].index + 1); err != nil {
+			return err
+		}
+	}
+	for b.size()+int64(len(buf)) > b.maxSize && len(b.segments) > 1 {
+		b.dropOldest()
+	}
+
+	if _, err := b.current.Write(buf); err != nil {
+		return errors.Wrap(err, "error writing to buffer")
+	}
+	b.segments[len(b.segments)-1].size += int64(len(buf))
+
+	select {
+	case b.written <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (b *buffer) size() int64 {
+	size := int64(0)
+	for _, s := range b.segments {
+		size += s.size
+	}
+	return size
+}
+
+// unread returns the number of bytes of the records not read yet.
+func (b *buffer) unread() int64 {
+	unread := int64(0)
+	for _, s := range b.segments {
+		if s.index > b.position.segment {
+			unread += s.size
+		} else if s.index == b.position.segment {
+			unread += s.size - b.position.offset
+		}
+	}
+	return unread
+}
+
+// Unread returns the number of bytes of the records not read yet.
+func (b *buffer) Unread() int64 {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.unread()
+}
+
+func (b *buffer) dropOldest() {
+	oldest := b.segments[0]
+	b.segments = b.segments[1:]
+	if err := os.Remove(b.segmentPath(oldest.index)); err != nil {
+		level.Warn(b.logger).Log("msg", "error removing buffer segment", "err", err)
+	}
+	if b.position.segment <= oldest.index {
+		level.Warn(b.logger).Log("msg", "buffer full, dropping the oldest entries not pushed", "bytes", oldest.size-b.position.offset)
+		b.position = position{segment: b.segments[0].index}
+	}
+}
+
+// Read returns the streams of the records from the read position, up to maxBytes, and the position
+// after them, to commit once they are pushed.
+func (b *buffer) Read(maxBytes int) ([]logproto.Stream, position, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	var (
+		streams []logproto.Stream
+		p       = b.position
+		read    = 0
+	)
+	for i, s := range b.segments {
+		if s.index < p.segment || read >= maxBytes {
+			continue
+		}
+		if s.index > p.segment {
+			p = position{segment: s.index}
+		}
+		if p.offset < s.size {
+			n, err := b.readSegment(p, maxBytes-read, &streams)
+			if err != nil {
+				// the rest of the segment is corrupted, e.g. the plugin stopped while writing it.
+				level.Warn(b.logger).Log("msg", "skipping the end of a buffer segment", "segment", s.index, "err", err)
+				n = s.size - p.offset
+			}
+			p.offset += n
+			read += int(n)
+		}
+		if p.offset < s.size || i == len(b.segments)-1 {
+			break
+		}
+	}
+	return streams, p, nil
+}
+
+// readSegment reads the records of a segment from the position, up to maxBytes. It returns the
+// number of bytes read.
+func (b *buffer) readSegment(p position, maxBytes int, streams *[]logproto.Stream) (int64, error) {
+	f, err := os.Open(b.segmentPath(p.segment))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(p.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(f)
+	read := int64(0)
+	var length [4]byte
+	for read < int64(maxBytes) {
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			if err == io.EOF {
+				return read, nil
+			}
+			return read, err
+		}
+		n := binary.BigEndian.Uint32(length[:])
+		if n > maxRecordSize {
+			return read, fmt.Errorf("invalid record length %d", n)
+		}
+		rec := make([]byte, n)
+		if _, err := io.ReadFull(r, rec); err != nil {
+			return read, err
+		}
+		var stream logproto.Stream
+		if err := stream.Unmarshal(rec); err != nil {
+			return read, err
+		}
+		*streams = append(*streams, stream)
+		read += 4 + int64(n)
+	}
+	return read, nil
+}
+
+// Commit moves the read position after the records pushed, removing the segments fully pushed.
+func (b *buffer) Commit(p position) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	// the segment read was dropped while pushing it.
+	if p.segment < b.segments[0].index {
+		p = position{segment: b.segments[0].index}
+	}
+	b.position = p
+	for len(b.segments) > 1 && b.segments[0].index < p.segment {
+		if err := os.Remove(b.segmentPath(b.segments[0].index)); err != nil {
+			return err
+		}
+		b.segments = b.segments[1:]
+	}
+
+	tmp := filepath.Join(b.dir, positionFile+".tmp")
+	if err := ioutil.WriteFile(tmp, []byte(fmt.Sprintf("%d %d\n", p.segment, p.offset)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(b.dir, positionFile))
+}
+
+// Close closes the buffer, which is removed when all its entries were pushed.
+func (b *buffer) Close() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.current.Close(); err != nil {
+		return err
+	}
+	if b.unread() > 0 {
+		level.Warn(b.logger).Log("msg", "keeping the buffered entries not pushed", "dir", b.dir, "bytes", b.unread())
+		return nil
+	}
+	return os.RemoveAll(b.dir)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	cortex_util "github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/promtail/client"
+)
+
+func writeEntries(t *testing.T, b *buffer, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		require.NoError(t, b.Handle(model.LabelSet{"container_name": "foo"}, time.Unix(0, int64(i)), fmt.Sprintf("line %d", i)))
+	}
+}
+
+func readLines(t *testing.T, b *buffer) []string {
+	t.Helper()
+	streams, pos, err := b.Read(1 << 20)
+	require.NoError(t, err)
+	require.NoError(t, b.Commit(pos))
+	var lines []string
+	for _, s := range streams {
+		for _, e := range s.Entries {
+			lines = append(lines, e.Line)
+		}
+	}
+	return lines
+}
+
+func Test_buffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dir = filepath.Join(dir, "container")
+
+	b, err := newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	writeEntries(t, b, 0, 3)
+
+	streams, pos, err := b.Read(1 << 20)
+	require.NoError(t, err)
+	require.Len(t, streams, 3)
+	require.Equal(t, `{container_name="foo"}`, streams[0].Labels)
+	require.Equal(t, []logproto.Entry{{Timestamp: time.Unix(0, 0).UTC(), Line: "line 0"}}, streams[0].Entries)
+
+	// the entries are read again until they are committed.
+	require.Equal(t, []string{"line 0", "line 1", "line 2"}, readLines(t, b))
+	require.Equal(t, int64(0), b.Unread())
+	require.Empty(t, readLines(t, b))
+	require.NoError(t, b.Commit(pos))
+
+	writeEntries(t, b, 3, 4)
+	require.Equal(t, []string{"line 3"}, readLines(t, b))
+
+	// the buffer is removed when all its entries are pushed.
+	require.NoError(t, b.Close())
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func Test_bufferReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	writeEntries(t, b, 0, 4)
+	streams, pos, err := b.Read(1)
+	require.NoError(t, err)
+	require.Len(t, streams, 1)
+	require.NoError(t, b.Commit(pos))
+	require.NoError(t, b.Close())
+
+	// the entries not pushed are resumed, after a partial record written when the plugin stopped.
+	last := b.segmentPath(b.segments[len(b.segments)-1].index)
+	f, err := os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	b, err = newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	writeEntries(t, b, 4, 5)
+	require.Equal(t, []string{"line 1", "line 2", "line 3", "line 4"}, readLines(t, b))
+	require.NoError(t, b.Close())
+}
+
+func Test_bufferFull(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := newBuffer(dir, 1000, log.NewNopLogger())
+	require.NoError(t, err)
+	writeEntries(t, b, 0, 100)
+	require.True(t, b.size() <= 1000, "buffer size %d", b.size())
+	require.True(t, len(b.segments) <= segmentsPerBuffer+1)
+
+	// the oldest entries are dropped.
+	lines := readLines(t, b)
+	require.True(t, len(lines) < 100)
+	require.Equal(t, "line 99", lines[len(lines)-1])
+	require.Equal(t, fmt.Sprintf("line %d", 100-len(lines)), lines[0])
+	require.NoError(t, b.Close())
+}
+
+func Test_bufferedClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dir = filepath.Join(dir, "container")
+
+	var (
+		mtx      sync.Mutex
+		requests int
+		received = map[string][]logproto.Stream{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		requests++
+		// the first push, of the requests of both tenants, fails and is retried.
+		if requests <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		var req logproto.PushRequest
+		if _, err := cortex_util.ParseProtoReader(context.Background(), r.Body, int(r.ContentLength), 1<<20, &req, cortex_util.RawSnappy); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tenantID := r.Header.Get("X-Scope-OrgID")
+		received[tenantID] = append(received[tenantID], req.Streams...)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/loki/api/v1/push")
+	require.NoError(t, err)
+	cfg := client.Config{
+		URL:       flagext.URLValue{URL: u},
+		BatchWait: 10 * time.Millisecond,
+		BatchSize: 1 << 20,
+		Timeout:   time.Second,
+		TenantID:  "default",
+		BackoffConfig: cortex_util.BackoffConfig{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 10 * time.Millisecond,
+			MaxRetries: 3,
+		},
+	}
+	b, err := newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, b.Handle(model.LabelSet{"container_name": "foo"}, time.Unix(0, 1), "line 1"))
+	require.NoError(t, b.Handle(model.LabelSet{"container_name": "foo", client.ReservedLabelTenantID: "tenant"}, time.Unix(0, 2), "line 2"))
+	require.NoError(t, b.Handle(model.LabelSet{"container_name": "foo"}, time.Unix(0, 3), "line 3"))
+	c, err := newBufferedClient(b, cfg, log.NewNopLogger())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(received) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	c.Stop()
+
+	require.Equal(t, map[string][]logproto.Stream{
+		"default": {{
+			Labels: `{container_name="foo"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(0, 1).UTC(), Line: "line 1"},
+				{Timestamp: time.Unix(0, 3).UTC(), Line: "line 3"},
+			},
+		}},
+		"tenant": {{
+			Labels:  `{container_name="foo"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(0, 2).UTC(), Line: "line 2"}},
+		}},
+	}, received)
+
+	// the buffer is removed once all the entries are pushed.
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err))
+}
+
+func Test_bufferedClientKeepsEntriesNotPushed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Loki is unavailable.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/loki/api/v1/push")
+	require.NoError(t, err)
+	cfg := client.Config{
+		URL:       flagext.URLValue{URL: u},
+		BatchWait: time.Minute,
+		BatchSize: 1 << 20,
+		Timeout:   time.Second,
+		BackoffConfig: cortex_util.BackoffConfig{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 10 * time.Millisecond,
+			MaxRetries: 2,
+		},
+	}
+	b, err := newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	c, err := newBufferedClient(b, cfg, log.NewNopLogger())
+	require.NoError(t, err)
+	writeEntries(t, b, 0, 3)
+	c.Stop()
+
+	// the entries are pushed when the container restarts.
+	b, err = newBuffer(dir, 1<<20, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, []string{"line 0", "line 1", "line 2"}, readLines(t, b))
+	require.NoError(t, b.Close())
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	cortex_util "github.com/cortexproject/cortex/pkg/util"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/promtail/client"
+)
+
+// bufferedClient is a client.Client writing the entries to a buffer, from which they are pushed to Loki.
+// Each push is sent once, the entries being committed once Loki accepted them, so that those which could
+// not be pushed are pushed again after a backoff. The buffer drops its oldest entries when it is full.
+type bufferedClient struct {
+	buffer *buffer
+	cfg    client.Config
+	pusher client.Pusher
+	logger log.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newBufferedClient(buffer *buffer, cfg client.Config, logger log.Logger) (*bufferedClient, error) {
+	pusher, err := client.NewPusherWithTransport(cfg, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bc := &bufferedClient{
+		buffer: buffer,
+		cfg:    cfg,
+		pusher: pusher,
+		logger: log.With(logger, "component", "buffered_client"),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go bc.run()
+	return bc, nil
+}
+
+// Handle implements api.EntryHandler.
+func (c *bufferedClient) Handle(ls model.LabelSet, t time.Time, line string) error {
+	return c.buffer.Handle(ls, t, line)
+}
+
+// Stop tries once to push the entries left and closes the buffer. The entries which could not be pushed
+// are kept in the buffer, to be pushed when the container restarts.
+func (c *bufferedClient) Stop() {
+	c.cancel()
+	<-c.done
+	c.push(context.Background())
+	if err := c.buffer.Close(); err != nil {
+		level.Error(c.logger).Log("msg", "error closing buffer", "err", err)
+	}
+}
+
+func (c *bufferedClient) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.BatchWait)
+	defer ticker.Stop()
+
+	// retry until the entries are pushed.
+	backoffCfg := c.cfg.BackoffConfig
+	backoffCfg.MaxRetries = 0
+	backoff := cortex_util.NewBackoff(c.ctx, backoffCfg)
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.buffer.written:
+			if c.buffer.Unread() < int64(c.cfg.BatchSize) {
+				continue
+			}
+		}
+		if c.push(c.ctx) {
+			backoff.Reset()
+			continue
+		}
+		backoff.Wait()
+	}
+}
+
+// push pushes the entries of the buffer, batch by batch, until the buffer is empty or a batch
+// can't be pushed. It returns whether all the entries were pushed.
+func (c *bufferedClient) push(ctx context.Context) bool {
+	for {
+		streams, pos, err := c.buffer.Read(c.cfg.BatchSize)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "error reading buffer", "err", err)
+			return false
+		}
+		if len(streams) == 0 {
+			return true
+		}
+		if err := c.pusher.Push(ctx, streams); err != nil {
+			level.Warn(c.logger).Log("msg", "error pushing buffered entries, will retry", "err", err)
+			return false
+		}
+		if err := c.buffer.Commit(pos); err != nil {
+			level.Error(c.logger).Log("msg", "error committing buffer position", "err", err)
+			return false
+		}
+	}
+}
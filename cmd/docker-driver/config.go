@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/loki/pkg/promtail/client"
 	"github.com/grafana/loki/pkg/promtail/targets"
 	"github.com/grafana/loki/pkg/util"
+	lokiflag "github.com/grafana/loki/pkg/util/flagext"
 )
 
 const (
@@ -48,6 +49,7 @@ const (
 	cfgNofile                = "no-file"
 	cfgKeepFile              = "keep-file"
 	cfgRelabelKey            = "loki-relabel-config"
+	cfgBufferMaxSizeKey      = "loki-buffer-max-size"
 
 	swarmServiceLabelKey = "com.docker.swarm.service.name"
 	swarmStackLabelKey   = "com.docker.stack.namespace"
@@ -82,6 +84,8 @@ type config struct {
 	labels       model.LabelSet
 	clientConfig client.Config
 	pipeline     PipelineConfig
+	// maximum size of the on-disk buffer of the entries, 0 when they are not buffered.
+	bufferMaxSize int64
 }
 
 type PipelineConfig struct {
@@ -111,6 +115,7 @@ func validateDriverOpt(loggerInfo logger.Info) error {
 		case cfgPipelineStagesFileKey:
 		case cfgTenantIDKey:
 		case cfgRelabelKey:
+		case cfgBufferMaxSizeKey:
 		case cfgNofile:
 		case cfgKeepFile:
 		case "labels":
@@ -294,15 +299,24 @@ func parseConfig(logCtx logger.Info) (*config, error) {
 		labels = relabeled
 	}
 
+	// parse buffer size
+	var bufferMaxSize lokiflag.ByteSize
+	if raw, ok := logCtx.Config[cfgBufferMaxSizeKey]; ok && raw != "" {
+		if err := bufferMaxSize.Set(raw); err != nil {
+			return nil, fmt.Errorf("%s: invalid option %s format: %s", driverName, cfgBufferMaxSizeKey, raw)
+		}
+	}
+
 	// parse pipeline stages
 	pipeline, err := parsePipeline(logCtx)
 	if err != nil {
 		return nil, err
 	}
 	return &config{
-		labels:       labels,
-		clientConfig: clientConfig,
-		pipeline:     pipeline,
+		labels:        labels,
+		clientConfig:  clientConfig,
+		pipeline:      pipeline,
+		bufferMaxSize: int64(bufferMaxSize),
 	}, nil
 }
 
@@ -40,9 +40,13 @@ type logPair struct {
 	folder string
 	// keep created files after stopping the container.
 	keepFile bool
+	// closed when the logger is closed, to stop following its logs.
+	stopped  chan struct{}
+	stopOnce sync.Once
 }
 
 func (l *logPair) Close() {
+	l.stopOnce.Do(func() { close(l.stopped) })
 	if err := l.stream.Close(); err != nil {
 		level.Error(l.logger).Log("msg", "error while closing fifo stream", "err", err)
 	}
@@ -108,7 +112,16 @@ func (d *driver) StartLogging(file string, logCtx logger.Info) error {
 	}
 
 	d.mu.Lock()
-	lf := &logPair{jsonl, lokil, f, logCtx, d.logger, folder, keepFile}
+	lf := &logPair{
+		jsonl:    jsonl,
+		lokil:    lokil,
+		stream:   f,
+		info:     logCtx,
+		logger:   d.logger,
+		folder:   folder,
+		keepFile: keepFile,
+		stopped:  make(chan struct{}),
+	}
 	d.logs[file] = lf
 	d.idx[logCtx.ContainerID] = lf
 	d.mu.Unlock()
@@ -181,11 +194,12 @@ func (d *driver) ReadLogs(info logger.Info, config logger.ReadConfig) (io.ReadCl
 	lf, exists := d.idx[info.ContainerID]
 	d.mu.Unlock()
 	if !exists {
-		return nil, fmt.Errorf("logger does not exist for %s", info.ContainerID)
+		// the plugin restarted since the container logged, its logs are read from Loki.
+		return readLokiLogs(info, config, nil, d.logger)
 	}
 
 	if lf.jsonl == nil {
-		return nil, fmt.Errorf("%s option set to true, no reading capability", cfgNofile)
+		return readLokiLogs(info, config, lf.stopped, d.logger)
 	}
 
 	r, w := io.Pipe()
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"path/filepath"
 
 	"github.com/docker/docker/daemon/logger"
 	"github.com/go-kit/kit/log"
@@ -30,9 +31,21 @@ func New(logCtx logger.Info, logger log.Logger) (logger.Logger, error) {
 	if err != nil {
 		return nil, err
 	}
-	c, err := client.New(cfg.clientConfig, logger)
-	if err != nil {
-		return nil, err
+	var c client.Client
+	if cfg.bufferMaxSize > 0 {
+		buf, err := newBuffer(filepath.Join(bufferRoot, logCtx.ContainerID), cfg.bufferMaxSize, logger)
+		if err != nil {
+			return nil, err
+		}
+		c, err = newBufferedClient(buf, cfg.clientConfig, logger)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		c, err = client.New(cfg.clientConfig, logger)
+		if err != nil {
+			return nil, err
+		}
 	}
 	var handler api.EntryHandler = c
 	if len(cfg.pipeline.PipelineStages) != 0 {
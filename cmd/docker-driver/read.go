@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/plugins/logdriver"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	protoio "github.com/gogo/protobuf/io"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	logcli "github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/promtail/client"
+)
+
+const (
+	containerNameLabel = model.LabelName("container_name")
+	sourceLabel        = "source"
+
+	// number of entries of the queries reading all the logs of a container.
+	readBatchSize = 1000
+)
+
+// logQuerier queries the logs of the containers, implemented by the logcli client.
+type logQuerier interface {
+	QueryRange(queryStr string, limit int, from, through time.Time, direction logproto.Direction, step, interval time.Duration, quiet bool) (*loghttp.QueryResponse, error)
+	LiveTailQueryConn(queryStr string, delayFor int, limit int, from int64, cursor *loghttp.TailCursor, quiet bool) (*websocket.Conn, error)
+}
+
+// readLokiLogs reads the logs of a container from Loki, for the containers without a local log file.
+// The logs are followed until the reader is closed or stopped is closed, and not followed when stopped is nil.
+func readLokiLogs(info logger.Info, config logger.ReadConfig, stopped <-chan struct{}, logger log.Logger) (io.ReadCloser, error) {
+	cfg, err := parseConfig(info)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := containerSelector(cfg.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w := io.Pipe()
+	logs := &lokiLogs{PipeReader: r, done: make(chan struct{})}
+	lr := &lokiReader{
+		querier:  newQueryClient(cfg.clientConfig),
+		selector: selector,
+		info:     info,
+		config:   config,
+		stopped:  stopped,
+		done:     logs.done,
+		logger:   log.With(logger, "container_id", info.ContainerID),
+	}
+	go func() {
+		err := lr.read(w)
+		if err != nil {
+			level.Error(lr.logger).Log("msg", "error reading logs from loki", "err", err)
+		}
+		_ = w.CloseWithError(err)
+	}()
+	return logs, nil
+}
+
+// newQueryClient returns the client querying the Loki the logs are pushed to.
+func newQueryClient(cfg client.Config) *logcli.Client {
+	u := *cfg.URL.URL
+	c := &logcli.Client{
+		TLSConfig: cfg.Client.TLSConfig,
+		OrgID:     cfg.TenantID,
+	}
+	if u.User != nil {
+		c.Username = u.User.Username()
+		c.Password, _ = u.User.Password()
+	}
+	u.User = nil
+	u.RawQuery = ""
+	for _, pushPath := range []string{"/loki/api/v1/push", "/api/prom/push"} {
+		if strings.HasSuffix(u.Path, pushPath) {
+			u.Path = strings.TrimSuffix(u.Path, pushPath)
+			break
+		}
+	}
+	c.Address = (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+	return c
+}
+
+// containerSelector returns the selector of the streams of a container, by its name and host.
+func containerSelector(labels model.LabelSet) (string, error) {
+	name, ok := labels[containerNameLabel]
+	if !ok {
+		return "", fmt.Errorf("the %s label is required to read the logs from loki", containerNameLabel)
+	}
+	selector := model.LabelSet{containerNameLabel: name}
+	if host, ok := labels[defaultHostLabelName]; ok {
+		selector[defaultHostLabelName] = host
+	}
+	return selector.String(), nil
+}
+
+// lokiLogs is the reader of the logs read from Loki, which stops reading them when closed.
+type lokiLogs struct {
+	*io.PipeReader
+	once sync.Once
+	done chan struct{}
+}
+
+func (l *lokiLogs) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return l.PipeReader.Close()
+}
+
+type lokiReader struct {
+	querier  logQuerier
+	selector string
+	info     logger.Info
+	config   logger.ReadConfig
+	stopped  <-chan struct{}
+	done     chan struct{}
+	logger   log.Logger
+
+	enc  protoio.WriteCloser
+	last *entry
+}
+
+// entry is an entry of the logs of the container, with the source of its stream.
+type entry struct {
+	source string
+	loghttp.Entry
+}
+
+// entryKey identifies the entries written at the timestamp a query starts from.
+type entryKey struct {
+	source, line string
+	ts           int64
+}
+
+func (e entry) key() entryKey {
+	return entryKey{source: e.source, line: e.Line, ts: e.Timestamp.UnixNano()}
+}
+
+func (r *lokiReader) read(w io.Writer) error {
+	r.enc = protoio.NewUint32DelimitedWriter(w, binary.BigEndian)
+
+	start, end := r.config.Since, r.config.Until
+	if start.IsZero() {
+		start = r.info.ContainerCreated
+	}
+	follow := r.config.Follow && end.IsZero() && r.stopped != nil
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	var err error
+	switch {
+	case r.config.Tail == 0:
+	case r.config.Tail > 0:
+		err = r.readTail(start, end)
+	default:
+		err = r.readAll(start, end)
+	}
+	if err != nil || !follow {
+		return err
+	}
+	return r.follow(end)
+}
+
+// readTail writes the last entries of the range.
+func (r *lokiReader) readTail(start, end time.Time) error {
+	resp, err := r.querier.QueryRange(r.selector, r.config.Tail, start, end, logproto.BACKWARD, 0, 0, true)
+	if err != nil {
+		return err
+	}
+	entries, err := sortedEntries(resp)
+	if err != nil {
+		return err
+	}
+	if len(entries) > r.config.Tail {
+		entries = entries[len(entries)-r.config.Tail:]
+	}
+	return r.write(entries)
+}
+
+// readAll writes all the entries of the range, querying them by batches.
+func (r *lokiReader) readAll(start, end time.Time) error {
+	// the entries at the start of the query, already written by the previous query.
+	written := map[entryKey]struct{}{}
+	for {
+		resp, err := r.querier.QueryRange(r.selector, readBatchSize, start, end, logproto.FORWARD, 0, 0, true)
+		if err != nil {
+			return err
+		}
+		entries, err := sortedEntries(resp)
+		if err != nil {
+			return err
+		}
+
+		toWrite := make([]entry, 0, len(entries))
+		for _, e := range entries {
+			if _, ok := written[e.key()]; !ok {
+				toWrite = append(toWrite, e)
+			}
+		}
+		if err := r.write(toWrite); err != nil {
+			return err
+		}
+		if len(entries) < readBatchSize {
+			return nil
+		}
+
+		last := entries[len(entries)-1].Timestamp
+		if last.Equal(start) && len(toWrite) == 0 {
+			// more entries than the batch size at the same timestamp, the others are skipped.
+			start = start.Add(time.Nanosecond)
+			continue
+		}
+		if !last.Equal(start) {
+			written = map[entryKey]struct{}{}
+		}
+		for _, e := range entries {
+			if e.Timestamp.Equal(last) {
+				written[e.key()] = struct{}{}
+			}
+		}
+		start = last
+	}
+}
+
+// follow tails the entries after the last one written, or after the end of the range.
+func (r *lokiReader) follow(end time.Time) error {
+	cursor := &loghttp.TailCursor{Timestamp: end}
+	if r.last != nil {
+		c := loghttp.NewTailCursor(r.last.Entry)
+		cursor = &c
+	}
+	conn, err := r.querier.LiveTailQueryConn(r.selector, 0, readBatchSize, end.UnixNano(), cursor, true)
+	if err != nil {
+		return errors.Wrap(err, "error tailing logs")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-r.done:
+		case <-r.stopped:
+		case <-stop:
+		}
+		_ = conn.Close()
+	}()
+
+	for {
+		var resp loghttp.TailResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			select {
+			case <-r.done:
+				return nil
+			case <-r.stopped:
+				return nil
+			default:
+				return err
+			}
+		}
+		var entries []entry
+		for _, s := range resp.Streams {
+			for _, e := range s.Entries {
+				entries = append(entries, entry{source: s.Labels[sourceLabel], Entry: e})
+			}
+		}
+		if err := r.write(entries); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *lokiReader) write(entries []entry) error {
+	var buf logdriver.LogEntry
+	for i, e := range entries {
+		buf.Reset()
+		buf.Source = e.source
+		buf.TimeNano = e.Timestamp.UnixNano()
+		buf.Line = []byte(e.Line + "\n")
+		if err := r.enc.WriteMsg(&buf); err != nil {
+			return err
+		}
+		r.last = &entries[i]
+	}
+	return nil
+}
+
+// sortedEntries returns the entries of the streams of a query response, sorted by timestamp.
+func sortedEntries(resp *loghttp.QueryResponse) ([]entry, error) {
+	streams, ok := resp.Data.Result.(loghttp.Streams)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s", resp.Data.ResultType)
+	}
+	var entries []entry
+	for _, s := range streams {
+		for _, e := range s.Entries {
+			entries = append(entries, entry{source: s.Labels[sourceLabel], Entry: e})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries, nil
+}
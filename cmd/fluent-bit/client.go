@@ -1,7 +1,11 @@
 package main
 
 import (
+	"net/http"
+
+	"github.com/cortexproject/cortex/pkg/util/flagext"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 
 	"github.com/grafana/loki/pkg/promtail/client"
 )
@@ -11,5 +15,71 @@ func NewClient(cfg *config, logger log.Logger) (client.Client, error) {
 	if cfg.bufferConfig.buffer {
 		return NewBuffer(cfg, logger)
 	}
-	return client.New(cfg.clientConfig, logger)
+	return newLokiClient(cfg, logger)
+}
+
+// newLokiClient creates the client pushing the entries to Loki, failing over to the next URLs
+// when the client URL fails.
+func newLokiClient(cfg *config, logger log.Logger) (client.Client, error) {
+	if len(cfg.failoverURLs) == 0 {
+		return client.New(cfg.clientConfig, logger)
+	}
+	urls := append([]flagext.URLValue{cfg.clientConfig.URL}, cfg.failoverURLs...)
+	return client.NewWithTransport(cfg.clientConfig, logger, func(next http.RoundTripper) http.RoundTripper {
+		return &failoverTransport{next: next, urls: urls, logger: logger}
+	})
+}
+
+// failoverTransport sends the push requests to the first URL and, when it fails with a
+// connection error, a 429 or a 5xx, to the next URLs in order.
+type failoverTransport struct {
+	next   http.RoundTripper
+	urls   []flagext.URLValue
+	logger log.Logger
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i, u := range t.urls {
+		r := req
+		if i > 0 {
+			if req.GetBody == nil || req.Context().Err() != nil {
+				break
+			}
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+			level.Warn(t.logger).Log("msg", "error sending batch, failing over", "to", u.Host, "status", statusCode(resp), "error", err)
+
+			r = req.Clone(req.Context())
+			r.URL = u.URL
+			r.Host = u.Host
+			if r.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+			// the http client sets the credentials of the first URL before the transport.
+			if u.User != nil {
+				password, _ := u.User.Password()
+				r.SetBasicAuth(u.User.Username(), password)
+			} else if req.URL.User != nil {
+				r.Header.Del("Authorization")
+			}
+		}
+
+		resp, err = t.next.RoundTrip(r)
+		if err == nil && resp.StatusCode != 429 && resp.StatusCode/100 != 5 {
+			return resp, nil
+		}
+	}
+	return resp, err
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return -1
+	}
+	return resp.StatusCode
 }
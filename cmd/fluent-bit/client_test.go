@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/common/model"
+)
+
+func Test_failoverClient(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		requests []string
+	)
+	handler := func(name string, status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			user, _, _ := r.BasicAuth()
+			requests = append(requests, name+":"+user+":"+r.Header.Get("X-Scope-OrgID"))
+			w.WriteHeader(status)
+		}
+	}
+	primary := httptest.NewServer(handler("primary", http.StatusServiceUnavailable))
+	defer primary.Close()
+	unavailable := httptest.NewServer(handler("unavailable", http.StatusTooManyRequests))
+	defer unavailable.Close()
+	secondary := httptest.NewServer(handler("secondary", http.StatusNoContent))
+	defer secondary.Close()
+
+	cfg, err := parseConfig(fakeConfig{
+		"URL": strings.Join([]string{
+			strings.Replace(primary.URL, "http://", "http://foo:bar@", 1),
+			unavailable.URL,
+			strings.Replace(secondary.URL, "http://", "http://buzz:fuzz@", 1),
+		}, ","),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.clientConfig.BatchWait = time.Hour
+	cfg.clientConfig.BackoffConfig = util.BackoffConfig{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRetries: 1}
+
+	c, err := newLokiClient(cfg, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Handle(model.LabelSet{"job": "foo", "__tenant_id__": "tenant"}, time.Now(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	c.Stop()
+
+	// the credentials of each URL are used.
+	want := []string{"primary:foo:tenant", "unavailable::tenant", "secondary:buzz:tenant"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
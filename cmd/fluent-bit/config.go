@@ -51,6 +51,10 @@ type config struct {
 	lineFormat           format
 	dropSingleKey        bool
 	labelMap             map[string]interface{}
+	// the URLs the batches are pushed to when the client URL fails, in order.
+	failoverURLs         []flagext.URLValue
+	tenantIDKey          recordAccessor
+	dropKubernetesLabels bool
 }
 
 func parseConfig(cfg ConfigGetter) (*config, error) {
@@ -60,19 +64,34 @@ func parseConfig(cfg ConfigGetter) (*config, error) {
 	res.bufferConfig = defaultBufferConfig
 
 	url := cfg.Get("URL")
-	var clientURL flagext.URLValue
 	if url == "" {
 		url = "http://localhost:3100/loki/api/v1/push"
 	}
-	err := clientURL.Set(url)
-	if err != nil {
-		return nil, errors.New("failed to parse client URL")
+	// the next URLs are the failover URLs.
+	for i, u := range strings.Split(url, ",") {
+		var clientURL flagext.URLValue
+		if err := clientURL.Set(strings.TrimSpace(u)); err != nil {
+			return nil, errors.New("failed to parse client URL")
+		}
+		if i == 0 {
+			res.clientConfig.URL = clientURL
+			continue
+		}
+		res.failoverURLs = append(res.failoverURLs, clientURL)
 	}
-	res.clientConfig.URL = clientURL
 
 	// cfg.Get will return empty string if not set, which is handled by the client library as no tenant
 	res.clientConfig.TenantID = cfg.Get("TenantID")
 
+	tenantIDKey := cfg.Get("TenantIDKey")
+	if tenantIDKey != "" {
+		accessor, err := parseRecordAccessor(tenantIDKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TenantIDKey: %v", err)
+		}
+		res.tenantIDKey = accessor
+	}
+
 	batchWait := cfg.Get("BatchWait")
 	if batchWait != "" {
 		batchWaitValue, err := strconv.Atoi(batchWait)
@@ -128,11 +147,21 @@ func parseConfig(cfg ConfigGetter) (*config, error) {
 	removeKey := cfg.Get("RemoveKeys")
 	if removeKey != "" {
 		res.removeKeys = strings.Split(removeKey, ",")
+		for _, k := range res.removeKeys {
+			if _, err := parseRecordAccessor(k); err != nil {
+				return nil, fmt.Errorf("invalid RemoveKeys: %v", err)
+			}
+		}
 	}
 
 	labelKeys := cfg.Get("LabelKeys")
 	if labelKeys != "" {
 		res.labelKeys = strings.Split(labelKeys, ",")
+		for _, k := range res.labelKeys {
+			if _, err := parseLabelKey(k); err != nil {
+				return nil, fmt.Errorf("invalid LabelKeys: %v", err)
+			}
+		}
 	}
 
 	dropSingleKey := cfg.Get("DropSingleKey")
@@ -145,6 +174,16 @@ func parseConfig(cfg ConfigGetter) (*config, error) {
 		return nil, fmt.Errorf("invalid boolean DropSingleKey: %v", dropSingleKey)
 	}
 
+	dropKubernetesLabels := cfg.Get("DropKubernetesLabels")
+	switch dropKubernetesLabels {
+	case falseStr, "":
+		res.dropKubernetesLabels = false
+	case trueStr:
+		res.dropKubernetesLabels = true
+	default:
+		return nil, fmt.Errorf("invalid boolean DropKubernetesLabels: %v", dropKubernetesLabels)
+	}
+
 	lineFormat := cfg.Get("LineFormat")
 	switch lineFormat {
 	case "json", "":
@@ -70,7 +70,7 @@ func newDque(cfg *config, logger log.Logger) (client.Client, error) {
 		_ = q.queue.TurboOn()
 	}
 
-	q.loki, err = client.New(cfg.clientConfig, logger)
+	q.loki, err = newLokiClient(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
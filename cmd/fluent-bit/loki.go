@@ -52,7 +52,16 @@ func (l *loki) sendRecord(r map[interface{}]interface{}, ts time.Time) error {
 	} else {
 		lbs = extractLabels(records, l.cfg.labelKeys)
 	}
-	removeKeys(records, append(l.cfg.labelKeys, l.cfg.removeKeys...))
+	if l.cfg.tenantIDKey != nil {
+		if v, ok := l.cfg.tenantIDKey.get(records); ok {
+			lbs[client.ReservedLabelTenantID] = model.LabelValue(fmt.Sprintf("%v", v))
+		}
+	}
+	removeLabelKeys(records, l.cfg.labelKeys)
+	removeKeys(records, l.cfg.removeKeys)
+	if l.cfg.dropKubernetesLabels {
+		dropKubernetesLabels(records, l.cfg)
+	}
 	if len(records) == 0 {
 		return nil
 	}
@@ -136,11 +145,15 @@ func autoLabels(records map[string]interface{}, kuberneteslbs model.LabelSet) er
 func extractLabels(records map[string]interface{}, keys []string) model.LabelSet {
 	res := model.LabelSet{}
 	for _, k := range keys {
-		v, ok := records[k]
+		key, err := parseLabelKey(k)
+		if err != nil {
+			continue
+		}
+		v, ok := key.accessor.get(records)
 		if !ok {
 			continue
 		}
-		ln := model.LabelName(k)
+		ln := key.name
 		// skips invalid name and values
 		if !ln.IsValid() {
 			continue
@@ -193,7 +206,62 @@ func getRecordValue(key string, records map[string]interface{}) (string, bool) {
 
 func removeKeys(records map[string]interface{}, keys []string) {
 	for _, k := range keys {
-		delete(records, k)
+		accessor, err := parseRecordAccessor(k)
+		if err != nil {
+			continue
+		}
+		accessor.remove(records)
+	}
+}
+
+// removeLabelKeys removes the keys extracted as labels from the records.
+func removeLabelKeys(records map[string]interface{}, keys []string) {
+	for _, k := range keys {
+		key, err := parseLabelKey(k)
+		if err != nil {
+			continue
+		}
+		key.accessor.remove(records)
+	}
+}
+
+// dropKubernetesLabels removes the Kubernetes metadata extracted as labels from the records,
+// either by AutoKubernetesLabels or by the label map.
+func dropKubernetesLabels(records map[string]interface{}, cfg *config) {
+	kube, ok := records["kubernetes"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if cfg.autoKubernetesLabels {
+		for k := range kube {
+			switch k {
+			case "docker_id", "pod_id", "annotations":
+			default:
+				delete(kube, k)
+			}
+		}
+	} else if mapping, ok := cfg.labelMap["kubernetes"].(map[string]interface{}); ok {
+		removeMappedKeys(kube, mapping)
+	}
+	if len(kube) == 0 {
+		delete(records, "kubernetes")
+	}
+}
+
+// removeMappedKeys removes the records converted into labels by the mapping, and the maps it leaves empty.
+func removeMappedKeys(records map[string]interface{}, mapping map[string]interface{}) {
+	for k, v := range mapping {
+		switch nextKey := v.(type) {
+		case map[string]interface{}:
+			if nextValue, ok := records[k].(map[string]interface{}); ok {
+				removeMappedKeys(nextValue, nextKey)
+				if len(nextValue) == 0 {
+					delete(records, k)
+				}
+			}
+		case string:
+			delete(records, k)
+		}
 	}
 }
 
@@ -72,6 +72,18 @@ func Test_loki_sendRecord(t *testing.T) {
 		},
 	}
 
+	var kubernetesRecordFixture = map[interface{}]interface{}{
+		"log": "hello",
+		"kubernetes": map[interface{}]interface{}{
+			"namespace_name": "prod",
+			"pod_name":       "foo-xxx",
+			"pod_id":         "1234",
+			"labels": map[interface{}]interface{}{
+				"app": "foo",
+			},
+		},
+	}
+
 	tests := []struct {
 		name    string
 		cfg     *config
@@ -89,6 +101,9 @@ func Test_loki_sendRecord(t *testing.T) {
 		{"single", &config{labelKeys: []string{"fake"}, dropSingleKey: true, lineFormat: kvPairFormat, removeKeys: []string{"foo", "error", "fake"}}, simpleRecordFixture, &entry{model.LabelSet{}, `500`, now}, false},
 		{"labelmap", &config{labelMap: map[string]interface{}{"bar": "other"}, lineFormat: jsonFormat, removeKeys: []string{"bar", "error"}}, simpleRecordFixture, &entry{model.LabelSet{"other": "500"}, `{"foo":"bar"}`, now}, false},
 		{"byte array", &config{labelKeys: []string{"label"}, lineFormat: jsonFormat}, byteArrayRecordFixture, &entry{model.LabelSet{"label": "label"}, `{"map":{"inner":"bar"},"outer":"foo"}`, now}, false},
+		{"tenant key", &config{labelKeys: []string{"$kubernetes['labels']['app']", "pod=$kubernetes['pod_name']"}, tenantIDKey: recordAccessor{"kubernetes", "namespace_name"}, lineFormat: kvPairFormat, removeKeys: []string{"$kubernetes['pod_id']"}}, kubernetesRecordFixture, &entry{model.LabelSet{"app": "foo", "pod": "foo-xxx", "__tenant_id__": "prod"}, `kubernetes=map[namespace_name:prod] log=hello`, now}, false},
+		{"drop auto kubernetes labels", &config{autoKubernetesLabels: true, dropKubernetesLabels: true, lineFormat: kvPairFormat}, kubernetesRecordFixture, &entry{model.LabelSet{"app": "foo", "pod_name": "foo-xxx", "namespace_name": "prod"}, `kubernetes=map[pod_id:1234] log=hello`, now}, false},
+		{"drop mapped kubernetes labels", &config{labelMap: map[string]interface{}{"kubernetes": map[string]interface{}{"pod_name": "pod", "labels": map[string]interface{}{"app": "app"}}}, dropKubernetesLabels: true, lineFormat: kvPairFormat}, kubernetesRecordFixture, &entry{model.LabelSet{"app": "foo", "pod": "foo-xxx"}, `kubernetes="map[namespace_name:prod pod_id:1234]" log=hello`, now}, false},
 		{"mixed types", &config{labelKeys: []string{"label"}, lineFormat: jsonFormat}, mixedTypesRecordFixture, &entry{model.LabelSet{"label": "label"}, `{"array":[42,42.42,"foo"],"float":42.42,"int":42,"map":{"nested":{"foo":"bar","invalid":"a\ufffdz"}}}`, now}, false},
 	}
 	for _, tt := range tests {
@@ -182,6 +197,8 @@ func Test_removeKeys(t *testing.T) {
 		{"remove none", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"foo": "bar"}, []string{}},
 		{"remove not existing", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"foo": "bar"}, []string{"bar"}},
 		{"remove one", map[string]interface{}{"foo": "bar", "bazz": "buzz"}, map[string]interface{}{"foo": "bar"}, []string{"bazz"}},
+		{"remove nested", map[string]interface{}{"foo": "bar", "bar": map[string]interface{}{"bizz": "bazz", "buzz": "fuzz"}}, map[string]interface{}{"foo": "bar", "bar": map[string]interface{}{"buzz": "fuzz"}}, []string{"$bar['bizz']"}},
+		{"remove emptied map", map[string]interface{}{"foo": "bar", "bar": map[string]interface{}{"bizz": "bazz"}}, map[string]interface{}{"foo": "bar"}, []string{"$bar['bizz']", "$foo['bizz']"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -206,6 +223,7 @@ func Test_extractLabels(t *testing.T) {
 		{"none", map[string]interface{}{"foo": nil}, []string{}, model.LabelSet{}},
 		{"missing", map[string]interface{}{"foo": "bar"}, []string{"foo", "buzz"}, model.LabelSet{"foo": "bar"}},
 		{"skip invalid", map[string]interface{}{"foo.blah": "bar", "bar": "a\xc5z"}, []string{"foo.blah", "bar"}, model.LabelSet{}},
+		{"nested", map[string]interface{}{"foo": "bar", "bar": map[string]interface{}{"bizz": "bazz", "app.kubernetes.io/name": "foo"}}, []string{"$bar['bizz']", "name=$bar[\"bizz\"]", "$bar['app.kubernetes.io/name']", "$bar['fuzz']", "$foo['bizz']"}, model.LabelSet{"bizz": "bazz", "name": "bazz", "app_kubernetes_io_name": "foo"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
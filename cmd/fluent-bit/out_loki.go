@@ -57,7 +57,9 @@ func FLBPluginInit(ctx unsafe.Pointer) int {
 	level.Info(logger).Log("[flb-go]", "Starting fluent-bit-go-loki", "version", version.Info())
 	paramLogger := log.With(logger, "[flb-go]", "provided parameter")
 	level.Info(paramLogger).Log("URL", conf.clientConfig.URL)
+	level.Info(paramLogger).Log("FailoverURLs", fmt.Sprintf("%+v", conf.failoverURLs))
 	level.Info(paramLogger).Log("TenantID", conf.clientConfig.TenantID)
+	level.Info(paramLogger).Log("TenantIDKey", fmt.Sprintf("%+v", conf.tenantIDKey))
 	level.Info(paramLogger).Log("BatchWait", conf.clientConfig.BatchWait)
 	level.Info(paramLogger).Log("BatchSize", conf.clientConfig.BatchSize)
 	level.Info(paramLogger).Log("Labels", conf.clientConfig.ExternalLabels)
@@ -67,6 +69,7 @@ func FLBPluginInit(ctx unsafe.Pointer) int {
 	level.Info(paramLogger).Log("LabelKeys", fmt.Sprintf("%+v", conf.labelKeys))
 	level.Info(paramLogger).Log("LineFormat", conf.lineFormat)
 	level.Info(paramLogger).Log("DropSingleKey", conf.dropSingleKey)
+	level.Info(paramLogger).Log("DropKubernetesLabels", conf.dropKubernetesLabels)
 	level.Info(paramLogger).Log("LabelMapPath", fmt.Sprintf("%+v", conf.labelMap))
 	level.Info(paramLogger).Log("Buffer", conf.bufferConfig.buffer)
 	level.Info(paramLogger).Log("BufferType", conf.bufferConfig.bufferType)
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+// recordAccessor is the path of a value in a nested record, written with the Fluent Bit
+// record accessor syntax, e.g. $kubernetes['labels']['app']. A key without a leading $
+// is the path of a top level key.
+type recordAccessor []string
+
+func parseRecordAccessor(s string) (recordAccessor, error) {
+	if !strings.HasPrefix(s, "$") {
+		if s == "" {
+			return nil, fmt.Errorf("empty record key")
+		}
+		return recordAccessor{s}, nil
+	}
+
+	rest := s[1:]
+	i := strings.IndexByte(rest, '[')
+	if i < 0 {
+		i = len(rest)
+	}
+	if i == 0 {
+		return nil, fmt.Errorf("invalid record accessor %s: missing key", s)
+	}
+	path := recordAccessor{rest[:i]}
+	rest = rest[i:]
+	for rest != "" {
+		if len(rest) < 4 || rest[0] != '[' || (rest[1] != '\'' && rest[1] != '"') {
+			return nil, fmt.Errorf("invalid record accessor %s: expected ['key'] at %s", s, rest)
+		}
+		end := strings.Index(rest[2:], string(rest[1])+"]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid record accessor %s: unterminated key at %s", s, rest)
+		}
+		path = append(path, rest[2:2+end])
+		rest = rest[2+end+2:]
+	}
+	return path, nil
+}
+
+// get returns the value at the path of the accessor.
+func (a recordAccessor) get(records map[string]interface{}) (interface{}, bool) {
+	current := records
+	for i, k := range a {
+		v, ok := current[k]
+		if !ok {
+			return nil, false
+		}
+		if i == len(a)-1 {
+			return v, true
+		}
+		if current, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// remove removes the value at the path of the accessor, and the maps it leaves empty.
+func (a recordAccessor) remove(records map[string]interface{}) {
+	if len(a) == 0 {
+		return
+	}
+	if len(a) == 1 {
+		delete(records, a[0])
+		return
+	}
+	next, ok := records[a[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	a[1:].remove(next)
+	if len(next) == 0 {
+		delete(records, a[0])
+	}
+}
+
+// labelKey is a key of the record used as a stream label.
+type labelKey struct {
+	name     model.LabelName
+	accessor recordAccessor
+}
+
+var labelNameReplacer = strings.NewReplacer("/", "_", ".", "_", "-", "_")
+
+// parseLabelKey parses an entry of LabelKeys: a top level key, a record accessor, or a label
+// name and a record accessor separated by =. The label of a record accessor is by default
+// named after its last key.
+func parseLabelKey(s string) (labelKey, error) {
+	var name string
+	if i := strings.Index(s, "=$"); i > 0 {
+		name, s = s[:i], s[i+1:]
+	}
+	accessor, err := parseRecordAccessor(s)
+	if err != nil {
+		return labelKey{}, err
+	}
+	switch {
+	case name != "":
+		if !model.LabelName(name).IsValid() {
+			return labelKey{}, fmt.Errorf("invalid label name %s", name)
+		}
+	case strings.HasPrefix(s, "$"):
+		name = labelNameReplacer.Replace(accessor[len(accessor)-1])
+	default:
+		name = s
+	}
+	return labelKey{name: model.LabelName(name), accessor: accessor}, nil
+}
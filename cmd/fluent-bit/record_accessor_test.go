@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseRecordAccessor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    recordAccessor
+		wantErr bool
+	}{
+		{"foo", recordAccessor{"foo"}, false},
+		{"foo.bar", recordAccessor{"foo.bar"}, false},
+		{"$foo", recordAccessor{"foo"}, false},
+		{"$kubernetes['labels']['app']", recordAccessor{"kubernetes", "labels", "app"}, false},
+		{`$kubernetes["labels"]['app.kubernetes.io/name']`, recordAccessor{"kubernetes", "labels", "app.kubernetes.io/name"}, false},
+		{"", nil, true},
+		{"$", nil, true},
+		{"$['foo']", nil, true},
+		{"$foo[bar]", nil, true},
+		{"$foo['bar'", nil, true},
+		{"$foo['bar']x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseRecordAccessor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRecordAccessor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRecordAccessor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_recordAccessor_get(t *testing.T) {
+	records := map[string]interface{}{
+		"log": "hello",
+		"kubernetes": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "foo"},
+		},
+	}
+	tests := []struct {
+		accessor recordAccessor
+		want     interface{}
+		wantOk   bool
+	}{
+		{recordAccessor{"log"}, "hello", true},
+		{recordAccessor{"kubernetes", "labels", "app"}, "foo", true},
+		{recordAccessor{"kubernetes", "labels"}, map[string]interface{}{"app": "foo"}, true},
+		{recordAccessor{"kubernetes", "labels", "tier"}, nil, false},
+		{recordAccessor{"log", "app"}, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.accessor.get(records)
+		if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("get(%v) = %v, %v want %v, %v", tt.accessor, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func Test_parseLabelKey(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    labelKey
+		wantErr bool
+	}{
+		{"foo", labelKey{"foo", recordAccessor{"foo"}}, false},
+		{"$kubernetes['labels']['app.kubernetes.io/name']", labelKey{"app_kubernetes_io_name", recordAccessor{"kubernetes", "labels", "app.kubernetes.io/name"}}, false},
+		{"app=$kubernetes['labels']['app']", labelKey{"app", recordAccessor{"kubernetes", "labels", "app"}}, false},
+		{"a=b", labelKey{"a=b", recordAccessor{"a=b"}}, false},
+		{"1app=$kubernetes['labels']['app']", labelKey{}, true},
+		{"app=$kubernetes['labels", labelKey{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseLabelKey(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseLabelKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLabelKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"time"
 
@@ -13,17 +14,23 @@ import (
 
 	_ "github.com/grafana/loki/pkg/build"
 	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/logcli/export"
 	"github.com/grafana/loki/pkg/logcli/labelquery"
 	"github.com/grafana/loki/pkg/logcli/output"
 	"github.com/grafana/loki/pkg/logcli/query"
 	"github.com/grafana/loki/pkg/logcli/seriesquery"
+	"github.com/grafana/loki/pkg/logcli/shell"
+	"github.com/grafana/loki/pkg/logcli/statsquery"
+	"github.com/grafana/loki/pkg/logcli/volumequery"
+	"github.com/grafana/loki/pkg/loghttp"
 )
 
 var (
 	app        = kingpin.New("logcli", "A command-line for loki.").Version(version.Print("logcli"))
 	quiet      = app.Flag("quiet", "Suppress query metadata").Default("false").Short('q').Bool()
 	statistics = app.Flag("stats", "Show query statistics").Default("false").Bool()
-	outputMode = app.Flag("output", "Specify output mode [default, raw, jsonl]. raw suppresses log labels and timestamp.").Default("default").Short('o').Enum("default", "raw", "jsonl")
+	outputMode = app.Flag("output", "Specify output mode [default, raw, jsonl, table, csv, promtext]. raw suppresses log labels and timestamp. table, csv and promtext also format the results of metric queries, which are printed as JSON otherwise.").Default("default").Short('o').Enum("default", "raw", "jsonl", "table", "csv", "promtext")
+	columns    = app.Flag("columns", "Labels printed in their own column by the csv output mode, instead of all the labels in a single column.").Strings()
 	timezone   = app.Flag("timezone", "Specify the timezone to use when formatting output timestamps [Local, UTC]").Default("Local").Short('z').Enum("Local", "UTC")
 
 	cpuProfile = app.Flag("cpuprofile", "Specify the location for writing a CPU profile.").Default("").String()
@@ -39,6 +46,9 @@ returned in a few output modes:
 	raw: log line
 	default: log timestamp + log labels + log line
 	jsonl: JSON response from Loki API of log line
+	table: log timestamp + log labels + log line, aligned in columns
+	csv: log timestamp + log labels + log line, as CSV records
+	promtext: log line
 
 The output of the log can be specified with the "-o" flag, for
 example, "-o raw" for the raw output format.
@@ -79,6 +89,53 @@ https://github.com/grafana/loki/blob/master/docs/logql.md`)
 
 	seriesCmd   = app.Command("series", "Run series query.")
 	seriesQuery = newSeriesQuery(seriesCmd)
+
+	exportCmd = app.Command("export", `Export the entries of a log query to a file.
+
+The "export" command pages through the time range of the query by windows
+of --window, and in batches within each window, so the export is not bound
+by the entries and query length limits of the server.
+
+With the default "ndjson" format, each line of the output is a JSON push
+request as accepted by /loki/api/v1/push, and the output is gzip compressed
+when the file name ends with ".gz". With the "chunks" format, the entries
+are written into chunks encoded as Loki stores them, under the output
+directory in a directory per tenant.
+
+Exported files and directories can be pushed back to any Loki with the
+"import" command.`)
+	exportQuery = newExport(exportCmd)
+
+	importCmd   = app.Command("import", "Push files and directories written by the export command to Loki, using the tenant set by --org-id.")
+	importQuery = newImport(importCmd)
+
+	shellCmd = app.Command("shell", `Start an interactive shell running LogQL queries.
+
+The "shell" command reads LogQL queries and runs them over the time range
+of the session, which is changed along with the tenant, the limit or the
+output mode with commands such as ":range 15m" or ":org-id tenant".
+Type ":help" in the shell for the list of commands.
+
+Tab completes the label names and values of stream selectors, looked up
+in the time range of the session, and the lines are kept in a history file
+recalled with the up and down keys.`)
+	shellSession = newShell(shellCmd)
+
+	statsCmd = app.Command("stats", `Show how much data a log query would read.
+
+The "stats" command reports the number of streams, chunks, lines and bytes
+of the streams matching the selector of the query, looked up in the index
+without fetching the chunks. The filters of the query are not applied to
+these numbers; with --exec the query is also run, counting the matching
+lines along with the statistics of its execution.`)
+	statsQuery = newStatsQuery(statsCmd)
+
+	volumeCmd = app.Command("volume", `Show the volume of the streams matching a selector over time.
+
+The "volume" command prints the number of lines or bytes of the streams
+matching the selector at each step, grouped by the labels given with --by,
+e.g. "--by namespace" to find which namespaces are the most verbose.`)
+	volumeQuery = newVolumeQuery(volumeCmd)
 )
 
 func main() {
@@ -121,7 +178,10 @@ func main() {
 		outputOptions := &output.LogOutputOptions{
 			Timezone: location,
 			NoLabels: rangeQuery.NoLabels,
+			Columns:  *columns,
 		}
+		// the label columns are kept in the labels of the entries, even when common to all of them.
+		rangeQuery.ShowLabelsKey = append(rangeQuery.ShowLabelsKey, *columns...)
 
 		out, err := output.NewLogOutput(*outputMode, outputOptions)
 		if err != nil {
@@ -142,7 +202,10 @@ func main() {
 		outputOptions := &output.LogOutputOptions{
 			Timezone: location,
 			NoLabels: instantQuery.NoLabels,
+			Columns:  *columns,
 		}
+		// the label columns are kept in the labels of the entries, even when common to all of them.
+		instantQuery.ShowLabelsKey = append(instantQuery.ShowLabelsKey, *columns...)
 
 		out, err := output.NewLogOutput(*outputMode, outputOptions)
 		if err != nil {
@@ -154,6 +217,33 @@ func main() {
 		labelsQuery.DoLabels(queryClient)
 	case seriesCmd.FullCommand():
 		seriesQuery.DoSeries(queryClient)
+	case exportCmd.FullCommand():
+		exportQuery.DoExport(queryClient)
+	case importCmd.FullCommand():
+		importQuery.DoImport(queryClient)
+	case shellCmd.FullCommand():
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			log.Fatalf("Unable to load timezone '%s': %s", *timezone, err)
+		}
+
+		shellSession.Client = queryClient
+		shellSession.OutputMode = *outputMode
+		shellSession.Columns = *columns
+		shellSession.Timezone = location
+		shellSession.Statistics = *statistics
+		if err := shellSession.Run(); err != nil {
+			log.Fatalf("Shell failed: %+v", err)
+		}
+	case statsCmd.FullCommand():
+		statsQuery.DoStats(queryClient)
+	case volumeCmd.FullCommand():
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			log.Fatalf("Unable to load timezone '%s': %s", *timezone, err)
+		}
+
+		volumeQuery.DoVolume(queryClient, location)
 	}
 }
 
@@ -238,6 +328,143 @@ func newSeriesQuery(cmd *kingpin.CmdClause) *seriesquery.SeriesQuery {
 	return q
 }
 
+func newExport(cmd *kingpin.CmdClause) *export.Export {
+	// calculate export range from cli params
+	var from, to string
+	var since time.Duration
+
+	e := &export.Export{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(c *kingpin.ParseContext) error {
+
+		defaultEnd := time.Now()
+		defaultStart := defaultEnd.Add(-since)
+
+		e.Start = mustParse(from, defaultStart)
+		e.End = mustParse(to, defaultEnd)
+		e.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("query", "eg '{foo=\"bar\",baz=~\".*blip\"} |~ \".*error.*\"'").Required().StringVar(&e.QueryString)
+	cmd.Flag("since", "Lookback window.").Default("1h").DurationVar(&since)
+	cmd.Flag("from", "Start looking for logs at this absolute time (inclusive)").StringVar(&from)
+	cmd.Flag("to", "Stop looking for logs at this absolute time (exclusive)").StringVar(&to)
+	cmd.Flag("batch", "Number of entries requested per query, must not exceed the max_entries_limit_per_query limit of the server.").Default("5000").IntVar(&e.BatchSize)
+	cmd.Flag("window", "Length of the time ranges queried, must not exceed the max_query_length limit of the server. 0 to query the whole range at once.").Default("1h").DurationVar(&e.Window)
+	cmd.Flag("format", "Format of the export, ndjson or chunks.").Default(export.FormatNDJSON).EnumVar(&e.Format, export.FormatNDJSON, export.FormatChunks)
+	cmd.Flag("output-file", "File to write the export to, gzip compressed if the name ends with .gz, or directory of the chunks with the chunks format. Defaults to stdout.").Default("-").StringVar(&e.OutputFile)
+
+	return e
+}
+
+func newImport(cmd *kingpin.CmdClause) *export.Import {
+	i := &export.Import{}
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		i.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("files", "Files or directories written by the export command, pushed in the given order.").Required().ExistingFilesOrDirsVar(&i.Files)
+	cmd.Flag("batch-size", "Maximum size in bytes of the log lines sent per push request.").Default("1048576").IntVar(&i.BatchSize)
+	cmd.Flag("rate-limit", "Maximum number of log line bytes pushed per second, 0 to disable.").Default("0").Float64Var(&i.RateLimit)
+	cmd.Flag("max-retries", "Maximum number of retries of a push request rejected with a 429 or 5xx status code.").Default("10").IntVar(&i.MaxRetries)
+	cmd.Flag("min-backoff", "Initial backoff time between retries.").Default("500ms").DurationVar(&i.MinBackoff)
+	cmd.Flag("max-backoff", "Maximum backoff time between retries.").Default("5m").DurationVar(&i.MaxBackoff)
+
+	return i
+}
+
+func newShell(cmd *kingpin.CmdClause) *shell.Shell {
+	var from, to string
+
+	s := &shell.Shell{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		if from != "" {
+			s.From = mustParse(from, time.Time{})
+			s.To = mustParse(to, time.Now())
+		}
+		s.Query.Quiet = *quiet
+		return nil
+	})
+
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, ".logcli_history")
+	}
+
+	cmd.Flag("since", "Initial lookback window of the queries.").Default("1h").DurationVar(&s.Since)
+	cmd.Flag("from", "Initial absolute start time of the queries (inclusive)").StringVar(&from)
+	cmd.Flag("to", "Initial absolute end time of the queries (exclusive), when --from is set. Defaults to now.").StringVar(&to)
+	cmd.Flag("limit", "Initial limit on number of entries to print.").Default("30").IntVar(&s.Query.Limit)
+	cmd.Flag("forward", "Scan forwards through logs.").Default("false").BoolVar(&s.Query.Forward)
+	cmd.Flag("no-labels", "Do not print any labels").Default("false").BoolVar(&s.Query.NoLabels)
+	cmd.Flag("delay-for", "Delay in tailing by number of seconds to accumulate logs for re-ordering").Default("0").IntVar(&s.DelayFor)
+	cmd.Flag("history-file", "File keeping the history of the shell, disabled when empty.").Default(historyFile).StringVar(&s.HistoryFile)
+
+	return s
+}
+
+func newStatsQuery(cmd *kingpin.CmdClause) *statsquery.StatsQuery {
+	var from, to string
+	var since time.Duration
+
+	q := &statsquery.StatsQuery{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(c *kingpin.ParseContext) error {
+
+		defaultEnd := time.Now()
+		defaultStart := defaultEnd.Add(-since)
+
+		q.Start = mustParse(from, defaultStart)
+		q.End = mustParse(to, defaultEnd)
+		q.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("query", "eg '{foo=\"bar\",baz=~\".*blip\"} |~ \".*error.*\"'").Required().StringVar(&q.QueryString)
+	cmd.Flag("since", "Lookback window.").Default("1h").DurationVar(&since)
+	cmd.Flag("from", "Start looking for logs at this absolute time (inclusive)").StringVar(&from)
+	cmd.Flag("to", "Stop looking for logs at this absolute time (exclusive)").StringVar(&to)
+	cmd.Flag("exec", "Also run the query, counting the lines matching its filters. This reads the chunks of the streams.").Default("false").BoolVar(&q.Exec)
+
+	return q
+}
+
+func newVolumeQuery(cmd *kingpin.CmdClause) *volumequery.VolumeQuery {
+	var from, to string
+	var since time.Duration
+
+	q := &volumequery.VolumeQuery{}
+
+	// executed after all command flags are parsed
+	cmd.Action(func(c *kingpin.ParseContext) error {
+
+		defaultEnd := time.Now()
+		defaultStart := defaultEnd.Add(-since)
+
+		q.Start = mustParse(from, defaultStart)
+		q.End = mustParse(to, defaultEnd)
+		q.Quiet = *quiet
+		return nil
+	})
+
+	cmd.Arg("selector", "eg '{foo=\"bar\",baz=~\".*blip\"}'").Required().StringVar(&q.QueryString)
+	cmd.Flag("since", "Lookback window.").Default("1h").DurationVar(&since)
+	cmd.Flag("from", "Start looking for logs at this absolute time (inclusive)").StringVar(&from)
+	cmd.Flag("to", "Stop looking for logs at this absolute time (exclusive)").StringVar(&to)
+	cmd.Flag("step", "Width of the rows, a multiple of a minute. Defaults to a step depending on the time range.").DurationVar(&q.Step)
+	cmd.Flag("by", "Label the volume is grouped by, can be repeated. The volume of all the streams is summed when not set.").StringsVar(&q.By)
+	cmd.Flag("type", "What is counted [lines, bytes].").Default(loghttp.VolumeTypeBytes).EnumVar(&q.Type, loghttp.VolumeTypeLines, loghttp.VolumeTypeBytes)
+
+	return q
+}
+
 func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 	// calculate query range from cli params
 	var now, from, to string
@@ -272,6 +499,7 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 		cmd.Flag("to", "Stop looking for logs at this absolute time (exclusive)").StringVar(&to)
 		cmd.Flag("step", "Query resolution step width, for metric queries. Evaluate the query at the specified step over the time range.").DurationVar(&q.Step)
 		cmd.Flag("interval", "Query interval, for log queries. Return entries at the specified interval, ignoring those between. **This parameter is experimental, please see Issue 1779**").DurationVar(&q.Interval)
+		cmd.Flag("stream", "Stream the entries of log queries as they are read by the server. Streamed queries are bound by the server side max_streamed_entries_per_query limit instead of max_entries_limit_per_query, and print entries in the order they are read. The query-frontend rejects streamed queries, which must be sent to the queriers.").Default("false").BoolVar(&q.Stream)
 	}
 
 	cmd.Flag("forward", "Scan forwards through logs.").Default("false").BoolVar(&q.Forward)
@@ -280,6 +508,7 @@ func newQuery(instant bool, cmd *kingpin.CmdClause) *query.Query {
 	cmd.Flag("include-label", "Include labels given the provided key during output.").StringsVar(&q.ShowLabelsKey)
 	cmd.Flag("labels-length", "Set a fixed padding to labels").Default("0").IntVar(&q.FixedLabelsLen)
 	cmd.Flag("store-config", "Execute the current query using a configured storage from a given Loki configuration file.").Default("").StringVar(&q.LocalConfig)
+	cmd.Flag("from-dir", "Execute the current query against the chunk files of a directory and its sub-directories, indexed from their headers instead of a store index. Only the chunks of the tenant set by --org-id are queried, if set.").Default("").StringVar(&q.FromDir)
 
 	return q
 }
@@ -3,7 +3,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
@@ -11,18 +13,31 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/go-kit/kit/log"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/version"
 
 	_ "github.com/grafana/loki/pkg/build"
 	"github.com/grafana/loki/pkg/canary/comparator"
+	canaryconfig "github.com/grafana/loki/pkg/canary/config"
 	"github.com/grafana/loki/pkg/canary/reader"
 	"github.com/grafana/loki/pkg/canary/writer"
+	"github.com/grafana/loki/pkg/promtail/client"
 )
 
 type canary struct {
 	lock sync.Mutex
 
+	streams []*streamCanary
+}
+
+// streamCanary writes and verifies one of the streams of the canary.
+type streamCanary struct {
+	file       *os.File
+	push       *writer.Push
 	writer     *writer.Writer
 	reader     *reader.Reader
 	comparator *comparator.Comparator
@@ -34,6 +49,8 @@ func main() {
 	lVal := flag.String("labelvalue", "loki-canary", "The unique label value for this instance of loki-canary to use in the log selector")
 	sName := flag.String("streamname", "stream", "The stream name for this instance of loki-canary to use in the log selector")
 	sValue := flag.String("streamvalue", "stdout", "The unique stream value for this instance of loki-canary to use in the log selector")
+	tenantID := flag.String("tenantid", "", "The tenant ID the stream is queried for, sent in the X-Scope-OrgID header")
+	streamsConfig := flag.String("streamsconfig", "", "YAML file of the streams to write and verify, instead of the stream defined by the label, stream and tenant flags")
 	port := flag.Int("port", 3500, "Port which loki-canary should expose metrics")
 	addr := flag.String("addr", "", "The Loki server URL:Port, e.g. loki:3100")
 	tls := flag.Bool("tls", false, "Does the loki connection use TLS?")
@@ -44,8 +61,21 @@ func main() {
 	size := flag.Int("size", 100, "Size in bytes of each log line")
 	wait := flag.Duration("wait", 60*time.Second, "Duration to wait for log entries before reporting them lost")
 	pruneInterval := flag.Duration("pruneinterval", 60*time.Second, "Frequency to check sent vs received logs, also the frequency which queries for missing logs will be dispatched to loki")
+	spotCheckInterval := flag.Duration("spotcheckinterval", 15*time.Minute, "Interval between the entries kept to be checked with a query until -spotcheckmax, 0 to disable spot checks")
+	spotCheckMax := flag.Duration("spotcheckmax", 4*time.Hour, "How long the spot check entries are checked after they are sent")
+	spotCheckQueryRate := flag.Duration("spotcheckqueryrate", 1*time.Minute, "Frequency of the queries of the spot check entries")
+	metricTestInterval := flag.Duration("metrictestinterval", 1*time.Hour, "Frequency of the count_over_time queries comparing the number of entries sent and stored, 0 to disable metric tests")
+	metricTestRange := flag.Duration("metrictestrange", 24*time.Hour, "Range of the count_over_time queries of the metric tests")
 	buckets := flag.Int("buckets", 10, "Number of buckets in the response_latency histogram")
 
+	push := flag.Bool("push", false, "Push the log entries directly to Loki, instead of writing them to stdout or the files of the streams for an agent to ship them")
+	pushBatchWait := flag.Duration("pushbatchwait", 1*time.Second, "Maximum wait period before pushing a batch of entries")
+	pushBatchSize := flag.Int("pushbatchsize", 100*1024, "Maximum size in bytes of a batch of entries pushed")
+	pushTimeout := flag.Duration("pushtimeout", 10*time.Second, "Maximum time to wait for Loki to respond to a push request")
+	pushMinBackoff := flag.Duration("pushminbackoff", 500*time.Millisecond, "Initial backoff time between the retries of a failed push")
+	pushMaxBackoff := flag.Duration("pushmaxbackoff", 5*time.Minute, "Maximum backoff time between the retries of a failed push")
+	pushMaxRetries := flag.Int("pushmaxretries", 10, "Maximum number of retries of a failed push before dropping its entries")
+
 	printVersion := flag.Bool("version", false, "Print this builds version information")
 
 	flag.Parse()
@@ -60,8 +90,48 @@ func main() {
 		os.Exit(1)
 	}
 
-	sentChan := make(chan time.Time)
-	receivedChan := make(chan time.Time)
+	streams := []canaryconfig.Stream{{
+		Name:     *sValue,
+		Tenant:   *tenantID,
+		Labels:   map[string]string{*lName: *lVal, *sName: *sValue},
+		Interval: *interval,
+		Size:     *size,
+	}}
+	if *streamsConfig != "" {
+		cfg, err := canaryconfig.Load(*streamsConfig, *interval, *size)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Unable to load the streams: %s\n", err)
+			os.Exit(1)
+		}
+		streams = cfg.Streams
+	}
+
+	var pushCfg client.Config
+	if *push {
+		scheme := "http"
+		if *tls {
+			scheme = "https"
+		}
+		u, err := url.Parse(fmt.Sprintf("%s://%s/loki/api/v1/push", scheme, *addr))
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid Loki address: %s\n", err)
+			os.Exit(1)
+		}
+		pushCfg = client.Config{
+			URL:       flagext.URLValue{URL: u},
+			BatchWait: *pushBatchWait,
+			BatchSize: *pushBatchSize,
+			Timeout:   *pushTimeout,
+			BackoffConfig: util.BackoffConfig{
+				MinBackoff: *pushMinBackoff,
+				MaxBackoff: *pushMaxBackoff,
+				MaxRetries: *pushMaxRetries,
+			},
+		}
+		if *user != "" {
+			pushCfg.Client.BasicAuth = &config.BasicAuth{Username: *user, Password: config.Secret(*pass)}
+		}
+	}
 
 	c := &canary{}
 	startCanary := func() {
@@ -70,9 +140,42 @@ func main() {
 		c.lock.Lock()
 		defer c.lock.Unlock()
 
-		c.writer = writer.NewWriter(os.Stdout, sentChan, *interval, *size)
-		c.reader = reader.NewReader(os.Stderr, receivedChan, *tls, *addr, *user, *pass, *lName, *lVal, *sName, *sValue)
-		c.comparator = comparator.NewComparator(os.Stderr, *wait, *pruneInterval, *buckets, sentChan, receivedChan, c.reader, true)
+		for _, s := range streams {
+			// the messages of the streams are told apart by their name when there are several of them.
+			var logs io.Writer = os.Stderr
+			if len(streams) > 1 {
+				logs = &prefixWriter{w: os.Stderr, prefix: fmt.Sprintf("[%s] ", s.Name)}
+			}
+
+			sc := &streamCanary{}
+			out := io.Writer(os.Stdout)
+			if *push {
+				p, err := writer.NewPush(pushCfg, s.Tenant, s.Name, s.Labels, log.NewLogfmtLogger(log.NewSyncWriter(logs)))
+				if err != nil {
+					_, _ = fmt.Fprintf(logs, "unable to create the push client of the stream: %s\n", err)
+					continue
+				}
+				sc.push = p
+				out = p
+			} else if s.File != "" {
+				f, err := os.OpenFile(s.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+				if err != nil {
+					_, _ = fmt.Fprintf(logs, "unable to open the file of the stream: %s\n", err)
+					continue
+				}
+				sc.file = f
+				out = f
+			}
+
+			sentChan := make(chan time.Time)
+			receivedChan := make(chan time.Time)
+			sc.writer = writer.NewWriter(out, sentChan, s.Interval, s.Size)
+			sc.reader = reader.NewReader(logs, receivedChan, *tls, *addr, *user, *pass, s.Tenant, s.Name, s.Selector())
+			sc.comparator = comparator.NewComparator(logs, s.Tenant, s.Name, *wait, *pruneInterval,
+				*spotCheckInterval, *spotCheckMax, *spotCheckQueryRate, *metricTestInterval, *metricTestRange,
+				*buckets, sentChan, receivedChan, sc.reader, true)
+			c.streams = append(c.streams, sc)
+		}
 	}
 
 	startCanary()
@@ -107,15 +210,29 @@ func (c *canary) stop() {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	if c.writer == nil || c.reader == nil || c.comparator == nil {
-		return
+	for _, sc := range c.streams {
+		sc.writer.Stop()
+		if sc.push != nil {
+			sc.push.Stop()
+		}
+		sc.reader.Stop()
+		sc.comparator.Stop()
+		if sc.file != nil {
+			_ = sc.file.Close()
+		}
 	}
+	c.streams = nil
+}
 
-	c.writer.Stop()
-	c.reader.Stop()
-	c.comparator.Stop()
+// prefixWriter prefixes the messages written to w, each of them being written at once.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+}
 
-	c.writer = nil
-	c.reader = nil
-	c.comparator = nil
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(p.w, p.prefix); err != nil {
+		return 0, err
+	}
+	return p.w.Write(b)
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/grafana/loki/pkg/cfg"
+	"github.com/grafana/loki/pkg/loki"
+	"github.com/grafana/loki/pkg/migrate"
+	"github.com/grafana/loki/pkg/storage"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+// migrate copies the chunks of a tenant between two stores configured by Loki configuration files,
+// e.g. to move from a BigTable index to boltdb-shipper, or from a bucket to another.
+func main() {
+	sourceConfig := flag.String("source.config.file", "", "Loki configuration file of the store to read the chunks from.")
+	destConfig := flag.String("dest.config.file", "", "Loki configuration file of the store to write the chunks to.")
+	tenant := flag.String("tenant", "", "Tenant whose chunks are migrated.")
+	selector := flag.String("selector", "", "Stream selector of the streams to migrate, e.g. {app=\"foo\"}. Defaults to all the streams.")
+	from := flag.String("from", "", "Start of the time range to migrate, in RFC3339 format.")
+	to := flag.String("to", "", "End of the time range to migrate, in RFC3339 format. Defaults to now.")
+	shardBy := flag.Duration("shard-by", 24*time.Hour, "Duration of the shards the time range is split in, each shard is migrated and checkpointed at once.")
+	parallelism := flag.Int("parallelism", 4, "Number of shards migrated concurrently.")
+	batch := flag.Int("batch", 50, "Number of chunks fetched and written at once.")
+	encoding := flag.String("encoding", "", "Re-encode the chunks with this encoding, e.g. snappy or zstd. Defaults to keeping the chunks as is.")
+	checkpoint := flag.String("checkpoint", "", "File recording the migrated shards, which are skipped when the migration is run again.")
+	dryRun := flag.Bool("dry-run", false, "Read the chunks to migrate without writing them.")
+	flag.Parse()
+
+	if *sourceConfig == "" || *destConfig == "" {
+		exit(fmt.Errorf("-source.config.file and -dest.config.file are required"))
+	}
+
+	start, err := time.Parse(time.RFC3339, *from)
+	if err != nil {
+		exit(fmt.Errorf("invalid -from: %w", err))
+	}
+	end := time.Now()
+	if *to != "" {
+		end, err = time.Parse(time.RFC3339, *to)
+		if err != nil {
+			exit(fmt.Errorf("invalid -to: %w", err))
+		}
+	}
+
+	migrateCfg := migrate.Config{
+		Tenant:         *tenant,
+		Selector:       *selector,
+		From:           start,
+		Through:        end,
+		ShardBy:        *shardBy,
+		Parallelism:    *parallelism,
+		BatchSize:      *batch,
+		Encoding:       *encoding,
+		CheckpointFile: *checkpoint,
+		DryRun:         *dryRun,
+	}
+	if err := run(migrateCfg, *sourceConfig, *destConfig); err != nil {
+		exit(err)
+	}
+}
+
+// run migrates the chunks between the stores, which are stopped before returning so that the
+// boltdb-shipper index files of the destination are uploaded.
+func run(migrateCfg migrate.Config, sourceConfig, destConfig string) error {
+	src, _, err := newStore(sourceConfig)
+	if err != nil {
+		return fmt.Errorf("creating the source store: %w", err)
+	}
+	defer src.Stop()
+
+	dst, destConf, err := newStore(destConfig)
+	if err != nil {
+		return fmt.Errorf("creating the destination store: %w", err)
+	}
+	defer dst.Stop()
+
+	migrateCfg.BlockSize = destConf.Ingester.BlockSize
+	migrateCfg.TargetChunkSize = destConf.Ingester.TargetChunkSize
+	migrateCfg.ZstdLevel = destConf.Ingester.ChunkZstdLevel
+	m, err := migrate.NewMigrator(migrateCfg, src, dst, util.Logger)
+	if err != nil {
+		return err
+	}
+
+	stats, err := m.Run(context.Background())
+	level.Info(util.Logger).Log(
+		"msg", "migration finished",
+		"shards", stats.Shards,
+		"shards_skipped", stats.ShardsSkipped,
+		"chunks_read", stats.ChunksRead,
+		"chunks_written", stats.ChunksWritten,
+		"bytes_written", stats.BytesWritten,
+		"dry_run", migrateCfg.DryRun,
+	)
+	return err
+}
+
+// newStore creates the store configured by a Loki configuration file.
+func newStore(file string) (storage.Store, loki.Config, error) {
+	var conf loki.Config
+	flagext.DefaultValues(&conf)
+	if err := cfg.YAML(&file)(&conf); err != nil {
+		return nil, conf, err
+	}
+	if err := conf.Validate(util.Logger); err != nil {
+		return nil, conf, err
+	}
+
+	limits, err := validation.NewOverrides(conf.LimitsConfig, nil)
+	if err != nil {
+		return nil, conf, err
+	}
+	// the boltdb-shipper index client is registered for the configuration of each store, so that
+	// both stores can use it.
+	storage.RegisterCustomIndexClients(conf.StorageConfig, nil)
+	s, err := storage.NewStore(conf.StorageConfig, conf.ChunkStoreConfig, conf.SchemaConfig, limits, nil)
+	return s, conf, err
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
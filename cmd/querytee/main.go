@@ -11,14 +11,13 @@ import (
 
 	"github.com/cortexproject/cortex/pkg/util"
 	"github.com/cortexproject/cortex/tools/querytee"
-
-	"github.com/grafana/loki/pkg/loghttp"
 )
 
 type Config struct {
 	ServerMetricsPort int
 	LogLevel          logging.Level
 	ProxyConfig       querytee.ProxyConfig
+	ComparatorConfig  ComparatorConfig
 }
 
 func main() {
@@ -27,12 +26,18 @@ func main() {
 	flag.IntVar(&cfg.ServerMetricsPort, "server.metrics-port", 9900, "The port where metrics are exposed.")
 	cfg.LogLevel.RegisterFlags(flag.CommandLine)
 	cfg.ProxyConfig.RegisterFlags(flag.CommandLine)
+	cfg.ComparatorConfig.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
 	util.InitLogger(&server.Config{
 		LogLevel: cfg.LogLevel,
 	})
 
+	if err := cfg.ComparatorConfig.Validate(); err != nil {
+		level.Error(util.Logger).Log("msg", "Invalid comparator config", "err", err.Error())
+		os.Exit(1)
+	}
+
 	// Run the instrumentation server.
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(prometheus.NewGoCollector())
@@ -44,7 +49,7 @@ func main() {
 	}
 
 	// Run the proxy.
-	proxy, err := querytee.NewProxy(cfg.ProxyConfig, util.Logger, lokiReadRoutes(), registry)
+	proxy, err := querytee.NewProxy(cfg.ProxyConfig, util.Logger, lokiReadRoutes(cfg.ComparatorConfig, registry), registry)
 	if err != nil {
 		level.Error(util.Logger).Log("msg", "Unable to initialize the proxy", "err", err.Error())
 		os.Exit(1)
@@ -58,18 +63,17 @@ func main() {
 	proxy.Await()
 }
 
-func lokiReadRoutes() []querytee.Route {
-	samplesComparator := querytee.NewSamplesComparator()
-	samplesComparator.RegisterSamplesType(loghttp.ResultTypeStream, compareStreams)
+func lokiReadRoutes(cfg ComparatorConfig, registerer prometheus.Registerer) []querytee.Route {
+	metrics := newComparatorMetrics(registerer)
 
 	return []querytee.Route{
-		{Path: "/loki/api/v1/query_range", RouteName: "api_v1_query_range", Methods: []string{"GET"}, ResponseComparator: samplesComparator},
-		{Path: "/loki/api/v1/query", RouteName: "api_v1_query", Methods: []string{"GET"}, ResponseComparator: samplesComparator},
+		{Path: "/loki/api/v1/query_range", RouteName: "api_v1_query_range", Methods: []string{"GET"}, ResponseComparator: NewResponseComparator("api_v1_query_range", cfg, metrics)},
+		{Path: "/loki/api/v1/query", RouteName: "api_v1_query", Methods: []string{"GET"}, ResponseComparator: NewResponseComparator("api_v1_query", cfg, metrics)},
 		{Path: "/loki/api/v1/label", RouteName: "api_v1_label", Methods: []string{"GET"}, ResponseComparator: nil},
 		{Path: "/loki/api/v1/labels", RouteName: "api_v1_labels", Methods: []string{"GET"}, ResponseComparator: nil},
 		{Path: "/loki/api/v1/label/{name}/values", RouteName: "api_v1_label_name_values", Methods: []string{"GET"}, ResponseComparator: nil},
 		{Path: "/loki/api/v1/series", RouteName: "api_v1_series", Methods: []string{"GET"}, ResponseComparator: nil},
-		{Path: "/api/prom/query", RouteName: "api_prom_query", Methods: []string{"GET"}, ResponseComparator: samplesComparator},
+		{Path: "/api/prom/query", RouteName: "api_prom_query", Methods: []string{"GET"}, ResponseComparator: NewResponseComparator("api_prom_query", cfg, metrics)},
 		{Path: "/api/prom/label", RouteName: "api_prom_label", Methods: []string{"GET"}, ResponseComparator: nil},
 		{Path: "/api/prom/label/{name}/values", RouteName: "api_prom_label_name_values", Methods: []string{"GET"}, ResponseComparator: nil},
 		{Path: "/api/prom/series", RouteName: "api_prom_series", Methods: []string{"GET"}, ResponseComparator: nil},
@@ -2,14 +2,256 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/cortexproject/cortex/pkg/util"
 	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logql/stats"
 )
 
+// ComparatorConfig configures the comparison of the responses of the backends.
+type ComparatorConfig struct {
+	ValueTolerance float64
+	SampleRatio    float64
+	ReportsDir     string
+}
+
+// RegisterFlags registers flags.
+func (cfg *ComparatorConfig) RegisterFlags(f *flag.FlagSet) {
+	f.Float64Var(&cfg.ValueTolerance, "proxy.value-comparison-tolerance", 0.000001, "The tolerance to apply when comparing the values of matrix, vector and scalar results, relative to the largest of the two values compared.")
+	f.Float64Var(&cfg.SampleRatio, "proxy.compare-sample-ratio", 1, "The ratio of the responses compared, between 0 and 1. The responses which are not sampled are counted as successful comparisons.")
+	f.StringVar(&cfg.ReportsDir, "proxy.diff-reports-dir", "", "The directory where a report of each failed comparison is written, with the responses of both backends. No report is written when empty.")
+}
+
+// Validate validates the config and creates the reports directory.
+func (cfg *ComparatorConfig) Validate() error {
+	if cfg.ValueTolerance < 0 {
+		return errors.New("the value comparison tolerance must be positive")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return errors.New("the compare sample ratio must be between 0 and 1")
+	}
+	if cfg.ReportsDir != "" {
+		if err := os.MkdirAll(cfg.ReportsDir, 0777); err != nil {
+			return errors.Wrap(err, "creating the diff reports directory")
+		}
+	}
+	return nil
+}
+
+type comparatorMetrics struct {
+	responsesNotSampled *prometheus.CounterVec
+	statsDelta          *prometheus.HistogramVec
+}
+
+func newComparatorMetrics(registerer prometheus.Registerer) *comparatorMetrics {
+	return &comparatorMetrics{
+		responsesNotSampled: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki_querytee",
+			Name:      "responses_not_sampled_total",
+			Help:      "Total number of responses not compared per route name, because they were not sampled.",
+		}, []string{"route"}),
+		statsDelta: promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "loki_querytee",
+			Name:      "stats_relative_delta",
+			Help:      "Difference of the query statistics of the secondary and preferred backends, relative to the largest of the two.",
+			Buckets:   []float64{-1, -0.5, -0.25, -0.1, -0.01, 0, 0.01, 0.1, 0.25, 0.5, 1},
+		}, []string{"route", "stat"}),
+	}
+}
+
+// ResponseComparator compares the responses of the query endpoints of two Loki backends.
+type ResponseComparator struct {
+	routeName string
+	cfg       ComparatorConfig
+	metrics   *comparatorMetrics
+
+	// sample tells whether a response is compared.
+	sample func() bool
+}
+
+// NewResponseComparator makes a ResponseComparator for the responses of a route.
+func NewResponseComparator(routeName string, cfg ComparatorConfig, metrics *comparatorMetrics) *ResponseComparator {
+	return &ResponseComparator{
+		routeName: routeName,
+		cfg:       cfg,
+		metrics:   metrics,
+		sample: func() bool {
+			return cfg.SampleRatio >= 1 || rand.Float64() < cfg.SampleRatio
+		},
+	}
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+		Statistics *stats.Result   `json:"stats"`
+	} `json:"data"`
+}
+
+// Compare implements querytee.ResponsesComparator.
+func (c *ResponseComparator) Compare(expectedResponse, actualResponse []byte) error {
+	if !c.sample() {
+		c.metrics.responsesNotSampled.WithLabelValues(c.routeName).Inc()
+		return nil
+	}
+
+	var expected, actual queryResponse
+	if err := json.Unmarshal(expectedResponse, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualResponse, &actual); err != nil {
+		return err
+	}
+
+	var deltas []statDelta
+	if expected.Data.Statistics != nil && actual.Data.Statistics != nil {
+		deltas = statsDeltas(*expected.Data.Statistics, *actual.Data.Statistics)
+		c.recordStatsDeltas(deltas)
+	}
+
+	err := c.compare(expected, actual)
+	if err != nil && c.cfg.ReportsDir != "" {
+		path, reportErr := c.writeReport(err, deltas, expectedResponse, actualResponse)
+		if reportErr != nil {
+			level.Warn(util.Logger).Log("msg", "unable to write the diff report", "route-name", c.routeName, "err", reportErr)
+			return err
+		}
+		return fmt.Errorf("%v (diff report %s)", err, path)
+	}
+	return err
+}
+
+func (c *ResponseComparator) compare(expected, actual queryResponse) error {
+	if expected.Status != actual.Status {
+		return fmt.Errorf("expected status %s but got %s", expected.Status, actual.Status)
+	}
+	if expected.Data.ResultType != actual.Data.ResultType {
+		return fmt.Errorf("expected resultType %s but got %s", expected.Data.ResultType, actual.Data.ResultType)
+	}
+
+	switch loghttp.ResultType(expected.Data.ResultType) {
+	case loghttp.ResultTypeStream:
+		return compareStreams(expected.Data.Result, actual.Data.Result)
+	case loghttp.ResultTypeMatrix:
+		return compareMatrix(expected.Data.Result, actual.Data.Result, c.cfg.ValueTolerance)
+	case loghttp.ResultTypeVector:
+		return compareVector(expected.Data.Result, actual.Data.Result, c.cfg.ValueTolerance)
+	case loghttp.ResultTypeScalar:
+		return compareScalar(expected.Data.Result, actual.Data.Result, c.cfg.ValueTolerance)
+	default:
+		return fmt.Errorf("resultType %s not registered for comparison", expected.Data.ResultType)
+	}
+}
+
+func (c *ResponseComparator) recordStatsDeltas(deltas []statDelta) {
+	keyvals := []interface{}{"msg", "stats deltas", "route-name", c.routeName}
+	for _, d := range deltas {
+		c.metrics.statsDelta.WithLabelValues(c.routeName, d.Stat).Observe(d.relative())
+		if d.Expected != d.Actual {
+			keyvals = append(keyvals, d.Stat, d.Actual-d.Expected)
+		}
+	}
+	level.Debug(util.Logger).Log(keyvals...)
+}
+
+type diffReport struct {
+	Route       string          `json:"route"`
+	Time        time.Time       `json:"time"`
+	Error       string          `json:"error"`
+	StatsDeltas []statDelta     `json:"statsDeltas,omitempty"`
+	Expected    json.RawMessage `json:"expected"`
+	Actual      json.RawMessage `json:"actual"`
+}
+
+// writeReport writes the report of a failed comparison and returns its path.
+func (c *ResponseComparator) writeReport(compareErr error, deltas []statDelta, expected, actual []byte) (string, error) {
+	now := time.Now()
+	var changed []statDelta
+	for _, d := range deltas {
+		if d.Expected != d.Actual {
+			changed = append(changed, d)
+		}
+	}
+	report, err := json.MarshalIndent(diffReport{
+		Route:       c.routeName,
+		Time:        now,
+		Error:       compareErr.Error(),
+		StatsDeltas: changed,
+		Expected:    expected,
+		Actual:      actual,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(c.cfg.ReportsDir, fmt.Sprintf("%s-%d.json", c.routeName, now.UnixNano()))
+	return path, ioutil.WriteFile(path, report, 0666)
+}
+
+// statDelta is the value of a query statistic for both backends.
+type statDelta struct {
+	Stat     string  `json:"stat"`
+	Expected float64 `json:"expected"`
+	Actual   float64 `json:"actual"`
+}
+
+// relative returns the difference of the values relative to the largest of them,
+// which is between -1 and 1 for the positive statistics.
+func (d statDelta) relative() float64 {
+	max := math.Max(math.Abs(d.Expected), math.Abs(d.Actual))
+	if max == 0 {
+		return 0
+	}
+	return (d.Actual - d.Expected) / max
+}
+
+// statsDeltas returns the values of each statistic of both results, named by their path in
+// the JSON of the results e.g. summary.totalBytesProcessed, and sorted by name.
+func statsDeltas(expected, actual stats.Result) []statDelta {
+	expectedValues, actualValues := flattenStats(expected), flattenStats(actual)
+	deltas := make([]statDelta, 0, len(expectedValues))
+	for name, value := range expectedValues {
+		deltas = append(deltas, statDelta{Stat: name, Expected: value, Actual: actualValues[name]})
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Stat < deltas[j].Stat })
+	return deltas
+}
+
+func flattenStats(r stats.Result) map[string]float64 {
+	values := map[string]float64{}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return values
+	}
+	var sections map[string]map[string]float64
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return values
+	}
+	for section, fields := range sections {
+		for name, value := range fields {
+			values[section+"."+name] = value
+		}
+	}
+	return values
+}
+
 func compareStreams(expectedRaw, actualRaw json.RawMessage) error {
 	var expected, actual loghttp.Streams
 
@@ -58,12 +300,174 @@ func compareStreams(expectedRaw, actualRaw json.RawMessage) error {
 				return fmt.Errorf("expected timestamp %v but got %v for stream %s", expectedSamplePair.Timestamp.UnixNano(),
 					actualSamplePair.Timestamp.UnixNano(), expectedStream.Labels)
 			}
-			if expectedSamplePair.Line != actualSamplePair.Line {
-				return fmt.Errorf("expected line %s for timestamp %v but got %s for stream %s", expectedSamplePair.Line,
-					expectedSamplePair.Timestamp.UnixNano(), actualSamplePair.Line, expectedStream.Labels)
+		}
+
+		// The order of the entries with the same timestamp is not defined, so their lines are compared sorted.
+		for start := 0; start < expectedValuesLen; {
+			end := start + 1
+			for end < expectedValuesLen && expectedStream.Entries[end].Timestamp.Equal(expectedStream.Entries[start].Timestamp) {
+				end++
+			}
+			expectedLines := sortedLines(expectedStream.Entries[start:end])
+			actualLines := sortedLines(actualStream.Entries[start:end])
+			for i := range expectedLines {
+				if expectedLines[i] != actualLines[i] {
+					return fmt.Errorf("expected line %s for timestamp %v but got %s for stream %s", expectedLines[i],
+						expectedStream.Entries[start].Timestamp.UnixNano(), actualLines[i], expectedStream.Labels)
+				}
+			}
+			start = end
+		}
+	}
+
+	return nil
+}
+
+func sortedLines(entries []loghttp.Entry) []string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, e.Line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func compareMatrix(expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Matrix
+
+	err := json.Unmarshal(expectedRaw, &expected)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(actualRaw, &actual)
+	if err != nil {
+		return err
+	}
+
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d metrics but got %d", len(expected), len(actual))
+	}
+
+	metricFingerprintToIndexMap := make(map[model.Fingerprint]int, len(expected))
+	for i, actualMetric := range actual {
+		metricFingerprintToIndexMap[actualMetric.Metric.Fingerprint()] = i
+	}
+
+	for _, expectedMetric := range expected {
+		actualMetricIndex, ok := metricFingerprintToIndexMap[expectedMetric.Metric.Fingerprint()]
+		if !ok {
+			return fmt.Errorf("expected metric %s missing from actual response", expectedMetric.Metric)
+		}
+
+		actualMetric := actual[actualMetricIndex]
+		expectedMetricLen := len(expectedMetric.Values)
+		actualMetricLen := len(actualMetric.Values)
+
+		if expectedMetricLen != actualMetricLen {
+			err := fmt.Errorf("expected %d samples for metric %s but got %d", expectedMetricLen,
+				expectedMetric.Metric, actualMetricLen)
+			if expectedMetricLen > 0 && actualMetricLen > 0 {
+				level.Error(util.Logger).Log("msg", err.Error(), "oldest-expected-ts", expectedMetric.Values[0].Timestamp,
+					"newest-expected-ts", expectedMetric.Values[expectedMetricLen-1].Timestamp,
+					"oldest-actual-ts", actualMetric.Values[0].Timestamp, "newest-actual-ts", actualMetric.Values[actualMetricLen-1].Timestamp)
+			}
+			return err
+		}
+
+		for i, expectedSamplePair := range expectedMetric.Values {
+			err := compareSamplePair(expectedSamplePair, actualMetric.Values[i], tolerance)
+			if err != nil {
+				return errors.Wrapf(err, "sample pair not matching for metric %s", expectedMetric.Metric)
 			}
 		}
 	}
 
 	return nil
 }
+
+func compareVector(expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Vector
+
+	err := json.Unmarshal(expectedRaw, &expected)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(actualRaw, &actual)
+	if err != nil {
+		return err
+	}
+
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d metrics but got %d", len(expected), len(actual))
+	}
+
+	metricFingerprintToIndexMap := make(map[model.Fingerprint]int, len(expected))
+	for i, actualMetric := range actual {
+		metricFingerprintToIndexMap[actualMetric.Metric.Fingerprint()] = i
+	}
+
+	for _, expectedMetric := range expected {
+		actualMetricIndex, ok := metricFingerprintToIndexMap[expectedMetric.Metric.Fingerprint()]
+		if !ok {
+			return fmt.Errorf("expected metric %s missing from actual response", expectedMetric.Metric)
+		}
+
+		actualMetric := actual[actualMetricIndex]
+		err := compareSamplePair(model.SamplePair{
+			Timestamp: expectedMetric.Timestamp,
+			Value:     expectedMetric.Value,
+		}, model.SamplePair{
+			Timestamp: actualMetric.Timestamp,
+			Value:     actualMetric.Value,
+		}, tolerance)
+		if err != nil {
+			return errors.Wrapf(err, "sample pair not matching for metric %s", expectedMetric.Metric)
+		}
+	}
+
+	return nil
+}
+
+func compareScalar(expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Scalar
+
+	err := json.Unmarshal(expectedRaw, &expected)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(actualRaw, &actual)
+	if err != nil {
+		return err
+	}
+
+	return compareSamplePair(model.SamplePair{
+		Timestamp: expected.Timestamp,
+		Value:     expected.Value,
+	}, model.SamplePair{
+		Timestamp: actual.Timestamp,
+		Value:     actual.Value,
+	}, tolerance)
+}
+
+func compareSamplePair(expected, actual model.SamplePair, tolerance float64) error {
+	if expected.Timestamp != actual.Timestamp {
+		return fmt.Errorf("expected timestamp %v but got %v", expected.Timestamp, actual.Timestamp)
+	}
+	if !compareSampleValue(expected.Value, actual.Value, tolerance) {
+		return fmt.Errorf("expected value %s for timestamp %v but got %s", expected.Value, expected.Timestamp, actual.Value)
+	}
+
+	return nil
+}
+
+// compareSampleValue tells whether the values are equal, within the tolerance relative to the largest of them.
+func compareSampleValue(expected, actual model.SampleValue, tolerance float64) bool {
+	e, a := float64(expected), float64(actual)
+	if math.IsNaN(e) || math.IsNaN(a) {
+		return math.IsNaN(e) && math.IsNaN(a)
+	}
+	if math.IsInf(e, 0) || math.IsInf(a, 0) {
+		return e == a
+	}
+	return math.Abs(e-a) <= tolerance*math.Max(math.Abs(e), math.Abs(a))
+}
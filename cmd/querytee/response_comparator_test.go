@@ -3,9 +3,17 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logql/stats"
 )
 
 func TestCompareStreams(t *testing.T) {
@@ -88,6 +96,25 @@ func TestCompareStreams(t *testing.T) {
 							{"stream":{"foo":"bar"},"values":[["1","1"],["2","2"]]}
 						]`),
 		},
+		{
+			name: "different order of the entries with the same timestamp",
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","1"],["2","a"],["2","b"],["3","3"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["1","1"],["2","b"],["2","a"],["3","3"]]}
+						]`),
+		},
+		{
+			name: "difference in the entries with the same timestamp",
+			expected: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["2","a"],["2","b"]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"stream":{"foo":"bar"},"values":[["2","c"],["2","a"]]}
+						]`),
+			err: errors.New("expected line b for timestamp 2 but got c for stream {foo=\"bar\"}"),
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := compareStreams(tc.expected, tc.actual)
@@ -100,3 +127,139 @@ func TestCompareStreams(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareMatrix(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		expected  json.RawMessage
+		actual    json.RawMessage
+		tolerance float64
+		err       error
+	}{
+		{
+			name:     "no metrics",
+			expected: json.RawMessage(`[]`),
+			actual:   json.RawMessage(`[]`),
+		},
+		{
+			name:     "extra metric in actual response",
+			expected: json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"]]}]`),
+			actual:   json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"]]},{"metric":{"foo":"baz"},"values":[[1,"1"]]}]`),
+			err:      errors.New("expected 1 metrics but got 2"),
+		},
+		{
+			name:     "difference in number of samples",
+			expected: json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"2"]]}]`),
+			actual:   json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"]]}]`),
+			err:      errors.New("expected 2 samples for metric {foo=\"bar\"} but got 1"),
+		},
+		{
+			name:     "difference in sample value",
+			expected: json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"2"]]}]`),
+			actual:   json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"3"]]}]`),
+			err:      errors.New("sample pair not matching for metric {foo=\"bar\"}: expected value 2 for timestamp 2 but got 3"),
+		},
+		{
+			name:      "difference in sample value within the tolerance",
+			expected:  json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1000000"],[2,"NaN"]]}]`),
+			actual:    json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1000000.5"],[2,"NaN"]]}]`),
+			tolerance: 0.000001,
+		},
+		{
+			name:      "difference in sample value over the tolerance",
+			expected:  json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1"]]}]`),
+			actual:    json.RawMessage(`[{"metric":{"foo":"bar"},"values":[[1,"1.1"]]}]`),
+			tolerance: 0.01,
+			err:       errors.New("sample pair not matching for metric {foo=\"bar\"}: expected value 1 for timestamp 1 but got 1.1"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := compareMatrix(tc.expected, tc.actual, tc.tolerance)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestCompareVector(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		expected  json.RawMessage
+		actual    json.RawMessage
+		tolerance float64
+		err       error
+	}{
+		{
+			name:     "missing metric in actual response",
+			expected: json.RawMessage(`[{"metric":{"foo":"bar"},"value":[1,"1"]}]`),
+			actual:   json.RawMessage(`[{"metric":{"foo":"baz"},"value":[1,"1"]}]`),
+			err:      errors.New("expected metric {foo=\"bar\"} missing from actual response"),
+		},
+		{
+			name:     "difference in sample timestamp",
+			expected: json.RawMessage(`[{"metric":{"foo":"bar"},"value":[1,"1"]}]`),
+			actual:   json.RawMessage(`[{"metric":{"foo":"bar"},"value":[2,"1"]}]`),
+			err:      errors.New("sample pair not matching for metric {foo=\"bar\"}: expected timestamp 1 but got 2"),
+		},
+		{
+			name:      "difference in sample value within the tolerance",
+			expected:  json.RawMessage(`[{"metric":{"foo":"bar"},"value":[1,"0.3"]},{"metric":{"foo":"baz"},"value":[1,"+Inf"]}]`),
+			actual:    json.RawMessage(`[{"metric":{"foo":"baz"},"value":[1,"+Inf"]},{"metric":{"foo":"bar"},"value":[1,"0.30000000000000004"]}]`),
+			tolerance: 0.000001,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := compareVector(tc.expected, tc.actual, tc.tolerance)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestResponseComparator(t *testing.T) {
+	expected := []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"1"]}],
+		"stats":{"summary":{"totalBytesProcessed":100,"execTime":1},"store":{},"ingester":{}}}}`)
+	actual := []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"2"]}],
+		"stats":{"summary":{"totalBytesProcessed":50,"execTime":1},"store":{},"ingester":{}}}}`)
+
+	dir, err := ioutil.TempDir("", "querytee")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	registry := prometheus.NewRegistry()
+	metrics := newComparatorMetrics(registry)
+	c := NewResponseComparator("api_v1_query", ComparatorConfig{SampleRatio: 1, ReportsDir: dir}, metrics)
+
+	err = c.Compare(expected, actual)
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "sample pair not matching for metric {foo=\"bar\"}: expected value 1 for timestamp 1 but got 2 (diff report "+dir))
+	require.NoError(t, c.Compare(expected, expected))
+
+	// the failed comparison is reported with its stats deltas.
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	b, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	var report diffReport
+	require.NoError(t, json.Unmarshal(b, &report))
+	require.Equal(t, "api_v1_query", report.Route)
+	require.Equal(t, []statDelta{{Stat: "summary.totalBytesProcessed", Expected: 100, Actual: 50}}, report.StatsDeltas)
+	require.JSONEq(t, string(actual), string(report.Actual))
+
+	require.Equal(t, len(flattenStats(stats.Result{})), testutil.CollectAndCount(metrics.statsDelta))
+	require.Equal(t, -0.5, statDelta{Expected: 100, Actual: 50}.relative())
+
+	// the responses which are not sampled are not compared.
+	c.sample = func() bool { return false }
+	require.NoError(t, c.Compare(expected, actual))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.responsesNotSampled.WithLabelValues("api_v1_query")))
+}
@@ -18,81 +18,138 @@ const (
 	ErrEntryNotReceived        = "failed to receive entry %v within %f seconds\n"
 	ErrDuplicateEntry          = "received a duplicate entry for ts %v\n"
 	ErrUnexpectedEntry         = "received an unexpected entry with ts %v\n"
+	ErrSpotCheckEntryNotFound  = "failed to find entry %v with a query, %f seconds after it was sent\n"
 	DebugWebsocketMissingEntry = "websocket missing entry: %v\n"
 	DebugQueryResult           = "confirmation query result: %v\n"
+	DebugMetricTest            = "metric test over %v: expected %d entries, counted %v\n"
 )
 
+// The metrics of the canary are labeled by the tenant and the name of the stream checked.
 var (
-	totalEntries = promauto.NewCounter(prometheus.CounterOpts{
+	totalEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "total_entries",
 		Help:      "counts log entries written to the file",
-	})
-	outOfOrderEntries = promauto.NewCounter(prometheus.CounterOpts{
+	}, []string{"tenant", "stream"})
+	outOfOrderEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "out_of_order_entries",
 		Help:      "counts log entries received with a timestamp more recent than the others in the queue",
-	})
-	wsMissingEntries = promauto.NewCounter(prometheus.CounterOpts{
+	}, []string{"tenant", "stream"})
+	wsMissingEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "websocket_missing_entries",
 		Help:      "counts log entries not received within the maxWait duration via the websocket connection",
-	})
-	missingEntries = promauto.NewCounter(prometheus.CounterOpts{
+	}, []string{"tenant", "stream"})
+	missingEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "missing_entries",
 		Help:      "counts log entries not received within the maxWait duration via both websocket and direct query",
-	})
-	unexpectedEntries = promauto.NewCounter(prometheus.CounterOpts{
+	}, []string{"tenant", "stream"})
+	unexpectedEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "unexpected_entries",
 		Help:      "counts a log entry received which was not expected (e.g. received after reported missing)",
-	})
-	duplicateEntries = promauto.NewCounter(prometheus.CounterOpts{
+	}, []string{"tenant", "stream"})
+	duplicateEntries = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "duplicate_entries",
 		Help:      "counts a log entry received more than one time",
-	})
-	responseLatency prometheus.Histogram
+	}, []string{"tenant", "stream"})
+	spotCheckEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki_canary",
+		Name:      "spot_check_entries_total",
+		Help:      "counts the checks of older log entries with a query, each entry being checked until spotCheckMax after it was sent",
+	}, []string{"tenant", "stream"})
+	spotCheckMissing = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki_canary",
+		Name:      "spot_check_missing_entries_total",
+		Help:      "counts the checks of older log entries which did not find the entry with a query",
+	}, []string{"tenant", "stream"})
+	metricTestExpected = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "loki_canary",
+		Name:      "metric_test_expected",
+		Help:      "is the number of log entries sent in the range of the last metric test",
+	}, []string{"tenant", "stream"})
+	metricTestActual = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "loki_canary",
+		Name:      "metric_test_actual",
+		Help:      "is the number of log entries counted by the count_over_time query of the last metric test",
+	}, []string{"tenant", "stream"})
+	responseLatency *prometheus.HistogramVec
 )
 
+// sentCount is the number of entries sent during a second.
+type sentCount struct {
+	second int64
+	count  int
+}
+
 type Comparator struct {
-	entMtx        sync.Mutex
-	w             io.Writer
-	entries       []*time.Time
-	ackdEntries   []*time.Time
-	maxWait       time.Duration
-	pruneInterval time.Duration
-	confirmAsync  bool
-	sent          chan time.Time
-	recv          chan time.Time
-	rdr           reader.LokiReader
-	quit          chan struct{}
-	done          chan struct{}
+	entMtx             sync.Mutex
+	spotMtx            sync.Mutex
+	w                  io.Writer
+	tenant             string
+	stream             string
+	entries            []*time.Time
+	ackdEntries        []*time.Time
+	spotCheck          []*time.Time
+	sentCounts         []sentCount
+	maxWait            time.Duration
+	pruneInterval      time.Duration
+	spotCheckInterval  time.Duration
+	spotCheckMax       time.Duration
+	spotCheckQueryRate time.Duration
+	spotCheckRunning   bool
+	metricTestInterval time.Duration
+	metricTestRange    time.Duration
+	metricTestRunning  bool
+	startTime          time.Time
+	confirmAsync       bool
+	sent               chan time.Time
+	recv               chan time.Time
+	rdr                reader.LokiReader
+	quit               chan struct{}
+	done               chan struct{}
 }
 
-func NewComparator(writer io.Writer, maxWait time.Duration, pruneInterval time.Duration,
+// NewComparator creates a comparator of the entries of the stream of tenant named stream, sent on
+// sentChan and received on receivedChan. Besides the entries received by the reader, an entry sent
+// every spotCheckInterval is looked up with a query every spotCheckQueryRate until it is spotCheckMax
+// old, and the entries sent in the last metricTestRange are counted with a count_over_time query
+// every metricTestInterval. Spot checks and metric tests are disabled by a zero interval.
+func NewComparator(writer io.Writer, tenant string, stream string, maxWait time.Duration, pruneInterval time.Duration,
+	spotCheckInterval, spotCheckMax, spotCheckQueryRate time.Duration,
+	metricTestInterval, metricTestRange time.Duration,
 	buckets int, sentChan chan time.Time, receivedChan chan time.Time, reader reader.LokiReader, confirmAsync bool) *Comparator {
 	c := &Comparator{
-		w:             writer,
-		entries:       []*time.Time{},
-		maxWait:       maxWait,
-		pruneInterval: pruneInterval,
-		confirmAsync:  confirmAsync,
-		sent:          sentChan,
-		recv:          receivedChan,
-		rdr:           reader,
-		quit:          make(chan struct{}),
-		done:          make(chan struct{}),
+		w:                  writer,
+		tenant:             tenant,
+		stream:             stream,
+		entries:            []*time.Time{},
+		maxWait:            maxWait,
+		pruneInterval:      pruneInterval,
+		spotCheckInterval:  spotCheckInterval,
+		spotCheckMax:       spotCheckMax,
+		spotCheckQueryRate: spotCheckQueryRate,
+		metricTestInterval: metricTestInterval,
+		metricTestRange:    metricTestRange,
+		startTime:          time.Now(),
+		confirmAsync:       confirmAsync,
+		sent:               sentChan,
+		recv:               receivedChan,
+		rdr:                reader,
+		quit:               make(chan struct{}),
+		done:               make(chan struct{}),
 	}
 
 	if responseLatency == nil {
-		responseLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		responseLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: "loki_canary",
 			Name:      "response_latency",
 			Help:      "is how long it takes for log lines to be returned from Loki in seconds.",
 			Buckets:   prometheus.ExponentialBuckets(0.5, 2, buckets),
-		})
+		}, []string{"tenant", "stream"})
 	}
 
 	go c.run()
@@ -108,11 +165,27 @@ func (c *Comparator) Stop() {
 	}
 }
 
-func (c *Comparator) entrySent(time time.Time) {
+func (c *Comparator) entrySent(ts time.Time) {
 	c.entMtx.Lock()
 	defer c.entMtx.Unlock()
-	c.entries = append(c.entries, &time)
-	totalEntries.Inc()
+	c.entries = append(c.entries, &ts)
+	totalEntries.WithLabelValues(c.tenant, c.stream).Inc()
+
+	if c.metricTestInterval > 0 {
+		if n := len(c.sentCounts); n > 0 && c.sentCounts[n-1].second == ts.Unix() {
+			c.sentCounts[n-1].count++
+		} else {
+			c.sentCounts = append(c.sentCounts, sentCount{second: ts.Unix(), count: 1})
+		}
+	}
+
+	if c.spotCheckInterval > 0 {
+		c.spotMtx.Lock()
+		if len(c.spotCheck) == 0 || ts.Sub(*c.spotCheck[len(c.spotCheck)-1]) >= c.spotCheckInterval {
+			c.spotCheck = append(c.spotCheck, &ts)
+		}
+		c.spotMtx.Unlock()
+	}
 }
 
 // entryReceived removes the received entry from the buffer if it exists, reports on out of order entries received
@@ -128,10 +201,10 @@ func (c *Comparator) entryReceived(ts time.Time) {
 			matched = true
 			// If this isn't the first item in the list we received it out of order
 			if i != 0 {
-				outOfOrderEntries.Inc()
+				outOfOrderEntries.WithLabelValues(c.tenant, c.stream).Inc()
 				fmt.Fprintf(c.w, ErrOutOfOrderEntry, e, c.entries[:i])
 			}
-			responseLatency.Observe(time.Since(ts).Seconds())
+			responseLatency.WithLabelValues(c.tenant, c.stream).Observe(time.Since(ts).Seconds())
 			// Put this element in the acknowledged entries list so we can use it to check for duplicates
 			c.ackdEntries = append(c.ackdEntries, c.entries[i])
 			// Do not increment output index, effectively causing this element to be dropped
@@ -148,14 +221,14 @@ func (c *Comparator) entryReceived(ts time.Time) {
 		for _, e := range c.ackdEntries {
 			if ts.Equal(*e) {
 				duplicate = true
-				duplicateEntries.Inc()
+				duplicateEntries.WithLabelValues(c.tenant, c.stream).Inc()
 				fmt.Fprintf(c.w, ErrDuplicateEntry, ts.UnixNano())
 				break
 			}
 		}
 		if !duplicate {
 			fmt.Fprintf(c.w, ErrUnexpectedEntry, ts.UnixNano())
-			unexpectedEntries.Inc()
+			unexpectedEntries.WithLabelValues(c.tenant, c.stream).Inc()
 		}
 	}
 	// Nil out the pointers to any trailing elements which were removed from the slice
@@ -173,8 +246,12 @@ func (c *Comparator) Size() int {
 
 func (c *Comparator) run() {
 	t := time.NewTicker(c.pruneInterval)
+	spotCheck, stopSpotCheck := newTicker(c.spotCheckQueryRate, c.spotCheckInterval > 0)
+	metricTest, stopMetricTest := newTicker(c.metricTestInterval, c.metricTestInterval > 0)
 	defer func() {
 		t.Stop()
+		stopSpotCheck()
+		stopMetricTest()
 		close(c.done)
 	}()
 
@@ -186,6 +263,10 @@ func (c *Comparator) run() {
 			c.entrySent(e)
 		case <-t.C:
 			c.pruneEntries()
+		case <-spotCheck:
+			c.startCheck(&c.spotCheckRunning, func() { c.spotCheckEntries(time.Now()) })
+		case <-metricTest:
+			c.startCheck(&c.metricTestRunning, func() { c.metricTest(time.Now()) })
 		case <-c.quit:
 			return
 		}
@@ -202,7 +283,7 @@ func (c *Comparator) pruneEntries() {
 		// If the time is outside our range, assume the entry has been lost report and remove it
 		if e.Before(time.Now().Add(-c.maxWait)) {
 			missing = append(missing, e)
-			wsMissingEntries.Inc()
+			wsMissingEntries.WithLabelValues(c.tenant, c.stream).Inc()
 			fmt.Fprintf(c.w, ErrEntryNotReceivedWs, e.UnixNano(), c.maxWait.Seconds())
 		} else {
 			if i != k {
@@ -289,7 +370,128 @@ func (c *Comparator) confirmMissing(missing []*time.Time) {
 	}
 	missing = missing[:k]
 	for _, e := range missing {
-		missingEntries.Inc()
+		missingEntries.WithLabelValues(c.tenant, c.stream).Inc()
 		fmt.Fprintf(c.w, ErrEntryNotReceived, e.UnixNano(), c.maxWait.Seconds())
 	}
 }
+
+// startCheck runs check in the background unless the previous run, flagged by running, is not done yet.
+func (c *Comparator) startCheck(running *bool, check func()) {
+	c.spotMtx.Lock()
+	defer c.spotMtx.Unlock()
+	if *running {
+		return
+	}
+	*running = true
+
+	go func() {
+		check()
+		c.spotMtx.Lock()
+		*running = false
+		c.spotMtx.Unlock()
+	}()
+}
+
+// spotCheckEntries looks up each of the spot check entries older than maxWait with a query, which
+// catches the entries lost after they were received, e.g. when the chunks are flushed.
+func (c *Comparator) spotCheckEntries(now time.Time) {
+	c.spotMtx.Lock()
+	k := 0
+	for i, e := range c.spotCheck {
+		// Entries older than spotCheckMax are not checked anymore
+		if e.Before(now.Add(-c.spotCheckMax)) {
+			continue
+		}
+		if i != k {
+			c.spotCheck[k] = c.spotCheck[i]
+		}
+		k++
+	}
+	for i := k; i < len(c.spotCheck); i++ {
+		c.spotCheck[i] = nil
+	}
+	c.spotCheck = c.spotCheck[:k]
+	entries := make([]time.Time, 0, k)
+	for _, e := range c.spotCheck {
+		// the recent entries are still checked by the websocket
+		if e.Before(now.Add(-c.maxWait)) {
+			entries = append(entries, *e)
+		}
+	}
+	c.spotMtx.Unlock()
+
+	for _, e := range entries {
+		// Because we are querying loki timestamps vs the timestamp in the log,
+		// make the range +/- 10 seconds to allow for clock inaccuracies
+		recvd, err := c.rdr.Query(e.Add(-10*time.Second), e.Add(10*time.Second))
+		if err != nil {
+			fmt.Fprintf(c.w, "error querying loki: %s\n", err)
+			return
+		}
+
+		spotCheckEntries.WithLabelValues(c.tenant, c.stream).Inc()
+		found := false
+		for _, r := range recvd {
+			if e.Equal(r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			spotCheckMissing.WithLabelValues(c.tenant, c.stream).Inc()
+			fmt.Fprintf(c.w, ErrSpotCheckEntryNotFound, e.UnixNano(), now.Sub(e).Seconds())
+		}
+	}
+}
+
+// metricTest compares the number of entries counted by a count_over_time query with the number of
+// entries sent over the same range. The range ends maxWait ago, as more recent entries may not be
+// received yet, and is shortened to the time since the comparator started, which has not sent the
+// entries before.
+func (c *Comparator) metricTest(now time.Time) {
+	at := now.Add(-c.maxWait).Truncate(time.Second)
+	queryRange := c.metricTestRange
+	if sinceStart := at.Sub(c.startTime); sinceStart < queryRange {
+		queryRange = sinceStart
+	}
+	queryRange = queryRange.Truncate(time.Second)
+	if queryRange <= 0 {
+		return
+	}
+
+	c.entMtx.Lock()
+	// Prune the counts of the seconds before the range of any later test
+	k := 0
+	for k < len(c.sentCounts) && c.sentCounts[k].second < at.Add(-c.metricTestRange).Unix() {
+		k++
+	}
+	c.sentCounts = append(c.sentCounts[:0], c.sentCounts[k:]...)
+
+	expected := 0
+	for _, sc := range c.sentCounts {
+		if sc.second >= at.Add(-queryRange).Unix() && sc.second < at.Unix() {
+			expected += sc.count
+		}
+	}
+	c.entMtx.Unlock()
+
+	actual, err := c.rdr.QueryCountOverTime(at, queryRange)
+	if err != nil {
+		fmt.Fprintf(c.w, "error running metric query test: %s\n", err)
+		return
+	}
+
+	metricTestExpected.WithLabelValues(c.tenant, c.stream).Set(float64(expected))
+	metricTestActual.WithLabelValues(c.tenant, c.stream).Set(actual)
+	fmt.Fprintf(c.w, DebugMetricTest, queryRange, expected, actual)
+}
+
+// newTicker returns the channel of a ticker of the given interval and a function stopping it, or a
+// nil channel when the ticker is disabled.
+func newTicker(interval time.Duration, enabled bool) (<-chan time.Time, func()) {
+	if !enabled || interval <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTicker(interval)
+	return t.C, t.Stop
+}
@@ -3,23 +3,22 @@ package comparator
 import (
 	"bytes"
 	"fmt"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestComparatorEntryReceivedOutOfOrder(t *testing.T) {
-	outOfOrderEntries = &mockCounter{}
-	wsMissingEntries = &mockCounter{}
-	unexpectedEntries = &mockCounter{}
-	duplicateEntries = &mockCounter{}
+	outOfOrderEntries.Reset()
+	wsMissingEntries.Reset()
+	unexpectedEntries.Reset()
+	duplicateEntries.Reset()
 
 	actual := &bytes.Buffer{}
-	c := NewComparator(actual, 1*time.Hour, 1*time.Hour, 1, make(chan time.Time), make(chan time.Time), nil, false)
+	c := NewComparator(actual, "", "test", 1*time.Hour, 1*time.Hour, 0, 0, 0, 0, 0, 1, make(chan time.Time), make(chan time.Time), nil, false)
 
 	t1 := time.Now()
 	t2 := t1.Add(1 * time.Second)
@@ -42,10 +41,10 @@ func TestComparatorEntryReceivedOutOfOrder(t *testing.T) {
 	expected := fmt.Sprintf(ErrOutOfOrderEntry, t4, []time.Time{t2, t3})
 	assert.Equal(t, expected, actual.String())
 
-	assert.Equal(t, 1, outOfOrderEntries.(*mockCounter).count)
-	assert.Equal(t, 0, unexpectedEntries.(*mockCounter).count)
-	assert.Equal(t, 0, wsMissingEntries.(*mockCounter).count)
-	assert.Equal(t, 0, duplicateEntries.(*mockCounter).count)
+	assert.Equal(t, 1, count(outOfOrderEntries))
+	assert.Equal(t, 0, count(unexpectedEntries))
+	assert.Equal(t, 0, count(wsMissingEntries))
+	assert.Equal(t, 0, count(duplicateEntries))
 
 	// This avoids a panic on subsequent test execution,
 	// seems ugly but was easy, and multiple instantiations
@@ -54,13 +53,13 @@ func TestComparatorEntryReceivedOutOfOrder(t *testing.T) {
 }
 
 func TestComparatorEntryReceivedNotExpected(t *testing.T) {
-	outOfOrderEntries = &mockCounter{}
-	wsMissingEntries = &mockCounter{}
-	unexpectedEntries = &mockCounter{}
-	duplicateEntries = &mockCounter{}
+	outOfOrderEntries.Reset()
+	wsMissingEntries.Reset()
+	unexpectedEntries.Reset()
+	duplicateEntries.Reset()
 
 	actual := &bytes.Buffer{}
-	c := NewComparator(actual, 1*time.Hour, 1*time.Hour, 1, make(chan time.Time), make(chan time.Time), nil, false)
+	c := NewComparator(actual, "", "test", 1*time.Hour, 1*time.Hour, 0, 0, 0, 0, 0, 1, make(chan time.Time), make(chan time.Time), nil, false)
 
 	t1 := time.Now()
 	t2 := t1.Add(1 * time.Second)
@@ -83,10 +82,10 @@ func TestComparatorEntryReceivedNotExpected(t *testing.T) {
 	expected := fmt.Sprintf(ErrUnexpectedEntry, t1.UnixNano())
 	assert.Equal(t, expected, actual.String())
 
-	assert.Equal(t, 0, outOfOrderEntries.(*mockCounter).count)
-	assert.Equal(t, 1, unexpectedEntries.(*mockCounter).count)
-	assert.Equal(t, 0, wsMissingEntries.(*mockCounter).count)
-	assert.Equal(t, 0, duplicateEntries.(*mockCounter).count)
+	assert.Equal(t, 0, count(outOfOrderEntries))
+	assert.Equal(t, 1, count(unexpectedEntries))
+	assert.Equal(t, 0, count(wsMissingEntries))
+	assert.Equal(t, 0, count(duplicateEntries))
 
 	// This avoids a panic on subsequent test execution,
 	// seems ugly but was easy, and multiple instantiations
@@ -95,13 +94,13 @@ func TestComparatorEntryReceivedNotExpected(t *testing.T) {
 }
 
 func TestComparatorEntryReceivedDuplicate(t *testing.T) {
-	outOfOrderEntries = &mockCounter{}
-	wsMissingEntries = &mockCounter{}
-	unexpectedEntries = &mockCounter{}
-	duplicateEntries = &mockCounter{}
+	outOfOrderEntries.Reset()
+	wsMissingEntries.Reset()
+	unexpectedEntries.Reset()
+	duplicateEntries.Reset()
 
 	actual := &bytes.Buffer{}
-	c := NewComparator(actual, 1*time.Hour, 1*time.Hour, 1, make(chan time.Time), make(chan time.Time), nil, false)
+	c := NewComparator(actual, "", "test", 1*time.Hour, 1*time.Hour, 0, 0, 0, 0, 0, 1, make(chan time.Time), make(chan time.Time), nil, false)
 
 	t1 := time.Now()
 	t2 := t1.Add(1 * time.Second)
@@ -127,10 +126,10 @@ func TestComparatorEntryReceivedDuplicate(t *testing.T) {
 	expected := fmt.Sprintf(ErrDuplicateEntry, t2.UnixNano())
 	assert.Equal(t, expected, actual.String())
 
-	assert.Equal(t, 0, outOfOrderEntries.(*mockCounter).count)
-	assert.Equal(t, 0, unexpectedEntries.(*mockCounter).count)
-	assert.Equal(t, 0, wsMissingEntries.(*mockCounter).count)
-	assert.Equal(t, 1, duplicateEntries.(*mockCounter).count)
+	assert.Equal(t, 0, count(outOfOrderEntries))
+	assert.Equal(t, 0, count(unexpectedEntries))
+	assert.Equal(t, 0, count(wsMissingEntries))
+	assert.Equal(t, 1, count(duplicateEntries))
 
 	// This avoids a panic on subsequent test execution,
 	// seems ugly but was easy, and multiple instantiations
@@ -139,11 +138,11 @@ func TestComparatorEntryReceivedDuplicate(t *testing.T) {
 }
 
 func TestEntryNeverReceived(t *testing.T) {
-	outOfOrderEntries = &mockCounter{}
-	wsMissingEntries = &mockCounter{}
-	missingEntries = &mockCounter{}
-	unexpectedEntries = &mockCounter{}
-	duplicateEntries = &mockCounter{}
+	outOfOrderEntries.Reset()
+	wsMissingEntries.Reset()
+	missingEntries.Reset()
+	unexpectedEntries.Reset()
+	duplicateEntries.Reset()
 
 	actual := &bytes.Buffer{}
 
@@ -158,7 +157,7 @@ func TestEntryNeverReceived(t *testing.T) {
 	mr := &mockReader{found}
 	maxWait := 50 * time.Millisecond
 	//We set the prune interval timer to a huge value here so that it never runs, instead we call pruneEntries manually below
-	c := NewComparator(actual, maxWait, 50*time.Hour, 1, make(chan time.Time), make(chan time.Time), mr, false)
+	c := NewComparator(actual, "", "test", maxWait, 50*time.Hour, 0, 0, 0, 0, 0, 1, make(chan time.Time), make(chan time.Time), mr, false)
 
 	c.entrySent(t1)
 	c.entrySent(t2)
@@ -199,11 +198,11 @@ func TestEntryNeverReceived(t *testing.T) {
 	assert.Equal(t, expected, actual.String())
 	assert.Equal(t, 0, c.Size())
 
-	assert.Equal(t, 2, outOfOrderEntries.(*mockCounter).count)
-	assert.Equal(t, 0, unexpectedEntries.(*mockCounter).count)
-	assert.Equal(t, 2, wsMissingEntries.(*mockCounter).count)
-	assert.Equal(t, 1, missingEntries.(*mockCounter).count)
-	assert.Equal(t, 0, duplicateEntries.(*mockCounter).count)
+	assert.Equal(t, 2, count(outOfOrderEntries))
+	assert.Equal(t, 0, count(unexpectedEntries))
+	assert.Equal(t, 2, count(wsMissingEntries))
+	assert.Equal(t, 1, count(missingEntries))
+	assert.Equal(t, 0, count(duplicateEntries))
 
 	// This avoids a panic on subsequent test execution,
 	// seems ugly but was easy, and multiple instantiations
@@ -216,7 +215,7 @@ func TestPruneAckdEntires(t *testing.T) {
 	actual := &bytes.Buffer{}
 	maxWait := 30 * time.Millisecond
 	//We set the prune interval timer to a huge value here so that it never runs, instead we call pruneEntries manually below
-	c := NewComparator(actual, maxWait, 50*time.Hour, 1, make(chan time.Time), make(chan time.Time), nil, false)
+	c := NewComparator(actual, "", "test", maxWait, 50*time.Hour, 0, 0, 0, 0, 0, 1, make(chan time.Time), make(chan time.Time), nil, false)
 
 	t1 := time.Now()
 	t2 := t1.Add(1 * time.Millisecond)
@@ -251,35 +250,99 @@ func TestPruneAckdEntires(t *testing.T) {
 
 }
 
-type mockCounter struct {
-	cLck  sync.Mutex
-	count int
-}
+func TestSpotCheck(t *testing.T) {
+	spotCheckEntries.Reset()
+	spotCheckMissing.Reset()
 
-func (m *mockCounter) Desc() *prometheus.Desc {
-	panic("implement me")
-}
+	actual := &bytes.Buffer{}
 
-func (m *mockCounter) Write(*io_prometheus_client.Metric) error {
-	panic("implement me")
-}
+	now := time.Now()
+	var sent, found []time.Time
+	for i := 0; i < 30; i++ {
+		ts := now.Add(time.Duration(i-30) * 10 * time.Minute)
+		sent = append(sent, ts)
+		// the entries sent between 235 and 175 minutes ago are lost
+		if ts.Before(now.Add(-235*time.Minute)) || ts.After(now.Add(-175*time.Minute)) {
+			found = append(found, ts)
+		}
+	}
 
-func (m *mockCounter) Describe(chan<- *prometheus.Desc) {
-	panic("implement me")
-}
+	mr := &mockReader{found}
+	// Spot check an entry every 30 minutes for 245 minutes
+	c := NewComparator(actual, "", "test", time.Minute, 50*time.Hour, 30*time.Minute, 245*time.Minute, 50*time.Hour, 0, 0, 1, make(chan time.Time), make(chan time.Time), mr, false)
+	for _, ts := range sent {
+		c.entrySent(ts)
+	}
+	assert.Len(t, c.spotCheck, 10)
+
+	c.spotCheckEntries(now)
+
+	// the entries older than 245 minutes are not checked anymore
+	assert.Len(t, c.spotCheck, 8)
+	assert.Equal(t, 8, count(spotCheckEntries))
+	assert.Equal(t, 2, count(spotCheckMissing))
+	assert.Equal(t, fmt.Sprintf(ErrSpotCheckEntryNotFound+ErrSpotCheckEntryNotFound,
+		sent[9].UnixNano(), now.Sub(sent[9]).Seconds(),
+		sent[12].UnixNano(), now.Sub(sent[12]).Seconds()), actual.String())
 
-func (m *mockCounter) Collect(chan<- prometheus.Metric) {
-	panic("implement me")
+	prometheus.Unregister(responseLatency)
 }
 
-func (m *mockCounter) Add(float64) {
-	panic("implement me")
+func TestMetricTest(t *testing.T) {
+	metricTestExpected.Reset()
+	metricTestActual.Reset()
+
+	actual := &bytes.Buffer{}
+
+	now := time.Now().Truncate(time.Second)
+	var sent []time.Time
+	for ts := now.Add(-2 * time.Hour); ts.Before(now); ts = ts.Add(time.Second) {
+		sent = append(sent, ts.Add(100*time.Millisecond))
+	}
+
+	// half of the entries of the last hour are lost
+	var found []time.Time
+	for i, ts := range sent {
+		if ts.Before(now.Add(-time.Hour)) || i%2 == 0 {
+			found = append(found, ts)
+		}
+	}
+
+	mr := &mockReader{found}
+	c := NewComparator(actual, "", "test", time.Minute, 50*time.Hour, 0, 0, 0, 50*time.Hour, 30*time.Minute, 1, make(chan time.Time), make(chan time.Time), mr, false)
+	c.startTime = now.Add(-2 * time.Hour)
+	for _, ts := range sent {
+		c.entrySent(ts)
+	}
+
+	// the range ends maxWait ago
+	c.metricTest(now.Add(-time.Hour))
+	assert.Equal(t, 1800.0, testutil.ToFloat64(metricTestExpected.WithLabelValues("", "test")))
+	assert.Equal(t, 1800.0, testutil.ToFloat64(metricTestActual.WithLabelValues("", "test")))
+
+	c.metricTest(now)
+	assert.Equal(t, 1800.0, testutil.ToFloat64(metricTestExpected.WithLabelValues("", "test")))
+	assert.Equal(t, 900.0, testutil.ToFloat64(metricTestActual.WithLabelValues("", "test")))
+	// the counts of the seconds before the range are pruned
+	assert.Len(t, c.sentCounts, 1860)
+
+	// the range is shortened to the time since the start of the comparator
+	c.startTime = now.Add(-11 * time.Minute)
+	c.metricTest(now)
+	assert.Equal(t, 600.0, testutil.ToFloat64(metricTestExpected.WithLabelValues("", "test")))
+	assert.Equal(t, 300.0, testutil.ToFloat64(metricTestActual.WithLabelValues("", "test")))
+
+	assert.Equal(t, fmt.Sprintf(DebugMetricTest+DebugMetricTest+DebugMetricTest,
+		30*time.Minute, 1800, 1800.0,
+		30*time.Minute, 1800, 900.0,
+		10*time.Minute, 600, 300.0), actual.String())
+
+	prometheus.Unregister(responseLatency)
 }
 
-func (m *mockCounter) Inc() {
-	m.cLck.Lock()
-	defer m.cLck.Unlock()
-	m.count++
+// count returns the value of the counter of the comparators of the tests.
+func count(c *prometheus.CounterVec) int {
+	return int(testutil.ToFloat64(c.WithLabelValues("", "test")))
 }
 
 type mockReader struct {
@@ -287,5 +350,22 @@ type mockReader struct {
 }
 
 func (r *mockReader) Query(start time.Time, end time.Time) ([]time.Time, error) {
-	return r.resp, nil
+	var res []time.Time
+	for _, ts := range r.resp {
+		if !ts.Before(start) && !ts.After(end) {
+			res = append(res, ts)
+		}
+	}
+	return res, nil
+}
+
+func (r *mockReader) QueryCountOverTime(at time.Time, queryRange time.Duration) (float64, error) {
+	// the entries in (at-queryRange, at], like count_over_time.
+	var n float64
+	for _, ts := range r.resp {
+		if ts.After(at.Add(-queryRange)) && !ts.After(at) {
+			n++
+		}
+	}
+	return n, nil
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the configuration of the streams written and verified by the canary.
+type Config struct {
+	Streams []Stream `yaml:"streams"`
+}
+
+// Stream is a stream written by the canary, which is shipped to Loki by an agent under its labels
+// and verified by the canary with queries for its tenant.
+type Stream struct {
+	// Name identifies the stream in the logs and metrics of the canary, defaults to its selector.
+	Name string `yaml:"name"`
+	// Tenant is the tenant the stream is pushed for by the agent, and queried for by the canary.
+	Tenant string `yaml:"tenant"`
+	// Labels are the labels of the stream in Loki, i.e. the labels set by the agent.
+	Labels map[string]string `yaml:"labels"`
+	// File is the file the entries are written to, or stdout when empty.
+	File string `yaml:"file"`
+	// Interval is the duration between entries, defaults to the -interval flag.
+	Interval time.Duration `yaml:"interval"`
+	// Size is the size in bytes of each entry, defaults to the -size flag.
+	Size int `yaml:"size"`
+}
+
+// Selector returns the stream selector of the stream.
+func (s Stream) Selector() string {
+	names := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	matchers := make([]string, 0, len(names))
+	for _, name := range names {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, s.Labels[name]))
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// Load reads the configuration file at path, defaulting the interval and size of its streams to
+// the given ones.
+func Load(path string, interval time.Duration, size int) (*Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.UnmarshalStrict(buf, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+
+	for i := range cfg.Streams {
+		cfg.Streams[i].setDefaults(interval, size)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", path)
+	}
+	return &cfg, nil
+}
+
+func (s *Stream) setDefaults(interval time.Duration, size int) {
+	if s.Interval == 0 {
+		s.Interval = interval
+	}
+	if s.Size == 0 {
+		s.Size = size
+	}
+	if s.Name == "" {
+		s.Name = s.Selector()
+	}
+}
+
+// Validate checks that the streams can be told apart, both in Loki and in the files they are written to.
+func (c *Config) Validate() error {
+	if len(c.Streams) == 0 {
+		return errors.New("no streams configured")
+	}
+
+	names := map[string]struct{}{}
+	files := map[string]struct{}{}
+	streams := map[string]struct{}{}
+	for _, s := range c.Streams {
+		if len(s.Labels) == 0 {
+			return fmt.Errorf("stream %s has no labels", s.Name)
+		}
+		if s.Interval <= 0 {
+			return fmt.Errorf("stream %s has a non-positive interval", s.Name)
+		}
+
+		if _, ok := names[s.Name]; ok {
+			return fmt.Errorf("duplicate stream name %s", s.Name)
+		}
+		names[s.Name] = struct{}{}
+
+		// the entries of the streams written to the same file would be shipped in the same stream.
+		if _, ok := files[s.File]; ok {
+			if s.File == "" {
+				return errors.New("only one stream can be written to stdout")
+			}
+			return fmt.Errorf("duplicate stream file %s", s.File)
+		}
+		files[s.File] = struct{}{}
+
+		key := s.Tenant + "/" + s.Selector()
+		if _, ok := streams[key]; ok {
+			return fmt.Errorf("streams with the same tenant and labels as %s", s.Name)
+		}
+		streams[key] = struct{}{}
+	}
+	return nil
+}
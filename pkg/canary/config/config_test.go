@@ -0,0 +1,82 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "canary-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "streams.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+streams:
+  - labels: {name: loki-canary, stream: stdout}
+  - name: large
+    tenant: team-a
+    labels: {name: loki-canary, stream: large}
+    file: /var/log/loki-canary/large.log
+    interval: 10s
+    size: 10240
+`), 0600))
+
+	cfg, err := Load(path, time.Second, 100)
+	require.NoError(t, err)
+	require.Equal(t, []Stream{
+		{
+			Name:     `{name="loki-canary",stream="stdout"}`,
+			Labels:   map[string]string{"name": "loki-canary", "stream": "stdout"},
+			Interval: time.Second,
+			Size:     100,
+		},
+		{
+			Name:     "large",
+			Tenant:   "team-a",
+			Labels:   map[string]string{"name": "loki-canary", "stream": "large"},
+			File:     "/var/log/loki-canary/large.log",
+			Interval: 10 * time.Second,
+			Size:     10240,
+		},
+	}, cfg.Streams)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`streams: [{labels: {name: a}, unknown: b}]`), 0600))
+	_, err = Load(path, time.Second, 100)
+	require.Error(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	stream := func(name, tenant, file string) Stream {
+		return Stream{Name: name, Tenant: tenant, Labels: map[string]string{"name": "loki-canary"}, File: file, Interval: time.Second}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		streams []Stream
+		err     string
+	}{
+		{"valid", []Stream{stream("a", "", ""), stream("b", "team-a", "b.log")}, ""},
+		{"no streams", nil, "no streams configured"},
+		{"no labels", []Stream{{Name: "a", Interval: time.Second}}, "stream a has no labels"},
+		{"no interval", []Stream{{Name: "a", Labels: map[string]string{"name": "a"}}}, "stream a has a non-positive interval"},
+		{"same name", []Stream{stream("a", "", "a.log"), stream("a", "team-a", "b.log")}, "duplicate stream name a"},
+		{"same file", []Stream{stream("a", "", "a.log"), stream("b", "team-a", "a.log")}, "duplicate stream file a.log"},
+		{"both stdout", []Stream{stream("a", "", ""), stream("b", "team-a", "")}, "only one stream can be written to stdout"},
+		{"same stream", []Stream{stream("a", "", "a.log"), stream("b", "", "b.log")}, "streams with the same tenant and labels as b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := (&Config{Streams: tc.streams}).Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
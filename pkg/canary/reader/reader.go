@@ -19,21 +19,24 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/loki/pkg/build"
-	loghttp "github.com/grafana/loki/pkg/loghttp/legacy"
+	"github.com/grafana/loki/pkg/loghttp"
+	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logproto"
 )
 
 var (
-	reconnects = promauto.NewCounter(prometheus.CounterOpts{
+	reconnects = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "loki_canary",
 		Name:      "ws_reconnects",
 		Help:      "counts every time the websocket connection has to reconnect",
-	})
+	}, []string{"tenant", "stream"})
 	userAgent = fmt.Sprintf("loki-canary/%s", build.Version)
 )
 
 type LokiReader interface {
 	Query(start time.Time, end time.Time) ([]time.Time, error)
+	// QueryCountOverTime returns the number of entries of the stream in the queryRange before at.
+	QueryCountOverTime(at time.Time, queryRange time.Duration) (float64, error)
 }
 
 type Reader struct {
@@ -42,10 +45,9 @@ type Reader struct {
 	addr         string
 	user         string
 	pass         string
-	sName        string
-	sValue       string
-	lName        string
-	lVal         string
+	tenant       string
+	selector     string
+	reconnects   prometheus.Counter
 	conn         *websocket.Conn
 	w            io.Writer
 	recv         chan time.Time
@@ -54,12 +56,16 @@ type Reader struct {
 	done         chan struct{}
 }
 
+// NewReader creates a reader tailing the stream matching selector, named stream in the metrics, for tenant.
 func NewReader(writer io.Writer, receivedChan chan time.Time, tls bool,
-	address string, user string, pass string, labelName string, labelVal string, streamName string, streamValue string) *Reader {
+	address string, user string, pass string, tenant string, stream string, selector string) *Reader {
 	h := http.Header{}
 	if user != "" {
 		h = http.Header{"Authorization": {"Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))}}
 	}
+	if tenant != "" {
+		h.Set("X-Scope-OrgID", tenant)
+	}
 
 	rd := Reader{
 		header:       h,
@@ -67,10 +73,9 @@ func NewReader(writer io.Writer, receivedChan chan time.Time, tls bool,
 		addr:         address,
 		user:         user,
 		pass:         pass,
-		sName:        streamName,
-		sValue:       streamValue,
-		lName:        labelName,
-		lVal:         labelVal,
+		tenant:       tenant,
+		selector:     selector,
+		reconnects:   reconnects.WithLabelValues(tenant, stream),
 		w:            writer,
 		recv:         receivedChan,
 		quit:         make(chan struct{}),
@@ -101,31 +106,86 @@ func (r *Reader) Stop() {
 }
 
 func (r *Reader) Query(start time.Time, end time.Time) ([]time.Time, error) {
+	u := r.url("/api/prom/query", fmt.Sprintf("start=%d&end=%d", start.UnixNano(), end.UnixNano())+
+		"&query="+url.QueryEscape(r.selector)+
+		"&limit=1000")
+	fmt.Fprintf(r.w, "Querying loki for missing values with query: %v\n", u)
+
+	var decoded logproto.QueryResponse
+	if err := r.get(u, &decoded); err != nil {
+		return nil, err
+	}
+
+	tss := []time.Time{}
+
+	for _, stream := range decoded.Streams {
+		for _, entry := range stream.Entries {
+			ts, err := parseResponse(&entry)
+			if err != nil {
+				fmt.Fprint(r.w, err)
+				continue
+			}
+			tss = append(tss, *ts)
+		}
+
+	}
+
+	return tss, nil
+}
+
+// QueryCountOverTime counts the entries of the stream in the queryRange before at with an instant
+// count_over_time query, to check the results of metric queries.
+func (r *Reader) QueryCountOverTime(at time.Time, queryRange time.Duration) (float64, error) {
+	query := fmt.Sprintf("sum(count_over_time(%s[%ds]))", r.selector, int64(queryRange.Seconds()))
+	u := r.url("/loki/api/v1/query", fmt.Sprintf("time=%d", at.UnixNano())+"&query="+url.QueryEscape(query))
+	fmt.Fprintf(r.w, "Querying loki for the number of entries with query: %v\n", u)
+
+	var decoded loghttp.QueryResponse
+	if err := r.get(u, &decoded); err != nil {
+		return 0, err
+	}
+
+	vector, ok := decoded.Data.Result.(loghttp.Vector)
+	if !ok {
+		return 0, fmt.Errorf("unexpected result type %s", decoded.Data.ResultType)
+	}
+	// the sum of no series at all is an empty vector.
+	if len(vector) == 0 {
+		return 0, nil
+	}
+	return float64(vector[0].Value), nil
+}
+
+func (r *Reader) url(path string, rawQuery string) string {
 	scheme := "http"
 	if r.tls {
 		scheme = "https"
 	}
 	u := url.URL{
-		Scheme: scheme,
-		Host:   r.addr,
-		Path:   "/api/prom/query",
-		RawQuery: fmt.Sprintf("start=%d&end=%d", start.UnixNano(), end.UnixNano()) +
-			"&query=" + url.QueryEscape(fmt.Sprintf("{%v=\"%v\",%v=\"%v\"}", r.sName, r.sValue, r.lName, r.lVal)) +
-			"&limit=1000",
+		Scheme:   scheme,
+		Host:     r.addr,
+		Path:     path,
+		RawQuery: rawQuery,
 	}
-	fmt.Fprintf(r.w, "Querying loki for missing values with query: %v\n", u.String())
+	return u.String()
+}
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+// get sends a GET request for the tenant of the reader and decodes its JSON response into v.
+func (r *Reader) get(u string, v interface{}) error {
+	req, err := http.NewRequest("GET", u, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req.SetBasicAuth(r.user, r.pass)
 	req.Header.Set("User-Agent", userAgent)
+	if r.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", r.tenant)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -135,36 +195,16 @@ func (r *Reader) Query(start time.Time, end time.Time) ([]time.Time, error) {
 
 	if resp.StatusCode/100 != 2 {
 		buf, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error response from server: %s (%v)", string(buf), err)
+		return fmt.Errorf("error response from server: %s (%v)", string(buf), err)
 	}
-	var decoded logproto.QueryResponse
-	err = json.NewDecoder(resp.Body).Decode(&decoded)
-	if err != nil {
-		return nil, err
-	}
-
-	tss := []time.Time{}
-
-	for _, stream := range decoded.Streams {
-		for _, entry := range stream.Entries {
-			ts, err := parseResponse(&entry)
-			if err != nil {
-				fmt.Fprint(r.w, err)
-				continue
-			}
-			tss = append(tss, *ts)
-		}
-
-	}
-
-	return tss, nil
+	return json.NewDecoder(resp.Body).Decode(v)
 }
 
 func (r *Reader) run() {
 
 	r.closeAndReconnect()
 
-	tailResponse := &loghttp.TailResponse{}
+	tailResponse := &legacy.TailResponse{}
 
 	for {
 		err := r.conn.ReadJSON(tailResponse)
@@ -199,7 +239,7 @@ func (r *Reader) closeAndReconnect() {
 		r.conn = nil
 		// By incrementing reconnects here we should only count a failure followed by a successful reconnect.
 		// Initial connections and reconnections from failed tries will not be counted.
-		reconnects.Inc()
+		r.reconnects.Inc()
 	}
 	for r.conn == nil {
 		scheme := "ws"
@@ -210,10 +250,10 @@ func (r *Reader) closeAndReconnect() {
 			Scheme:   scheme,
 			Host:     r.addr,
 			Path:     "/api/prom/tail",
-			RawQuery: "query=" + url.QueryEscape(fmt.Sprintf("{%v=\"%v\",%v=\"%v\"}", r.sName, r.sValue, r.lName, r.lVal)),
+			RawQuery: "query=" + url.QueryEscape(r.selector),
 		}
 
-		fmt.Fprintf(r.w, "Connecting to loki at %v, querying for label '%v' with value '%v'\n", u.String(), r.lName, r.lVal)
+		fmt.Fprintf(r.w, "Connecting to loki at %v, querying for stream %v\n", u.String(), r.selector)
 
 		c, _, err := websocket.DefaultDialer.Dial(u.String(), r.header)
 		if err != nil {
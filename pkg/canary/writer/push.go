@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/promtail/client"
+)
+
+var (
+	pushDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "loki_canary",
+		Name:      "push_request_duration_seconds",
+		Help:      "is the duration of the push requests sent directly to Loki, by status code or error for the requests which failed to be sent",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tenant", "stream", "status_code"})
+	pushErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki_canary",
+		Name:      "push_errors_total",
+		Help:      "counts the push requests sent directly to Loki which failed, each of them being retried with a backoff",
+	}, []string{"tenant", "stream"})
+)
+
+// Push is the io.Writer of a Writer pushing its entries directly to Loki, instead of
+// writing them for an agent to ship them. The entries are batched by the promtail client,
+// which retries the failed pushes with a backoff.
+type Push struct {
+	client client.Client
+	labels model.LabelSet
+}
+
+// NewPush makes a Push sending the entries of the stream with the given labels to the
+// Loki of cfg, for the tenant.
+func NewPush(cfg client.Config, tenant, stream string, labels map[string]string, logger log.Logger) (*Push, error) {
+	cfg.TenantID = tenant
+	c, err := client.NewWithTransport(cfg, logger, func(next http.RoundTripper) http.RoundTripper {
+		return &instrumentedTransport{next: next, tenant: tenant, stream: stream}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ls := make(model.LabelSet, len(labels))
+	for name, value := range labels {
+		ls[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return &Push{client: c, labels: ls}, nil
+}
+
+// Write pushes a log line, timestamped with the time it starts with.
+func (p *Push) Write(b []byte) (int, error) {
+	line := strings.TrimSuffix(string(b), "\n")
+	t := time.Now()
+	if i := strings.IndexByte(line, ' '); i > 0 {
+		if ts, err := strconv.ParseInt(line[:i], 10, 64); err == nil {
+			t = time.Unix(0, ts)
+		}
+	}
+	if err := p.client.Handle(p.labels, t, line); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Stop sends the pending entries and stops the client.
+func (p *Push) Stop() {
+	p.client.Stop()
+}
+
+// instrumentedTransport records the duration and the errors of the push requests.
+type instrumentedTransport struct {
+	next   http.RoundTripper
+	tenant string
+	stream string
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	pushDuration.WithLabelValues(t.tenant, t.stream, status).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode/100 != 2 {
+		pushErrors.WithLabelValues(t.tenant, t.stream).Inc()
+	}
+	return resp, err
+}
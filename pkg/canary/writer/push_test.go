@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/promtail/client"
+)
+
+func TestPush(t *testing.T) {
+	type received struct {
+		tenant string
+		req    logproto.PushRequest
+	}
+	reqs := make(chan received, 10)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var pushReq logproto.PushRequest
+		if _, err := util.ParseProtoReader(req.Context(), req.Body, int(req.ContentLength), math.MaxInt32, &pushReq, util.RawSnappy); err != nil {
+			rw.WriteHeader(400)
+			return
+		}
+		// the first push fails and is retried.
+		requests++
+		if requests == 1 {
+			rw.WriteHeader(500)
+			return
+		}
+		reqs <- received{tenant: req.Header.Get("X-Scope-OrgID"), req: pushReq}
+		rw.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	cfg := client.Config{
+		URL:           flagext.URLValue{URL: u},
+		BatchWait:     10 * time.Millisecond,
+		BatchSize:     1024,
+		Timeout:       time.Second,
+		BackoffConfig: util.BackoffConfig{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRetries: 3},
+	}
+	pushDuration.Reset()
+	pushErrors.Reset()
+
+	p, err := NewPush(cfg, "tenant-1", "push", map[string]string{"name": "loki-canary", "stream": "push"}, log.NewNopLogger())
+	require.NoError(t, err)
+	n, err := p.Write([]byte("1000000000 ppp\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 15, n)
+	p.Stop()
+
+	select {
+	case r := <-reqs:
+		assert.Equal(t, "tenant-1", r.tenant)
+		require.Len(t, r.req.Streams, 1)
+		assert.Equal(t, `{name="loki-canary", stream="push"}`, r.req.Streams[0].Labels)
+		require.Len(t, r.req.Streams[0].Entries, 1)
+		assert.Equal(t, "1000000000 ppp", r.req.Streams[0].Entries[0].Line)
+		assert.Equal(t, time.Unix(1, 0).UnixNano(), r.req.Streams[0].Entries[0].Timestamp.UnixNano())
+	default:
+		t.Fatal("the entry was not pushed")
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(pushErrors.WithLabelValues("tenant-1", "push")))
+	assert.Equal(t, 2, testutil.CollectAndCount(pushDuration))
+}
@@ -4,6 +4,8 @@ import (
 	"io"
 
 	"github.com/cortexproject/cortex/pkg/chunk/encoding"
+
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 // GzipLogChunk is a cortex encoding type for our chunks.
@@ -27,6 +29,8 @@ type Facade struct {
 	c          Chunk
 	blockSize  int
 	targetSize int
+	// volume of the chunk, as returned by Volume, nil if unknown.
+	volume []logproto.VolumeBucket
 	encoding.Chunk
 }
 
@@ -39,6 +43,17 @@ func NewFacade(c Chunk, blockSize, targetSize int) encoding.Chunk {
 	}
 }
 
+// NewFacadeWithVolume makes a new Facade of a chunk whose volume is known, so that it
+// can be indexed without decompressing the chunk.
+func NewFacadeWithVolume(c Chunk, blockSize, targetSize int, volume []logproto.VolumeBucket) encoding.Chunk {
+	return &Facade{
+		c:          c,
+		blockSize:  blockSize,
+		targetSize: targetSize,
+		volume:     volume,
+	}
+}
+
 // Marshal implements encoding.Chunk.
 func (f Facade) Marshal(w io.Writer) error {
 	if f.c == nil {
@@ -85,6 +100,11 @@ func (f Facade) LokiChunk() Chunk {
 	return f.c
 }
 
+// Volume returns the volume of the chunk given to NewFacadeWithVolume, nil if unknown.
+func (f Facade) Volume() []logproto.VolumeBucket {
+	return f.volume
+}
+
 // UncompressedSize is a helper function to hide the type assertion kludge when wanting the uncompressed size of the Cortex interface encoding.Chunk.
 func UncompressedSize(c encoding.Chunk) (int, bool) {
 	f, ok := c.(*Facade)
@@ -19,6 +19,9 @@ var (
 	ErrInvalidSize     = errors.New("invalid size")
 	ErrInvalidFlag     = errors.New("invalid flag")
 	ErrInvalidChecksum = errors.New("invalid chunk checksum")
+	// ErrStructuredMetadata is returned when appending an entry with structured metadata to a
+	// chunk which already has blocks in a format not supporting it.
+	ErrStructuredMetadata = errors.New("structured metadata not supported by the chunk format")
 )
 
 // Encoding is the identifier for a chunk encoding.
@@ -37,6 +40,8 @@ const (
 	EncLZ4_256k
 	EncLZ4_1M
 	EncLZ4_4M
+
+	EncZstd
 )
 
 var supportedEncoding = []Encoding{
@@ -44,6 +49,7 @@ var supportedEncoding = []Encoding{
 	EncGZIP,
 	EncLZ4_64k,
 	EncSnappy,
+	EncZstd,
 }
 
 func (e Encoding) String() string {
@@ -64,6 +70,8 @@ func (e Encoding) String() string {
 		return "lz4-4M"
 	case EncSnappy:
 		return "snappy"
+	case EncZstd:
+		return "zstd"
 	default:
 		return "unknown"
 	}
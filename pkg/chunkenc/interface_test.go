@@ -9,6 +9,7 @@ func TestParseEncoding(t *testing.T) {
 		wantErr bool
 	}{
 		{"gzip", EncGZIP, false},
+		{"zstd", EncZstd, false},
 		{"bad", 0, true},
 	}
 	for _, tt := range tests {
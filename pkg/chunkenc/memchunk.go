@@ -31,6 +31,12 @@ var (
 
 	chunkFormatV1 = byte(1)
 	chunkFormatV2 = byte(2)
+	// chunkFormatV3 adds the ID of the zstd dictionary to the header, 0 for none.
+	chunkFormatV3 = byte(3)
+	// chunkFormatV4 adds the structured metadata of the entries to the blocks. The chunks
+	// are written in this format only once they have structured metadata, so that they
+	// stay readable by the versions not supporting it.
+	chunkFormatV4 = byte(4)
 )
 
 // The table gets initialized with sync.Once but may still cause a race
@@ -63,9 +69,11 @@ type MemChunk struct {
 	// Current in-mem block being appended to.
 	head *headBlock
 
-	// the chunk format default to v2
+	// the chunk format: v2 for NewMemChunk and v3 for zstd chunks, upgraded to v4 only once the chunk
+	// has structured metadata (or EnableStructuredMetadata is called).
 	format   byte
 	encoding Encoding
+	dictID   uint32
 
 	readers ReaderPool
 	writers WriterPool
@@ -81,6 +89,7 @@ type block struct {
 	offset           int // The offset of the block in the chunk.
 	uncompressedSize int // Total uncompressed size in bytes when the chunk is cut.
 
+	format  byte
 	readers ReaderPool
 }
 
@@ -98,22 +107,22 @@ func (hb *headBlock) isEmpty() bool {
 	return len(hb.entries) == 0
 }
 
-func (hb *headBlock) append(ts int64, line string) error {
+func (hb *headBlock) append(ts int64, line string, metadata []logproto.LabelPair) error {
 	if !hb.isEmpty() && hb.maxt > ts {
 		return ErrOutOfOrder
 	}
 
-	hb.entries = append(hb.entries, entry{ts, line})
+	hb.entries = append(hb.entries, entry{ts, line, metadata})
 	if hb.mint == 0 || hb.mint > ts {
 		hb.mint = ts
 	}
 	hb.maxt = ts
-	hb.size += len(line)
+	hb.size += len(line) + metadataSize(metadata)
 
 	return nil
 }
 
-func (hb *headBlock) serialise(pool WriterPool) ([]byte, error) {
+func (hb *headBlock) serialise(pool WriterPool, format byte) ([]byte, error) {
 	inBuf := serializeBytesBufferPool.Get().(*bytes.Buffer)
 	defer func() {
 		inBuf.Reset()
@@ -128,10 +137,32 @@ func (hb *headBlock) serialise(pool WriterPool) ([]byte, error) {
 		n := binary.PutVarint(encBuf, logEntry.t)
 		inBuf.Write(encBuf[:n])
 
-		n = binary.PutUvarint(encBuf, uint64(len(logEntry.s)))
+		lineSize := uint64(len(logEntry.s))
+		if format >= chunkFormatV4 {
+			// chunk format v4 flags the entries with structured metadata in the lowest bit of the line length.
+			lineSize <<= 1
+			if len(logEntry.metadata) > 0 {
+				lineSize |= 1
+			}
+		}
+		n = binary.PutUvarint(encBuf, lineSize)
 		inBuf.Write(encBuf[:n])
 
 		inBuf.WriteString(logEntry.s)
+
+		if format >= chunkFormatV4 && len(logEntry.metadata) > 0 {
+			// followed by the structured metadata after the line.
+			n = binary.PutUvarint(encBuf, uint64(len(logEntry.metadata)))
+			inBuf.Write(encBuf[:n])
+			for _, l := range logEntry.metadata {
+				n = binary.PutUvarint(encBuf, uint64(len(l.Name)))
+				inBuf.Write(encBuf[:n])
+				inBuf.WriteString(l.Name)
+				n = binary.PutUvarint(encBuf, uint64(len(l.Value)))
+				inBuf.Write(encBuf[:n])
+				inBuf.WriteString(l.Value)
+			}
+		}
 	}
 
 	if _, err := compressedWriter.Write(inBuf.Bytes()); err != nil {
@@ -145,12 +176,26 @@ func (hb *headBlock) serialise(pool WriterPool) ([]byte, error) {
 }
 
 type entry struct {
-	t int64
-	s string
+	t        int64
+	s        string
+	metadata []logproto.LabelPair
+}
+
+// metadataSize returns the uncompressed size of structured metadata.
+func metadataSize(metadata []logproto.LabelPair) int {
+	size := 0
+	for _, l := range metadata {
+		size += len(l.Name) + len(l.Value)
+	}
+	return size
 }
 
-// NewMemChunk returns a new in-mem chunk.
+// NewMemChunk returns a new in-mem chunk. The zstd chunks use the default level without dictionary,
+// NewZstdMemChunk takes the level and the dictionary.
 func NewMemChunk(enc Encoding, blockSize, targetSize int) *MemChunk {
+	if enc == EncZstd {
+		return NewZstdMemChunk(ZstdOptions{}, blockSize, targetSize)
+	}
 	c := &MemChunk{
 		blockSize:  blockSize,  // The blockSize in bytes.
 		targetSize: targetSize, // Desired chunk size in compressed bytes
@@ -167,6 +212,33 @@ func NewMemChunk(enc Encoding, blockSize, targetSize int) *MemChunk {
 	return c
 }
 
+// NewZstdMemChunk returns a new in-mem chunk compressed with zstd.
+func NewZstdMemChunk(opts ZstdOptions, blockSize, targetSize int) *MemChunk {
+	pool := getZstdPool(opts.Level, opts.Dictionary)
+	c := &MemChunk{
+		blockSize:  blockSize,
+		targetSize: targetSize,
+		blocks:     []block{},
+
+		head:   &headBlock{},
+		format: chunkFormatV3,
+
+		encoding: EncZstd,
+		writers:  pool,
+		readers:  pool,
+	}
+	if opts.Dictionary != nil {
+		c.dictID = opts.Dictionary.ID()
+	}
+	return c
+}
+
+// EnableStructuredMetadata writes the chunk in the format supporting structured metadata
+// even if none of its entries has any. It must be called before any block is cut.
+func (c *MemChunk) EnableStructuredMetadata() {
+	c.format = chunkFormatV4
+}
+
 // NewByteChunk returns a MemChunk on the passed bytes.
 func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 	bc := &MemChunk{
@@ -196,6 +268,27 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 		}
 		bc.encoding = enc
 		bc.readers, bc.writers = getReaderPool(enc), getWriterPool(enc)
+	case chunkFormatV3, chunkFormatV4:
+		// format v3 has a byte for block encoding and the ID of the zstd dictionary,
+		// v4 has the same header.
+		enc := Encoding(db.byte())
+		dictID := db.uvarint64()
+		if db.err() != nil {
+			return nil, errors.Wrap(db.err(), "verifying encoding")
+		}
+		bc.encoding, bc.dictID = enc, uint32(dictID)
+		bc.readers, bc.writers = getReaderPool(enc), getWriterPool(enc)
+		if dictID != 0 {
+			if enc != EncZstd {
+				return nil, errors.Errorf("invalid dictionary %d for encoding %s", dictID, enc)
+			}
+			dict, err := GetDictionary(bc.dictID)
+			if err != nil {
+				return nil, err
+			}
+			pool := getZstdPool(0, dict)
+			bc.readers, bc.writers = pool, pool
+		}
 	default:
 		return nil, errors.Errorf("invalid version %d", version)
 	}
@@ -215,6 +308,7 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 
 	for i := 0; i < num; i++ {
 		blk := block{
+			format:  bc.format,
 			readers: bc.readers,
 		}
 		// Read #entries.
@@ -267,10 +361,14 @@ func (c *MemChunk) Bytes() ([]byte, error) {
 	// Write the header (magicNum + version).
 	eb.putBE32(magicNumber)
 	eb.putByte(c.format)
-	if c.format == chunkFormatV2 {
+	if c.format >= chunkFormatV2 {
 		// chunk format v2 has a byte for encoding.
 		eb.putByte(byte(c.encoding))
 	}
+	if c.format >= chunkFormatV3 {
+		// chunk format v3 has the ID of the zstd dictionary.
+		eb.putUvarint64(uint64(c.dictID))
+	}
 
 	n, err := buf.Write(eb.get())
 	if err != nil {
@@ -350,10 +448,13 @@ func (c *MemChunk) BlockCount() int {
 
 // SpaceFor implements Chunk.
 func (c *MemChunk) SpaceFor(e *logproto.Entry) bool {
+	if len(e.StructuredMetadata) > 0 && !c.supportsStructuredMetadata() {
+		return false
+	}
 	if c.targetSize > 0 {
 		// This is looking to see if the uncompressed lines will fit which is not
 		// a great check, but it will guarantee we are always under the target size
-		newHBSize := c.head.size + len(e.Line)
+		newHBSize := c.head.size + len(e.Line) + metadataSize(e.StructuredMetadata)
 		return (c.cutBlockSize + newHBSize) < c.targetSize
 	}
 	// if targetSize is not defined, default to the original behavior of fixed blocks per chunk
@@ -406,7 +507,14 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 		return ErrOutOfOrder
 	}
 
-	if err := c.head.append(entryTimestamp, entry.Line); err != nil {
+	if len(entry.StructuredMetadata) > 0 && c.format < chunkFormatV4 {
+		if !c.supportsStructuredMetadata() {
+			return ErrStructuredMetadata
+		}
+		c.format = chunkFormatV4
+	}
+
+	if err := c.head.append(entryTimestamp, entry.Line, entry.StructuredMetadata); err != nil {
 		return err
 	}
 
@@ -417,6 +525,12 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 	return nil
 }
 
+// supportsStructuredMetadata returns whether entries with structured metadata can be appended,
+// which requires switching to the chunk format v4 before any block is cut in an older format.
+func (c *MemChunk) supportsStructuredMetadata() bool {
+	return c.format >= chunkFormatV4 || len(c.blocks) == 0
+}
+
 // Close implements Chunk.
 // TODO: Fix this to check edge cases.
 func (c *MemChunk) Close() error {
@@ -429,12 +543,13 @@ func (c *MemChunk) cut() error {
 		return nil
 	}
 
-	b, err := c.head.serialise(c.writers)
+	b, err := c.head.serialise(c.writers, c.format)
 	if err != nil {
 		return err
 	}
 
 	c.blocks = append(c.blocks, block{
+		format:           c.format,
 		readers:          c.readers,
 		b:                b,
 		numEntries:       len(c.head.entries),
@@ -519,7 +634,7 @@ func (b block) Iterator(ctx context.Context, filter logql.LineFilter) iter.Entry
 	if len(b.b) == 0 {
 		return emptyIterator
 	}
-	return newBufferedIterator(ctx, b.readers, b.b, filter)
+	return newBufferedIterator(ctx, b.readers, b.b, b.format, filter)
 }
 
 func (b block) Offset() int {
@@ -550,8 +665,8 @@ func (hb *headBlock) iterator(ctx context.Context, mint, maxt int64, filter logq
 	chunkStats.HeadChunkLines += int64(len(hb.entries))
 	entries := make([]entry, 0, len(hb.entries))
 	for _, e := range hb.entries {
-		chunkStats.HeadChunkBytes += int64(len(e.s))
-		if filter == nil || filter.Filter([]byte(e.s)) {
+		chunkStats.HeadChunkBytes += int64(len(e.s) + metadataSize(e.metadata))
+		if filter == nil || logql.FilterEntry(filter, []byte(e.s), e.metadata) {
 			entries = append(entries, e)
 		}
 	}
@@ -587,8 +702,9 @@ func (li *listIterator) Entry() logproto.Entry {
 	cur := li.entries[li.cur]
 
 	return logproto.Entry{
-		Timestamp: time.Unix(0, cur.t),
-		Line:      cur.s,
+		Timestamp:          time.Unix(0, cur.t),
+		Line:               cur.s,
+		StructuredMetadata: cur.metadata,
 	}
 }
 
@@ -603,6 +719,7 @@ type bufferedIterator struct {
 	bufReader *bufio.Reader
 	reader    io.Reader
 	pool      ReaderPool
+	format    byte
 
 	cur logproto.Entry
 
@@ -616,7 +733,7 @@ type bufferedIterator struct {
 	filter logql.LineFilter
 }
 
-func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte, filter logql.LineFilter) *bufferedIterator {
+func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, filter logql.LineFilter) *bufferedIterator {
 	chunkStats := stats.GetChunkData(ctx)
 	chunkStats.CompressedBytes += int64(len(b))
 	return &bufferedIterator{
@@ -625,6 +742,7 @@ func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte, filter
 		reader:    nil, // will be initialized later
 		bufReader: nil, // will be initialized later
 		pool:      pool,
+		format:    format,
 		filter:    filter,
 		decBuf:    make([]byte, binary.MaxVarintLen64),
 	}
@@ -638,45 +756,51 @@ func (si *bufferedIterator) Next() bool {
 	}
 
 	for {
-		ts, line, ok := si.moveNext()
+		ts, line, metadata, ok := si.moveNext()
 		if !ok {
 			si.Close()
 			return false
 		}
 		// we decode always the line length and ts as varint
-		si.stats.DecompressedBytes += int64(len(line)) + 2*binary.MaxVarintLen64
+		si.stats.DecompressedBytes += int64(len(line)+metadataSize(metadata)) + 2*binary.MaxVarintLen64
 		si.stats.DecompressedLines++
-		if si.filter != nil && !si.filter.Filter(line) {
+		if si.filter != nil && !logql.FilterEntry(si.filter, line, metadata) {
 			continue
 		}
 		si.cur.Line = string(line)
 		si.cur.Timestamp = time.Unix(0, ts)
+		si.cur.StructuredMetadata = metadata
 		return true
 	}
 }
 
 // moveNext moves the buffer to the next entry
-func (si *bufferedIterator) moveNext() (int64, []byte, bool) {
+func (si *bufferedIterator) moveNext() (int64, []byte, []logproto.LabelPair, bool) {
 	ts, err := binary.ReadVarint(si.bufReader)
 	if err != nil {
 		if err != io.EOF {
 			si.err = err
 		}
-		return 0, nil, false
+		return 0, nil, nil, false
 	}
 
 	l, err := binary.ReadUvarint(si.bufReader)
 	if err != nil {
 		if err != io.EOF {
 			si.err = err
-			return 0, nil, false
+			return 0, nil, nil, false
 		}
 	}
+	hasMetadata := false
+	if si.format >= chunkFormatV4 {
+		hasMetadata = l&1 == 1
+		l >>= 1
+	}
 	lineSize := int(l)
 
 	if lineSize >= maxLineLength {
 		si.err = fmt.Errorf("line too long %d, maximum %d", lineSize, maxLineLength)
-		return 0, nil, false
+		return 0, nil, nil, false
 	}
 	// If the buffer is not yet initialize or too small, we get a new one.
 	if si.buf == nil || lineSize > cap(si.buf) {
@@ -687,7 +811,7 @@ func (si *bufferedIterator) moveNext() (int64, []byte, bool) {
 		si.buf = BytesBufferPool.Get(lineSize).([]byte)
 		if lineSize > cap(si.buf) {
 			si.err = fmt.Errorf("could not get a line buffer of size %d, actual %d", lineSize, cap(si.buf))
-			return 0, nil, false
+			return 0, nil, nil, false
 		}
 	}
 
@@ -695,17 +819,62 @@ func (si *bufferedIterator) moveNext() (int64, []byte, bool) {
 	n, err := si.bufReader.Read(si.buf[:lineSize])
 	if err != nil && err != io.EOF {
 		si.err = err
-		return 0, nil, false
+		return 0, nil, nil, false
 	}
 	for n < lineSize {
 		r, err := si.bufReader.Read(si.buf[n:lineSize])
 		if err != nil {
 			si.err = err
-			return 0, nil, false
+			return 0, nil, nil, false
 		}
 		n += r
 	}
-	return ts, si.buf[:lineSize], true
+	if !hasMetadata {
+		return ts, si.buf[:lineSize], nil, true
+	}
+	metadata, err := si.readMetadata()
+	if err != nil {
+		si.err = err
+		return 0, nil, nil, false
+	}
+	return ts, si.buf[:lineSize], metadata, true
+}
+
+// readMetadata reads the structured metadata following the line of an entry.
+func (si *bufferedIterator) readMetadata() ([]logproto.LabelPair, error) {
+	count, err := binary.ReadUvarint(si.bufReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading structured metadata")
+	}
+	metadata := make([]logproto.LabelPair, 0, count)
+	for i := uint64(0); i < count; i++ {
+		name, err := si.readString()
+		if err != nil {
+			return nil, err
+		}
+		value, err := si.readString()
+		if err != nil {
+			return nil, err
+		}
+		metadata = append(metadata, logproto.LabelPair{Name: name, Value: value})
+	}
+	return metadata, nil
+}
+
+// readString reads a length prefixed string.
+func (si *bufferedIterator) readString() (string, error) {
+	l, err := binary.ReadUvarint(si.bufReader)
+	if err != nil {
+		return "", errors.Wrap(err, "reading structured metadata")
+	}
+	if l >= maxLineLength {
+		return "", fmt.Errorf("structured metadata too long %d, maximum %d", l, maxLineLength)
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(si.bufReader, b); err != nil {
+		return "", errors.Wrap(err, "reading structured metadata")
+	}
+	return string(b), nil
 }
 
 func (si *bufferedIterator) Entry() logproto.Entry {
@@ -29,6 +29,7 @@ var testEncoding = []Encoding{
 	EncLZ4_1M,
 	EncLZ4_4M,
 	EncSnappy,
+	EncZstd,
 }
 
 var (
@@ -133,9 +134,9 @@ func TestBlock(t *testing.T) {
 
 func TestReadFormatV1(t *testing.T) {
 	c := NewMemChunk(EncGZIP, testBlockSize, testTargetSize)
-	fillChunk(c)
 	// overrides default v2 format
 	c.format = chunkFormatV1
+	fillChunk(c)
 
 	b, err := c.Bytes()
 	if err != nil {
@@ -219,6 +220,170 @@ func TestRoundtripV2(t *testing.T) {
 
 }
 
+func TestReadFormatV2(t *testing.T) {
+	c := NewMemChunk(EncSnappy, testBlockSize, testTargetSize)
+	// overrides default v2 format
+	c.format = chunkFormatV2
+	fillChunk(c)
+
+	b, err := c.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, chunkFormatV2, b[4])
+
+	r, err := NewByteChunk(b, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+	require.Equal(t, EncSnappy, r.Encoding())
+
+	it, err := r.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+	require.NoError(t, err)
+
+	i := int64(0)
+	for it.Next() {
+		require.Equal(t, i, it.Entry().Timestamp.UnixNano())
+		require.Equal(t, testdata.LogString(i), it.Entry().Line)
+		require.Nil(t, it.Entry().StructuredMetadata)
+		i++
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, int64(c.Size()), i)
+}
+
+func TestStructuredMetadata(t *testing.T) {
+	metadata := func(i int) []logproto.LabelPair {
+		switch i % 3 {
+		case 0:
+			return nil
+		case 1:
+			return []logproto.LabelPair{{Name: "trace_id", Value: fmt.Sprintf("%d", i)}}
+		default:
+			return []logproto.LabelPair{{Name: "trace_id", Value: fmt.Sprintf("%d", i)}, {Name: "user", Value: "bob"}}
+		}
+	}
+
+	for _, enc := range testEncoding {
+		t.Run(enc.String(), func(t *testing.T) {
+			c := NewMemChunk(enc, 1024, 0)
+			for i := 0; i < 100; i++ {
+				require.NoError(t, c.Append(&logproto.Entry{
+					Timestamp:          time.Unix(0, int64(i)),
+					Line:               testdata.LogString(int64(i)),
+					StructuredMetadata: metadata(i),
+				}))
+			}
+
+			assertEntries := func(c *MemChunk) {
+				it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+				require.NoError(t, err)
+				i := 0
+				for it.Next() {
+					require.Equal(t, testdata.LogString(int64(i)), it.Entry().Line)
+					require.Equal(t, metadata(i), it.Entry().StructuredMetadata)
+					i++
+				}
+				require.NoError(t, it.Close())
+				require.Equal(t, 100, i)
+
+				selector, err := logql.ParseLogSelector(`{app="foo"} | user="bob"`)
+				require.NoError(t, err)
+				filter, err := selector.Filter()
+				require.NoError(t, err)
+				it, err = c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, filter)
+				require.NoError(t, err)
+				n := 0
+				for it.Next() {
+					require.Equal(t, int64(2), it.Entry().Timestamp.UnixNano()%3)
+					n++
+				}
+				require.NoError(t, it.Close())
+				require.Equal(t, 33, n)
+			}
+
+			// both the head block and the cut blocks.
+			require.NotEmpty(t, c.blocks)
+			require.False(t, c.head.isEmpty())
+			assertEntries(c)
+
+			b, err := c.Bytes()
+			require.NoError(t, err)
+			require.Equal(t, chunkFormatV4, b[4])
+			r, err := NewByteChunk(b, 1024, 0)
+			require.NoError(t, err)
+			assertEntries(r)
+		})
+	}
+}
+
+func TestDefaultFormatReadableByOldReaders(t *testing.T) {
+	for _, enc := range testEncoding {
+		t.Run(enc.String(), func(t *testing.T) {
+			c := NewMemChunk(enc, testBlockSize, testTargetSize)
+			fillChunk(c)
+			b, err := c.Bytes()
+			require.NoError(t, err)
+
+			expectedFormat := chunkFormatV2
+			if enc == EncZstd {
+				expectedFormat = chunkFormatV3
+			}
+			require.Equal(t, expectedFormat, b[4])
+
+			// decode the blocks with the entries layout of the readers before the format v4.
+			i := int64(0)
+			for _, blk := range c.blocks {
+				r := blk.readers.GetReader(bytes.NewReader(b[blk.offset : blk.offset+len(blk.b)]))
+				buf := new(bytes.Buffer)
+				_, err := buf.ReadFrom(r)
+				require.NoError(t, err)
+				blk.readers.PutReader(r)
+
+				for buf.Len() > 0 {
+					ts, err := binary.ReadVarint(buf)
+					require.NoError(t, err)
+					l, err := binary.ReadUvarint(buf)
+					require.NoError(t, err)
+					require.Equal(t, i, ts)
+					require.Equal(t, testdata.LogString(i), string(buf.Next(int(l))))
+					i++
+				}
+			}
+			require.Equal(t, int64(c.Size()), i)
+		})
+	}
+}
+
+func TestStructuredMetadataFormat(t *testing.T) {
+	entry := func(i int64, metadata []logproto.LabelPair) *logproto.Entry {
+		return &logproto.Entry{Timestamp: time.Unix(0, i), Line: testdata.LogString(i), StructuredMetadata: metadata}
+	}
+	metadata := []logproto.LabelPair{{Name: "trace_id", Value: "1"}}
+
+	// switches to the format v4 while no block is cut.
+	c := NewMemChunk(EncGZIP, 1024, 0)
+	require.NoError(t, c.Append(entry(0, nil)))
+	require.True(t, c.SpaceFor(entry(1, metadata)))
+	require.NoError(t, c.Append(entry(1, metadata)))
+	require.Equal(t, chunkFormatV4, c.format)
+
+	// can't once a block is cut in an older format.
+	c = NewMemChunk(EncGZIP, 1024, 0)
+	require.NoError(t, c.Append(entry(0, nil)))
+	require.NoError(t, c.cut())
+	require.False(t, c.SpaceFor(entry(1, metadata)))
+	require.Equal(t, ErrStructuredMetadata, c.Append(entry(1, metadata)))
+	require.True(t, c.SpaceFor(entry(1, nil)))
+
+	// unless enabled from the start.
+	c = NewMemChunk(EncGZIP, 1024, 0)
+	c.EnableStructuredMetadata()
+	require.NoError(t, c.Append(entry(0, nil)))
+	require.NoError(t, c.cut())
+	require.True(t, c.SpaceFor(entry(1, metadata)))
+	require.NoError(t, c.Append(entry(1, metadata)))
+	b, err := c.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, chunkFormatV4, b[4])
+}
+
 func TestSerialization(t *testing.T) {
 	for _, enc := range testEncoding {
 		t.Run(enc.String(), func(t *testing.T) {
@@ -626,7 +791,7 @@ func BenchmarkHeadBlockIterator(b *testing.B) {
 			h := headBlock{}
 
 			for i := 0; i < j; i++ {
-				if err := h.append(int64(i), "this is the append string"); err != nil {
+				if err := h.append(int64(i), "this is the append string", nil); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -35,6 +35,8 @@ var (
 
 	// Snappy is the snappy compression pool
 	Snappy SnappyPool
+	// Zstd is the zstandard compression pool, with the default level and no dictionary
+	Zstd ZstdPool
 	// Noop is the no compression pool
 	Noop NoopPool
 
@@ -72,6 +74,8 @@ func getReaderPool(enc Encoding) ReaderPool {
 		return &Lz4_4M
 	case EncSnappy:
 		return &Snappy
+	case EncZstd:
+		return &Zstd
 	case EncNone:
 		return &Noop
 	default:
@@ -0,0 +1,41 @@
+package chunkenc
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// VolumeBucketPeriod is the period covered by each bucket of the volume of a chunk.
+const VolumeBucketPeriod = time.Minute
+
+// Volume counts the lines and bytes of the entries of c between from and through,
+// by bucket of VolumeBucketPeriod. Only the non empty buckets are returned, oldest first.
+func Volume(ctx context.Context, c Chunk, from, through time.Time) ([]logproto.VolumeBucket, error) {
+	it, err := c.Iterator(ctx, from, through, logproto.FORWARD, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var buckets []logproto.VolumeBucket
+	for it.Next() {
+		e := it.Entry()
+		buckets = AppendVolume(buckets, &e)
+	}
+	return buckets, it.Error()
+}
+
+// AppendVolume counts an entry in the volume buckets of the entries appended before it,
+// which must not be newer, and returns the updated buckets.
+func AppendVolume(buckets []logproto.VolumeBucket, e *logproto.Entry) []logproto.VolumeBucket {
+	ts := e.Timestamp.Truncate(VolumeBucketPeriod).UnixNano() / int64(time.Millisecond)
+	if n := len(buckets); n == 0 || buckets[n-1].TimestampMs != ts {
+		buckets = append(buckets, logproto.VolumeBucket{TimestampMs: ts})
+	}
+	b := &buckets[len(buckets)-1]
+	b.Lines++
+	b.Bytes += uint64(len(e.Line))
+	return buckets
+}
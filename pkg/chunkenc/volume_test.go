@@ -0,0 +1,40 @@
+package chunkenc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestVolume(t *testing.T) {
+	c := NewMemChunk(EncSnappy, testBlockSize, 0)
+	for _, e := range []*logproto.Entry{
+		{Timestamp: time.Unix(0, 0), Line: "a"},
+		{Timestamp: time.Unix(30, 0), Line: "bb"},
+		{Timestamp: time.Unix(59, 0), Line: "ccc"},
+		{Timestamp: time.Unix(180, 0), Line: "dddd"},
+		{Timestamp: time.Unix(200, 0), Line: "eeeee"},
+	} {
+		require.NoError(t, c.Append(e))
+	}
+	require.NoError(t, c.cut())
+	require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(239, 0), Line: "ffffff"}))
+
+	buckets, err := Volume(context.Background(), c, time.Unix(0, 0), time.Unix(300, 0))
+	require.NoError(t, err)
+	require.Equal(t, []logproto.VolumeBucket{
+		{TimestampMs: 0, Lines: 3, Bytes: 6},
+		{TimestampMs: 180000, Lines: 3, Bytes: 15},
+	}, buckets)
+
+	buckets, err = Volume(context.Background(), c, time.Unix(30, 0), time.Unix(190, 0))
+	require.NoError(t, err)
+	require.Equal(t, []logproto.VolumeBucket{
+		{TimestampMs: 0, Lines: 2, Bytes: 5},
+		{TimestampMs: 180000, Lines: 1, Bytes: 4},
+	}, buckets)
+}
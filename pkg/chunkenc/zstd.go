@@ -0,0 +1,243 @@
+package chunkenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// DictionaryFileExtension is the extension of the dictionary files loaded by LoadDictionaries.
+const DictionaryFileExtension = ".dict"
+
+// ErrUnknownDictionary is returned when a chunk was compressed with a dictionary which is not registered.
+var ErrUnknownDictionary = errors.New("unknown zstd dictionary")
+
+// dictionaryMagic starts the zstd dictionaries, followed by their ID, as described in
+// https://github.com/facebook/zstd/blob/master/doc/zstd_compression_format.md#dictionary-format
+var dictionaryMagic = []byte{0x37, 0xa4, 0x30, 0xec}
+
+var (
+	dictionariesMtx sync.RWMutex
+	dictionaries    = map[uint32]*Dictionary{}
+
+	zstdPoolsMtx sync.Mutex
+	zstdPools    = map[zstdPoolKey]*ZstdPool{}
+)
+
+// Dictionary is a zstd dictionary shared by the chunks of a tenant.
+// Its ID is recorded in the header of the chunks compressed with it.
+type Dictionary struct {
+	id  uint32
+	raw []byte
+}
+
+// NewDictionary parses a zstd dictionary, as trained by `zstd --train --dictID=<id>`.
+func NewDictionary(b []byte) (*Dictionary, error) {
+	if len(b) < 8 || !bytes.Equal(b[:4], dictionaryMagic) {
+		return nil, errors.New("parsing zstd dictionary: invalid magic number")
+	}
+	id := binary.LittleEndian.Uint32(b[4:8])
+	if id == 0 {
+		return nil, errors.New("the zstd dictionary ID 0 is reserved")
+	}
+	// the decoder parses the entropy tables and the content of the dictionary.
+	d, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderDicts(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing zstd dictionary")
+	}
+	d.Close()
+	return &Dictionary{id: id, raw: b}, nil
+}
+
+// ID returns the ID of the dictionary.
+func (d *Dictionary) ID() uint32 {
+	return d.id
+}
+
+// Bytes returns the serialized dictionary.
+func (d *Dictionary) Bytes() []byte {
+	return d.raw
+}
+
+// RegisterDictionary makes a dictionary available to compress and decompress chunks.
+func RegisterDictionary(d *Dictionary) {
+	dictionariesMtx.Lock()
+	defer dictionariesMtx.Unlock()
+	dictionaries[d.id] = d
+}
+
+// GetDictionary returns the registered dictionary with the given ID.
+func GetDictionary(id uint32) (*Dictionary, error) {
+	dictionariesMtx.RLock()
+	defer dictionariesMtx.RUnlock()
+	d, ok := dictionaries[id]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownDictionary, "id %d", id)
+	}
+	return d, nil
+}
+
+// LoadDictionaries registers the dictionaries stored in the files of dir with the DictionaryFileExtension.
+func LoadDictionaries(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+DictionaryFileExtension))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		d, err := NewDictionary(b)
+		if err != nil {
+			return errors.Wrapf(err, "loading %s", file)
+		}
+		RegisterDictionary(d)
+	}
+	return nil
+}
+
+// ZstdOptions are the options of the chunks compressed with zstd.
+type ZstdOptions struct {
+	// Level is the zstd compression level, from 1 to 22. 0 uses the default level.
+	Level int
+	// Dictionary is the optional dictionary to compress with.
+	Dictionary *Dictionary
+}
+
+type zstdPoolKey struct {
+	level  zstd.EncoderLevel
+	dictID uint32
+}
+
+// getZstdPool returns the pool for the level and the optional dictionary.
+func getZstdPool(level int, dict *Dictionary) *ZstdPool {
+	if level == 0 && dict == nil {
+		return &Zstd
+	}
+	encoderLevel := zstd.SpeedDefault
+	if level > 0 {
+		encoderLevel = zstd.EncoderLevelFromZstd(level)
+	}
+	key := zstdPoolKey{level: encoderLevel}
+	if dict != nil {
+		key.dictID = dict.id
+	}
+
+	zstdPoolsMtx.Lock()
+	defer zstdPoolsMtx.Unlock()
+	pool, ok := zstdPools[key]
+	if !ok {
+		pool = &ZstdPool{level: encoderLevel, dict: dict}
+		zstdPools[key] = pool
+	}
+	return pool
+}
+
+// ZstdPool is a zstandard compression pool, optionally compressing with a dictionary.
+//
+// The zstd decoders run background goroutines until they are closed, so they are not pooled:
+// the blocks are decompressed at once by a decoder shared by the readers of the pool.
+type ZstdPool struct {
+	writers sync.Pool
+	level   zstd.EncoderLevel
+	dict    *Dictionary
+
+	decoderOnce sync.Once
+	decoder     *zstd.Decoder
+	decoderErr  error
+}
+
+func (pool *ZstdPool) getDecoder() (*zstd.Decoder, error) {
+	pool.decoderOnce.Do(func() {
+		// the decoder decompresses as many blocks concurrently as there are CPUs.
+		opts := []zstd.DOption{zstd.WithDecoderLowmem(true)}
+		if pool.dict != nil {
+			opts = append(opts, zstd.WithDecoderDicts(pool.dict.raw))
+		}
+		pool.decoder, pool.decoderErr = zstd.NewReader(nil, opts...)
+	})
+	return pool.decoder, pool.decoderErr
+}
+
+// GetReader returns a reader of the decompressed content of src. The errors are returned by the
+// reader.
+func (pool *ZstdPool) GetReader(src io.Reader) io.Reader {
+	decoder, err := pool.getDecoder()
+	if err != nil {
+		return errReader{err}
+	}
+	var compressed []byte
+	if buf, ok := src.(*bytes.Buffer); ok {
+		compressed = buf.Bytes()
+	} else if compressed, err = ioutil.ReadAll(src); err != nil {
+		return errReader{err}
+	}
+	b, err := decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return errReader{err}
+	}
+	return bytes.NewReader(b)
+}
+
+// PutReader places back in the pool a CompressionReader
+func (pool *ZstdPool) PutReader(reader io.Reader) {}
+
+// GetWriter gets or creates a new CompressionWriter and reset it to write to dst. The errors are
+// returned by the writer.
+func (pool *ZstdPool) GetWriter(dst io.Writer) io.WriteCloser {
+	if w := pool.writers.Get(); w != nil {
+		writer := w.(*zstd.Encoder)
+		writer.Reset(dst)
+		return writer
+	}
+
+	level := pool.level
+	if level == 0 {
+		level = zstd.SpeedDefault
+	}
+	opts := []zstd.EOption{zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1)}
+	if pool.dict != nil {
+		opts = append(opts, zstd.WithEncoderDict(pool.dict.raw))
+	}
+	w, err := zstd.NewWriter(dst, opts...)
+	if err != nil {
+		return errWriter{err}
+	}
+	return w
+}
+
+// PutWriter places back in the pool a CompressionWriter
+func (pool *ZstdPool) PutWriter(writer io.WriteCloser) {
+	if _, ok := writer.(*zstd.Encoder); ok {
+		pool.writers.Put(writer)
+	}
+}
+
+// errReader is a reader returning an error.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// errWriter is a writer returning an error.
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func (w errWriter) Close() error {
+	return w.err
+}
@@ -0,0 +1,178 @@
+package chunkenc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/chunkenc/testdata"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// loadTestDictionary loads the dictionary of testdata, trained from the lines of testdata.LogString
+// with `zstd --train --maxdict=16384`, with the given ID.
+func loadTestDictionary(t testing.TB, id uint32) *Dictionary {
+	b, err := ioutil.ReadFile("testdata/zstd.dict")
+	require.NoError(t, err)
+	binary.LittleEndian.PutUint32(b[4:8], id)
+	dict, err := NewDictionary(b)
+	require.NoError(t, err)
+	return dict
+}
+
+func TestZstdDictionaryRoundtrip(t *testing.T) {
+	dict := loadTestDictionary(t, 42)
+	require.Equal(t, uint32(42), dict.ID())
+	RegisterDictionary(dict)
+
+	c := NewZstdMemChunk(ZstdOptions{Level: 9, Dictionary: dict}, 4*1024, 0)
+	inserted := fillChunk(c)
+	b, err := c.Bytes()
+	require.NoError(t, err)
+
+	plain := NewMemChunk(EncZstd, 4*1024, 0)
+	fillChunk(plain)
+	require.Less(t, c.CompressedSize(), plain.CompressedSize())
+
+	bc, err := NewByteChunk(b, 4*1024, 0)
+	require.NoError(t, err)
+	require.Equal(t, EncZstd, bc.Encoding())
+	require.Equal(t, uint32(42), bc.dictID)
+
+	it, err := bc.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, inserted*int64(time.Hour)), logproto.FORWARD, nil)
+	require.NoError(t, err)
+	i := int64(0)
+	for it.Next() {
+		require.Equal(t, time.Unix(0, i), it.Entry().Timestamp)
+		require.Equal(t, testdata.LogString(i), it.Entry().Line)
+		i++
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, int64(c.Size()), i)
+}
+
+func TestZstdUnknownDictionary(t *testing.T) {
+	dict := loadTestDictionary(t, 43)
+
+	c := NewZstdMemChunk(ZstdOptions{Dictionary: dict}, testBlockSize, 0)
+	fillChunk(c)
+	b, err := c.Bytes()
+	require.NoError(t, err)
+
+	_, err = NewByteChunk(b, testBlockSize, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrUnknownDictionary.Error())
+}
+
+func TestLoadDictionaries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionaries")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dict := loadTestDictionary(t, 44)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "44"+DictionaryFileExtension), dict.Bytes(), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0644))
+
+	require.NoError(t, LoadDictionaries(dir))
+	loaded, err := GetDictionary(44)
+	require.NoError(t, err)
+	require.Equal(t, dict.Bytes(), loaded.Bytes())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad"+DictionaryFileExtension), []byte("not a dictionary"), 0644))
+	require.Error(t, LoadDictionaries(dir))
+}
+
+func TestZstdPoolReaders(t *testing.T) {
+	pool := getZstdPool(0, loadTestDictionary(t, 46))
+
+	var compressed bytes.Buffer
+	w := pool.GetWriter(&compressed)
+	_, err := w.Write([]byte(testdata.LogString(0)))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	pool.PutWriter(w)
+
+	// the readers share the decoder of the pool, which starts its goroutines once.
+	_, err = ioutil.ReadAll(pool.GetReader(bytes.NewReader(compressed.Bytes())))
+	require.NoError(t, err)
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		r := pool.GetReader(bytes.NewBuffer(compressed.Bytes()))
+		b, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, testdata.LogString(0), string(b))
+		pool.PutReader(r)
+	}
+	require.Equal(t, goroutines, runtime.NumGoroutine())
+
+	// the errors are returned by the reader.
+	_, err = ioutil.ReadAll(pool.GetReader(bytes.NewBufferString("not zstd")))
+	require.Error(t, err)
+}
+
+var zstdBenchmarks = []struct {
+	name string
+	opts func(b *testing.B) ZstdOptions
+}{
+	{"default", func(*testing.B) ZstdOptions { return ZstdOptions{} }},
+	{"level-1", func(*testing.B) ZstdOptions { return ZstdOptions{Level: 1} }},
+	{"level-9", func(*testing.B) ZstdOptions { return ZstdOptions{Level: 9} }},
+	{"dictionary", func(b *testing.B) ZstdOptions { return ZstdOptions{Dictionary: loadTestDictionary(b, 45)} }},
+}
+
+// BenchmarkCompressionRatio reports the compression ratio of the encodings on small blocks,
+// for which a dictionary matters the most.
+func BenchmarkCompressionRatio(b *testing.B) {
+	report := func(b *testing.B, newChunk func() *MemChunk) {
+		b.ResetTimer()
+		var c *MemChunk
+		for n := 0; n < b.N; n++ {
+			c = newChunk()
+			fillChunk(c)
+		}
+		b.ReportMetric(float64(c.UncompressedSize())/float64(c.CompressedSize()), "ratio")
+	}
+	for _, blockSize := range []int{4 * 1024, testBlockSize} {
+		for _, enc := range testEncoding {
+			b.Run(fmt.Sprintf("%s-%d", enc, blockSize), func(b *testing.B) {
+				report(b, func() *MemChunk { return NewMemChunk(enc, blockSize, testTargetSize) })
+			})
+		}
+		for _, bench := range zstdBenchmarks {
+			b.Run(fmt.Sprintf("zstd-%s-%d", bench.name, blockSize), func(b *testing.B) {
+				opts := bench.opts(b)
+				report(b, func() *MemChunk { return NewZstdMemChunk(opts, blockSize, testTargetSize) })
+			})
+		}
+	}
+}
+
+func BenchmarkZstdRead(b *testing.B) {
+	for _, bench := range zstdBenchmarks {
+		b.Run(bench.name, func(b *testing.B) {
+			opts := bench.opts(b)
+			c := NewZstdMemChunk(opts, 4*1024, testTargetSize)
+			size := fillChunk(c)
+			require.NoError(b, c.Close())
+			b.SetBytes(size)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Now(), logproto.FORWARD, nil)
+				require.NoError(b, err)
+				for it.Next() {
+					_ = it.Entry()
+				}
+				require.NoError(b, it.Close())
+			}
+		})
+	}
+}
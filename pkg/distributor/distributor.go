@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -50,6 +51,11 @@ var (
 		Name:      "distributor_lines_received_total",
 		Help:      "The total number of lines received per tenant",
 	}, []string{"tenant"})
+	streamsSharded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "distributor_streams_sharded_total",
+		Help:      "The total number of pushed streams split into shards for exceeding the desired rate per tenant",
+	}, []string{"tenant"})
 )
 
 // Config for a Distributor.
@@ -85,6 +91,9 @@ type Distributor struct {
 
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
+
+	// Rates of the pushed streams, used to shard the streams above the desired rate.
+	streamRates *streamRates
 }
 
 // New a distributor creates.
@@ -128,6 +137,7 @@ func New(cfg Config, clientCfg client.Config, ingestersRing ring.ReadRing, overr
 		validator:            validator,
 		pool:                 cortex_distributor.NewPool(clientCfg.PoolConfig, ingestersRing, factory, cortex_util.Logger),
 		ingestionRateLimiter: limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		streamRates:          newStreamRates(),
 	}
 
 	servs = append(servs, d.pool)
@@ -147,11 +157,18 @@ func (d *Distributor) starting(ctx context.Context) error {
 }
 
 func (d *Distributor) running(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return nil
-	case err := <-d.subservicesWatcher.Chan():
-		return errors.Wrap(err, "distributor subservice failed")
+	ticker := time.NewTicker(streamRateWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-d.subservicesWatcher.Chan():
+			return errors.Wrap(err, "distributor subservice failed")
+		case now := <-ticker.C:
+			d.streamRates.cleanup(now)
+		}
 	}
 }
 
@@ -166,6 +183,32 @@ type streamTracker struct {
 	maxFailures int
 	succeeded   int32
 	failed      int32
+
+	// index of the pushed stream and of its entries the tracked stream is made of.
+	pushed  int
+	entries []int
+
+	mtx sync.Mutex
+	// result of the ingester which accepted the most entries, nil if the ingesters
+	// didn't report any.
+	result *logproto.StreamPushResult
+}
+
+// observe records the result of the push of the stream to an ingester.
+func (s *streamTracker) observe(result *logproto.StreamPushResult) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.result == nil || result.Accepted > s.result.Accepted {
+		s.result = result
+	}
+}
+
+func (s *streamTracker) getResult() *logproto.StreamPushResult {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.result
 }
 
 // TODO taken from Cortex, see if we can refactor out an usable interface.
@@ -195,6 +238,13 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	bytesIngested.WithLabelValues(userID).Add(float64(bytesCount))
 	linesIngested.WithLabelValues(userID).Add(float64(lineCount))
 
+	// The response reports the accepted and rejected entries of each pushed stream,
+	// along with the index of the first entry which can be retried.
+	resp := &logproto.PushResponse{
+		Streams: make([]logproto.StreamPushResult, len(req.Streams)),
+	}
+	retryFrom := make([]int, len(req.Streams))
+
 	// First we flatten out the request into a list of samples.
 	// We use the heuristic of 1 sample per TS to size the array.
 	// We also work out the hash value at the same time.
@@ -203,44 +253,73 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	var validationErr error
 	validatedSamplesSize := 0
 	validatedSamplesCount := 0
+	now := time.Now()
 
-	for _, stream := range req.Streams {
-		if err := d.validator.ValidateLabels(userID, stream); err != nil {
+	for i, stream := range req.Streams {
+		result := &resp.Streams[i]
+		result.Labels = stream.Labels
+		retryFrom[i] = len(stream.Entries)
+
+		if reason, err := d.validator.ValidateLabels(userID, stream); err != nil {
 			validationErr = err
+			result.Reject(reason, len(stream.Entries), false, errorMessage(err))
 			continue
 		}
 
 		entries := make([]logproto.Entry, 0, len(stream.Entries))
-		for _, entry := range stream.Entries {
-			if err := d.validator.ValidateEntry(userID, stream.Labels, entry); err != nil {
+		indexes := make([]int, 0, len(stream.Entries))
+		streamSize := 0
+		for j, entry := range stream.Entries {
+			if reason, err := d.validator.ValidateEntry(userID, stream.Labels, entry); err != nil {
 				validationErr = err
+				result.Reject(reason, 1, false, errorMessage(err))
 				continue
 			}
 			entries = append(entries, entry)
-			validatedSamplesSize += len(entry.Line)
-			validatedSamplesCount++
+			indexes = append(indexes, j)
+			streamSize += len(entry.Line)
 		}
+		validatedSamplesSize += streamSize
+		validatedSamplesCount += len(entries)
 
 		if len(entries) == 0 {
 			continue
 		}
 		stream.Entries = entries
-		keys = append(keys, util.TokenFor(userID, stream.Labels))
-		streams = append(streams, streamTracker{
-			stream: stream,
-		})
+
+		shards, err := d.shardStream(userID, stream, streamSize, now)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		for _, shard := range shards {
+			tracked := make([]int, len(shard.indexes))
+			for k, j := range shard.indexes {
+				tracked[k] = indexes[j]
+			}
+			keys = append(keys, util.TokenFor(userID, shard.stream.Labels))
+			streams = append(streams, streamTracker{
+				stream:  shard.stream,
+				pushed:  i,
+				entries: tracked,
+			})
+		}
 	}
 
 	if len(streams) == 0 {
-		return &logproto.PushResponse{}, validationErr
+		return resp, validationErr
 	}
 
-	now := time.Now()
 	if !d.ingestionRateLimiter.AllowN(now, userID, validatedSamplesSize) {
 		// Return a 429 to indicate to the client they are being rate limited
 		validation.DiscardedSamples.WithLabelValues(validation.RateLimited, userID).Add(float64(validatedSamplesCount))
 		validation.DiscardedBytes.WithLabelValues(validation.RateLimited, userID).Add(float64(validatedSamplesSize))
-		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.RateLimitedErrorMsg(int(d.ingestionRateLimiter.Limit(now, userID)), validatedSamplesCount, validatedSamplesSize))
+		msg := validation.RateLimitedErrorMsg(int(d.ingestionRateLimiter.Limit(now, userID)), validatedSamplesCount, validatedSamplesSize)
+		for i := range streams {
+			resp.Streams[streams[i].pushed].Reject(validation.RateLimited, len(streams[i].entries), true, msg)
+			retryFrom[streams[i].pushed] = minInt(retryFrom[streams[i].pushed], streams[i].entries[0])
+		}
+		setRetryable(req, resp, retryFrom)
+		return resp, httpgrpc.Errorf(http.StatusTooManyRequests, msg)
 	}
 
 	const maxExpectedReplicationSet = 5 // typical replication factor 3 plus one for inactive plus one for luck
@@ -283,15 +362,104 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	case err := <-tracker.err:
 		return nil, err
 	case <-tracker.done:
-		return &logproto.PushResponse{}, validationErr
+		return pushResponse(req, resp, streams, retryFrom, validationErr)
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
 
+// pushResponse adds the results reported by the ingesters to the response and returns the error
+// describing the rejected entries, if any. The status of the error is 429 when some entries can
+// be retried, 400 otherwise.
+func pushResponse(req *logproto.PushRequest, resp *logproto.PushResponse, streams []streamTracker, retryFrom []int, validationErr error) (*logproto.PushResponse, error) {
+	for i := range streams {
+		s := &streams[i]
+		pushed := &resp.Streams[s.pushed]
+
+		result := s.getResult()
+		if result == nil {
+			pushed.Accepted += uint32(len(s.entries))
+			continue
+		}
+		pushed.Accepted += result.Accepted
+		for _, r := range result.Rejections {
+			pushed.Reject(r.Reason, int(r.Count), r.Retryable, r.Error)
+		}
+		// The entries of sharded streams are interleaved, the retryable entries of the
+		// pushed stream start with the first retryable entry of any of its shards.
+		if n := int(result.Retryable); n > 0 && n <= len(s.entries) {
+			retryFrom[s.pushed] = minInt(retryFrom[s.pushed], s.entries[len(s.entries)-n])
+		}
+	}
+	setRetryable(req, resp, retryFrom)
+
+	rejection := resp.Rejection()
+	switch {
+	case rejection == nil:
+		return resp, nil
+	case rejection.Retryable:
+		return resp, httpgrpc.Errorf(http.StatusTooManyRequests, rejection.Error)
+	case validationErr != nil:
+		return resp, validationErr
+	default:
+		return resp, httpgrpc.Errorf(http.StatusBadRequest, rejection.Error)
+	}
+}
+
+// setRetryable sets the number of trailing entries of each pushed stream which can be retried.
+func setRetryable(req *logproto.PushRequest, resp *logproto.PushResponse, retryFrom []int) {
+	for i := range resp.Streams {
+		resp.Streams[i].Retryable = uint32(len(req.Streams[i].Entries) - retryFrom[i])
+	}
+}
+
+// errorMessage returns the body of an http error, or the error message for other errors.
+func errorMessage(err error) string {
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return string(resp.Body)
+	}
+	return err.Error()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// shardStream splits the stream into shards when the desired rate of the tenant is exceeded,
+// so that a single stream is spread across more ingesters.
+func (d *Distributor) shardStream(userID string, stream logproto.Stream, size int, now time.Time) ([]streamShard, error) {
+	desiredRate := d.validator.ShardStreamsDesiredRate(userID)
+	if desiredRate <= 0 {
+		return shardStream(stream, 1, 0)
+	}
+
+	rate, next := d.streamRates.observe(userID+"\xff"+stream.Labels, now, size, len(stream.Entries))
+	// Every distributor only receives its part of the stream, so with the global strategy
+	// the rate is estimated from the number of distributors.
+	if d.distributorsRing != nil {
+		if n := d.distributorsRing.HealthyInstancesCount(); n > 0 {
+			rate *= float64(n)
+		}
+	}
+	shards := streamShards(rate, desiredRate)
+	if shards > 1 {
+		streamsSharded.WithLabelValues(userID).Inc()
+	}
+	return shardStream(stream, shards, next)
+}
+
 // TODO taken from Cortex, see if we can refactor out an usable interface.
 func (d *Distributor) sendSamples(ctx context.Context, ingester ring.IngesterDesc, streamTrackers []*streamTracker, pushTracker *pushTracker) {
-	err := d.sendSamplesErr(ctx, ingester, streamTrackers)
+	resp, err := d.sendSamplesErr(ctx, ingester, streamTrackers)
+	// Ingesters not reporting results per stream accepted all the entries they didn't fail on.
+	if err == nil && resp != nil && len(resp.Streams) == len(streamTrackers) {
+		for i := range streamTrackers {
+			streamTrackers[i].observe(&resp.Streams[i])
+		}
+	}
 
 	// If we succeed, decrement each sample's pending count by one.  If we reach
 	// the required number of successful puts on this sample, then decrement the
@@ -322,10 +490,10 @@ func (d *Distributor) sendSamples(ctx context.Context, ingester ring.IngesterDes
 }
 
 // TODO taken from Cortex, see if we can refactor out an usable interface.
-func (d *Distributor) sendSamplesErr(ctx context.Context, ingester ring.IngesterDesc, streams []*streamTracker) error {
+func (d *Distributor) sendSamplesErr(ctx context.Context, ingester ring.IngesterDesc, streams []*streamTracker) (*logproto.PushResponse, error) {
 	c, err := d.pool.GetClientFor(ingester.Addr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := &logproto.PushRequest{
@@ -335,12 +503,16 @@ func (d *Distributor) sendSamplesErr(ctx context.Context, ingester ring.Ingester
 		req.Streams[i] = s.stream
 	}
 
-	_, err = c.(logproto.PusherClient).Push(ctx, req)
+	resp, err := c.(logproto.PusherClient).Push(ctx, req)
 	ingesterAppends.WithLabelValues(ingester.Addr).Inc()
 	if err != nil {
+		// the ingester rejected some entries and reported the result of every stream.
+		if resp, ok := logproto.PushResponseFromError(err); ok {
+			return resp, nil
+		}
 		ingesterAppendFailures.WithLabelValues(ingester.Addr).Inc()
 	}
-	return err
+	return resp, err
 }
 
 // Check implements the grpc healthcheck
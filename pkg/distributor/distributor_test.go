@@ -37,10 +37,16 @@ const (
 )
 
 var (
-	success = &logproto.PushResponse{}
-	ctx     = user.InjectOrgID(context.Background(), "test")
+	ctx = user.InjectOrgID(context.Background(), "test")
 )
 
+// makePushResponse returns the response to the push of a request made by makeWriteRequest.
+func makePushResponse(labels string, accepted, retryable int, rejections ...logproto.PushRejection) *logproto.PushResponse {
+	return &logproto.PushResponse{Streams: []logproto.StreamPushResult{
+		{Labels: labels, Accepted: uint32(accepted), Rejections: rejections, Retryable: uint32(retryable)},
+	}}
+}
+
 func TestDistributor(t *testing.T) {
 	ingestionRateLimit := 0.000096 // 100 Bytes/s limit
 
@@ -53,23 +59,30 @@ func TestDistributor(t *testing.T) {
 	}{
 		{
 			lines:            10,
-			expectedResponse: success,
+			expectedResponse: makePushResponse(`{foo="bar"}`, 10, 0),
 		},
 		{
-			lines:         100,
+			lines: 100,
+			expectedResponse: makePushResponse(`{foo="bar"}`, 0, 100, logproto.PushRejection{
+				Reason: validation.RateLimited, Count: 100, Retryable: true, Error: validation.RateLimitedErrorMsg(100, 100, 1000),
+			}),
 			expectedError: httpgrpc.Errorf(http.StatusTooManyRequests, validation.RateLimitedErrorMsg(100, 100, 1000)),
 		},
 		{
-			lines:            100,
-			maxLineSize:      1,
-			expectedResponse: success,
-			expectedError:    httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg(1, 10, "{foo=\"bar\"}")),
+			lines:       100,
+			maxLineSize: 1,
+			expectedResponse: makePushResponse(`{foo="bar"}`, 0, 0, logproto.PushRejection{
+				Reason: validation.LineTooLong, Count: 100, Error: validation.LineTooLongErrorMsg(1, 10, "{foo=\"bar\"}"),
+			}),
+			expectedError: httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg(1, 10, "{foo=\"bar\"}")),
 		},
 		{
-			lines:            100,
-			mangleLabels:     true,
-			expectedResponse: success,
-			expectedError:    httpgrpc.Errorf(http.StatusBadRequest, "error parsing labels: parse error at line 1, col 4: literal not terminated"),
+			lines:        100,
+			mangleLabels: true,
+			expectedResponse: makePushResponse(`{ab"`, 0, 0, logproto.PushRejection{
+				Reason: validation.InvalidLabels, Count: 100, Error: "error parsing labels: parse error at line 1, col 4: literal not terminated",
+			}),
+			expectedError: httpgrpc.Errorf(http.StatusBadRequest, "error parsing labels: parse error at line 1, col 4: literal not terminated"),
 		},
 	} {
 		t.Run(fmt.Sprintf("[%d](samples=%v)", i, tc.lines), func(t *testing.T) {
@@ -182,10 +195,12 @@ func TestDistributor_PushIngestionRateLimiter(t *testing.T) {
 				response, err := distributors[0].Push(ctx, request)
 
 				if push.expectedError == nil {
-					assert.Equal(t, success, response)
+					assert.Equal(t, makePushResponse(`{foo="bar"}`, 1, 0), response)
 					assert.Nil(t, err)
 				} else {
-					assert.Nil(t, response)
+					assert.Equal(t, makePushResponse(`{foo="bar"}`, 0, 1, logproto.PushRejection{
+						Reason: validation.RateLimited, Count: 1, Retryable: true, Error: errorMessage(push.expectedError),
+					}), response)
 					assert.Equal(t, push.expectedError, err)
 				}
 			}
@@ -193,6 +208,42 @@ func TestDistributor_PushIngestionRateLimiter(t *testing.T) {
 	}
 }
 
+func TestPushResponse(t *testing.T) {
+	req := makeWriteRequest(6, 10)
+	msg := validation.StreamRateLimitedErrorMsg(10, `{foo="bar"}`, 1, 10)
+
+	// The stream is split in two shards, the second one having its last entry rate limited.
+	streams := []streamTracker{
+		{pushed: 0, entries: []int{0, 2, 4}},
+		{pushed: 0, entries: []int{1, 3, 5}},
+	}
+	streams[0].observe(&logproto.StreamPushResult{Accepted: 3})
+	streams[1].observe(&logproto.StreamPushResult{Accepted: 1, Rejections: []logproto.PushRejection{
+		{Reason: validation.StreamRateLimit, Count: 2, Retryable: true, Error: msg},
+	}, Retryable: 2})
+	// The result of the replica accepting the most entries is kept.
+	streams[1].observe(&logproto.StreamPushResult{Accepted: 2, Rejections: []logproto.PushRejection{
+		{Reason: validation.StreamRateLimit, Count: 1, Retryable: true, Error: msg},
+	}, Retryable: 1})
+
+	resp, err := pushResponse(req, makePushResponse(`{foo="bar"}`, 0, 0), streams, []int{6}, nil)
+	require.Equal(t, httpgrpc.Errorf(http.StatusTooManyRequests, msg), err)
+	require.Equal(t, makePushResponse(`{foo="bar"}`, 5, 1, logproto.PushRejection{
+		Reason: validation.StreamRateLimit, Count: 1, Retryable: true, Error: msg,
+	}), resp)
+
+	// Entries rejected by the ingesters for non retryable reasons return a 400.
+	streams = []streamTracker{{pushed: 0, entries: []int{0, 1, 2, 3, 4, 5}}}
+	streams[0].observe(&logproto.StreamPushResult{Accepted: 4, Rejections: []logproto.PushRejection{
+		{Reason: validation.OutOfOrder, Count: 2, Error: "out of order"},
+	}})
+	resp, err = pushResponse(req, makePushResponse(`{foo="bar"}`, 0, 0), streams, []int{6}, nil)
+	require.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, "out of order"), err)
+	require.Equal(t, makePushResponse(`{foo="bar"}`, 4, 0, logproto.PushRejection{
+		Reason: validation.OutOfOrder, Count: 2, Error: "out of order",
+	}), resp)
+}
+
 func prepare(t *testing.T, limits *validation.Limits, kvStore kv.Client) *Distributor {
 	var (
 		distributorConfig Config
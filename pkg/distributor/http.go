@@ -1,9 +1,13 @@
 package distributor
 
 import (
+	"encoding/json"
 	"math"
 	"net/http"
+	"strings"
 
+	"github.com/go-kit/kit/log/level"
+	"github.com/gogo/protobuf/proto"
 	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/cortexproject/cortex/pkg/util"
@@ -14,11 +18,19 @@ import (
 	unmarshal_legacy "github.com/grafana/loki/pkg/logql/unmarshal/legacy"
 )
 
-var contentType = http.CanonicalHeaderKey("Content-Type")
+var (
+	contentType = http.CanonicalHeaderKey("Content-Type")
+	accept      = http.CanonicalHeaderKey("Accept")
+)
 
-const applicationJSON = "application/json"
+const (
+	applicationJSON     = "application/json"
+	applicationProtobuf = "application/x-protobuf"
+)
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
+// When some entries are rejected and the client accepts a proto or JSON response, the
+// response body describes the accepted and rejected entries of each stream.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 	var req logproto.PushRequest
 
@@ -44,16 +56,48 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	_, err := d.Push(r.Context(), &req)
+	pushResp, err := d.Push(r.Context(), &req)
 	if err == nil {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
 	resp, ok := httpgrpc.HTTPResponseFromError(err)
-	if ok {
-		http.Error(w, string(resp.Body), int(resp.Code))
-	} else {
+	if !ok {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if pushResp != nil && writePushResponse(w, r.Header.Get(accept), pushResp, int(resp.Code)) {
+		return
+	}
+	http.Error(w, string(resp.Body), int(resp.Code))
+}
+
+// writePushResponse writes the push response in the format accepted by the client.
+// It returns false if the client accepts neither proto nor JSON responses.
+func writePushResponse(w http.ResponseWriter, accepted string, resp *logproto.PushResponse, code int) bool {
+	var (
+		body []byte
+		err  error
+	)
+	switch {
+	case strings.Contains(accepted, applicationProtobuf):
+		accepted = applicationProtobuf
+		body, err = proto.Marshal(resp)
+	case strings.Contains(accepted, applicationJSON):
+		accepted = applicationJSON
+		body, err = json.Marshal(resp)
+	default:
+		return false
+	}
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set(contentType, accepted)
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		level.Warn(util.Logger).Log("msg", "failed to write push response", "err", err)
 	}
+	return true
 }
@@ -0,0 +1,39 @@
+package distributor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestWritePushResponse(t *testing.T) {
+	resp := &logproto.PushResponse{Streams: []logproto.StreamPushResult{
+		{
+			Labels:   `{foo="bar"}`,
+			Accepted: 1,
+			Rejections: []logproto.PushRejection{
+				{Reason: "rate_limited", Count: 2, Retryable: true, Error: "rate limited"},
+			},
+			Retryable: 2,
+		},
+	}}
+
+	w := httptest.NewRecorder()
+	require.True(t, writePushResponse(w, "application/json", resp, http.StatusTooManyRequests))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"streams":[{"labels":"{foo=\"bar\"}","accepted":1,"rejections":[{"reason":"rate_limited","count":2,"retryable":true,"error":"rate limited"}],"retryable":2}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	require.True(t, writePushResponse(w, "application/x-protobuf", resp, http.StatusTooManyRequests))
+	require.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+	var decoded logproto.PushResponse
+	require.NoError(t, decoded.Unmarshal(w.Body.Bytes()))
+	require.Equal(t, *resp, decoded)
+
+	require.False(t, writePushResponse(httptest.NewRecorder(), "", resp, http.StatusTooManyRequests))
+}
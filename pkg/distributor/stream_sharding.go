@@ -0,0 +1,122 @@
+package distributor
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util"
+)
+
+const (
+	// window over which the rate of a stream is computed.
+	streamRateWindow = 10 * time.Second
+	// maximum number of shards a stream is split into.
+	maxStreamShards = 32
+)
+
+type streamRate struct {
+	windowStart time.Time
+	windowBytes int
+	// bytes per second of the previous window.
+	rate float64
+	// round robin shard of the next entry of the stream.
+	next int
+}
+
+// streamRates tracks the rate of the streams pushed to the distributor, by tenant and labels.
+type streamRates struct {
+	mtx   sync.Mutex
+	rates map[string]*streamRate
+}
+
+func newStreamRates() *streamRates {
+	return &streamRates{rates: map[string]*streamRate{}}
+}
+
+// observe records the bytes and entries of a stream pushed at now. It returns the estimated
+// rate of the stream in bytes per second and the round robin shard of the first entry.
+func (s *streamRates) observe(key string, now time.Time, bytes, entries int) (float64, int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	r, ok := s.rates[key]
+	if !ok {
+		r = &streamRate{windowStart: now}
+		s.rates[key] = r
+	}
+	if elapsed := now.Sub(r.windowStart); elapsed >= streamRateWindow {
+		r.rate = float64(r.windowBytes) / elapsed.Seconds()
+		r.windowStart = now
+		r.windowBytes = 0
+	}
+	r.windowBytes += bytes
+	next := r.next
+	r.next = (r.next + entries) % maxStreamShards
+
+	return math.Max(r.rate, float64(r.windowBytes)/streamRateWindow.Seconds()), next
+}
+
+// cleanup removes the streams which were not pushed since the previous window.
+func (s *streamRates) cleanup(now time.Time) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for key, r := range s.rates {
+		if now.Sub(r.windowStart) > 2*streamRateWindow {
+			delete(s.rates, key)
+		}
+	}
+}
+
+// streamShards returns the number of shards needed for each shard to stay below the desired rate.
+func streamShards(rate float64, desiredRate int) int {
+	if desiredRate <= 0 {
+		return 1
+	}
+	shards := int(math.Ceil(rate / float64(desiredRate)))
+	if shards < 1 {
+		return 1
+	}
+	if shards > maxStreamShards {
+		return maxStreamShards
+	}
+	return shards
+}
+
+// streamShard is a shard of a stream, along with the indexes of its entries in the sharded stream.
+type streamShard struct {
+	stream  logproto.Stream
+	indexes []int
+}
+
+// shardStream splits the entries of a stream in a round robin fashion across shards, starting
+// with the shard next. Each shard is identified by the shard label.
+func shardStream(stream logproto.Stream, shards, next int) ([]streamShard, error) {
+	if shards <= 1 {
+		indexes := make([]int, len(stream.Entries))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return []streamShard{{stream: stream, indexes: indexes}}, nil
+	}
+	result := make([]streamShard, 0, shards)
+	index := make(map[int]int, shards)
+	for i, entry := range stream.Entries {
+		shard := (next + i) % shards
+		j, ok := index[shard]
+		if !ok {
+			labels, err := util.AddStreamShard(stream.Labels, shard)
+			if err != nil {
+				return nil, err
+			}
+			j = len(result)
+			index[shard] = j
+			result = append(result, streamShard{stream: logproto.Stream{Labels: labels}})
+		}
+		result[j].stream.Entries = append(result[j].stream.Entries, entry)
+		result[j].indexes = append(result[j].indexes, i)
+	}
+	return result, nil
+}
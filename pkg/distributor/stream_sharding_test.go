@@ -0,0 +1,89 @@
+package distributor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestStreamRates(t *testing.T) {
+	rates := newStreamRates()
+	now := time.Unix(0, 0)
+
+	rate, next := rates.observe("foo", now, 1000, 3)
+	require.Equal(t, 100., rate)
+	require.Equal(t, 0, next)
+
+	rate, next = rates.observe("foo", now.Add(time.Second), 1000, 2)
+	require.Equal(t, 200., rate)
+	require.Equal(t, 3, next)
+
+	// the rate of the previous window is kept until exceeded by the current window.
+	rate, next = rates.observe("foo", now.Add(streamRateWindow), 500, 1)
+	require.Equal(t, 200., rate)
+	require.Equal(t, 5, next)
+
+	rates.cleanup(now.Add(2 * streamRateWindow))
+	require.Len(t, rates.rates, 1)
+	rates.cleanup(now.Add(4 * streamRateWindow))
+	require.Len(t, rates.rates, 0)
+}
+
+func TestStreamShards(t *testing.T) {
+	for _, tc := range []struct {
+		rate        float64
+		desiredRate int
+		expected    int
+	}{
+		{rate: 100, desiredRate: 0, expected: 1},
+		{rate: 0, desiredRate: 100, expected: 1},
+		{rate: 100, desiredRate: 100, expected: 1},
+		{rate: 101, desiredRate: 100, expected: 2},
+		{rate: 1000, desiredRate: 100, expected: 10},
+		{rate: 1e6, desiredRate: 100, expected: maxStreamShards},
+	} {
+		require.Equal(t, tc.expected, streamShards(tc.rate, tc.desiredRate), "rate %f desired %d", tc.rate, tc.desiredRate)
+	}
+}
+
+func TestShardStream(t *testing.T) {
+	stream := logproto.Stream{
+		Labels: `{foo="bar"}`,
+		Entries: []logproto.Entry{
+			{Timestamp: time.Unix(1, 0), Line: "1"},
+			{Timestamp: time.Unix(2, 0), Line: "2"},
+			{Timestamp: time.Unix(3, 0), Line: "3"},
+		},
+	}
+
+	shards, err := shardStream(stream, 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, []streamShard{{stream: stream, indexes: []int{0, 1, 2}}}, shards)
+
+	shards, err = shardStream(stream, 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, []streamShard{
+		{
+			stream: logproto.Stream{
+				Labels: `{__stream_shard__="1", foo="bar"}`,
+				Entries: []logproto.Entry{
+					{Timestamp: time.Unix(1, 0), Line: "1"},
+					{Timestamp: time.Unix(3, 0), Line: "3"},
+				},
+			},
+			indexes: []int{0, 2},
+		},
+		{
+			stream: logproto.Stream{
+				Labels: `{__stream_shard__="0", foo="bar"}`,
+				Entries: []logproto.Entry{
+					{Timestamp: time.Unix(2, 0), Line: "2"},
+				},
+			},
+			indexes: []int{1},
+		},
+	}, shards)
+}
@@ -25,18 +25,18 @@ func NewValidator(l Limits) (*Validator, error) {
 	return &Validator{l}, nil
 }
 
-// ValidateEntry returns an error if the entry is invalid
-func (v Validator) ValidateEntry(userID string, labels string, entry logproto.Entry) error {
+// ValidateEntry returns an error and the reason to discard the entry if it is invalid
+func (v Validator) ValidateEntry(userID string, labels string, entry logproto.Entry) (string, error) {
 	if v.RejectOldSamples(userID) && entry.Timestamp.UnixNano() < time.Now().Add(-v.RejectOldSamplesMaxAge(userID)).UnixNano() {
 		validation.DiscardedSamples.WithLabelValues(validation.GreaterThanMaxSampleAge, userID).Inc()
 		validation.DiscardedBytes.WithLabelValues(validation.GreaterThanMaxSampleAge, userID).Add(float64(len(entry.Line)))
-		return httpgrpc.Errorf(http.StatusBadRequest, validation.GreaterThanMaxSampleAgeErrorMsg(labels, entry.Timestamp))
+		return validation.GreaterThanMaxSampleAge, httpgrpc.Errorf(http.StatusBadRequest, validation.GreaterThanMaxSampleAgeErrorMsg(labels, entry.Timestamp))
 	}
 
 	if entry.Timestamp.UnixNano() > time.Now().Add(v.CreationGracePeriod(userID)).UnixNano() {
 		validation.DiscardedSamples.WithLabelValues(validation.TooFarInFuture, userID).Inc()
 		validation.DiscardedBytes.WithLabelValues(validation.TooFarInFuture, userID).Add(float64(len(entry.Line)))
-		return httpgrpc.Errorf(http.StatusBadRequest, validation.TooFarInFutureErrorMsg(labels, entry.Timestamp))
+		return validation.TooFarInFuture, httpgrpc.Errorf(http.StatusBadRequest, validation.TooFarInFutureErrorMsg(labels, entry.Timestamp))
 	}
 
 	if maxSize := v.MaxLineSize(userID); maxSize != 0 && len(entry.Line) > maxSize {
@@ -46,21 +46,21 @@ func (v Validator) ValidateEntry(userID string, labels string, entry logproto.En
 		// for parity.
 		validation.DiscardedSamples.WithLabelValues(validation.LineTooLong, userID).Inc()
 		validation.DiscardedBytes.WithLabelValues(validation.LineTooLong, userID).Add(float64(len(entry.Line)))
-		return httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg(maxSize, len(entry.Line), labels))
+		return validation.LineTooLong, httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg(maxSize, len(entry.Line), labels))
 	}
 
-	return nil
+	return "", nil
 }
 
-// Validate labels returns an error if the labels are invalid
-func (v Validator) ValidateLabels(userID string, stream logproto.Stream) error {
+// Validate labels returns an error and the reason to discard the stream if the labels are invalid
+func (v Validator) ValidateLabels(userID string, stream logproto.Stream) (string, error) {
 	ls, err := util.ToClientLabels(stream.Labels)
 	if err != nil {
 		// I wish we didn't return httpgrpc errors here as it seems
 		// an orthogonal concept (we need not use ValidateLabels in this context)
 		// but the upstream cortex_validation pkg uses it, so we keep this
 		// for parity.
-		return httpgrpc.Errorf(http.StatusBadRequest, "error parsing labels: %v", err)
+		return validation.InvalidLabels, httpgrpc.Errorf(http.StatusBadRequest, "error parsing labels: %v", err)
 	}
 
 	numLabelNames := len(ls)
@@ -71,7 +71,7 @@ func (v Validator) ValidateLabels(userID string, stream logproto.Stream) error {
 			bytes += len(e.Line)
 		}
 		validation.DiscardedBytes.WithLabelValues(validation.MaxLabelNamesPerSeries, userID).Add(float64(bytes))
-		return httpgrpc.Errorf(http.StatusBadRequest, validation.MaxLabelNamesPerSeriesErrorMsg(cortex_client.FromLabelAdaptersToMetric(ls).String(), numLabelNames, v.MaxLabelNamesPerSeries(userID)))
+		return validation.MaxLabelNamesPerSeries, httpgrpc.Errorf(http.StatusBadRequest, validation.MaxLabelNamesPerSeriesErrorMsg(cortex_client.FromLabelAdaptersToMetric(ls).String(), numLabelNames, v.MaxLabelNamesPerSeries(userID)))
 	}
 
 	maxLabelNameLength := v.MaxLabelNameLength(userID)
@@ -80,17 +80,17 @@ func (v Validator) ValidateLabels(userID string, stream logproto.Stream) error {
 	for _, l := range ls {
 		if len(l.Name) > maxLabelNameLength {
 			updateMetrics(validation.LabelNameTooLong, userID, stream)
-			return httpgrpc.Errorf(http.StatusBadRequest, validation.LabelNameTooLongErrorMsg(stream.Labels, l.Name))
+			return validation.LabelNameTooLong, httpgrpc.Errorf(http.StatusBadRequest, validation.LabelNameTooLongErrorMsg(stream.Labels, l.Name))
 		} else if len(l.Value) > maxLabelValueLength {
 			updateMetrics(validation.LabelValueTooLong, userID, stream)
-			return httpgrpc.Errorf(http.StatusBadRequest, validation.LabelValueTooLongErrorMsg(stream.Labels, l.Value))
+			return validation.LabelValueTooLong, httpgrpc.Errorf(http.StatusBadRequest, validation.LabelValueTooLongErrorMsg(stream.Labels, l.Value))
 		} else if cmp := strings.Compare(lastLabelName, l.Name); cmp == 0 {
 			updateMetrics(validation.DuplicateLabelNames, userID, stream)
-			return httpgrpc.Errorf(http.StatusBadRequest, validation.DuplicateLabelNamesErrorMsg(stream.Labels, l.Name))
+			return validation.DuplicateLabelNames, httpgrpc.Errorf(http.StatusBadRequest, validation.DuplicateLabelNamesErrorMsg(stream.Labels, l.Name))
 		}
 		lastLabelName = l.Name
 	}
-	return nil
+	return "", nil
 }
 
 func updateMetrics(reason, userID string, stream logproto.Stream) {
@@ -0,0 +1,55 @@
+package ingester
+
+import (
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	// period covered by each bucket of the stream churn.
+	churnBucketPeriod = 10 * time.Minute
+	// number of stream churn buckets kept, i.e. a day.
+	churnBuckets = 144
+)
+
+// streamChurn counts the streams created and removed by an instance over the last day,
+// in buckets of churnBucketPeriod. It is not safe for concurrent use.
+type streamChurn struct {
+	buckets []logproto.StreamChurn
+}
+
+// observe adds the streams created and removed at now.
+func (c *streamChurn) observe(now time.Time, created, removed int) {
+	start := now.Truncate(churnBucketPeriod)
+	if n := len(c.buckets); n == 0 || c.buckets[n-1].Start.Before(start) {
+		c.buckets = append(c.buckets, logproto.StreamChurn{Start: start})
+	}
+	b := &c.buckets[len(c.buckets)-1]
+	b.Created += uint32(created)
+	b.Removed += uint32(removed)
+
+	expired := c.expired(now)
+	if expired > 0 {
+		c.buckets = append(c.buckets[:0], c.buckets[expired:]...)
+	}
+}
+
+// get returns a copy of the buckets which are not expired at now, oldest first.
+func (c *streamChurn) get(now time.Time) []logproto.StreamChurn {
+	buckets := c.buckets[c.expired(now):]
+	if len(buckets) == 0 {
+		return nil
+	}
+	return append([]logproto.StreamChurn(nil), buckets...)
+}
+
+// expired returns the number of buckets older than the retained period at now.
+func (c *streamChurn) expired(now time.Time) int {
+	oldest := now.Truncate(churnBucketPeriod).Add(-(churnBuckets - 1) * churnBucketPeriod)
+	i := 0
+	for i < len(c.buckets) && c.buckets[i].Start.Before(oldest) {
+		i++
+	}
+	return i
+}
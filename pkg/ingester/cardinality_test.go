@@ -0,0 +1,39 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestStreamChurn(t *testing.T) {
+	var (
+		c   streamChurn
+		now = time.Unix(0, 0).Add(churnBucketPeriod)
+	)
+
+	c.observe(now, 2, 0)
+	c.observe(now.Add(time.Minute), 1, 1)
+	c.observe(now.Add(churnBucketPeriod), 0, 3)
+	require.Equal(t, []logproto.StreamChurn{
+		{Start: now, Created: 3, Removed: 1},
+		{Start: now.Add(churnBucketPeriod), Removed: 3},
+	}, c.get(now.Add(churnBucketPeriod)))
+
+	// the first bucket expires once a day has passed.
+	later := now.Add(churnBuckets * churnBucketPeriod)
+	require.Equal(t, []logproto.StreamChurn{
+		{Start: now.Add(churnBucketPeriod), Removed: 3},
+	}, c.get(later))
+
+	c.observe(later, 1, 0)
+	require.Equal(t, []logproto.StreamChurn{
+		{Start: now.Add(churnBucketPeriod), Removed: 3},
+		{Start: later, Created: 1},
+	}, c.get(later))
+
+	require.Nil(t, c.get(later.Add(churnBuckets*churnBucketPeriod)))
+}
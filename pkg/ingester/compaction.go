@@ -0,0 +1,313 @@
+package ingester
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	loki_util "github.com/grafana/loki/pkg/util"
+)
+
+var (
+	chunksCompacted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "ingester_compacted_chunks_total",
+		Help:      "Total small flushed chunks merged into larger chunks per tenant.",
+	}, []string{"tenant"})
+	chunksCompactionCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "ingester_compaction_created_chunks_total",
+		Help:      "Total chunks created by merging small flushed chunks per tenant.",
+	}, []string{"tenant"})
+	chunksCompactionDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "ingester_compaction_deleted_chunks_total",
+		Help:      "Total merged chunks deleted from the store.",
+	})
+	chunkCompactionFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "ingester_chunk_compaction_failures_total",
+		Help:      "Total failures to merge chunks or to delete the merged chunks, per operation.",
+	}, []string{"operation"})
+)
+
+// ChunkCompactionConfig configures the merging of the small chunks flushed by the ingester.
+type ChunkCompactionConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Interval       time.Duration `yaml:"interval"`
+	MaxUtilization float64       `yaml:"max_utilization"`
+}
+
+// RegisterFlags registers the flags.
+func (cfg *ChunkCompactionConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.chunk-compaction.enabled", false, "Merge the adjacent small chunks flushed for a stream into full chunks.")
+	f.DurationVar(&cfg.Interval, "ingester.chunk-compaction.interval", 10*time.Minute, "How often the small flushed chunks are merged.")
+	f.Float64Var(&cfg.MaxUtilization, "ingester.chunk-compaction.max-utilization", 0.5, "Utilization below which a flushed chunk is merged with the adjacent small chunks of its stream.")
+}
+
+// CompactionStore is the interface we need to merge the chunks flushed to the store.
+type CompactionStore interface {
+	Get(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]chunk.Chunk, error)
+	Put(ctx context.Context, chunks []chunk.Chunk) error
+	DeleteChunk(ctx context.Context, from, through model.Time, userID, chunkID string, metric labels.Labels, partiallyDeletedInterval *model.Interval) error
+}
+
+// chunkCompactor merges the adjacent small chunks flushed by the ingester for a stream.
+//
+// The original chunks are deleted as soon as the merged chunks are stored and indexed, so that
+// nothing is left to delete when the ingester stops. Queries deduplicate the entries of both in
+// between. Only the ingester owning a stream in the ring merges its chunks, so that the replicas
+// don't store the same merged chunks.
+type chunkCompactor struct {
+	cfg                   ChunkCompactionConfig
+	store                 CompactionStore
+	factory               func(userID string) chunkenc.Chunk
+	blockSize, targetSize int
+	// owns reports whether the chunks of a stream are merged by this ingester, nil if it owns all the streams.
+	owns func(userID string, metric labels.Labels) bool
+
+	mtx     sync.Mutex
+	streams map[string]*compactionStream
+}
+
+// compactionStream holds the small chunks flushed for a stream, in runs of chunks which were
+// flushed one after the other.
+type compactionStream struct {
+	userID string
+	fp     model.Fingerprint
+	metric labels.Labels
+	runs   [][]chunk.Chunk
+	// inactive is set once the stream is removed from the ingester, its last run can't be extended anymore.
+	inactive bool
+}
+
+func newChunkCompactor(cfg ChunkCompactionConfig, store CompactionStore, factory func(userID string) chunkenc.Chunk, blockSize, targetSize int, owns func(userID string, metric labels.Labels) bool) *chunkCompactor {
+	return &chunkCompactor{
+		cfg:        cfg,
+		store:      store,
+		factory:    factory,
+		blockSize:  blockSize,
+		targetSize: targetSize,
+		owns:       owns,
+		streams:    map[string]*compactionStream{},
+	}
+}
+
+// observe records the chunks flushed for a stream, in time order. The chunks under the
+// utilization threshold become candidates to be merged with the adjacent small chunks.
+func (c *chunkCompactor) observe(userID string, chunks []chunk.Chunk) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, ch := range chunks {
+		key := fmt.Sprintf("%s/%s", userID, ch.Fingerprint)
+		s, ok := c.streams[key]
+		if ch.Data.Utilization() >= c.cfg.MaxUtilization {
+			// a full chunk ends the current run.
+			if ok && len(s.runs[len(s.runs)-1]) > 0 {
+				s.runs = append(s.runs, nil)
+			}
+			continue
+		}
+		if !ok {
+			s = &compactionStream{userID: userID, fp: ch.Fingerprint, metric: ch.Metric, runs: make([][]chunk.Chunk, 1)}
+			c.streams[key] = s
+		}
+		s.inactive = false
+		// only keep what's needed to reference the chunk.
+		ref := chunk.Chunk{
+			Fingerprint: ch.Fingerprint,
+			UserID:      ch.UserID,
+			From:        ch.From,
+			Through:     ch.Through,
+			Metric:      ch.Metric,
+			ChecksumSet: ch.ChecksumSet,
+			Checksum:    ch.Checksum,
+			Encoding:    ch.Encoding,
+		}
+		last := len(s.runs) - 1
+		s.runs[last] = append(s.runs[last], ref)
+	}
+}
+
+// forget records that a stream was removed from the ingester once all its chunks were flushed.
+// Its runs are still merged by the next compaction, but a last run of a single chunk is dropped.
+func (c *chunkCompactor) forget(userID string, fp model.Fingerprint) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if s, ok := c.streams[fmt.Sprintf("%s/%s", userID, fp)]; ok {
+		s.inactive = true
+	}
+}
+
+// compact merges the runs of small chunks observed since the last compaction, for the streams
+// owned by the ingester.
+func (c *chunkCompactor) compact(timeout time.Duration) {
+	for _, s := range c.takeRuns() {
+		if c.owns != nil && !c.owns(s.userID, s.metric) {
+			continue
+		}
+		for _, run := range s.runs {
+			ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), s.userID), timeout)
+			err := c.mergeRun(ctx, s, run)
+			cancel()
+			if err != nil {
+				chunkCompactionFailures.WithLabelValues("merge").Inc()
+				level.Error(util.WithUserID(s.userID, util.Logger)).Log("msg", "failed to merge chunks", "fp", s.fp, "err", err)
+			}
+		}
+	}
+}
+
+// takeRuns removes and returns the runs of at least two chunks. The last run of an active stream
+// is kept when it holds a single chunk since the next flushed chunk may extend it, the streams
+// left with nothing to merge being removed.
+func (c *chunkCompactor) takeRuns() []*compactionStream {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var result []*compactionStream
+	for key, s := range c.streams {
+		taken := &compactionStream{userID: s.userID, fp: s.fp, metric: s.metric}
+		for _, run := range s.runs {
+			if len(run) > 1 {
+				taken.runs = append(taken.runs, run)
+			}
+		}
+		if len(taken.runs) > 0 {
+			result = append(result, taken)
+		}
+		if last := s.runs[len(s.runs)-1]; len(last) == 1 && !s.inactive {
+			s.runs = [][]chunk.Chunk{last}
+			continue
+		}
+		delete(c.streams, key)
+	}
+	return result
+}
+
+// mergeRun fetches the chunks of a run, stores the merged chunks and deletes the original ones.
+// Nothing is done when merging doesn't reduce the number of chunks.
+func (c *chunkCompactor) mergeRun(ctx context.Context, s *compactionStream, run []chunk.Chunk) error {
+	matchers := make([]*labels.Matcher, 0, len(s.metric))
+	for _, l := range s.metric {
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, l.Name, l.Value))
+	}
+	fetched, err := c.store.Get(ctx, s.userID, run[0].From, run[len(run)-1].Through, matchers...)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]chunk.Chunk, len(fetched))
+	for _, ch := range fetched {
+		byKey[ch.ExternalKey()] = ch
+	}
+
+	// chunks deleted in the meantime, i.e. by the retention, are skipped.
+	originals := make([]chunk.Chunk, 0, len(run))
+	for _, ref := range run {
+		if ch, ok := byKey[ref.ExternalKey()]; ok {
+			originals = append(originals, ch)
+		}
+	}
+	if len(originals) < 2 {
+		return nil
+	}
+
+	merged, err := c.merge(ctx, s.userID, originals)
+	if err != nil || len(merged) >= len(originals) {
+		return err
+	}
+
+	wireChunks := make([]chunk.Chunk, 0, len(merged))
+	for _, m := range merged {
+		firstTime, lastTime := loki_util.RoundToMilliseconds(m.chunk.Bounds())
+		ch := chunk.NewChunk(
+			s.userID, s.fp, s.metric,
+			chunkenc.NewFacadeWithVolume(m.chunk, c.blockSize, c.targetSize, m.volume),
+			firstTime,
+			lastTime,
+		)
+		if err := ch.Encode(); err != nil {
+			return err
+		}
+		wireChunks = append(wireChunks, ch)
+	}
+	if err := c.store.Put(ctx, wireChunks); err != nil {
+		return err
+	}
+	chunksCompacted.WithLabelValues(s.userID).Add(float64(len(originals)))
+	chunksCompactionCreated.WithLabelValues(s.userID).Add(float64(len(wireChunks)))
+
+	// the merged chunks are indexed once stored, the original ones can go.
+	for _, ch := range originals {
+		if err := c.store.DeleteChunk(ctx, ch.From, ch.Through, ch.UserID, ch.ExternalKey(), ch.Metric, nil); err != nil {
+			chunkCompactionFailures.WithLabelValues("delete").Inc()
+			level.Error(util.WithUserID(ch.UserID, util.Logger)).Log("msg", "failed to delete merged chunk", "chunk", ch.ExternalKey(), "err", err)
+			continue
+		}
+		chunksCompactionDeleted.Inc()
+	}
+	return nil
+}
+
+// mergedChunk is a chunk created by merging flushed chunks, along with its volume.
+type mergedChunk struct {
+	chunk  chunkenc.Chunk
+	volume []logproto.VolumeBucket
+}
+
+// merge appends the entries of the chunks, in time order, to new chunks cut when full.
+func (c *chunkCompactor) merge(ctx context.Context, userID string, chunks []chunk.Chunk) ([]mergedChunk, error) {
+	var (
+		result []mergedChunk
+		cur    = mergedChunk{chunk: c.factory(userID)}
+	)
+	for _, ch := range chunks {
+		facade, ok := ch.Data.(*chunkenc.Facade)
+		if !ok {
+			return nil, fmt.Errorf("unexpected chunk encoding %s of chunk %s", ch.Encoding, ch.ExternalKey())
+		}
+		it, err := facade.LokiChunk().Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			entry := it.Entry()
+			if !cur.chunk.SpaceFor(&entry) {
+				result = append(result, cur)
+				cur = mergedChunk{chunk: c.factory(userID)}
+			}
+			if err := cur.chunk.Append(&entry); err != nil {
+				_ = it.Close()
+				return nil, err
+			}
+			cur.volume = chunkenc.AppendVolume(cur.volume, &entry)
+		}
+		if err := it.Error(); err != nil {
+			_ = it.Close()
+			return nil, err
+		}
+		if err := it.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if cur.chunk.Size() > 0 {
+		result = append(result, cur)
+	}
+	return result, nil
+}
@@ -0,0 +1,215 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	loki_util "github.com/grafana/loki/pkg/util"
+)
+
+type compactionStoreMock struct {
+	mtx    sync.Mutex
+	chunks map[string]chunk.Chunk
+}
+
+func (s *compactionStoreMock) Get(_ context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([]chunk.Chunk, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var result []chunk.Chunk
+outer:
+	for _, c := range s.chunks {
+		if c.UserID != userID || c.Through < from || c.From > through {
+			continue
+		}
+		for _, m := range matchers {
+			if !m.Matches(c.Metric.Get(m.Name)) {
+				continue outer
+			}
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (s *compactionStoreMock) Put(ctx context.Context, chunks []chunk.Chunk) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, err := user.ExtractOrgID(ctx); err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		s.chunks[c.ExternalKey()] = c
+	}
+	return nil
+}
+
+func (s *compactionStoreMock) DeleteChunk(_ context.Context, _, _ model.Time, _, chunkID string, _ labels.Labels, _ *model.Interval) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.chunks[chunkID]; !ok {
+		return fmt.Errorf("chunk %s not found", chunkID)
+	}
+	delete(s.chunks, chunkID)
+	return nil
+}
+
+// entries returns the entries of the stored chunks, by chunk in time order.
+func (s *compactionStoreMock) entries(t *testing.T) [][]string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	chunks := make([]chunk.Chunk, 0, len(s.chunks))
+	for _, c := range s.chunks {
+		chunks = append(chunks, c)
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].From != chunks[j].From {
+			return chunks[i].From < chunks[j].From
+		}
+		return chunks[i].Through < chunks[j].Through
+	})
+
+	result := make([][]string, 0, len(chunks))
+	for _, c := range chunks {
+		it, err := c.Data.(*chunkenc.Facade).LokiChunk().Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+		require.NoError(t, err)
+		var lines []string
+		for it.Next() {
+			lines = append(lines, it.Entry().Line)
+		}
+		require.NoError(t, it.Close())
+		result = append(result, lines)
+	}
+	return result
+}
+
+func TestChunkCompaction(t *testing.T) {
+	const blockSize = 256
+	factory := func(string) chunkenc.Chunk {
+		return chunkenc.NewMemChunk(chunkenc.EncGZIP, blockSize, 0)
+	}
+	store := &compactionStoreMock{chunks: map[string]chunk.Chunk{}}
+	compactor := newChunkCompactor(ChunkCompactionConfig{MaxUtilization: 0.5}, store, factory, blockSize, 0, nil)
+
+	metric := labels.Labels{{Name: nameLabel, Value: logsValue}, {Name: "app", Value: "foo"}}
+	ts := time.Unix(0, 0)
+	newChunk := func(lines ...string) chunk.Chunk {
+		c := factory("fake")
+		for _, line := range lines {
+			ts = ts.Add(time.Second)
+			require.NoError(t, c.Append(&logproto.Entry{Timestamp: ts, Line: line}))
+		}
+		from, through := loki_util.RoundToMilliseconds(c.Bounds())
+		wc := chunk.NewChunk("fake", model.Fingerprint(1), metric, chunkenc.NewFacade(c, blockSize, 0), from, through)
+		require.NoError(t, wc.Encode())
+		return wc
+	}
+	var full []string
+	for i := 0; i < 100; i++ {
+		full = append(full, fmt.Sprintf("a long enough line to fill the chunk %d", i))
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	chunks := []chunk.Chunk{
+		newChunk("1", "2"),
+		newChunk("3"),
+		newChunk(full...),
+		newChunk("4"),
+		newChunk("5", "6"),
+		newChunk("7"),
+	}
+	require.NoError(t, store.Put(ctx, chunks))
+	compactor.observe("fake", chunks)
+
+	// the merged chunks are deleted as soon as their replacement is stored.
+	compactor.compact(time.Minute)
+	require.Equal(t, [][]string{{"1", "2", "3"}, full, {"4", "5", "6", "7"}}, store.entries(t))
+	require.Empty(t, compactor.streams)
+
+	// the volume of the merged chunks is given to the store to be indexed.
+	for _, c := range store.chunks {
+		facade := c.Data.(*chunkenc.Facade)
+		if facade.LokiChunk().Size() == len(full) {
+			continue
+		}
+		from, through := facade.LokiChunk().Bounds()
+		volume, err := chunkenc.Volume(ctx, facade.LokiChunk(), from, through.Add(time.Nanosecond))
+		require.NoError(t, err)
+		require.Equal(t, volume, facade.Volume())
+	}
+}
+
+func TestChunkCompactionNotOwned(t *testing.T) {
+	store := &compactionStoreMock{chunks: map[string]chunk.Chunk{}}
+	factory := func(string) chunkenc.Chunk { return defaultFactory() }
+	owns := func(userID string, metric labels.Labels) bool { return metric.Get("app") == "foo" }
+	compactor := newChunkCompactor(ChunkCompactionConfig{MaxUtilization: 0.5}, store, factory, 512, 0, owns)
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	ts := time.Unix(0, 0)
+	for _, app := range []string{"foo", "bar"} {
+		metric := labels.Labels{{Name: nameLabel, Value: logsValue}, {Name: "app", Value: app}}
+		var chunks []chunk.Chunk
+		for i := 0; i < 2; i++ {
+			c := factory("fake")
+			ts = ts.Add(time.Second)
+			require.NoError(t, c.Append(&logproto.Entry{Timestamp: ts, Line: app}))
+			from, through := loki_util.RoundToMilliseconds(c.Bounds())
+			wc := chunk.NewChunk("fake", model.Fingerprint(metric.Hash()), metric, chunkenc.NewFacade(c, 512, 0), from, through)
+			require.NoError(t, wc.Encode())
+			chunks = append(chunks, wc)
+		}
+		require.NoError(t, store.Put(ctx, chunks))
+		compactor.observe("fake", chunks)
+	}
+
+	// only the chunks of the stream owned by the ingester are merged, the other replicas merge the others.
+	compactor.compact(time.Minute)
+	require.Equal(t, [][]string{{"foo", "foo"}, {"bar"}, {"bar"}}, store.entries(t))
+	require.Empty(t, compactor.streams)
+}
+
+func TestChunkCompactionKeepsLastSingleChunk(t *testing.T) {
+	store := &compactionStoreMock{chunks: map[string]chunk.Chunk{}}
+	factory := func(string) chunkenc.Chunk { return defaultFactory() }
+	compactor := newChunkCompactor(ChunkCompactionConfig{MaxUtilization: 0.5}, store, factory, 512, 0, nil)
+
+	c := chunk.NewChunk("fake", model.Fingerprint(1), labels.Labels{{Name: "app", Value: "foo"}}, chunkenc.NewFacade(defaultFactory(), 512, 0), 0, 1)
+	compactor.observe("fake", []chunk.Chunk{c})
+	compactor.compact(time.Minute)
+
+	// the next flushed chunk may be merged with it.
+	require.Len(t, compactor.streams, 1)
+}
+
+func TestChunkCompactionForgetsInactiveStreams(t *testing.T) {
+	store := &compactionStoreMock{chunks: map[string]chunk.Chunk{}}
+	factory := func(string) chunkenc.Chunk { return defaultFactory() }
+	compactor := newChunkCompactor(ChunkCompactionConfig{MaxUtilization: 0.5}, store, factory, 512, 0, nil)
+
+	c := chunk.NewChunk("fake", model.Fingerprint(1), labels.Labels{{Name: "app", Value: "foo"}}, chunkenc.NewFacade(defaultFactory(), 512, 0), 0, 1)
+	compactor.observe("fake", []chunk.Chunk{c})
+	compactor.compact(time.Minute)
+	require.Len(t, compactor.streams, 1)
+
+	// once removed from the ingester, no flushed chunk can extend the stream anymore.
+	compactor.forget("fake", model.Fingerprint(1))
+	compactor.compact(time.Minute)
+	require.Empty(t, compactor.streams)
+}
@@ -302,7 +302,11 @@ func (i *Ingester) removeFlushedChunks(instance *instance, stream *stream) {
 		delete(instance.streams, stream.fp)
 		instance.index.Delete(stream.labels, stream.fp)
 		instance.streamsRemovedTotal.Inc()
+		instance.churn.observe(now, 0, 1)
 		memoryStreams.WithLabelValues(instance.instanceID).Dec()
+		if i.compactor != nil {
+			i.compactor.forget(instance.instanceID, stream.fp)
+		}
 	}
 }
 
@@ -318,10 +322,18 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 
 	wireChunks := make([]chunk.Chunk, 0, len(cs))
 	for _, c := range cs {
-		firstTime, lastTime := loki_util.RoundToMilliseconds(c.chunk.Bounds())
+		from, through := c.chunk.Bounds()
+		volume := c.volume
+		if volume == nil {
+			// the volume of the chunks transferred by another ingester is unknown.
+			if volume, err = chunkenc.Volume(ctx, c.chunk, from, through.Add(time.Nanosecond)); err != nil {
+				return err
+			}
+		}
+		firstTime, lastTime := loki_util.RoundToMilliseconds(from, through)
 		c := chunk.NewChunk(
 			userID, fp, metric,
-			chunkenc.NewFacade(c.chunk, i.cfg.BlockSize, i.cfg.TargetChunkSize),
+			chunkenc.NewFacadeWithVolume(c.chunk, i.cfg.BlockSize, i.cfg.TargetChunkSize, volume),
 			firstTime,
 			lastTime,
 		)
@@ -337,6 +349,9 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 	if err := i.store.Put(ctx, wireChunks); err != nil {
 		return err
 	}
+	if i.compactor != nil {
+		i.compactor.observe(userID, wireChunks)
+	}
 
 	// Record statistics only when actual put request did not return error.
 	sizePerTenant := chunkSizePerTenant.WithLabelValues(userID)
@@ -156,6 +156,14 @@ func TestFlushMaxAge(t *testing.T) {
 		},
 	})
 
+	// the volume of the chunk is given to the store to be indexed.
+	chunks := store.getChunksForUser(userID)
+	require.Len(t, chunks, 1)
+	require.Equal(t, []logproto.VolumeBucket{
+		{TimestampMs: 0, Lines: 1, Bytes: 1},
+		{TimestampMs: 60000, Lines: 2, Bytes: 2},
+	}, chunks[0].Data.(*chunkenc.Facade).Volume())
+
 	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ing))
 }
 
@@ -8,9 +8,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/weaveworks/common/user"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
@@ -54,14 +56,20 @@ type Config struct {
 	BlockSize         int           `yaml:"chunk_block_size"`
 	TargetChunkSize   int           `yaml:"chunk_target_size"`
 	ChunkEncoding     string        `yaml:"chunk_encoding"`
+	ChunkZstdLevel    int           `yaml:"chunk_zstd_level"`
 	MaxChunkAge       time.Duration `yaml:"max_chunk_age"`
 
+	// Writes the chunks in the format supporting structured metadata from their creation.
+	ChunkStructuredMetadata bool `yaml:"chunk_structured_metadata"`
+
 	// Synchronization settings. Used to make sure that ingesters cut their chunks at the same moments.
 	SyncPeriod         time.Duration `yaml:"sync_period"`
 	SyncMinUtilization float64       `yaml:"sync_min_utilization"`
 
 	MaxReturnedErrors int `yaml:"max_returned_stream_errors"`
 
+	ChunkCompaction ChunkCompactionConfig `yaml:"chunk_compaction"`
+
 	// For testing, you can override the address and ID of this ingester.
 	ingesterClientFactory func(cfg client.Config, addr string) (client.HealthAndIngesterClient, error)
 
@@ -72,6 +80,7 @@ type Config struct {
 // RegisterFlags registers the flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.LifecyclerConfig.RegisterFlags(f)
+	cfg.ChunkCompaction.RegisterFlags(f)
 
 	f.IntVar(&cfg.MaxTransferRetries, "ingester.max-transfer-retries", 10, "Number of times to try and transfer chunks before falling back to flushing. If set to 0 or negative value, transfers are disabled.")
 	f.IntVar(&cfg.ConcurrentFlushes, "ingester.concurrent-flushed", 16, "")
@@ -82,6 +91,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.BlockSize, "ingester.chunks-block-size", 256*1024, "")
 	f.IntVar(&cfg.TargetChunkSize, "ingester.chunk-target-size", 0, "")
 	f.StringVar(&cfg.ChunkEncoding, "ingester.chunk-encoding", chunkenc.EncGZIP.String(), fmt.Sprintf("The algorithm to use for compressing chunk. (%s)", chunkenc.SupportedEncoding()))
+	f.IntVar(&cfg.ChunkZstdLevel, "ingester.chunk-zstd-level", 0, "The zstd compression level of chunks, from 1 to 22, when the chunk encoding is zstd. 0 uses the default level.")
+	f.BoolVar(&cfg.ChunkStructuredMetadata, "ingester.chunk-structured-metadata", false, "Write all the chunks in the format supporting structured metadata, which the Loki versions without structured metadata can't read. By default only the chunks with structured metadata are.")
 	f.DurationVar(&cfg.SyncPeriod, "ingester.sync-period", 0, "How often to cut chunks to synchronize ingesters.")
 	f.Float64Var(&cfg.SyncMinUtilization, "ingester.sync-min-utilization", 0, "Minimum utilization of chunk when doing synchronization.")
 	f.IntVar(&cfg.MaxReturnedErrors, "ingester.max-ignored-stream-errors", 10, "Maximum number of ignored stream errors to return. 0 to return all errors.")
@@ -116,7 +127,17 @@ type Ingester struct {
 	flushQueuesDone sync.WaitGroup
 
 	limiter *Limiter
-	factory func() chunkenc.Chunk
+	limits  *validation.Overrides
+	factory func(userID string) chunkenc.Chunk
+	// ID of the missing dictionary per user, to warn once per user that its chunks
+	// are compressed without dictionary.
+	missingDictionaries sync.Map
+
+	// nil when chunk compaction is disabled.
+	compactor *chunkCompactor
+	// ring of the ingesters, read to merge only the chunks of the streams owned by this ingester.
+	// nil when chunk compaction is disabled.
+	compactionRing *ring.Ring
 }
 
 // ChunkStore is the interface we need to store chunks.
@@ -143,9 +164,31 @@ func New(cfg Config, clientConfig client.Config, store ChunkStore, limits *valid
 		loopQuit:     make(chan struct{}),
 		flushQueues:  make([]*util.PriorityQueue, cfg.ConcurrentFlushes),
 		tailersQuit:  make(chan struct{}),
-		factory: func() chunkenc.Chunk {
-			return chunkenc.NewMemChunk(enc, cfg.BlockSize, cfg.TargetChunkSize)
-		},
+		limits:       limits,
+	}
+	i.factory = func(userID string) chunkenc.Chunk {
+		var c *chunkenc.MemChunk
+		if enc == chunkenc.EncZstd {
+			c = i.newZstdChunk(userID)
+		} else {
+			c = chunkenc.NewMemChunk(enc, cfg.BlockSize, cfg.TargetChunkSize)
+		}
+		if cfg.ChunkStructuredMetadata {
+			c.EnableStructuredMetadata()
+		}
+		return c
+	}
+
+	if cfg.ChunkCompaction.Enabled {
+		cs, ok := store.(CompactionStore)
+		if !ok {
+			return nil, errors.New("chunk compaction is not supported by the chunk store")
+		}
+		i.compactionRing, err = ring.New(cfg.LifecyclerConfig.RingConfig, "ingester-compaction", ring.IngesterRingKey, registerer)
+		if err != nil {
+			return nil, err
+		}
+		i.compactor = newChunkCompactor(cfg.ChunkCompaction, cs, i.factory, cfg.BlockSize, cfg.TargetChunkSize, i.ownsStream)
 	}
 
 	i.lifecycler, err = ring.NewLifecycler(cfg.LifecyclerConfig, i, "ingester", ring.IngesterRingKey, true, registerer)
@@ -164,6 +207,24 @@ func New(cfg Config, clientConfig client.Config, store ChunkStore, limits *valid
 	return i, nil
 }
 
+// newZstdChunk returns a new zstd chunk, compressed with the dictionary of the user if any.
+func (i *Ingester) newZstdChunk(userID string) *chunkenc.MemChunk {
+	opts := chunkenc.ZstdOptions{Level: i.cfg.ChunkZstdLevel}
+	if id := i.limits.ChunkDictionaryID(userID); id != 0 {
+		dict, err := chunkenc.GetDictionary(id)
+		if err != nil {
+			if prev, ok := i.missingDictionaries.Load(userID); !ok || prev.(uint32) != id {
+				i.missingDictionaries.Store(userID, id)
+				level.Warn(util.Logger).Log("msg", "compressing chunks without dictionary", "user", userID, "err", err)
+			}
+		} else {
+			i.missingDictionaries.Delete(userID)
+		}
+		opts.Dictionary = dict
+	}
+	return chunkenc.NewZstdMemChunk(opts, i.cfg.BlockSize, i.cfg.TargetChunkSize)
+}
+
 func (i *Ingester) starting(ctx context.Context) error {
 	i.flushQueuesDone.Add(i.cfg.ConcurrentFlushes)
 	for j := 0; j < i.cfg.ConcurrentFlushes; j++ {
@@ -185,6 +246,14 @@ func (i *Ingester) starting(ctx context.Context) error {
 	// start our loop
 	i.loopDone.Add(1)
 	go i.loop()
+
+	if i.compactor != nil {
+		if err := services.StartAndAwaitRunning(ctx, i.compactionRing); err != nil {
+			return err
+		}
+		i.loopDone.Add(1)
+		go i.compactionLoop()
+	}
 	return nil
 }
 
@@ -223,6 +292,11 @@ func (i *Ingester) stopping(_ error) error {
 	}
 	i.flushQueuesDone.Wait()
 
+	if i.compactionRing != nil {
+		if ringErr := services.StopAndAwaitTerminated(context.Background(), i.compactionRing); err == nil {
+			err = ringErr
+		}
+	}
 	return err
 }
 
@@ -243,6 +317,35 @@ func (i *Ingester) loop() {
 	}
 }
 
+// compactionLoop periodically merges the small chunks flushed to the store.
+func (i *Ingester) compactionLoop() {
+	defer i.loopDone.Done()
+
+	compactionTicker := time.NewTicker(i.cfg.ChunkCompaction.Interval)
+	defer compactionTicker.Stop()
+
+	for {
+		select {
+		case <-compactionTicker.C:
+			i.compactor.compact(i.cfg.FlushOpTimeout)
+
+		case <-i.loopQuit:
+			return
+		}
+	}
+}
+
+// ownsStream returns whether the ingester is the first healthy ingester of the replication set of the
+// stream, which is the one merging its chunks.
+func (i *Ingester) ownsStream(userID string, metric labels.Labels) bool {
+	lbls := labels.NewBuilder(metric).Del(nameLabel).Labels()
+	replicationSet, err := i.compactionRing.Get(listutil.TokenFor(userID, lbls.String()), ring.Write, nil)
+	if err != nil || len(replicationSet.Ingesters) == 0 {
+		return false
+	}
+	return replicationSet.Ingesters[0].Addr == i.lifecycler.Addr
+}
+
 // Push implements logproto.Pusher.
 func (i *Ingester) Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
 	instanceID, err := user.ExtractOrgID(ctx)
@@ -253,8 +356,13 @@ func (i *Ingester) Push(ctx context.Context, req *logproto.PushRequest) (*logpro
 	}
 
 	instance := i.getOrCreateInstance(instanceID)
-	err = instance.Push(ctx, req)
-	return &logproto.PushResponse{}, err
+	resp, err := instance.Push(ctx, req)
+	if err != nil {
+		// rejections are returned as the status errors distributors expect, the response
+		// detailing the rejected entries of each stream is carried by the error.
+		return nil, logproto.ErrorWithPushResponse(err, resp)
+	}
+	return resp, nil
 }
 
 func (i *Ingester) getOrCreateInstance(instanceID string) *instance {
@@ -267,7 +375,8 @@ func (i *Ingester) getOrCreateInstance(instanceID string) *instance {
 	defer i.instancesMtx.Unlock()
 	inst, ok = i.instances[instanceID]
 	if !ok {
-		inst = newInstance(&i.cfg, instanceID, i.factory, i.limiter, i.cfg.SyncPeriod, i.cfg.SyncMinUtilization)
+		factory := func() chunkenc.Chunk { return i.factory(instanceID) }
+		inst = newInstance(&i.cfg, instanceID, factory, i.limiter, i.cfg.SyncPeriod, i.cfg.SyncMinUtilization)
 		i.instances[instanceID] = inst
 	}
 	return inst
@@ -424,7 +533,7 @@ func (i *Ingester) Tail(req *logproto.TailRequest, queryServer logproto.Querier_
 	}
 
 	instance := i.getOrCreateInstance(instanceID)
-	tailer, err := newTailer(instanceID, req.Query, queryServer)
+	tailer, err := newTailer(instanceID, req.Query, req.Backpressure, queryServer)
 	if err != nil {
 		return err
 	}
@@ -451,6 +560,34 @@ func (i *Ingester) TailersCount(ctx context.Context, in *logproto.TailersCountRe
 	return &resp, nil
 }
 
+// Cardinality returns the cardinality of the streams of the tenant in this ingester.
+func (i *Ingester) Cardinality(ctx context.Context, req *logproto.CardinalityRequest) (*logproto.CardinalityResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := i.getInstanceByID(instanceID)
+	if !ok {
+		return &logproto.CardinalityResponse{}, nil
+	}
+	return instance.Cardinality(ctx, req)
+}
+
+// Volume returns the volume of the chunks of the streams of the tenant in this ingester.
+func (i *Ingester) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := i.getInstanceByID(instanceID)
+	if !ok {
+		return &logproto.VolumeResponse{}, nil
+	}
+	return instance.Volume(ctx, req)
+}
+
 // buildStoreRequest returns a store request from an ingester request, returns nit if QueryStore is set to false in configuration.
 // The request may be truncated due to QueryStoreMaxLookBackPeriod which limits the range of request to make sure
 // we only query enough to not miss any data and not add too to many duplicates by covering the who time range in query.
@@ -1,7 +1,9 @@
 package ingester
 
 import (
+	"encoding/binary"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"sync"
 	"testing"
@@ -17,6 +19,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
@@ -245,6 +248,105 @@ func TestIngesterStreamLimitExceeded(t *testing.T) {
 	}
 }
 
+func TestIngesterPushRejections(t *testing.T) {
+	ingesterConfig := defaultIngesterTestConfig(t)
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	store := &mockStore{
+		chunks: map[string][]chunk.Chunk{},
+	}
+
+	i, err := New(ingesterConfig, client.Config{}, store, overrides, nil)
+	require.NoError(t, err)
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{foo="bar"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(2, 0), Line: "line 2"}}},
+	}})
+	require.NoError(t, err)
+
+	_, err = i.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{foo="bar"}`, Entries: []logproto.Entry{
+			{Timestamp: time.Unix(1, 0), Line: "line 1"},
+			{Timestamp: time.Unix(3, 0), Line: "line 3"},
+		}},
+	}})
+	// the rejection is still a status error, with the rejected entries carried along.
+	httpResp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusBadRequest), httpResp.Code)
+
+	resp, ok := logproto.PushResponseFromError(err)
+	require.True(t, ok)
+	require.Len(t, resp.Streams, 1)
+	require.Equal(t, uint32(1), resp.Streams[0].Accepted)
+	require.Len(t, resp.Streams[0].Rejections, 1)
+	require.Equal(t, validation.OutOfOrder, resp.Streams[0].Rejections[0].Reason)
+	require.Equal(t, uint32(1), resp.Streams[0].Rejections[0].Count)
+}
+
+func TestIngesterZstdDictionary(t *testing.T) {
+	var samples [][]byte
+	for i := 0; i < 100; i++ {
+		samples = append(samples, []byte(fmt.Sprintf("level=info msg=\"request served\" path=/api/v1/push status=200 duration=%dms", i)))
+	}
+	// the dictionary of the chunkenc tests, with the ID 7.
+	raw, err := ioutil.ReadFile("../chunkenc/testdata/zstd.dict")
+	require.NoError(t, err)
+	binary.LittleEndian.PutUint32(raw[4:8], 7)
+	dict, err := chunkenc.NewDictionary(raw)
+	require.NoError(t, err)
+	chunkenc.RegisterDictionary(dict)
+
+	ingesterConfig := defaultIngesterTestConfig(t)
+	ingesterConfig.ChunkEncoding = chunkenc.EncZstd.String()
+	limits := defaultLimitsTestConfig()
+	limits.ChunkDictionaryID = 7
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	i, err := New(ingesterConfig, client.Config{}, &mockStore{chunks: map[string][]chunk.Chunk{}}, overrides, nil)
+	require.NoError(t, err)
+
+	c := i.factory("test")
+	for j, sample := range samples {
+		require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(0, int64(j)), Line: string(sample)}))
+	}
+	b, err := c.Bytes()
+	require.NoError(t, err)
+	// the header holds the magic number, the format, the encoding and the dictionary ID.
+	require.Equal(t, []byte{3, byte(chunkenc.EncZstd), 7}, b[4:7])
+
+	bc, err := chunkenc.NewByteChunk(b, ingesterConfig.BlockSize, ingesterConfig.TargetChunkSize)
+	require.NoError(t, err)
+	require.Equal(t, len(samples), bc.Size())
+}
+
+func TestIngesterChunkStructuredMetadata(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		ingesterConfig := defaultIngesterTestConfig(t)
+		ingesterConfig.ChunkStructuredMetadata = enabled
+		overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+		require.NoError(t, err)
+
+		i, err := New(ingesterConfig, client.Config{}, &mockStore{chunks: map[string][]chunk.Chunk{}}, overrides, nil)
+		require.NoError(t, err)
+
+		c := i.factory("test")
+		require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(0, 1), Line: "line"}))
+		b, err := c.Bytes()
+		require.NoError(t, err)
+		// the chunks without structured metadata are readable by older versions unless enabled.
+		expectedFormat := byte(2)
+		if enabled {
+			expectedFormat = 4
+		}
+		require.Equal(t, expectedFormat, b[4])
+	}
+}
+
 type mockStore struct {
 	mtx    sync.Mutex
 	chunks map[string][]chunk.Chunk
@@ -64,6 +64,7 @@ type instance struct {
 
 	streamsCreatedTotal prometheus.Counter
 	streamsRemovedTotal prometheus.Counter
+	churn               streamChurn // guarded by streamsMtx
 
 	tailers   map[uint32]*tailer
 	tailerMtx sync.RWMutex
@@ -109,9 +110,10 @@ func (i *instance) consumeChunk(ctx context.Context, labels []client.LabelAdapte
 	stream, ok := i.streams[fp]
 	if !ok {
 		sortedLabels := i.index.Add(labels, fp)
-		stream = newStream(i.cfg, fp, sortedLabels, i.factory)
+		stream = newStream(i.cfg, fp, sortedLabels, i.factory, i.limiter.StreamRateLimiter(i.instanceID))
 		i.streams[fp] = stream
 		i.streamsCreatedTotal.Inc()
+		i.churn.observe(time.Now(), 1, 0)
 		memoryStreams.WithLabelValues(i.instanceID).Inc()
 		i.addTailersToNewStream(stream)
 	}
@@ -124,63 +126,127 @@ func (i *instance) consumeChunk(ctx context.Context, labels []client.LabelAdapte
 	return err
 }
 
-func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
+// Push appends the streams of the request. The entries rejected by the limits or for being out of
+// order are reported by the response, and the error of the last failed stream is returned.
+func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
 	i.streamsMtx.Lock()
 	defer i.streamsMtx.Unlock()
 
+	resp := &logproto.PushResponse{
+		Streams: make([]logproto.StreamPushResult, len(req.Streams)),
+	}
 	var appendErr error
-	for _, s := range req.Streams {
+	for j, s := range req.Streams {
+		result := &resp.Streams[j]
+		result.Labels = s.Labels
 
-		stream, err := i.getOrCreateStream(s)
+		stream, reason, err := i.getOrCreateStream(s)
 		if err != nil {
 			appendErr = err
+			if reason == "" {
+				continue
+			}
+			retryable := isHTTPError(err, http.StatusTooManyRequests)
+			result.Reject(reason, len(s.Entries), retryable, httpErrorMessage(err))
+			if retryable {
+				result.Retryable = uint32(len(s.Entries))
+			}
 			continue
 		}
 
 		prevNumChunks := len(stream.chunks)
-		if err := stream.Push(ctx, s.Entries, i.syncPeriod, i.syncMinUtil); err != nil {
+		streamResult, err := stream.Push(ctx, s.Entries, i.syncPeriod, i.syncMinUtil)
+		result.Accepted = streamResult.Accepted
+		result.Rejections = streamResult.Rejections
+		result.Retryable = streamResult.Retryable
+		if err != nil {
 			appendErr = err
-			continue
 		}
 
 		memoryChunks.Add(float64(len(stream.chunks) - prevNumChunks))
 	}
 
-	return appendErr
+	return resp, appendErr
+}
+
+// isHTTPError returns whether err is an http error with the given code.
+func isHTTPError(err error, code int) bool {
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	return ok && int(resp.Code) == code
+}
+
+// httpErrorMessage returns the body of an http error, or the error string of other errors.
+func httpErrorMessage(err error) string {
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return string(resp.Body)
+	}
+	return err.Error()
 }
 
-func (i *instance) getOrCreateStream(pushReqStream logproto.Stream) (*stream, error) {
+// getOrCreateStream returns the stream of the pushed labels, creating it if needed. When the
+// stream can't be created because of its labels or the limits of the tenant, the reason of the
+// rejection is returned along with the error.
+func (i *instance) getOrCreateStream(pushReqStream logproto.Stream) (*stream, string, error) {
 	labels, err := util.ToClientLabels(pushReqStream.Labels)
 	if err != nil {
-		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		return nil, validation.InvalidLabels, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 	}
 	rawFp := client.FastFingerprint(labels)
 	fp := i.mapper.mapFP(rawFp, labels)
 
 	stream, ok := i.streams[fp]
 	if ok {
-		return stream, nil
+		return stream, "", nil
 	}
 
 	err = i.limiter.AssertMaxStreamsPerUser(i.instanceID, len(i.streams))
 	if err != nil {
-		validation.DiscardedSamples.WithLabelValues(validation.StreamLimit, i.instanceID).Add(float64(len(pushReqStream.Entries)))
-		bytes := 0
-		for _, e := range pushReqStream.Entries {
-			bytes += len(e.Line)
-		}
-		validation.DiscardedBytes.WithLabelValues(validation.StreamLimit, i.instanceID).Add(float64(bytes))
-		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamLimitErrorMsg())
+		i.discard(validation.StreamLimit, pushReqStream.Entries)
+		return nil, validation.StreamLimit, httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamLimitErrorMsg())
+	}
+
+	if name, limit, exceeded := i.exceedsLabelValuesLimit(labels); exceeded {
+		i.discard(validation.LabelValuesLimit, pushReqStream.Entries)
+		return nil, validation.LabelValuesLimit, httpgrpc.Errorf(http.StatusTooManyRequests, validation.LabelValuesLimitErrorMsg(name, pushReqStream.Labels, limit))
 	}
 
 	sortedLabels := i.index.Add(labels, fp)
-	stream = newStream(i.cfg, fp, sortedLabels, i.factory)
+	stream = newStream(i.cfg, fp, sortedLabels, i.factory, i.limiter.StreamRateLimiter(i.instanceID))
 	i.streams[fp] = stream
 	memoryStreams.WithLabelValues(i.instanceID).Inc()
 	i.streamsCreatedTotal.Inc()
+	i.churn.observe(time.Now(), 1, 0)
 	i.addTailersToNewStream(stream)
 
-	return stream, nil
+	return stream, "", nil
+}
+
+// exceedsLabelValuesLimit returns the first label of a new stream adding a value to a label name
+// which already has the maximum number of distinct values. Must hold streamsMtx.
+func (i *instance) exceedsLabelValuesLimit(lbls []client.LabelAdapter) (string, int, bool) {
+	limit := i.limiter.MaxLabelValuesPerLabelName(i.instanceID)
+	if limit <= 0 {
+		return "", 0, false
+	}
+	for _, l := range lbls {
+		if len(i.index.Lookup([]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, l.Name, l.Value)})) > 0 {
+			continue
+		}
+		if len(i.index.LabelValues(l.Name)) >= limit {
+			return l.Name, limit, true
+		}
+	}
+	return "", 0, false
+}
+
+// discard records the entries of a stream discarded for the given reason.
+func (i *instance) discard(reason string, entries []logproto.Entry) {
+	validation.DiscardedSamples.WithLabelValues(reason, i.instanceID).Add(float64(len(entries)))
+	bytes := 0
+	for _, e := range entries {
+		bytes += len(e.Line)
+	}
+	validation.DiscardedBytes.WithLabelValues(reason, i.instanceID).Add(float64(bytes))
 }
 
 // Return labels associated with given fingerprint. Used by fingerprint mapper. Must hold streamsMtx.
@@ -243,6 +309,67 @@ func (i *instance) Label(_ context.Context, req *logproto.LabelRequest) (*logpro
 	}, nil
 }
 
+// Cardinality returns the number of streams by label name and value, and the recent stream churn.
+// At most req.Limit values are returned per label name, 0 meaning all of them.
+func (i *instance) Cardinality(_ context.Context, req *logproto.CardinalityRequest) (*logproto.CardinalityResponse, error) {
+	i.streamsMtx.RLock()
+	defer i.streamsMtx.RUnlock()
+
+	resp := &logproto.CardinalityResponse{
+		Streams: uint32(len(i.streams)),
+		Churn:   i.churn.get(time.Now()),
+	}
+	for _, name := range i.index.LabelNames() {
+		values := i.index.LabelValues(name)
+		label := logproto.LabelCardinality{
+			Name:      name,
+			Values:    uint32(len(values)),
+			TopValues: make([]logproto.LabelValueCardinality, 0, len(values)),
+		}
+		for _, value := range values {
+			streams := uint32(len(i.index.Lookup([]*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, name, value)})))
+			label.Streams += streams
+			label.TopValues = append(label.TopValues, logproto.LabelValueCardinality{Value: value, Streams: streams})
+		}
+		label.TopValues = logproto.TopLabelValues(label.TopValues, int(req.Limit))
+		resp.Labels = append(resp.Labels, label)
+	}
+	return resp, nil
+}
+
+// Volume returns the volume of each chunk of the streams matching the selector of req,
+// between req.Start and req.End.
+func (i *instance) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	matchers, err := logql.ParseMatchers(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &logproto.VolumeResponse{}
+	err = i.forMatchingStreams(matchers, func(stream *stream) error {
+		volume := logproto.StreamVolume{Labels: stream.labelsString}
+		for _, c := range stream.chunks {
+			from, through := c.chunk.Bounds()
+			if through.Before(req.Start) || !from.Before(req.End) {
+				continue
+			}
+			buckets, err := chunkenc.Volume(ctx, c.chunk, req.Start, req.End)
+			if err != nil {
+				return err
+			}
+			volume.Chunks = append(volume.Chunks, logproto.ChunkVolume{From: from, Through: through, Buckets: buckets})
+		}
+		if len(volume.Chunks) > 0 {
+			resp.Streams = append(resp.Streams, volume)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (i *instance) Series(_ context.Context, req *logproto.SeriesRequest) (*logproto.SeriesResponse, error) {
 	groups, err := loghttp.Match(req.GetGroups())
 	if err != nil {
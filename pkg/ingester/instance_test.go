@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/logproto"
@@ -33,7 +35,7 @@ func TestLabelsCollisions(t *testing.T) {
 	tt := time.Now().Add(-5 * time.Minute)
 
 	// Notice how labels aren't sorted.
-	err = i.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+	_, err = i.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
 		// both label sets have FastFingerprint=e002a3a451262627
 		{Labels: "{app=\"l\",uniq0=\"0\",uniq1=\"1\"}", Entries: entries(5, tt.Add(time.Minute))},
 		{Labels: "{uniq0=\"1\",app=\"m\",uniq1=\"1\"}", Entries: entries(5, tt)},
@@ -82,7 +84,7 @@ func TestConcurrentPushes(t *testing.T) {
 			tt := time.Now().Add(-5 * time.Minute)
 
 			for i := 0; i < iterations; i++ {
-				err := inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+				_, err := inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
 					{Labels: labels, Entries: entries(entriesPerIteration, tt)},
 				}})
 
@@ -123,11 +125,11 @@ func TestSyncPeriod(t *testing.T) {
 		tt = tt.Add(time.Duration(1 + rand.Int63n(randomStep.Nanoseconds())))
 	}
 	pr := &logproto.PushRequest{Streams: []logproto.Stream{{Labels: lbls, Entries: result}}}
-	err = inst.Push(context.Background(), pr)
+	_, err = inst.Push(context.Background(), pr)
 	require.NoError(t, err)
 
 	// let's verify results
-	s, err := inst.getOrCreateStream(pr.Streams[0])
+	s, _, err := inst.getOrCreateStream(pr.Streams[0])
 	require.NoError(t, err)
 
 	// make sure each chunk spans max 'sync period' time
@@ -160,3 +162,119 @@ func makeRandomLabels() string {
 	}
 	return ls.Labels().String()
 }
+
+func TestInstanceCardinality(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{MaxLocalStreamsPerUser: 1000}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	inst := newInstance(&Config{}, "test", defaultFactory, limiter, 0, 0)
+
+	tt := time.Now().Add(-5 * time.Minute)
+	_, err = inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo",pod="a"}`, Entries: entries(1, tt)},
+		{Labels: `{app="foo",pod="b"}`, Entries: entries(1, tt)},
+		{Labels: `{app="bar",pod="c"}`, Entries: entries(1, tt)},
+		{Labels: `{app="baz"}`, Entries: entries(1, tt)},
+	}})
+	require.NoError(t, err)
+
+	resp, err := inst.Cardinality(context.Background(), &logproto.CardinalityRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), resp.Streams)
+	require.Equal(t, []logproto.LabelCardinality{
+		{
+			Name:    "app",
+			Streams: 4,
+			Values:  3,
+			TopValues: []logproto.LabelValueCardinality{
+				{Value: "foo", Streams: 2},
+				{Value: "bar", Streams: 1},
+			},
+		},
+		{
+			Name:    "pod",
+			Streams: 3,
+			Values:  3,
+			TopValues: []logproto.LabelValueCardinality{
+				{Value: "a", Streams: 1},
+				{Value: "b", Streams: 1},
+			},
+		},
+	}, resp.Labels)
+	require.Len(t, resp.Churn, 1)
+	require.Equal(t, uint32(4), resp.Churn[0].Created)
+}
+
+func TestInstanceLabelValuesLimit(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{MaxLocalStreamsPerUser: 1000, MaxLabelValuesPerLabelName: 2}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	inst := newInstance(&Config{}, "test", defaultFactory, limiter, 0, 0)
+
+	tt := time.Now().Add(-5 * time.Minute)
+	resp, err := inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo",pod="a"}`, Entries: entries(1, tt)},
+		{Labels: `{app="foo",pod="b"}`, Entries: entries(1, tt)},
+		{Labels: `{app="foo",pod="c"}`, Entries: entries(3, tt)},
+		// existing values are still accepted in new streams.
+		{Labels: `{app="foo",pod="a",job="x"}`, Entries: entries(1, tt)},
+	}})
+	require.Equal(t, httpgrpc.Errorf(http.StatusTooManyRequests, validation.LabelValuesLimitErrorMsg("pod", `{app="foo",pod="c"}`, 2)), err)
+
+	require.Equal(t, uint32(1), resp.Streams[0].Accepted)
+	require.Equal(t, uint32(1), resp.Streams[1].Accepted)
+	require.Equal(t, uint32(0), resp.Streams[2].Accepted)
+	require.Equal(t, uint32(3), resp.Streams[2].Retryable)
+	require.Equal(t, []logproto.PushRejection{{
+		Reason:    validation.LabelValuesLimit,
+		Count:     3,
+		Retryable: true,
+		Error:     validation.LabelValuesLimitErrorMsg("pod", `{app="foo",pod="c"}`, 2),
+	}}, resp.Streams[2].Rejections)
+	require.Equal(t, uint32(1), resp.Streams[3].Accepted)
+	require.Len(t, inst.streams, 3)
+}
+
+func TestInstanceVolume(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{MaxLocalStreamsPerUser: 1000}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	inst := newInstance(&Config{}, "test", defaultFactory, limiter, 0, 0)
+
+	tt := time.Now().Add(-5 * time.Minute).Truncate(time.Minute)
+	_, err = inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+			{Timestamp: tt, Line: "a"},
+			{Timestamp: tt.Add(time.Second), Line: "bb"},
+			{Timestamp: tt.Add(time.Minute), Line: "ccc"},
+		}},
+		{Labels: `{app="bar"}`, Entries: entries(1, tt)},
+	}})
+	require.NoError(t, err)
+
+	resp, err := inst.Volume(context.Background(), &logproto.VolumeRequest{
+		Selector: `{app="foo"}`,
+		Start:    tt,
+		End:      tt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Streams, 1)
+	require.Equal(t, `{app="foo"}`, resp.Streams[0].Labels)
+	require.Len(t, resp.Streams[0].Chunks, 1)
+	ms := tt.UnixNano() / int64(time.Millisecond)
+	require.Equal(t, []logproto.VolumeBucket{
+		{TimestampMs: ms, Lines: 2, Bytes: 3},
+		{TimestampMs: ms + 60000, Lines: 1, Bytes: 3},
+	}, resp.Streams[0].Chunks[0].Buckets)
+
+	resp, err = inst.Volume(context.Background(), &logproto.VolumeRequest{
+		Selector: `{app="foo"}`,
+		Start:    tt.Add(-time.Hour),
+		End:      tt,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Streams)
+}
@@ -3,10 +3,18 @@ package ingester
 import (
 	"fmt"
 	"math"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util/limiter"
 
 	"github.com/grafana/loki/pkg/util/validation"
 )
 
+const (
+	// period after which the per stream rate limits are reloaded from the tenant limits.
+	streamRateLimitRecheckPeriod = 10 * time.Second
+)
+
 const (
 	errMaxStreamsPerUserLimitExceeded = "tenant '%v' per-user streams limit exceeded, streams: %d exceeds calculated limit: %d (local limit: %d, global limit: %d, global/ingesters: %d)"
 )
@@ -88,3 +96,72 @@ func (l *Limiter) minNonZero(first, second int) int {
 
 	return first
 }
+
+// MaxLabelValuesPerLabelName returns the maximum number of distinct values of a label name
+// across the streams of a tenant in this ingester, 0 if unlimited.
+func (l *Limiter) MaxLabelValuesPerLabelName(userID string) int {
+	return l.limits.MaxLabelValuesPerLabelName(userID)
+}
+
+// StreamRateLimiter returns the rate limiter of a new stream of the tenant.
+func (l *Limiter) StreamRateLimiter(userID string) *StreamRateLimiter {
+	return &StreamRateLimiter{
+		userID: userID,
+		limits: l.limits,
+	}
+}
+
+// StreamRateLimiter limits the rate of the bytes appended to a single stream.
+// Not thread-safe; assume accesses to this are locked by the caller, as for the stream.
+type StreamRateLimiter struct {
+	userID string
+	limits *validation.Overrides
+	// built once the tenant has a per stream rate limit, nil before.
+	limiter *limiter.RateLimiter
+}
+
+// AllowN reports whether n bytes may be appended to the stream at time now.
+func (l *StreamRateLimiter) AllowN(now time.Time, n int) bool {
+	if l.limiter == nil {
+		// the streams created before the tenant had a limit are limited once it has one.
+		if l.Limit() == 0 {
+			return true
+		}
+		l.limiter = limiter.NewRateLimiter(&streamRateLimiterStrategy{limits: l.limits}, streamRateLimitRecheckPeriod)
+	}
+	return l.limiter.AllowN(now, l.userID, n)
+}
+
+// Limit returns the current per stream rate limit in bytes per second, 0 if unlimited.
+func (l *StreamRateLimiter) Limit() int {
+	limit, _ := l.limits.PerStreamRateLimit(l.userID)
+	return limit
+}
+
+// Burst returns the current per stream burst size in bytes, the maximum number of bytes AllowN
+// can ever allow at once, 0 if unlimited.
+func (l *StreamRateLimiter) Burst() int {
+	limit, burst := l.limits.PerStreamRateLimit(l.userID)
+	if limit == 0 {
+		return 0
+	}
+	return burst
+}
+
+type streamRateLimiterStrategy struct {
+	limits *validation.Overrides
+}
+
+func (s *streamRateLimiterStrategy) Limit(userID string) float64 {
+	limit, _ := s.limits.PerStreamRateLimit(userID)
+	if limit == 0 {
+		// equivalent to rate.Inf, which disables the limiter.
+		return math.MaxFloat64
+	}
+	return float64(limit)
+}
+
+func (s *streamRateLimiterStrategy) Burst(userID string) int {
+	_, burst := s.limits.PerStreamRateLimit(userID)
+	return burst
+}
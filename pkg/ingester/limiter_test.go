@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/util/flagext"
 	"github.com/grafana/loki/pkg/util/validation"
 )
 
@@ -116,6 +118,68 @@ func TestLimiter_AssertMaxStreamsPerUser(t *testing.T) {
 	}
 }
 
+func TestLimiter_StreamRateLimiter(t *testing.T) {
+	tests := map[string]struct {
+		limit, burst int
+		allowed      []bool
+	}{
+		"unlimited": {},
+		"burst defaults to the limit": {
+			limit:   100,
+			allowed: []bool{true, false, false},
+		},
+		"burst": {
+			limit:   100,
+			burst:   200,
+			allowed: []bool{true, true, true},
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			limits, err := validation.NewOverrides(validation.Limits{
+				PerStreamRateLimit:      flagext.ByteSize(testData.limit),
+				PerStreamRateLimitBurst: flagext.ByteSize(testData.burst),
+			}, nil)
+			require.NoError(t, err)
+
+			limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1).StreamRateLimiter("test")
+			now := time.Now()
+			var allowed []bool
+			for range testData.allowed {
+				allowed = append(allowed, limiter.AllowN(now, 60))
+			}
+			assert.Equal(t, testData.allowed, allowed)
+			assert.Equal(t, testData.limit, limiter.Limit())
+			if testData.limit == 0 {
+				// no rate limiter is allocated for the streams of tenants without a per stream limit.
+				assert.Nil(t, limiter.limiter)
+			}
+		})
+	}
+}
+
+func TestLimiter_StreamRateLimiterLimitSetLater(t *testing.T) {
+	tenantLimits := &validation.Limits{}
+	limits, err := validation.NewOverrides(validation.Limits{}, func(userID string) *validation.Limits {
+		return tenantLimits
+	})
+	require.NoError(t, err)
+
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1).StreamRateLimiter("test")
+	now := time.Now()
+	require.True(t, limiter.AllowN(now, 100))
+	require.Equal(t, 0, limiter.Burst())
+
+	// the limit applies to the streams created before it was set.
+	tenantLimits.PerStreamRateLimit = 100
+	require.True(t, limiter.AllowN(now, 60))
+	require.False(t, limiter.AllowN(now, 60))
+	require.Equal(t, 100, limiter.Burst())
+}
+
 func TestLimiter_minNonZero(t *testing.T) {
 	t.Parallel()
 
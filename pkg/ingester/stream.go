@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/util/validation"
 )
 
 var (
@@ -66,6 +67,8 @@ type stream struct {
 	labelsString string
 	factory      func() chunkenc.Chunk
 	lastLine     line
+	// limiter of the bytes rate of the stream, nil if unlimited.
+	limiter *StreamRateLimiter
 
 	tailers   map[uint32]*tailer
 	tailerMtx sync.RWMutex
@@ -76,6 +79,9 @@ type chunkDesc struct {
 	closed  bool
 	synced  bool
 	flushed time.Time
+	// volume of the entries appended to the chunk, nil for the chunks transferred by
+	// another ingester.
+	volume []logproto.VolumeBucket
 
 	lastUpdated time.Time
 }
@@ -85,13 +91,14 @@ type entryWithError struct {
 	e     error
 }
 
-func newStream(cfg *Config, fp model.Fingerprint, labels labels.Labels, factory func() chunkenc.Chunk) *stream {
+func newStream(cfg *Config, fp model.Fingerprint, labels labels.Labels, factory func() chunkenc.Chunk, limiter *StreamRateLimiter) *stream {
 	return &stream{
 		cfg:          cfg,
 		fp:           fp,
 		labels:       labels,
 		labelsString: labels.String(),
 		factory:      factory,
+		limiter:      limiter,
 		tailers:      map[uint32]*tailer{},
 	}
 }
@@ -111,7 +118,9 @@ func (s *stream) consumeChunk(_ context.Context, chunk *logproto.Chunk) error {
 	return nil
 }
 
-func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronizePeriod time.Duration, minUtilization float64) error {
+// Push appends the entries to the stream. The returned result reports the entries rejected for
+// being rate limited or out of order, which are also described by the returned error.
+func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronizePeriod time.Duration, minUtilization float64) (logproto.StreamPushResult, error) {
 	var lastChunkTimestamp time.Time
 	if len(s.chunks) == 0 {
 		s.chunks = append(s.chunks, chunkDesc{
@@ -124,6 +133,13 @@ func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronize
 
 	storedEntries := []logproto.Entry{}
 	failedEntriesWithError := []entryWithError{}
+	rateLimitedLines, rateLimitedBytes := 0, 0
+	burstExceededLines, burstExceededBytes := 0, 0
+	now := time.Now()
+	burst := 0
+	if s.limiter != nil {
+		burst = s.limiter.Burst()
+	}
 
 	// Don't fail on the first append error - if samples are sent out of order,
 	// we still want to append the later ones.
@@ -141,7 +157,37 @@ func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronize
 		}
 
 		chunk := &s.chunks[len(s.chunks)-1]
-		if chunk.closed || !chunk.chunk.SpaceFor(&entries[i]) || s.cutChunkForSynchronization(entries[i].Timestamp, lastChunkTimestamp, chunk, synchronizePeriod, minUtilization) {
+		full := chunk.closed || !chunk.chunk.SpaceFor(&entries[i])
+		synced := !full && s.cutChunkForSynchronization(entries[i].Timestamp, lastChunkTimestamp, chunk, synchronizePeriod, minUtilization)
+
+		// Out of order entries are rejected before the rate limit is checked, so that they don't
+		// use up the budget of the stream. Only the chunk the entry is appended to orders it.
+		if !full && !synced && entries[i].Timestamp.Before(lastChunkTimestamp) {
+			failedEntriesWithError = append(failedEntriesWithError, entryWithError{&entries[i], chunkenc.ErrOutOfOrder})
+			chunk.lastUpdated = time.Now()
+			continue
+		}
+
+		// The lines larger than the burst are never allowed by the rate limit, they are rejected
+		// for good instead of being retried.
+		if burst > 0 && len(entries[i].Line) > burst {
+			burstExceededLines++
+			burstExceededBytes += len(entries[i].Line)
+			continue
+		}
+
+		// Once an entry is rate limited, the following ones are rejected as well so that
+		// the client can retry them without them being out of order.
+		if rateLimitedLines > 0 || (s.limiter != nil && !s.limiter.AllowN(now, len(entries[i].Line))) {
+			rateLimitedLines++
+			rateLimitedBytes += len(entries[i].Line)
+			continue
+		}
+
+		if full || synced {
+			if synced {
+				chunk.synced = true
+			}
 			// If the chunk has no more space call Close to make sure anything in the head block is cut and compressed
 			err := chunk.chunk.Close()
 			if err != nil {
@@ -166,6 +212,7 @@ func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronize
 		} else {
 			// send only stored entries to tailers
 			storedEntries = append(storedEntries, entries[i])
+			chunk.volume = chunkenc.AppendVolume(chunk.volume, &entries[i])
 			lastChunkTimestamp = entries[i].Timestamp
 			s.lastLine = line{ts: lastChunkTimestamp, content: entries[i].Line}
 		}
@@ -199,8 +246,15 @@ func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronize
 		}()
 	}
 
+	result := logproto.StreamPushResult{
+		Labels:   s.labelsString,
+		Accepted: uint32(len(entries) - len(failedEntriesWithError) - burstExceededLines - rateLimitedLines),
+	}
+
+	var err error
 	if len(failedEntriesWithError) > 0 {
 		lastEntryWithErr := failedEntriesWithError[len(failedEntriesWithError)-1]
+		err = lastEntryWithErr.e
 		if lastEntryWithErr.e == chunkenc.ErrOutOfOrder {
 			// return bad http status request response with all failed entries
 			buf := bytes.Buffer{}
@@ -219,12 +273,33 @@ func (s *stream) Push(ctx context.Context, entries []logproto.Entry, synchronize
 
 			fmt.Fprintf(&buf, "total ignored: %d out of %d", len(failedEntriesWithError), len(entries))
 
-			return httpgrpc.Errorf(http.StatusBadRequest, buf.String())
+			result.Reject(validation.OutOfOrder, len(failedEntriesWithError), false, buf.String())
+			err = httpgrpc.Errorf(http.StatusBadRequest, buf.String())
 		}
-		return lastEntryWithErr.e
 	}
 
-	return nil
+	if burstExceededLines > 0 {
+		validation.DiscardedSamples.WithLabelValues(validation.StreamBurstExceeded, s.limiter.userID).Add(float64(burstExceededLines))
+		validation.DiscardedBytes.WithLabelValues(validation.StreamBurstExceeded, s.limiter.userID).Add(float64(burstExceededBytes))
+		msg := validation.StreamBurstExceededErrorMsg(burst, s.labelsString, burstExceededLines)
+		result.Reject(validation.StreamBurstExceeded, burstExceededLines, false, msg)
+		if err == nil {
+			err = httpgrpc.Errorf(http.StatusBadRequest, msg)
+		}
+	}
+
+	// The rate limited entries are retryable, which the status of the error tells the client,
+	// the entries rejected for good being reported by the result.
+	if rateLimitedLines > 0 {
+		validation.DiscardedSamples.WithLabelValues(validation.StreamRateLimit, s.limiter.userID).Add(float64(rateLimitedLines))
+		validation.DiscardedBytes.WithLabelValues(validation.StreamRateLimit, s.limiter.userID).Add(float64(rateLimitedBytes))
+		msg := validation.StreamRateLimitedErrorMsg(s.limiter.Limit(), s.labelsString, rateLimitedLines, rateLimitedBytes)
+		result.Reject(validation.StreamRateLimit, rateLimitedLines, true, msg)
+		result.Retryable = uint32(rateLimitedLines)
+		err = httpgrpc.Errorf(http.StatusTooManyRequests, msg)
+	}
+
+	return result, err
 }
 
 // Returns true, if chunk should be cut before adding new entry. This is done to make ingesters
@@ -242,12 +317,10 @@ func (s *stream) cutChunkForSynchronization(entryTimestamp, prevEntryTimestamp t
 	// if current entry timestamp has rolled over synchronization period
 	if cts < pts {
 		if minUtilization <= 0 {
-			c.synced = true
 			return true
 		}
 
 		if c.chunk.Utilization() > minUtilization {
-			c.synced = true
 			return true
 		}
 	}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util/validation"
 )
 
 func TestMaxReturnedStreamsErrors(t *testing.T) {
@@ -39,9 +40,10 @@ func TestMaxReturnedStreamsErrors(t *testing.T) {
 					{Name: "foo", Value: "bar"},
 				},
 				defaultFactory,
+				nil,
 			)
 
-			err := s.Push(context.Background(), []logproto.Entry{
+			_, err := s.Push(context.Background(), []logproto.Entry{
 				{Timestamp: time.Unix(int64(numLogs), 0), Line: "log"},
 			}, 0, 0)
 			require.NoError(t, err)
@@ -62,7 +64,7 @@ func TestMaxReturnedStreamsErrors(t *testing.T) {
 			fmt.Fprintf(&expected, "total ignored: %d out of %d", numLogs, numLogs)
 			expectErr := httpgrpc.Errorf(http.StatusBadRequest, expected.String())
 
-			err = s.Push(context.Background(), newLines, 0, 0)
+			_, err = s.Push(context.Background(), newLines, 0, 0)
 			require.Error(t, err)
 			require.Equal(t, expectErr.Error(), err.Error())
 		})
@@ -77,9 +79,10 @@ func TestPushDeduplication(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		defaultFactory,
+		nil,
 	)
 
-	err := s.Push(context.Background(), []logproto.Entry{
+	_, err := s.Push(context.Background(), []logproto.Entry{
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "newer, better test"},
@@ -90,6 +93,129 @@ func TestPushDeduplication(t *testing.T) {
 		"expected exact duplicate to be dropped and newer content with same timestamp to be appended")
 }
 
+func TestPushRateLimit(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{
+		PerStreamRateLimit:      10,
+		PerStreamRateLimitBurst: 10,
+	}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	s := newStream(
+		&Config{},
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		defaultFactory,
+		limiter.StreamRateLimiter("test"),
+	)
+
+	result, err := s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "aaaaaaaaaa"},
+		{Timestamp: time.Unix(2, 0), Line: "bbbbb"},
+		{Timestamp: time.Unix(3, 0), Line: "ccccc"},
+	}, 0, 0)
+	msg := validation.StreamRateLimitedErrorMsg(10, `{foo="bar"}`, 2, 10)
+	require.Equal(t, httpgrpc.Errorf(http.StatusTooManyRequests, msg), err)
+	require.Equal(t, 1, s.chunks[0].chunk.Size(), "expected the entries within the burst to be appended")
+	require.Equal(t, logproto.StreamPushResult{
+		Labels:   `{foo="bar"}`,
+		Accepted: 1,
+		Rejections: []logproto.PushRejection{
+			{Reason: validation.StreamRateLimit, Count: 2, Retryable: true, Error: msg},
+		},
+		Retryable: 2,
+	}, result)
+}
+
+func TestPushRateLimitOutOfOrder(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{
+		PerStreamRateLimit:      10,
+		PerStreamRateLimitBurst: 10,
+	}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	s := newStream(
+		&Config{},
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		defaultFactory,
+		limiter.StreamRateLimiter("test"),
+	)
+
+	_, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(2, 0), Line: "aaaaa"},
+	}, 0, 0)
+	require.NoError(t, err)
+
+	// the out of order entry is rejected without using up the budget of the valid one.
+	result, err := s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "bbbbb"},
+		{Timestamp: time.Unix(3, 0), Line: "ccccc"},
+	}, 0, 0)
+	require.Error(t, err)
+	require.Equal(t, 2, s.chunks[0].chunk.Size())
+	require.Equal(t, uint32(1), result.Accepted)
+	require.Len(t, result.Rejections, 1)
+	require.Equal(t, validation.OutOfOrder, result.Rejections[0].Reason)
+
+	// both the out of order and the rate limited entries are reported, the rate limited ones
+	// being retryable.
+	result, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "ddddd"},
+		{Timestamp: time.Unix(4, 0), Line: "eeeee"},
+	}, 0, 0)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusTooManyRequests), resp.Code)
+	require.Equal(t, uint32(0), result.Accepted)
+	require.Equal(t, uint32(1), result.Retryable)
+	require.Len(t, result.Rejections, 2)
+	require.Equal(t, validation.OutOfOrder, result.Rejections[0].Reason)
+	require.False(t, result.Rejections[0].Retryable)
+	require.Equal(t, validation.StreamRateLimit, result.Rejections[1].Reason)
+	require.True(t, result.Rejections[1].Retryable)
+}
+
+func TestPushBurstExceeded(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{
+		PerStreamRateLimit:      10,
+		PerStreamRateLimitBurst: 10,
+	}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	s := newStream(
+		&Config{},
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		defaultFactory,
+		limiter.StreamRateLimiter("test"),
+	)
+
+	// the line larger than the burst is rejected for good, without blocking the following ones.
+	result, err := s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "aaaaaaaaaaa"},
+		{Timestamp: time.Unix(2, 0), Line: "bbbbb"},
+	}, 0, 0)
+	msg := validation.StreamBurstExceededErrorMsg(10, `{foo="bar"}`, 1)
+	require.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, msg), err)
+	require.Equal(t, 1, s.chunks[0].chunk.Size())
+	require.Equal(t, logproto.StreamPushResult{
+		Labels:   `{foo="bar"}`,
+		Accepted: 1,
+		Rejections: []logproto.PushRejection{
+			{Reason: validation.StreamBurstExceeded, Count: 1, Error: msg},
+		},
+	}, result)
+}
+
 func TestStreamIterator(t *testing.T) {
 	const chunks = 3
 	const entries = 100
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	cortex_util "github.com/cortexproject/cortex/pkg/util"
@@ -16,9 +17,23 @@ import (
 	"github.com/grafana/loki/pkg/util"
 )
 
-const bufferSizeForTailResponse = 5
+const (
+	bufferSizeForTailResponse = 5
+	// Tailers with backpressure buffer more responses, and wait for the querier to read them
+	// before dropping entries.
+	bufferSizeForBackpressuredTailResponse = 100
+	backpressuredSendWait                  = time.Second
+
+	// maxBlockedTailDuration is how long a tailer is kept while it drops entries, or with backpressure
+	// while the querier doesn't read any response.
+	maxBlockedTailDuration = 15 * time.Second
+)
 
 type tailer struct {
+	// last time a response was sent to the querier, in unix nanoseconds.
+	// First in the struct for the 64-bit alignment required by atomic operations.
+	lastSentAt int64
+
 	id       uint32
 	orgID    string
 	matchers []*labels.Matcher
@@ -36,10 +51,13 @@ type tailer struct {
 	blockedMtx     sync.RWMutex
 	droppedStreams []*logproto.DroppedStream
 
+	// backpressure makes send wait for the querier to read the buffered responses.
+	backpressure bool
+
 	conn logproto.Querier_TailServer
 }
 
-func newTailer(orgID, query string, conn logproto.Querier_TailServer) (*tailer, error) {
+func newTailer(orgID, query string, backpressure bool, conn logproto.Querier_TailServer) (*tailer, error) {
 	expr, err := logql.ParseLogSelector(query)
 	if err != nil {
 		return nil, err
@@ -50,16 +68,23 @@ func newTailer(orgID, query string, conn logproto.Querier_TailServer) (*tailer,
 	}
 	matchers := expr.Matchers()
 
+	bufferSize := bufferSizeForTailResponse
+	if backpressure {
+		bufferSize = bufferSizeForBackpressuredTailResponse
+	}
+
 	return &tailer{
 		orgID:          orgID,
 		matchers:       matchers,
 		filter:         filter,
-		sendChan:       make(chan *logproto.Stream, bufferSizeForTailResponse),
+		sendChan:       make(chan *logproto.Stream, bufferSize),
 		conn:           conn,
 		droppedStreams: []*logproto.DroppedStream{},
 		id:             generateUniqueID(orgID, query),
 		closeChan:      make(chan struct{}),
 		expr:           expr,
+		backpressure:   backpressure,
+		lastSentAt:     time.Now().UnixNano(),
 	}, nil
 }
 
@@ -99,6 +124,7 @@ func (t *tailer) loop() {
 				t.close()
 				return
 			}
+			atomic.StoreInt64(&t.lastSentAt, time.Now().UnixNano())
 		}
 	}
 }
@@ -108,9 +134,17 @@ func (t *tailer) send(stream logproto.Stream) {
 		return
 	}
 
+	// the shards of a stream are tailed as the stream itself.
+	stream.Labels = util.RemoveStreamShard(stream.Labels)
+
+	if t.backpressure {
+		t.sendWithBackpressure(stream)
+		return
+	}
+
 	// if we are already dropping streams due to blocked connection, drop new streams directly to save some effort
 	if blockedSince := t.blockedSince(); blockedSince != nil {
-		if blockedSince.Before(time.Now().Add(-time.Second * 15)) {
+		if blockedSince.Before(time.Now().Add(-maxBlockedTailDuration)) {
 			t.close()
 			return
 		}
@@ -131,6 +165,37 @@ func (t *tailer) send(stream logproto.Stream) {
 	}
 }
 
+// sendWithBackpressure waits for the querier to read the buffered responses before dropping the stream.
+// The querier stops reading while its client is slow, so the tailer is only closed once the querier
+// hasn't read any response for maxBlockedTailDuration while entries are dropped.
+func (t *tailer) sendWithBackpressure(stream logproto.Stream) {
+	if t.blockedSince() != nil && time.Since(time.Unix(0, atomic.LoadInt64(&t.lastSentAt))) > maxBlockedTailDuration {
+		t.close()
+		return
+	}
+
+	t.filterEntriesInStream(&stream)
+
+	if len(stream.Entries) == 0 {
+		return
+	}
+
+	select {
+	case t.sendChan <- &stream:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(backpressuredSendWait)
+	defer timer.Stop()
+	select {
+	case t.sendChan <- &stream:
+	case <-t.closeChan:
+	case <-timer.C:
+		t.dropStream(stream)
+	}
+}
+
 func (t *tailer) filterEntriesInStream(stream *logproto.Stream) {
 	// Optimization: skip filtering entirely, if no filter is set
 	if t.filter == nil {
@@ -139,7 +204,7 @@ func (t *tailer) filterEntriesInStream(stream *logproto.Stream) {
 
 	var filteredEntries []logproto.Entry
 	for _, e := range stream.Entries {
-		if t.filter.Filter([]byte(e.Line)) {
+		if logql.FilterEntry(t.filter, []byte(e.Line), e.StructuredMetadata) {
 			filteredEntries = append(filteredEntries, e)
 		}
 	}
@@ -1,6 +1,8 @@
 package ingester
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -24,7 +26,7 @@ func TestTailer_sendRaceConditionOnSendWhileClosing(t *testing.T) {
 	}
 
 	for run := 0; run < runs; run++ {
-		tailer, err := newTailer("org-id", stream.Labels, nil)
+		tailer, err := newTailer("org-id", stream.Labels, false, nil)
 		require.NoError(t, err)
 		require.NotNil(t, tailer)
 
@@ -46,3 +48,97 @@ func TestTailer_sendRaceConditionOnSendWhileClosing(t *testing.T) {
 		routines.Wait()
 	}
 }
+
+// blockingTailServer blocks the responses until it is released.
+type blockingTailServer struct {
+	logproto.Querier_TailServer
+	ctx     context.Context
+	release chan struct{}
+
+	mtx       sync.Mutex
+	responses []*logproto.TailResponse
+}
+
+func (s *blockingTailServer) Context() context.Context { return s.ctx }
+
+func (s *blockingTailServer) Send(resp *logproto.TailResponse) error {
+	<-s.release
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func (s *blockingTailServer) received() (entries, dropped int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, resp := range s.responses {
+		entries += len(resp.Stream.Entries)
+		dropped += len(resp.DroppedStreams)
+	}
+	return entries, dropped
+}
+
+func TestTailer_Backpressure(t *testing.T) {
+	for _, backpressure := range []bool{false, true} {
+		t.Run(fmt.Sprintf("backpressure=%v", backpressure), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			server := &blockingTailServer{ctx: ctx, release: make(chan struct{})}
+
+			tailer, err := newTailer("org-id", `{type="test"}`, backpressure, server)
+			require.NoError(t, err)
+			go tailer.loop()
+			defer tailer.close()
+
+			// the querier reads the responses after a while.
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				close(server.release)
+			}()
+
+			sent := bufferSizeForBackpressuredTailResponse + 10
+			for i := 0; i < sent; i++ {
+				tailer.send(logproto.Stream{
+					Labels:  `{type="test"}`,
+					Entries: []logproto.Entry{{Timestamp: time.Unix(int64(i), 0), Line: "line"}},
+				})
+			}
+			// a last stream to report the dropped ones.
+			time.Sleep(50 * time.Millisecond)
+			tailer.send(logproto.Stream{
+				Labels:  `{type="test"}`,
+				Entries: []logproto.Entry{{Timestamp: time.Unix(int64(sent), 0), Line: "line"}},
+			})
+
+			require.Eventually(t, func() bool {
+				entries, dropped := server.received()
+				if backpressure {
+					return entries == sent+1 && dropped == 0
+				}
+				return entries+dropped == sent+1 && dropped > 0
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestTailer_RemovesStreamShard(t *testing.T) {
+	tailer, err := newTailer("org-id", `{type="test"}`, false, nil)
+	require.NoError(t, err)
+	defer tailer.close()
+
+	// the streams which don't fit in the buffer are dropped.
+	for i := 0; i <= bufferSizeForTailResponse; i++ {
+		tailer.send(logproto.Stream{
+			Labels:  fmt.Sprintf(`{__stream_shard__="%d", type="test"}`, i),
+			Entries: []logproto.Entry{{Timestamp: time.Unix(int64(i), 0), Line: "line"}},
+		})
+	}
+
+	for i := 0; i < bufferSizeForTailResponse; i++ {
+		require.Equal(t, `{type="test"}`, (<-tailer.sendChan).Labels)
+	}
+	dropped := tailer.popDroppedStreams()
+	require.Len(t, dropped, 1)
+	require.Equal(t, `{type="test"}`, dropped[0].Labels)
+}
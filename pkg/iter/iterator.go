@@ -261,7 +261,7 @@ func (i *heapIterator) Next() bool {
 			continue
 		}
 		// we count as duplicates only if the tuple is not the one (t) used to fill the current entry
-		if i.tuples[j] != t {
+		if i.tuples[j].EntryIterator != t.EntryIterator {
 			i.stats.TotalDuplicates++
 		}
 		i.requeue(i.tuples[j].EntryIterator, false)
@@ -1,16 +1,22 @@
 package client
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/gorilla/websocket"
 	json "github.com/json-iterator/go"
 	"github.com/prometheus/common/config"
@@ -18,6 +24,7 @@ import (
 	"github.com/grafana/loki/pkg/build"
 	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/stats"
 	"github.com/grafana/loki/pkg/util"
 )
 
@@ -27,13 +34,28 @@ const (
 	labelsPath      = "/loki/api/v1/labels"
 	labelValuesPath = "/loki/api/v1/label/%s/values"
 	seriesPath      = "/loki/api/v1/series"
+	indexStatsPath  = "/loki/api/v1/index/stats"
+	volumePath      = "/loki/api/v1/volume"
 	tailPath        = "/loki/api/v1/tail"
+	pushPath        = "/loki/api/v1/push"
+
+	protobufContentType = "application/x-protobuf"
 )
 
 var (
 	userAgent = fmt.Sprintf("loki-logcli/%s", build.Version)
 )
 
+// ErrorResponse is returned when the server answers a request with a non 2xx status code.
+type ErrorResponse struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("Error response from server: %s (%d)", e.Body, e.StatusCode)
+}
+
 // Client contains fields necessary to query a Loki instance
 type Client struct {
 	TLSConfig config.TLSConfig
@@ -80,6 +102,70 @@ func (c *Client) QueryRange(queryStr string, limit int, from, through time.Time,
 	return c.doQuery(queryRangePath, params.Encode(), quiet)
 }
 
+// QueryRangeStream uses the /api/v1/query_range endpoint to execute a streamed range log query,
+// calling f for every entry line of the response as soon as it's received. It returns the
+// statistics sent by the server once the query completes.
+// excluding interfacer b/c it suggests taking the interface promql.Node instead of logproto.Direction b/c it happens to have a String() method
+// nolint:interfacer
+func (c *Client) QueryRangeStream(queryStr string, limit int, from, through time.Time, direction logproto.Direction, interval time.Duration, quiet bool, f func(loghttp.QueryStreamLine) error) (*stats.Result, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	params.SetInt32("limit", limit)
+	params.SetInt("start", from.UnixNano())
+	params.SetInt("end", through.UnixNano())
+	params.SetString("direction", direction.String())
+	params.SetString("stream", "true")
+
+	if interval != 0 {
+		params.SetInt("interval", int64(interval.Seconds()))
+	}
+
+	resp, err := c.doHTTPRequest("GET", queryRangePath, params.Encode(), nil, "", quiet)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+	}()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line loghttp.QueryStreamLine
+		if err := dec.Decode(&line); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("streamed query response ended unexpectedly")
+			}
+			return nil, err
+		}
+		switch {
+		case line.Error != "":
+			return nil, fmt.Errorf("Error response from server: %s", line.Error)
+		case line.Statistics != nil:
+			return line.Statistics, nil
+		}
+		if err := f(line); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Push uses the /loki/api/v1/push endpoint to push the streams of the request as a snappy-compressed proto.
+func (c *Client) Push(request *logproto.PushRequest, quiet bool) error {
+	buf, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+	buf = snappy.Encode(nil, buf)
+
+	resp, err := c.doHTTPRequest("POST", pushPath, "", bytes.NewReader(buf), protobufContentType, quiet)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
 // ListLabelNames uses the /api/v1/label endpoint to list label names
 func (c *Client) ListLabelNames(quiet bool, from, through time.Time) (*loghttp.LabelResponse, error) {
 	var labelResponse loghttp.LabelResponse
@@ -119,6 +205,39 @@ func (c *Client) Series(matchers []string, from, through time.Time, quiet bool)
 	return &seriesResponse, nil
 }
 
+// IndexStats uses the /api/v1/index/stats endpoint to count the streams, chunks, lines and bytes
+// matching a selector, without running a query
+func (c *Client) IndexStats(queryStr string, from, through time.Time, quiet bool) (*loghttp.IndexStatsResponse, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	params.SetInt("start", from.UnixNano())
+	params.SetInt("end", through.UnixNano())
+
+	var statsResponse loghttp.IndexStatsResponse
+	if err := c.doRequest(indexStatsPath, params.Encode(), quiet, &statsResponse); err != nil {
+		return nil, err
+	}
+	return &statsResponse, nil
+}
+
+// Volume uses the /api/v1/volume endpoint to get the lines or bytes of the streams matching a
+// selector over time, grouped by the labels in by
+func (c *Client) Volume(queryStr string, from, through time.Time, step time.Duration, by []string, volumeType string, quiet bool) (*loghttp.QueryResponse, error) {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	params.SetInt("start", from.UnixNano())
+	params.SetInt("end", through.UnixNano())
+	if step != 0 {
+		params.SetInt("step", int64(step.Seconds()))
+	}
+	if len(by) > 0 {
+		params.SetString("by", strings.Join(by, ","))
+	}
+	params.SetString("type", volumeType)
+
+	return c.doQuery(volumePath, params.Encode(), quiet)
+}
+
 func (c *Client) doQuery(path string, query string, quiet bool) (*loghttp.QueryResponse, error) {
 	var err error
 	var r loghttp.QueryResponse
@@ -131,18 +250,36 @@ func (c *Client) doQuery(path string, query string, quiet bool) (*loghttp.QueryR
 }
 
 func (c *Client) doRequest(path, query string, quiet bool, out interface{}) error {
+	resp, err := c.doHTTPRequest("GET", path, query, nil, "", quiet)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+	}()
 
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doHTTPRequest sends a request and returns the response if it was successful.
+// The caller is responsible for closing the response body.
+func (c *Client) doHTTPRequest(method, path, query string, body io.Reader, contentType string, quiet bool) (*http.Response, error) {
 	us, err := buildURL(c.Address, path, query)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !quiet {
 		log.Print(us)
 	}
 
-	req, err := http.NewRequest("GET", us, nil)
+	req, err := http.NewRequest(method, us, body)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	req.SetBasicAuth(c.Username, c.Password)
@@ -159,34 +296,41 @@ func (c *Client) doRequest(path, query string, quiet bool, out interface{}) erro
 
 	client, err := config.NewClientFromConfig(clientConfig, "logcli", false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Println("error closing body", err)
-		}
-	}()
 
 	if resp.StatusCode/100 != 2 {
 		buf, _ := ioutil.ReadAll(resp.Body) // nolint
-		return fmt.Errorf("Error response from server: %s (%v)", string(buf), err)
+		if err := resp.Body.Close(); err != nil {
+			log.Println("error closing body", err)
+		}
+		return nil, &ErrorResponse{StatusCode: resp.StatusCode, Body: string(buf)}
 	}
 
-	return json.NewDecoder(resp.Body).Decode(out)
+	return resp, nil
 }
 
-// LiveTailQueryConn uses /api/prom/tail to set up a websocket connection and returns it
-func (c *Client) LiveTailQueryConn(queryStr string, delayFor int, limit int, from int64, quiet bool) (*websocket.Conn, error) {
+// LiveTailQueryConn uses /api/prom/tail to set up a websocket connection and returns it.
+// When cursor is not nil, the tail resumes after the entry of the cursor.
+func (c *Client) LiveTailQueryConn(queryStr string, delayFor int, limit int, from int64, cursor *loghttp.TailCursor, quiet bool) (*websocket.Conn, error) {
 	qsb := util.NewQueryStringBuilder()
 	qsb.SetString("query", queryStr)
 	qsb.SetInt("delay_for", int64(delayFor))
 	qsb.SetInt("limit", int64(limit))
 	qsb.SetInt("from", from)
+	if cursor != nil {
+		qsb.SetInt("resume_from", cursor.Timestamp.UnixNano())
+		hashes := make([]string, 0, len(cursor.Hashes))
+		for _, h := range cursor.Hashes {
+			hashes = append(hashes, strconv.FormatUint(h, 10))
+		}
+		qsb.SetStringArray("resume_hash", hashes)
+	}
 
 	return c.wsConnect(tailPath, qsb.Encode(), quiet)
 }
@@ -1,6 +1,17 @@
 package client
 
-import "testing"
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+)
 
 func Test_buildURL(t *testing.T) {
 	tests := []struct {
@@ -26,3 +37,57 @@ func Test_buildURL(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_QueryRangeStream(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		body    string
+		lines   int
+		wantErr bool
+	}{
+		{
+			"success",
+			`{"stream":{"foo":"bar"},"values":[["1","line 1"]]}
+{"stream":{"foo":"buzz"},"values":[["2","line 2"]]}
+{"stats":{"summary":{"totalLinesProcessed":2}}}
+`, 2, false,
+		},
+		{
+			"error",
+			`{"stream":{"foo":"bar"},"values":[["1","line 1"]]}
+{"error":"context deadline exceeded"}
+`, 1, true,
+		},
+		{
+			"truncated",
+			`{"stream":{"foo":"bar"},"values":[["1","line 1"]]}
+`, 1, true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, queryRangePath, r.URL.Path)
+				require.Equal(t, "true", r.URL.Query().Get("stream"))
+				require.Equal(t, "50000", r.URL.Query().Get("limit"))
+				fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			c := &Client{Address: server.URL}
+			var lines []loghttp.QueryStreamLine
+			result, err := c.QueryRangeStream(`{foo=~".+"}`, 50000, time.Unix(0, 0), time.Unix(10, 0), logproto.FORWARD, 0, true, func(line loghttp.QueryStreamLine) error {
+				lines = append(lines, line)
+				return nil
+			})
+			require.Len(t, lines, tc.lines)
+			require.Equal(t, loghttp.LabelSet{"foo": "bar"}, lines[0].Labels)
+			require.Equal(t, []loghttp.Entry{{Timestamp: time.Unix(0, 1), Line: "line 1"}}, lines[0].Entries)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int64(2), result.Summary.TotalLinesProcessed)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util"
+)
+
+const (
+	// chunks are cut as by the ingesters with their default configuration.
+	chunkBlockSize  = 256 * 1024
+	chunkTargetSize = 0
+
+	nameLabel = "__name__"
+	logsValue = "logs"
+)
+
+// chunkWriter writes the exported entries into chunks, one open chunk per stream.
+type chunkWriter struct {
+	dir    string
+	tenant string
+	chunks map[string]*exportChunk
+}
+
+type exportChunk struct {
+	labels labels.Labels
+	chunk  chunkenc.Chunk
+}
+
+func newChunkWriter(dir, tenant string) *chunkWriter {
+	return &chunkWriter{
+		dir:    dir,
+		tenant: tenant,
+		chunks: map[string]*exportChunk{},
+	}
+}
+
+func newExportChunk() chunkenc.Chunk {
+	return chunkenc.NewMemChunk(chunkenc.EncGZIP, chunkBlockSize, chunkTargetSize)
+}
+
+func (w *chunkWriter) write(batch loghttp.PushRequest) error {
+	for _, s := range batch.Streams {
+		key := s.Labels.String()
+		c, ok := w.chunks[key]
+		if !ok {
+			c = &exportChunk{labels: labels.FromMap(s.Labels), chunk: newExportChunk()}
+			w.chunks[key] = c
+		}
+		for _, e := range s.Entries {
+			entry := &logproto.Entry{Timestamp: e.Timestamp, Line: e.Line, StructuredMetadata: e.StructuredMetadata}
+			if !c.chunk.SpaceFor(entry) {
+				if err := w.flush(c); err != nil {
+					return err
+				}
+				c.chunk = newExportChunk()
+			}
+			if err := c.chunk.Append(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *chunkWriter) close() error {
+	for _, c := range w.chunks {
+		if err := w.flush(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush encodes the chunk as the ingesters do and writes it to the directory of its tenant.
+func (w *chunkWriter) flush(c *exportChunk) error {
+	if c.chunk.Size() == 0 {
+		return nil
+	}
+	if err := c.chunk.Close(); err != nil {
+		return err
+	}
+
+	metric := labels.NewBuilder(c.labels).Set(nameLabel, logsValue).Labels()
+	from, through := util.RoundToMilliseconds(c.chunk.Bounds())
+	wireChunk := chunk.NewChunk(
+		w.tenant, model.Fingerprint(c.labels.Hash()), metric,
+		chunkenc.NewFacade(c.chunk, chunkBlockSize, chunkTargetSize),
+		from,
+		through,
+	)
+	if err := wireChunk.Encode(); err != nil {
+		return err
+	}
+	buf, err := wireChunk.Encoded()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(w.dir, wireChunk.ExternalKey())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf, 0644)
+}
+
+// readChunk calls f with the stream of the entries of an encoded chunk.
+func readChunk(buf []byte, f func(logproto.PushRequest) error) error {
+	var c chunk.Chunk
+	if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+		return err
+	}
+	facade, ok := c.Data.(*chunkenc.Facade)
+	if !ok {
+		return fmt.Errorf("not a chunk of logs, encoding %s", c.Encoding)
+	}
+
+	it, err := facade.LokiChunk().Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	stream := logproto.Stream{Labels: labels.NewBuilder(c.Metric).Del(nameLabel).Labels().String()}
+	for it.Next() {
+		stream.Entries = append(stream.Entries, it.Entry())
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return f(logproto.PushRequest{Streams: []logproto.Stream{stream}})
+}
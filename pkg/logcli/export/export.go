@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// Output formats of an export.
+const (
+	// FormatNDJSON writes newline delimited JSON, each line being a push request body as accepted by
+	// /loki/api/v1/push, which makes it readable by Import or any Loki push client. Files with
+	// a .gz extension are gzip compressed.
+	FormatNDJSON = "ndjson"
+	// FormatChunks writes the entries into chunks encoded as Loki stores them, with their labels. The chunk
+	// files are written to a directory, under the tenant and named after their key as in the directory of the
+	// filesystem object store.
+	FormatChunks = "chunks"
+)
+
+// Export contains all necessary fields to export the entries of a log query to a file.
+type Export struct {
+	QueryString string
+	Start       time.Time
+	End         time.Time
+	// BatchSize is the number of entries requested per query, it must not exceed the
+	// max_entries_limit_per_query limit of the server.
+	BatchSize int
+	// Window is the length of the time ranges the export is queried by, it must not exceed the
+	// max_query_length limit of the server. 0 queries the whole time range at once.
+	Window     time.Duration
+	Format     string
+	OutputFile string
+	Quiet      bool
+}
+
+// DoExport exports the entries of the query to the output file.
+func (e *Export) DoExport(c *client.Client) {
+	out, err := e.output(c.OrgID)
+	if err != nil {
+		log.Fatalf("Unable to open output: %+v", err)
+	}
+
+	exported, err := e.Export(c, out)
+	if cerr := out.close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Fatalf("Export failed: %+v", err)
+	}
+
+	if !e.Quiet {
+		log.Printf("Exported %d entries", exported)
+	}
+}
+
+// Export pages through the time range of the export by windows of Window, and in batches of BatchSize
+// entries within each window, writing every batch to w. It returns the number of exported entries.
+func (e *Export) Export(c *client.Client, w batchWriter) (int, error) {
+	exported := 0
+	for start := e.Start; start.Before(e.End); {
+		end := e.End
+		if e.Window > 0 && start.Add(e.Window).Before(e.End) {
+			end = start.Add(e.Window)
+		}
+		n, err := e.exportWindow(c, w, start, end)
+		exported += n
+		if err != nil {
+			return exported, err
+		}
+		start = end
+	}
+	return exported, nil
+}
+
+// exportWindow exports the entries from start (inclusive) to end (exclusive).
+func (e *Export) exportWindow(c *client.Client, w batchWriter, start, end time.Time) (int, error) {
+	var (
+		exported = 0
+		// entries already written at the timestamp the next batch starts at, since the next
+		// batch includes them again.
+		boundary = map[string]struct{}{}
+	)
+
+	for start.Before(end) {
+		resp, err := c.QueryRange(e.QueryString, e.BatchSize, start, end, logproto.FORWARD, 0, 0, e.Quiet)
+		if err != nil {
+			return exported, err
+		}
+		streams, ok := resp.Data.Result.(loghttp.Streams)
+		if !ok {
+			return exported, fmt.Errorf("only log queries can be exported, got result type %s", resp.Data.ResultType)
+		}
+
+		batch, received, last := e.nextBatch(streams, start, boundary)
+		if len(batch.Streams) > 0 {
+			if err := w.write(batch); err != nil {
+				return exported, err
+			}
+			for _, s := range batch.Streams {
+				exported += len(s.Entries)
+			}
+		}
+
+		if received < e.BatchSize {
+			return exported, nil
+		}
+		if len(batch.Streams) == 0 {
+			return exported, fmt.Errorf("more than %d entries at %s, increase the batch size", e.BatchSize, start.Format(time.RFC3339Nano))
+		}
+		start = last
+	}
+	return exported, nil
+}
+
+// nextBatch builds the push request of the received streams, skipping the entries already exported
+// at the start of the batch. The boundary set is updated with the entries of the last timestamp of the batch,
+// which is returned with the number of received entries.
+func (e *Export) nextBatch(streams loghttp.Streams, start time.Time, boundary map[string]struct{}) (loghttp.PushRequest, int, time.Time) {
+	var (
+		batch    loghttp.PushRequest
+		received = 0
+		last     = start
+	)
+
+	for _, s := range streams {
+		received += len(s.Entries)
+		for _, entry := range s.Entries {
+			if entry.Timestamp.After(last) {
+				last = entry.Timestamp
+			}
+		}
+	}
+
+	next := map[string]struct{}{}
+	for i := range streams {
+		s := streams[i]
+		labels := s.Labels.String()
+		entries := make([]loghttp.Entry, 0, len(s.Entries))
+		for _, entry := range s.Entries {
+			key := entryKey(labels, entry)
+			if entry.Timestamp.Equal(start) {
+				if _, ok := boundary[key]; ok {
+					continue
+				}
+			}
+			if entry.Timestamp.Equal(last) {
+				next[key] = struct{}{}
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+		batch.Streams = append(batch.Streams, &loghttp.Stream{Labels: s.Labels, Entries: entries})
+	}
+
+	// the boundary carries over when the whole batch is at the same timestamp.
+	if last.Equal(start) {
+		for key := range boundary {
+			next[key] = struct{}{}
+		}
+	}
+	for key := range boundary {
+		delete(boundary, key)
+	}
+	for key := range next {
+		boundary[key] = struct{}{}
+	}
+	return batch, received, last
+}
+
+// batchWriter writes the batches of an export.
+type batchWriter interface {
+	write(batch loghttp.PushRequest) error
+	close() error
+}
+
+func (e *Export) output(tenant string) (batchWriter, error) {
+	switch e.Format {
+	case "", FormatNDJSON:
+		return e.ndjsonOutput()
+	case FormatChunks:
+		if e.OutputFile == "" || e.OutputFile == "-" {
+			return nil, errors.New("chunks can't be written to stdout, set the output directory")
+		}
+		if tenant == "" {
+			// the tenant of Loki when authentication is disabled.
+			tenant = "fake"
+		}
+		return newChunkWriter(e.OutputFile, tenant), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", e.Format)
+	}
+}
+
+func (e *Export) ndjsonOutput() (batchWriter, error) {
+	if e.OutputFile == "" || e.OutputFile == "-" {
+		return &ndjsonWriter{enc: json.NewEncoder(os.Stdout), closer: func() error { return nil }}, nil
+	}
+
+	f, err := os.Create(e.OutputFile)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(f)
+
+	if !strings.HasSuffix(e.OutputFile, ".gz") {
+		return &ndjsonWriter{enc: json.NewEncoder(buf), closer: func() error {
+			if err := buf.Flush(); err != nil {
+				_ = f.Close()
+				return err
+			}
+			return f.Close()
+		}}, nil
+	}
+
+	gz := gzip.NewWriter(buf)
+	return &ndjsonWriter{enc: json.NewEncoder(gz), closer: func() error {
+		if err := gz.Close(); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}}, nil
+}
+
+// ndjsonWriter writes every batch as a line of JSON.
+type ndjsonWriter struct {
+	enc    *json.Encoder
+	closer func() error
+}
+
+func (w *ndjsonWriter) write(batch loghttp.PushRequest) error {
+	return w.enc.Encode(batch)
+}
+
+func (w *ndjsonWriter) close() error {
+	return w.closer()
+}
+
+func entryKey(labels string, e loghttp.Entry) string {
+	return labels + e.Line
+}
@@ -0,0 +1,280 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/util"
+	json "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/marshal"
+)
+
+// queryRangeServer serves forward range log queries over streams, failing queries longer than maxQueryLength if set.
+func queryRangeServer(t *testing.T, streams []logproto.Stream, maxQueryLength time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "FORWARD", r.Form.Get("direction"))
+		start, err := strconv.ParseInt(r.Form.Get("start"), 10, 64)
+		require.NoError(t, err)
+		end, err := strconv.ParseInt(r.Form.Get("end"), 10, 64)
+		require.NoError(t, err)
+		if maxQueryLength > 0 && time.Duration(end-start) > maxQueryLength {
+			http.Error(w, "invalid query, length > limit", http.StatusBadRequest)
+			return
+		}
+		limit, err := strconv.Atoi(r.Form.Get("limit"))
+		require.NoError(t, err)
+
+		type entry struct {
+			labels string
+			logproto.Entry
+		}
+		var entries []entry
+		for _, s := range streams {
+			for _, e := range s.Entries {
+				if e.Timestamp.UnixNano() >= start && e.Timestamp.UnixNano() < end {
+					entries = append(entries, entry{s.Labels, e})
+				}
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+		if len(entries) > limit {
+			entries = entries[:limit]
+		}
+
+		result := map[string]*logproto.Stream{}
+		for _, e := range entries {
+			if _, ok := result[e.labels]; !ok {
+				result[e.labels] = &logproto.Stream{Labels: e.labels}
+			}
+			result[e.labels].Entries = append(result[e.labels].Entries, e.Entry)
+		}
+		var res logql.Streams
+		for _, s := range result {
+			res = append(res, *s)
+		}
+		require.NoError(t, marshal.WriteQueryResponseJSON(logql.Result{Data: res}, w))
+	}))
+}
+
+// pushServer records the streams pushed to it, failing the first failures requests.
+type pushServer struct {
+	*httptest.Server
+	mtx      sync.Mutex
+	streams  map[string][]logproto.Entry
+	failures int
+	tenants  []string
+}
+
+func newPushServer(t *testing.T, failures int) *pushServer {
+	s := &pushServer{streams: map[string][]logproto.Entry{}, failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		require.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		if s.failures > 0 {
+			s.failures--
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		var req logproto.PushRequest
+		_, err := util.ParseProtoReader(r.Context(), r.Body, int(r.ContentLength), math.MaxInt32, &req, util.RawSnappy)
+		require.NoError(t, err)
+		for _, stream := range req.Streams {
+			s.streams[stream.Labels] = append(s.streams[stream.Labels], stream.Entries...)
+		}
+		s.tenants = append(s.tenants, r.Header.Get("X-Scope-OrgID"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return s
+}
+
+func TestExportImport(t *testing.T) {
+	streams := []logproto.Stream{
+		{Labels: `{app="foo"}`},
+		{Labels: `{app="bar"}`},
+	}
+	for i := 0; i < 100; i++ {
+		streams[0].Entries = append(streams[0].Entries, logproto.Entry{Timestamp: time.Unix(int64(i/3), 0).UTC(), Line: "foo " + strconv.Itoa(i)})
+		if i%2 == 0 {
+			streams[1].Entries = append(streams[1].Entries, logproto.Entry{Timestamp: time.Unix(int64(i/3), 0).UTC(), Line: "bar " + strconv.Itoa(i)})
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "logcli-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		file   string
+		format string
+		window time.Duration
+	}{
+		{"export.ndjson", FormatNDJSON, 0},
+		{"export.ndjson.gz", FormatNDJSON, 0},
+		{"windows.ndjson", FormatNDJSON, 10 * time.Second},
+		{"chunks", FormatChunks, 10 * time.Second},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			queryServer := queryRangeServer(t, streams, tc.window)
+			defer queryServer.Close()
+
+			e := &Export{
+				QueryString: `{app=~"foo|bar"}`,
+				Start:       time.Unix(0, 0),
+				End:         time.Unix(1000, 0),
+				BatchSize:   7,
+				Window:      tc.window,
+				Format:      tc.format,
+				OutputFile:  filepath.Join(dir, tc.file),
+				Quiet:       true,
+			}
+			out, err := e.output("tenant")
+			require.NoError(t, err)
+			exported, err := e.Export(&client.Client{Address: queryServer.URL}, out)
+			require.NoError(t, err)
+			require.NoError(t, out.close())
+			require.Equal(t, 150, exported)
+			if tc.format == FormatChunks {
+				// a chunk per stream, named after its key.
+				files, err := ioutil.ReadDir(filepath.Join(e.OutputFile, "tenant"))
+				require.NoError(t, err)
+				require.Len(t, files, 2)
+				for _, f := range files {
+					_, err := chunk.ParseExternalKey("tenant", "tenant/"+f.Name())
+					require.NoError(t, err)
+				}
+			}
+
+			push := newPushServer(t, 2)
+			defer push.Close()
+
+			i := &Import{
+				Files:      []string{e.OutputFile},
+				BatchSize:  100,
+				MaxRetries: 5,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: time.Millisecond,
+				Quiet:      true,
+			}
+			imported, err := i.Import(context.Background(), &client.Client{Address: push.URL, OrgID: "tenant"})
+			require.NoError(t, err)
+			require.Equal(t, 150, imported)
+			require.Equal(t, map[string][]logproto.Entry{
+				`{app="foo"}`: streams[0].Entries,
+				`{app="bar"}`: streams[1].Entries,
+			}, push.streams)
+			for _, tenant := range push.tenants {
+				require.Equal(t, "tenant", tenant)
+			}
+		})
+	}
+}
+
+func TestExport_TooManyEntriesAtTimestamp(t *testing.T) {
+	stream := logproto.Stream{Labels: `{app="foo"}`}
+	for i := 0; i < 10; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(1, 0), Line: strconv.Itoa(i)})
+	}
+	server := queryRangeServer(t, []logproto.Stream{stream}, 0)
+	defer server.Close()
+
+	e := &Export{
+		QueryString: `{app="foo"}`,
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(10, 0),
+		BatchSize:   5,
+		Quiet:       true,
+	}
+	var buf bytes.Buffer
+	_, err := e.Export(&client.Client{Address: server.URL}, &ndjsonWriter{enc: json.NewEncoder(&buf), closer: func() error { return nil }})
+	require.Error(t, err)
+}
+
+func TestExport_QueryLengthLimit(t *testing.T) {
+	stream := logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "line"}}}
+	server := queryRangeServer(t, []logproto.Stream{stream}, time.Hour)
+	defer server.Close()
+
+	e := &Export{
+		QueryString: `{app="foo"}`,
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(0, 0).Add(2 * time.Hour),
+		BatchSize:   5,
+		Quiet:       true,
+	}
+	var buf bytes.Buffer
+	_, err := e.Export(&client.Client{Address: server.URL}, &ndjsonWriter{enc: json.NewEncoder(&buf), closer: func() error { return nil }})
+	require.Error(t, err)
+
+	e.Window = time.Hour
+	exported, err := e.Export(&client.Client{Address: server.URL}, &ndjsonWriter{enc: json.NewEncoder(&buf), closer: func() error { return nil }})
+	require.NoError(t, err)
+	require.Equal(t, 1, exported)
+}
+
+func TestImport_NonRetryableError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "entry out of order", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	f, err := ioutil.TempFile("", "logcli-import")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"streams":[{"stream":{"app":"foo"},"values":[["1","line"]]}]}` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	i := &Import{
+		Files:      []string{f.Name()},
+		BatchSize:  100,
+		MaxRetries: 5,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+		Quiet:      true,
+	}
+	_, err = i.Import(context.Background(), &client.Client{Address: server.URL})
+	require.Error(t, err)
+	var resp *client.ErrorResponse
+	require.True(t, errors.As(err, &resp))
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestListFiles_TimeOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logcli-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// window files named without zero padding, listed by name as 1, 10, 2.
+	for name, ts := range map[string]int{"window-1.ndjson": 1, "window-2.ndjson": 2, "window-10.ndjson": 10} {
+		line := `{"streams":[{"stream":{"app":"foo"},"values":[["` + strconv.Itoa(ts*int(time.Hour)) + `","line"]]}]}` + "\n"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(line), 0644))
+	}
+
+	files, err := listFiles([]string{dir})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "window-1.ndjson"),
+		filepath.Join(dir, "window-2.ndjson"),
+		filepath.Join(dir, "window-10.ndjson"),
+	}, files)
+}
@@ -0,0 +1,316 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/util"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/unmarshal"
+)
+
+// maxLineSize is the maximum size of a line of an imported file.
+const maxLineSize = 64 << 20
+
+// Import contains all necessary fields to push files written by Export to a Loki instance.
+type Import struct {
+	// Files are the files to import, the files of directories included, imported in the order of their first entry.
+	Files []string
+	// BatchSize is the maximum size in bytes of the log lines sent in a single push request.
+	BatchSize int
+	// RateLimit is the maximum number of log line bytes pushed per second, 0 to disable.
+	RateLimit  float64
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	Quiet      bool
+}
+
+// DoImport pushes the content of all files.
+func (i *Import) DoImport(c *client.Client) {
+	imported, err := i.Import(context.Background(), c)
+	if err != nil {
+		log.Fatalf("Import failed: %+v", err)
+	}
+	if !i.Quiet {
+		log.Printf("Imported %d entries", imported)
+	}
+}
+
+// Import pushes the content of all files in order, which keeps the entries of every stream
+// ordered as long as the files are. It returns the number of imported entries.
+func (i *Import) Import(ctx context.Context, c *client.Client) (int, error) {
+	var (
+		b        = newImportBatch()
+		imported = 0
+		pushed   = 0
+		start    = time.Now()
+	)
+	flush := func() error {
+		if b.empty() {
+			return nil
+		}
+		// delay the push until the average rate of pushed bytes is below the limit.
+		if i.RateLimit > 0 {
+			due := start.Add(time.Duration(float64(pushed) / i.RateLimit * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		if err := i.push(ctx, c, b.request()); err != nil {
+			return err
+		}
+		imported += b.entries
+		pushed += b.bytes
+		b = newImportBatch()
+		return nil
+	}
+
+	files, err := listFiles(i.Files)
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		err := i.readFile(file, func(req logproto.PushRequest) error {
+			for _, s := range req.Streams {
+				b.add(s)
+				if b.bytes >= i.BatchSize {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return imported, fmt.Errorf("importing %s: %w", file, err)
+		}
+	}
+	return imported, flush()
+}
+
+// listFiles returns the files, replacing the directories by the files they contain, sorted by the
+// time of their first entry. Window files and chunks of a stream are then imported in time order
+// whatever their names are.
+func listFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	starts := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		start, err := fileStart(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		starts[file] = start
+	}
+	sort.SliceStable(files, func(i, j int) bool { return starts[files[i]].Before(starts[files[j]]) })
+	return files, nil
+}
+
+// fileStart returns the time of the first entry of a file, read from the header of chunks and from
+// the first push request of newline delimited JSON files, which are written in time order.
+func fileStart(file string) (time.Time, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer in.Close()
+
+	r, isChunk, err := newFileReader(in)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if isChunk {
+		buf, err := ioutil.ReadAll(r)
+		if err != nil {
+			return time.Time{}, err
+		}
+		var c chunk.Chunk
+		if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+			return time.Time{}, err
+		}
+		return c.From.Time(), nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req logproto.PushRequest
+		if err := unmarshal.DecodePushRequest(bytes.NewReader(line), &req); err != nil {
+			return time.Time{}, err
+		}
+		var start time.Time
+		for _, s := range req.Streams {
+			for _, e := range s.Entries {
+				if start.IsZero() || e.Timestamp.Before(start) {
+					start = e.Timestamp
+				}
+			}
+		}
+		if !start.IsZero() {
+			return start, nil
+		}
+	}
+	return time.Time{}, scanner.Err()
+}
+
+// readFile calls f for every push request of the file, decompressing it first if it is gzip compressed.
+// Files which aren't newline delimited JSON are read as chunks.
+func (i *Import) readFile(file string, f func(logproto.PushRequest) error) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	r, isChunk, err := newFileReader(in)
+	if err != nil {
+		return err
+	}
+	if isChunk {
+		buf, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return readChunk(buf, f)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req logproto.PushRequest
+		if err := unmarshal.DecodePushRequest(bytes.NewReader(line), &req); err != nil {
+			return err
+		}
+		if err := f(req); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// newFileReader returns a reader of the content of the file, decompressed if it is gzip compressed,
+// and whether the file is a chunk rather than newline delimited JSON.
+func newFileReader(in io.Reader) (io.Reader, bool, error) {
+	r := bufio.NewReader(in)
+	magic, err := r.Peek(2)
+	if err != nil {
+		return r, false, nil
+	}
+	switch {
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, false, err
+		}
+		return gz, false, nil
+	case magic[0] != '{':
+		return r, true, nil
+	}
+	return r, false, nil
+}
+
+// push sends the request, retrying with backoff when the server is rate limiting or failing.
+func (i *Import) push(ctx context.Context, c *client.Client, req *logproto.PushRequest) error {
+	backoff := util.NewBackoff(ctx, util.BackoffConfig{
+		MinBackoff: i.MinBackoff,
+		MaxBackoff: i.MaxBackoff,
+		MaxRetries: i.MaxRetries,
+	})
+	var err error
+	for backoff.Ongoing() {
+		err = c.Push(req, i.Quiet)
+		if err == nil {
+			return nil
+		}
+		if resp, ok := err.(*client.ErrorResponse); ok && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode/100 != 5 {
+			return err
+		}
+		if !i.Quiet {
+			log.Printf("push failed, retrying: %v", err)
+		}
+		backoff.Wait()
+	}
+	if err == nil {
+		err = backoff.Err()
+	}
+	return err
+}
+
+// importBatch accumulates streams into a single push request, merging the entries of identical streams.
+type importBatch struct {
+	streams map[string]*logproto.Stream
+	order   []string
+	bytes   int
+	entries int
+}
+
+func newImportBatch() *importBatch {
+	return &importBatch{streams: map[string]*logproto.Stream{}}
+}
+
+func (b *importBatch) add(s logproto.Stream) {
+	stream, ok := b.streams[s.Labels]
+	if !ok {
+		stream = &logproto.Stream{Labels: s.Labels}
+		b.streams[s.Labels] = stream
+		b.order = append(b.order, s.Labels)
+	}
+	stream.Entries = append(stream.Entries, s.Entries...)
+	for _, e := range s.Entries {
+		b.bytes += len(e.Line)
+	}
+	b.entries += len(s.Entries)
+}
+
+func (b *importBatch) empty() bool {
+	return b.entries == 0
+}
+
+func (b *importBatch) request() *logproto.PushRequest {
+	req := &logproto.PushRequest{Streams: make([]logproto.Stream, 0, len(b.order))}
+	for _, labels := range b.order {
+		req.Streams = append(req.Streams, *b.streams[labels])
+	}
+	return req
+}
@@ -0,0 +1,116 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// CSVOutput prints logs and the samples of metric queries as CSV records, with either a column per
+// label of the Columns option or all the labels in a single column
+type CSVOutput struct {
+	options *LogOutputOptions
+}
+
+// Header returns the names of the columns of the entries
+func (o *CSVOutput) Header(maxLabelsLen int) string {
+	return o.record(append(append([]string{"timestamp"}, o.labelColumns()...), "line"))
+}
+
+// Format a log entry as a CSV record
+func (o *CSVOutput) Format(ts time.Time, lbls loghttp.LabelSet, maxLabelsLen int, line string) string {
+	fields := []string{ts.In(o.options.Timezone).Format(time.RFC3339Nano)}
+	fields = append(fields, o.labelValues(lbls)...)
+	return o.record(append(fields, strings.TrimRight(line, "\n")))
+}
+
+// FormatValue prints a record per sample of a matrix, vector or scalar, with its timestamp, labels and value
+func (o *CSVOutput) FormatValue(w io.Writer, value loghttp.ResultValue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(append([]string{"timestamp"}, o.labelColumns()...), "value")); err != nil {
+		return err
+	}
+
+	sample := func(ts model.Time, metric model.Metric, v model.SampleValue) error {
+		fields := []string{ts.Time().In(o.options.Timezone).Format(time.RFC3339Nano)}
+		fields = append(fields, o.labelValues(metricLabels(metric))...)
+		return cw.Write(append(fields, strconv.FormatFloat(float64(v), 'f', -1, 64)))
+	}
+
+	switch v := value.(type) {
+	case loghttp.Matrix:
+		for _, s := range v {
+			for _, p := range s.Values {
+				if err := sample(p.Timestamp, s.Metric, p.Value); err != nil {
+					return err
+				}
+			}
+		}
+	case loghttp.Vector:
+		for _, s := range v {
+			if err := sample(s.Timestamp, s.Metric, s.Value); err != nil {
+				return err
+			}
+		}
+	case loghttp.Scalar:
+		if err := sample(v.Timestamp, nil, v.Value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported value type %s", value.Type())
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (o *CSVOutput) labelColumns() []string {
+	if len(o.options.Columns) > 0 {
+		return o.options.Columns
+	}
+	if o.options.NoLabels {
+		return nil
+	}
+	return []string{"labels"}
+}
+
+func (o *CSVOutput) labelValues(lbls loghttp.LabelSet) []string {
+	if len(o.options.Columns) > 0 {
+		values := make([]string, 0, len(o.options.Columns))
+		for _, name := range o.options.Columns {
+			values = append(values, lbls[name])
+		}
+		return values
+	}
+	if o.options.NoLabels {
+		return nil
+	}
+	return []string{lbls.String()}
+}
+
+func metricLabels(metric model.Metric) loghttp.LabelSet {
+	lbls := make(loghttp.LabelSet, len(metric))
+	for name, value := range metric {
+		lbls[string(name)] = string(value)
+	}
+	return lbls
+}
+
+// record returns fields as a single CSV record, without the trailing new line.
+func (o *CSVOutput) record(fields []string) string {
+	var sb strings.Builder
+	cw := csv.NewWriter(&sb)
+	if err := cw.Write(fields); err != nil {
+		log.Fatalf("error writing record: %s", err)
+	}
+	cw.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+func TestCSVOutput_Format(t *testing.T) {
+	t.Parallel()
+
+	timestamp, _ := time.Parse(time.RFC3339Nano, "2006-01-02T15:04:05.123+07:00")
+	someLabels := loghttp.LabelSet(map[string]string{
+		"app":   "api",
+		"level": "error",
+	})
+
+	out := &CSVOutput{&LogOutputOptions{Timezone: time.UTC}}
+	assert.Equal(t, "timestamp,labels,line", out.Header(0))
+	assert.Equal(t, `2006-01-02T08:04:05.123Z,"{app=""api"", level=""error""}","msg=""failed, retrying"""`, out.Format(timestamp, someLabels, 0, `msg="failed, retrying"`+"\n"))
+
+	out = &CSVOutput{&LogOutputOptions{Timezone: time.UTC, NoLabels: true}}
+	assert.Equal(t, "timestamp,line", out.Header(0))
+	assert.Equal(t, "2006-01-02T08:04:05.123Z,Hello", out.Format(timestamp, someLabels, 0, "Hello"))
+
+	out = &CSVOutput{&LogOutputOptions{Timezone: time.UTC, Columns: []string{"level", "namespace"}}}
+	assert.Equal(t, "timestamp,level,namespace,line", out.Header(0))
+	assert.Equal(t, "2006-01-02T08:04:05.123Z,error,,Hello", out.Format(timestamp, someLabels, 0, "Hello"))
+}
+
+func TestCSVOutput_FormatValue(t *testing.T) {
+	t.Parallel()
+
+	out := &CSVOutput{&LogOutputOptions{Timezone: time.UTC, Columns: []string{"level"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, out.FormatValue(&buf, loghttp.Matrix{
+		{
+			Metric: model.Metric{"level": "error", "app": "api"},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 1}, {Timestamp: 60000, Value: 0.25}},
+		},
+	}))
+	assert.Equal(t, `timestamp,level,value
+1970-01-01T00:00:00Z,error,1
+1970-01-01T00:01:00Z,error,0.25
+`, buf.String())
+
+	out = &CSVOutput{&LogOutputOptions{Timezone: time.UTC}}
+	buf.Reset()
+	require.NoError(t, out.FormatValue(&buf, loghttp.Vector{
+		{Metric: model.Metric{"level": "error", "app": "api"}, Timestamp: 60000, Value: 3},
+	}))
+	assert.Equal(t, `timestamp,labels,value
+1970-01-01T00:01:00Z,"{app=""api"", level=""error""}",3
+`, buf.String())
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/grafana/loki/pkg/loghttp"
@@ -12,10 +13,26 @@ type LogOutput interface {
 	Format(ts time.Time, lbls loghttp.LabelSet, maxLabelsLen int, line string) string
 }
 
+// HeaderOutput is implemented by the output modes printing a header before the log entries.
+type HeaderOutput interface {
+	// Header returns the header of the entries formatted with the same maxLabelsLen.
+	Header(maxLabelsLen int) string
+}
+
+// MetricOutput is implemented by the output modes formatting the results of metric queries,
+// which are printed as JSON by the other output modes.
+type MetricOutput interface {
+	// FormatValue writes a matrix, vector or scalar value to w.
+	FormatValue(w io.Writer, value loghttp.ResultValue) error
+}
+
 // LogOutputOptions defines options supported by LogOutput
 type LogOutputOptions struct {
 	Timezone *time.Location
 	NoLabels bool
+	// Columns are the labels printed in their own column by the csv output, instead of
+	// all the labels in a single column.
+	Columns []string
 }
 
 // NewLogOutput creates a log output based on the input mode and options
@@ -37,6 +54,18 @@ func NewLogOutput(mode string, options *LogOutputOptions) (LogOutput, error) {
 		return &RawOutput{
 			options: options,
 		}, nil
+	case "table":
+		return &TableOutput{
+			options: options,
+		}, nil
+	case "csv":
+		return &CSVOutput{
+			options: options,
+		}, nil
+	case "promtext":
+		return &PromTextOutput{
+			options: options,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown log output mode '%s'", mode)
 	}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestNewLogOutput(t *testing.T) {
-	options := &LogOutputOptions{time.UTC, false}
+	options := &LogOutputOptions{time.UTC, false, nil}
 
 	out, err := NewLogOutput("default", options)
 	assert.NoError(t, err)
@@ -22,6 +22,18 @@ func TestNewLogOutput(t *testing.T) {
 	assert.NoError(t, err)
 	assert.IsType(t, &RawOutput{options}, out)
 
+	out, err = NewLogOutput("table", options)
+	assert.NoError(t, err)
+	assert.IsType(t, &TableOutput{options}, out)
+
+	out, err = NewLogOutput("csv", options)
+	assert.NoError(t, err)
+	assert.IsType(t, &CSVOutput{options}, out)
+
+	out, err = NewLogOutput("promtext", options)
+	assert.NoError(t, err)
+	assert.IsType(t, &PromTextOutput{options}, out)
+
 	out, err = NewLogOutput("unknown", options)
 	assert.Error(t, err)
 	assert.Nil(t, out)
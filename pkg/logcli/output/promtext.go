@@ -0,0 +1,90 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// promTextMetricName is the name of the series of metric queries, which have none.
+const promTextMetricName = "logql_query_result"
+
+// promTextEscaper escapes label values as in the Prometheus text format.
+var promTextEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PromTextOutput prints the samples of metric queries in the Prometheus text exposition format,
+// and logs in their original form like the raw output
+type PromTextOutput struct {
+	options *LogOutputOptions
+}
+
+// Format a log entry as is
+func (o *PromTextOutput) Format(ts time.Time, lbls loghttp.LabelSet, maxLabelsLen int, line string) string {
+	return strings.TrimSuffix(line, "\n")
+}
+
+// FormatValue prints a line per sample of a matrix, vector or scalar, with its timestamp in milliseconds
+func (o *PromTextOutput) FormatValue(w io.Writer, value loghttp.ResultValue) error {
+	var err error
+	sample := func(ts model.Time, metric model.Metric, v model.SampleValue) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, "%s %s %d\n", promTextSeries(metric), strconv.FormatFloat(float64(v), 'g', -1, 64), int64(ts))
+		}
+	}
+
+	switch v := value.(type) {
+	case loghttp.Matrix:
+		for _, s := range v {
+			for _, p := range s.Values {
+				sample(p.Timestamp, s.Metric, p.Value)
+			}
+		}
+	case loghttp.Vector:
+		for _, s := range v {
+			sample(s.Timestamp, s.Metric, s.Value)
+		}
+	case loghttp.Scalar:
+		sample(v.Timestamp, nil, v.Value)
+	default:
+		return fmt.Errorf("unsupported value type %s", value.Type())
+	}
+	return err
+}
+
+// promTextSeries returns the name and labels of a series, named promTextMetricName unless it has a name.
+func promTextSeries(metric model.Metric) string {
+	name := promTextMetricName
+	if n, ok := metric[model.MetricNameLabel]; ok {
+		name = string(n)
+	}
+
+	names := make([]string, 0, len(metric))
+	for n := range metric {
+		if n != model.MetricNameLabel {
+			names = append(names, string(n))
+		}
+	}
+	if len(names) == 0 {
+		return name
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `%s="%s"`, n, promTextEscaper.Replace(string(metric[model.LabelName(n)])))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+func TestPromTextOutput_Format(t *testing.T) {
+	t.Parallel()
+
+	out := &PromTextOutput{&LogOutputOptions{Timezone: time.UTC}}
+	assert.Equal(t, "Hello", out.Format(time.Now(), loghttp.LabelSet{"app": "api"}, 0, "Hello\n"))
+}
+
+func TestPromTextOutput_FormatValue(t *testing.T) {
+	t.Parallel()
+
+	out := &PromTextOutput{&LogOutputOptions{Timezone: time.UTC}}
+
+	var buf bytes.Buffer
+	require.NoError(t, out.FormatValue(&buf, loghttp.Matrix{
+		{
+			Metric: model.Metric{"level": "error", "msg": "a \"quoted\"\nmessage"},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: 1}, {Timestamp: 61000, Value: 0.25}},
+		},
+		{
+			Metric: model.Metric{},
+			Values: []model.SamplePair{{Timestamp: 1000, Value: model.SampleValue(math.Inf(1))}},
+		},
+	}))
+	assert.Equal(t, `logql_query_result{level="error",msg="a \"quoted\"\nmessage"} 1 1000
+logql_query_result{level="error",msg="a \"quoted\"\nmessage"} 0.25 61000
+logql_query_result +Inf 1000
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, out.FormatValue(&buf, loghttp.Vector{
+		{Metric: model.Metric{model.MetricNameLabel: "errors", "app": "api"}, Timestamp: 60000, Value: 3},
+	}))
+	require.NoError(t, out.FormatValue(&buf, loghttp.Scalar{Timestamp: 60000, Value: 2}))
+	assert.Equal(t, `errors{app="api"} 3 60000
+logql_query_result 2 60000
+`, buf.String())
+}
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// tableTimeFormat is RFC3339 with a fixed number of fractional digits, so that the timestamps of a
+// timezone have the same width.
+const tableTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// plotWidth is the maximum number of characters of the plots of the series of a matrix.
+const plotWidth = 40
+
+var plotRunes = []rune("▁▂▃▄▅▆▇█")
+
+// lineEscaper keeps each entry on a single row of the table.
+var lineEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// TableOutput prints logs as a table with aligned timestamp, labels and line columns, and the series
+// of metric queries with a summary and a plot of their values
+type TableOutput struct {
+	options *LogOutputOptions
+}
+
+// Header returns the header of the columns of the entries
+func (o *TableOutput) Header(maxLabelsLen int) string {
+	timestamp := padRight("TIMESTAMP", len(time.Time{}.In(o.options.Timezone).Format(tableTimeFormat)))
+	if o.options.NoLabels {
+		return fmt.Sprintf("%s | %s", timestamp, "LINE")
+	}
+	return fmt.Sprintf("%s | %s | %s", timestamp, padRight("LABELS", labelsWidth(maxLabelsLen)), "LINE")
+}
+
+// Format a log entry as a row of the table
+func (o *TableOutput) Format(ts time.Time, lbls loghttp.LabelSet, maxLabelsLen int, line string) string {
+	timestamp := ts.In(o.options.Timezone).Format(tableTimeFormat)
+	line = lineEscaper.Replace(strings.TrimRight(line, "\n"))
+
+	if o.options.NoLabels {
+		return fmt.Sprintf("%s | %s", timestamp, line)
+	}
+	return fmt.Sprintf("%s | %s | %s", timestamp, padRight(lbls.String(), labelsWidth(maxLabelsLen)), line)
+}
+
+// FormatValue prints the series of a matrix with the number of points, the min, max, average and last
+// of their values and a plot of the values over the time range of the matrix, or the samples of a
+// vector or a scalar in a table
+func (o *TableOutput) FormatValue(w io.Writer, value loghttp.ResultValue) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	switch v := value.(type) {
+	case loghttp.Matrix:
+		from, through, steps := matrixRange(v)
+		width := steps
+		if width > plotWidth {
+			width = plotWidth
+		}
+		fmt.Fprintln(tw, "SERIES\tPOINTS\tMIN\tMAX\tAVG\tLAST\tPLOT")
+		for _, s := range v {
+			if len(s.Values) == 0 {
+				continue
+			}
+			min, max, sum := math.Inf(1), math.Inf(-1), 0.0
+			for _, p := range s.Values {
+				min = math.Min(min, float64(p.Value))
+				max = math.Max(max, float64(p.Value))
+				sum += float64(p.Value)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", seriesName(s.Metric), len(s.Values),
+				formatFloat(min), formatFloat(max), formatFloat(sum/float64(len(s.Values))),
+				formatFloat(float64(s.Values[len(s.Values)-1].Value)), plot(s.Values, from, through, width, min, max))
+		}
+		if from != through {
+			fmt.Fprintf(tw, "\t\t\t\t\t\t%s - %s\n", from.Time().In(o.options.Timezone).Format(time.RFC3339), through.Time().In(o.options.Timezone).Format(time.RFC3339))
+		}
+	case loghttp.Vector:
+		fmt.Fprintln(tw, "SERIES\tVALUE")
+		for _, s := range v {
+			fmt.Fprintf(tw, "%s\t%s\n", seriesName(s.Metric), formatFloat(float64(s.Value)))
+		}
+	case loghttp.Scalar:
+		fmt.Fprintln(tw, "TIMESTAMP\tVALUE")
+		fmt.Fprintf(tw, "%s\t%s\n", v.Timestamp.Time().In(o.options.Timezone).Format(time.RFC3339), formatFloat(float64(v.Value)))
+	default:
+		return fmt.Errorf("unsupported value type %s", value.Type())
+	}
+	return tw.Flush()
+}
+
+// plot draws the values of a series over the time range of the matrix with width characters, scaled
+// between min and max. The characters without values are left blank, and those of several values
+// show the largest.
+func plot(values []model.SamplePair, from, through model.Time, width int, min, max float64) string {
+	columns := make([]float64, width)
+	filled := make([]bool, width)
+	for _, p := range values {
+		i := 0
+		if through > from {
+			i = int(math.Round(float64(p.Timestamp-from) / float64(through-from) * float64(width-1)))
+		}
+		if !filled[i] || float64(p.Value) > columns[i] {
+			columns[i] = float64(p.Value)
+		}
+		filled[i] = true
+	}
+
+	var sb strings.Builder
+	for i, v := range columns {
+		if !filled[i] {
+			sb.WriteRune(' ')
+			continue
+		}
+		level := len(plotRunes) - 1
+		if max > min {
+			level = int((v - min) / (max - min) * float64(len(plotRunes)-1))
+		}
+		sb.WriteRune(plotRunes[level])
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// matrixRange returns the timestamps of the first and last points of a matrix, along with the number
+// of steps between them, the step being the smallest interval between the timestamps of the points.
+func matrixRange(matrix loghttp.Matrix) (from, through model.Time, steps int) {
+	var timestamps []model.Time
+	for _, s := range matrix {
+		for _, p := range s.Values {
+			timestamps = append(timestamps, p.Timestamp)
+		}
+	}
+	if len(timestamps) == 0 {
+		return 0, 0, 0
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	var step model.Time
+	for i := 1; i < len(timestamps); i++ {
+		if d := timestamps[i] - timestamps[i-1]; d > 0 && (step == 0 || d < step) {
+			step = d
+		}
+	}
+
+	from, through = timestamps[0], timestamps[len(timestamps)-1]
+	if step == 0 {
+		return from, through, 1
+	}
+	return from, through, int((through-from)/step) + 1
+}
+
+func seriesName(metric model.Metric) string {
+	if len(metric) == 0 {
+		return "{}"
+	}
+	return metric.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// labelsWidth returns the width of the labels column, which is at least the width of its header.
+func labelsWidth(maxLabelsLen int) int {
+	if maxLabelsLen < len("LABELS") {
+		return len("LABELS")
+	}
+	return maxLabelsLen
+}
+
+func padRight(s string, n int) string {
+	if len(s) < n {
+		s += strings.Repeat(" ", n-len(s))
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+func TestTableOutput_Format(t *testing.T) {
+	t.Parallel()
+
+	timestamp, _ := time.Parse(time.RFC3339, "2006-01-02T15:04:05+07:00")
+	someLabels := loghttp.LabelSet(map[string]string{
+		"type": "test",
+	})
+
+	out := &TableOutput{&LogOutputOptions{Timezone: time.UTC}}
+	assert.Equal(t, "TIMESTAMP                | LABELS           | LINE", out.Header(16))
+	assert.Equal(t, `2006-01-02T08:04:05.000Z | {type="test"}    | multi\nline\twith tab`, out.Format(timestamp, someLabels, 16, "multi\nline\twith tab\n"))
+
+	// the labels column is as wide as its header
+	assert.Equal(t, "TIMESTAMP                | LABELS | LINE", out.Header(2))
+	assert.Equal(t, "2006-01-02T08:04:05.000Z | {}     | Hello", out.Format(timestamp, loghttp.LabelSet{}, 2, "Hello"))
+
+	out = &TableOutput{&LogOutputOptions{Timezone: time.FixedZone("UTC+2", 2*60*60), NoLabels: true}}
+	assert.Equal(t, "TIMESTAMP                     | LINE", out.Header(16))
+	assert.Equal(t, "2006-01-02T10:04:05.000+02:00 | Hello", out.Format(timestamp, someLabels, 16, "Hello"))
+}
+
+func TestTableOutput_FormatValue(t *testing.T) {
+	t.Parallel()
+
+	out := &TableOutput{&LogOutputOptions{Timezone: time.UTC}}
+
+	var buf bytes.Buffer
+	require.NoError(t, out.FormatValue(&buf, loghttp.Matrix{
+		{
+			Metric: model.Metric{"level": "error"},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 1}, {Timestamp: 60000, Value: 3}, {Timestamp: 120000, Value: 5}, {Timestamp: 240000, Value: 2}},
+		},
+		{
+			Metric: model.Metric{"level": "info"},
+			Values: []model.SamplePair{{Timestamp: 120000, Value: 10}},
+		},
+	}))
+	assert.Equal(t, `SERIES           POINTS  MIN  MAX  AVG   LAST  PLOT
+{level="error"}  4       1    5    2.75  2     ▁▄█ ▂
+{level="info"}   1       10   10   10    10      █
+                                               1970-01-01T00:00:00Z - 1970-01-01T00:04:00Z
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, out.FormatValue(&buf, loghttp.Vector{
+		{Metric: model.Metric{"level": "error"}, Value: 0.5},
+		{Metric: model.Metric{}, Value: 42},
+	}))
+	assert.Equal(t, `SERIES           VALUE
+{level="error"}  0.5
+{}               42
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, out.FormatValue(&buf, loghttp.Scalar{Timestamp: 0, Value: 1}))
+	assert.Equal(t, `TIMESTAMP             VALUE
+1970-01-01T00:00:00Z  1
+`, buf.String())
+}
@@ -0,0 +1,154 @@
+package query
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/golang/snappy"
+	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// chunkFile is a chunk file of a directory, indexed with the header of the chunk.
+type chunkFile struct {
+	path string
+	// header holds the metadata of the chunk, its data is only decoded when queried.
+	header chunk.Chunk
+	labels string
+}
+
+// chunkDir is an in-memory index of the chunk files of a directory, such as the chunks directory
+// of a filesystem store or chunks copied from an object store, e.g. to be inspected with chunks-inspect.
+// It implements logql.Querier, only decoding the chunks whose labels and time range match the queries.
+type chunkDir struct {
+	files []chunkFile
+}
+
+// loadChunkDir indexes the chunks of the tenant found in dir and its sub-directories, or the chunks of
+// all the tenants when orgID is empty. Files which are not chunks are skipped.
+func loadChunkDir(dir, orgID string, quiet bool) (*chunkDir, error) {
+	d := &chunkDir{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		header, err := readChunkHeader(path)
+		if err != nil {
+			if !quiet {
+				log.Printf("Skipping %s: %v", path, err)
+			}
+			return nil
+		}
+		if orgID != "" && header.UserID != orgID {
+			return nil
+		}
+
+		d.files = append(d.files, chunkFile{
+			path:   path,
+			header: header,
+			labels: labels.NewBuilder(header.Metric).Del(labels.MetricName).Labels().String(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// readChunkHeader decodes the metadata at the start of a chunk file, without reading its data.
+func readChunkHeader(path string) (chunk.Chunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return chunk.Chunk{}, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var metadataLen uint32
+	if err := binary.Read(r, binary.BigEndian, &metadataLen); err != nil {
+		return chunk.Chunk{}, errors.Wrap(err, "when reading metadata length from chunk")
+	}
+
+	var header chunk.Chunk
+	if err := json.ConfigFastest.NewDecoder(snappy.NewReader(r)).Decode(&header); err != nil {
+		return chunk.Chunk{}, errors.Wrap(err, "when decoding chunk metadata")
+	}
+	if len(header.Metric) == 0 {
+		return chunk.Chunk{}, errors.New("chunk metadata without labels")
+	}
+	return header, nil
+}
+
+// Select implements logql.Querier.
+func (d *chunkDir) Select(ctx context.Context, params logql.SelectParams) (iter.EntryIterator, error) {
+	expr, err := params.LogSelector()
+	if err != nil {
+		return nil, err
+	}
+	filter, err := expr.Filter()
+	if err != nil {
+		return nil, err
+	}
+	matchers := expr.Matchers()
+	from, through := util.RoundToMilliseconds(params.Start, params.End)
+
+	var iterators []iter.EntryIterator
+outer:
+	for _, f := range d.files {
+		if f.header.Through < from || through < f.header.From {
+			continue
+		}
+		for _, m := range matchers {
+			if !m.Matches(f.header.Metric.Get(m.Name)) {
+				continue outer
+			}
+		}
+
+		c, err := f.decode()
+		if err != nil {
+			return nil, errors.Wrap(err, f.path)
+		}
+		it, err := c.Data.(*chunkenc.Facade).LokiChunk().Iterator(ctx, params.Start, params.End, params.Direction, filter)
+		if err != nil {
+			return nil, errors.Wrap(err, f.path)
+		}
+		iterators = append(iterators, iter.NewNonOverlappingIterator([]iter.EntryIterator{it}, f.labels))
+	}
+
+	// the entries replicated in the chunks of several ingesters are deduplicated by the heap iterator.
+	return iter.NewHeapIterator(ctx, iterators, params.Direction), nil
+}
+
+// decode reads and decodes the whole chunk.
+func (f *chunkFile) decode() (chunk.Chunk, error) {
+	buf, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return chunk.Chunk{}, err
+	}
+
+	var c chunk.Chunk
+	if err := c.Decode(chunk.NewDecodeContext(), buf); err != nil {
+		return chunk.Chunk{}, err
+	}
+	if _, ok := c.Data.(*chunkenc.Facade); !ok {
+		return chunk.Chunk{}, errors.Errorf("unexpected chunk encoding %v", c.Encoding)
+	}
+	return c, nil
+}
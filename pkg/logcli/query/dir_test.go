@@ -0,0 +1,99 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/ingester/client"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+)
+
+// writeTestChunk writes a chunk of the entries at the given seconds to dir.
+func writeTestChunk(t *testing.T, dir, userID, name, lbs string, seconds ...int64) {
+	ls, err := parser.ParseMetric(lbs)
+	require.NoError(t, err)
+	ls = labels.NewBuilder(ls).Set(labels.MetricName, "logs").Labels()
+
+	chk := chunkenc.NewMemChunk(chunkenc.EncGZIP, 256*1024, 0)
+	for _, s := range seconds {
+		require.NoError(t, chk.Append(&logproto.Entry{Timestamp: time.Unix(s, 0), Line: fmt.Sprintf("line %d", s)}))
+	}
+	require.NoError(t, chk.Close())
+
+	c := chunk.NewChunk(userID, client.Fingerprint(ls), ls, chunkenc.NewFacade(chk, 0, 0),
+		model.TimeFromUnix(seconds[0]), model.TimeFromUnix(seconds[len(seconds)-1]))
+	require.NoError(t, c.Encode())
+	buf, err := c.Encoded()
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, userID), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, userID, name), buf, 0666))
+}
+
+func Test_chunkDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logcli-chunks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestChunk(t, dir, "fake", "1", `{app="foo"}`, 10, 20, 30)
+	// replicated by another ingester
+	writeTestChunk(t, dir, "fake", "2", `{app="foo"}`, 10, 20, 30)
+	writeTestChunk(t, dir, "fake", "3", `{app="foo"}`, 100, 110)
+	writeTestChunk(t, dir, "fake", "4", `{app="bar"}`, 10, 20)
+	writeTestChunk(t, dir, "other", "5", `{app="foo"}`, 10, 20)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "not-a-chunk"), []byte("foo"), 0666))
+
+	d, err := loadChunkDir(dir, "fake", true)
+	require.NoError(t, err)
+	require.Len(t, d.files, 4)
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	eng := logql.NewEngine(logql.EngineOpts{}, d)
+
+	// log query, with the chunks outside of the time range not being decoded: the data of the
+	// third chunk is truncated, only its header is valid.
+	info, err := os.Stat(filepath.Join(dir, "fake", "3"))
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(filepath.Join(dir, "fake", "3"), info.Size()-10))
+	res, err := eng.Query(logql.NewLiteralParams(`{app="foo"} |= "line"`, time.Unix(0, 0), time.Unix(60, 0), 0, 0, logproto.FORWARD, 100, nil)).Exec(ctx)
+	require.NoError(t, err)
+	streams := res.Data.(logql.Streams)
+	require.Len(t, streams, 1)
+	require.Equal(t, `{app="foo"}`, streams[0].Labels)
+	require.Len(t, streams[0].Entries, 3)
+	require.Equal(t, "line 10", streams[0].Entries[0].Line)
+
+	// metric query
+	res, err = eng.Query(logql.NewLiteralParams(`sum by (app) (count_over_time({app=~".+"}[1m]))`, time.Unix(60, 0), time.Unix(60, 0), 0, 0, logproto.FORWARD, 100, nil)).Exec(ctx)
+	require.NoError(t, err)
+	vector := res.Data.(promql.Vector)
+	require.Len(t, vector, 2)
+	values := map[string]float64{}
+	for _, s := range vector {
+		values[s.Metric.Get("app")] = s.V
+	}
+	require.Equal(t, map[string]float64{"foo": 3, "bar": 2}, values)
+
+	_, err = eng.Query(logql.NewLiteralParams(`{app="foo"}`, time.Unix(0, 0), time.Unix(200, 0), 0, 0, logproto.FORWARD, 100, nil)).Exec(ctx)
+	require.Error(t, err)
+
+	// all the tenants
+	d, err = loadChunkDir(dir, "", true)
+	require.NoError(t, err)
+	require.Len(t, d.files, 5)
+}
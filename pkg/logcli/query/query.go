@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"sort"
 	"strings"
@@ -50,18 +51,35 @@ type Query struct {
 	IgnoreLabelsKey []string
 	ShowLabelsKey   []string
 	FixedLabelsLen  int
+	// Stream prints the entries of range log queries as they are received, which is not subject
+	// to the max_entries_limit_per_query limit of the server.
+	Stream bool
 
 	LocalConfig string
+	// FromDir is a directory of chunk files the query is executed against, without an index.
+	FromDir string
 }
 
 // DoQuery executes the query and prints out the results
 func (q *Query) DoQuery(c *client.Client, out output.LogOutput, statistics bool) {
+	if err := q.Exec(c, out, statistics); err != nil {
+		log.Fatalf("Query failed: %+v", err)
+	}
+}
 
+// Exec executes the query and prints out the results, returning the error of a failed query
+// instead of exiting.
+func (q *Query) Exec(c *client.Client, out output.LogOutput, statistics bool) error {
 	if q.LocalConfig != "" {
-		if err := q.DoLocalQuery(out, statistics, c.OrgID); err != nil {
-			log.Fatalf("Query failed: %+v", err)
-		}
-		return
+		return q.DoLocalQuery(out, statistics, c.OrgID)
+	}
+
+	if q.FromDir != "" {
+		return q.DoDirQuery(out, statistics, c.OrgID)
+	}
+
+	if q.Stream && !q.isInstant() {
+		return q.DoStreamQuery(c, out, statistics)
 	}
 
 	d := q.resultsDirection()
@@ -76,7 +94,7 @@ func (q *Query) DoQuery(c *client.Client, out output.LogOutput, statistics bool)
 	}
 
 	if err != nil {
-		log.Fatalf("Query failed: %+v", err)
+		return err
 	}
 
 	if statistics {
@@ -84,19 +102,54 @@ func (q *Query) DoQuery(c *client.Client, out output.LogOutput, statistics bool)
 	}
 
 	q.printResult(resp.Data.Result, out)
+	return nil
+}
+
+// DoStreamQuery executes a range log query as a streamed query and prints the entries as soon as
+// they are received. Entries are printed in the order they are read by the server, and since the
+// labels of the whole result are unknown upfront common labels are not removed.
+func (q *Query) DoStreamQuery(c *client.Client, out output.LogOutput, statistics bool) error {
+	if len(q.IgnoreLabelsKey) > 0 && !q.Quiet {
+		log.Println("Ignoring labels key:", color.RedString(strings.Join(q.IgnoreLabelsKey, ",")))
+	}
+
+	maxLabelsLen := q.FixedLabelsLen
+	printHeader(out, maxLabelsLen)
+	result, err := c.QueryRangeStream(q.QueryString, q.Limit, q.Start, q.End, q.resultsDirection(), q.Interval, q.Quiet, func(line loghttp.QueryStreamLine) error {
+		ls := line.Labels
+		if len(q.IgnoreLabelsKey) > 0 {
+			ls = matchLabels(false, ls, q.IgnoreLabelsKey)
+		}
+		if l := len(ls.String()); maxLabelsLen < l {
+			maxLabelsLen = l
+		}
+		for _, e := range line.Entries {
+			fmt.Println(out.Format(e.Timestamp, ls, maxLabelsLen, e.Line))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
+	if statistics {
+		q.printStats(*result)
+	}
+	return nil
 }
 
 func (q *Query) printResult(value loghttp.ResultValue, out output.LogOutput) {
 	switch value.Type() {
 	case logql.ValueTypeStreams:
 		q.printStream(value.(loghttp.Streams), out)
-	case parser.ValueTypeScalar:
-		q.printScalar(value.(loghttp.Scalar))
-	case parser.ValueTypeMatrix:
-		q.printMatrix(value.(loghttp.Matrix))
-	case parser.ValueTypeVector:
-		q.printVector(value.(loghttp.Vector))
+	case parser.ValueTypeScalar, parser.ValueTypeMatrix, parser.ValueTypeVector:
+		if metricOut, ok := out.(output.MetricOutput); ok {
+			if err := metricOut.FormatValue(os.Stdout, value); err != nil {
+				log.Fatalf("Error printing %s: %v", value.Type(), err)
+			}
+			return
+		}
+		q.printValue(value)
 	default:
 		log.Fatalf("Unable to print unsupported type: %v", value.Type())
 	}
@@ -122,7 +175,25 @@ func (q *Query) DoLocalQuery(out output.LogOutput, statistics bool, orgID string
 		return err
 	}
 
-	eng := logql.NewEngine(conf.Querier.Engine, querier)
+	return q.execEngine(logql.NewEngine(conf.Querier.Engine, querier), out, statistics, orgID)
+}
+
+// DoDirQuery executes the query against the chunk files of the FromDir directory, using an in-memory
+// index of their headers instead of the index of a store.
+func (q *Query) DoDirQuery(out output.LogOutput, statistics bool, orgID string) error {
+	dir, err := loadChunkDir(q.FromDir, orgID, q.Quiet)
+	if err != nil {
+		return err
+	}
+
+	if !q.Quiet {
+		log.Printf("Indexed %d chunk files from %s", len(dir.files), q.FromDir)
+	}
+	return q.execEngine(logql.NewEngine(logql.EngineOpts{}, dir), out, statistics, orgID)
+}
+
+// execEngine executes the query with a local LogQL engine and prints out the results.
+func (q *Query) execEngine(eng *logql.Engine, out output.LogOutput, statistics bool, orgID string) error {
 	var query logql.Query
 
 	if q.isInstant() {
@@ -137,11 +208,16 @@ func (q *Query) DoLocalQuery(out output.LogOutput, statistics bool, orgID string
 			nil,
 		))
 	} else {
+		step := q.Step
+		if step == 0 {
+			// same default step as the query range API of the server.
+			step = time.Duration(math.Max(math.Floor(q.End.Sub(q.Start).Seconds()/250), 1)) * time.Second
+		}
 		query = eng.Query(logql.NewLiteralParams(
 			q.QueryString,
 			q.Start,
 			q.End,
-			q.Step,
+			step,
 			q.Interval,
 			q.resultsDirection(),
 			uint32(q.Limit),
@@ -249,42 +325,31 @@ func (q *Query) printStream(streams loghttp.Streams, out output.LogOutput) {
 		sort.Slice(allEntries, func(i, j int) bool { return allEntries[i].entry.Timestamp.After(allEntries[j].entry.Timestamp) })
 	}
 
+	printHeader(out, maxLabelsLen)
 	for _, e := range allEntries {
 		fmt.Println(out.Format(e.entry.Timestamp, e.labels, maxLabelsLen, e.entry.Line))
 	}
 }
 
-func (q *Query) printMatrix(matrix loghttp.Matrix) {
+// printValue prints a matrix, vector or scalar as JSON.
+func (q *Query) printValue(value loghttp.ResultValue) {
 	// yes we are effectively unmarshalling and then immediately marshalling this object back to json.  we are doing this b/c
 	// it gives us more flexibility with regard to output types in the future.  initially we are supporting just formatted json but eventually
 	// we might add output options such as render to an image file on disk
-	bytes, err := json.MarshalIndent(matrix, "", "  ")
-
-	if err != nil {
-		log.Fatalf("Error marshalling matrix: %v", err)
-	}
-
-	fmt.Print(string(bytes))
-}
-
-func (q *Query) printVector(vector loghttp.Vector) {
-	bytes, err := json.MarshalIndent(vector, "", "  ")
+	bytes, err := json.MarshalIndent(value, "", "  ")
 
 	if err != nil {
-		log.Fatalf("Error marshalling vector: %v", err)
+		log.Fatalf("Error marshalling %s: %v", value.Type(), err)
 	}
 
 	fmt.Print(string(bytes))
 }
 
-func (q *Query) printScalar(scalar loghttp.Scalar) {
-	bytes, err := json.MarshalIndent(scalar, "", "  ")
-
-	if err != nil {
-		log.Fatalf("Error marshalling scalar: %v", err)
+// printHeader prints the header of the output modes having one.
+func printHeader(out output.LogOutput, maxLabelsLen int) {
+	if headerOut, ok := out.(output.HeaderOutput); ok {
+		fmt.Println(headerOut.Header(maxLabelsLen))
 	}
-
-	fmt.Print(string(bytes))
 }
 
 type kvLogger struct {
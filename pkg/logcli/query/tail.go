@@ -1,13 +1,16 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/cortexproject/cortex/pkg/util"
 	"github.com/fatih/color"
 	"github.com/gorilla/websocket"
 
@@ -16,24 +19,36 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 )
 
-// TailQuery connects to the Loki websocket endpoint and tails logs
-func (q *Query) TailQuery(delayFor int, c *client.Client, out output.LogOutput) {
-	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start.UnixNano(), q.Quiet)
-	if err != nil {
-		log.Fatalf("Tailing logs failed: %+v", err)
-	}
+// tailBackoffConfig is the backoff between reconnections when the tail connection is lost.
+var tailBackoffConfig = util.BackoffConfig{
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+	MaxRetries: 10,
+}
 
+// TailQuery connects to the Loki websocket endpoint and tails logs until interrupted.
+// When the connection is lost, it reconnects and resumes after the last received entry.
+func (q *Query) TailQuery(delayFor int, c *client.Client, out output.LogOutput) {
+	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		stopChan := make(chan os.Signal, 1)
 		signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 		<-stopChan
-		if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
-			log.Println("Error closing websocket:", err)
-		}
-		os.Exit(0)
+		cancel()
 	}()
 
-	tailResponse := new(loghttp.TailResponse)
+	if err := q.Tail(ctx, delayFor, c, out); err != nil {
+		log.Fatalf("Tailing logs failed: %+v", err)
+	}
+}
+
+// Tail tails logs like TailQuery until ctx is done, returning an error when the tail
+// connection cannot be established or re-established.
+func (q *Query) Tail(ctx context.Context, delayFor int, c *client.Client, out output.LogOutput) error {
+	var (
+		cursor  *loghttp.TailCursor
+		backoff = util.NewBackoff(ctx, tailBackoffConfig)
+	)
 
 	if len(q.IgnoreLabelsKey) > 0 {
 		log.Println("Ignoring labels key:", color.RedString(strings.Join(q.IgnoreLabelsKey, ",")))
@@ -43,12 +58,65 @@ func (q *Query) TailQuery(delayFor int, c *client.Client, out output.LogOutput)
 		log.Println("Print only labels key:", color.RedString(strings.Join(q.ShowLabelsKey, ",")))
 	}
 
+	printHeader(out, 0)
+	connected := false
+	for {
+		ws, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start.UnixNano(), cursor, q.Quiet)
+		if err != nil && !connected {
+			return err
+		}
+		if err == nil {
+			connected = true
+			err = q.readTailUntilDone(ctx, ws, out, &cursor, backoff)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		log.Println("Tail connection lost:", err)
+		backoff.Wait()
+		if !backoff.Ongoing() {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed after %d reconnections: %v", backoff.NumRetries(), err)
+		}
+		log.Println("Reconnecting...")
+	}
+}
+
+// readTailUntilDone reads the tail responses of the connection like readTail, closing it
+// when ctx is done.
+func (q *Query) readTailUntilDone(ctx context.Context, conn *websocket.Conn, out output.LogOutput, cursor **loghttp.TailCursor, backoff *util.Backoff) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+				log.Println("Error closing websocket:", err)
+			}
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	err := q.readTail(conn, out, cursor, backoff)
+	_ = conn.Close()
+	return err
+}
+
+// readTail prints the tail responses received on the connection until it fails, keeping
+// the cursor at the latest received entry.
+func (q *Query) readTail(conn *websocket.Conn, out output.LogOutput, cursor **loghttp.TailCursor, backoff *util.Backoff) error {
 	for {
+		tailResponse := new(loghttp.TailResponse)
 		err := conn.ReadJSON(tailResponse)
 		if err != nil {
-			log.Println("Error reading stream:", err)
-			return
+			return err
 		}
+		backoff.Reset()
 
 		labels := loghttp.LabelSet{}
 		for _, stream := range tailResponse.Streams {
@@ -76,10 +144,21 @@ func (q *Query) TailQuery(delayFor int, c *client.Client, out output.LogOutput)
 
 			for _, entry := range stream.Entries {
 				fmt.Println(out.Format(entry.Timestamp, labels, 0, entry.Line))
+				if *cursor == nil {
+					c := loghttp.NewTailCursor(entry)
+					*cursor = &c
+				} else {
+					(*cursor).Add(entry)
+				}
 			}
 
 		}
-		if len(tailResponse.DroppedStreams) != 0 {
+		if len(tailResponse.DroppedCounts) != 0 {
+			log.Println("Server dropped following entries due to slow client")
+			for _, d := range tailResponse.DroppedCounts {
+				log.Println(d.Count, d.Labels)
+			}
+		} else if len(tailResponse.DroppedStreams) != 0 {
 			log.Println("Server dropped following entries due to slow client")
 			for _, d := range tailResponse.DroppedStreams {
 				log.Println(d.Timestamp, d.Labels)
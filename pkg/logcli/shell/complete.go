@@ -0,0 +1,290 @@
+package shell
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+)
+
+// labelsCacheTTL is how long the label names and values of the completion are cached, since they
+// are looked up again on every tab press.
+const labelsCacheTTL = time.Minute
+
+// valueEscaper escapes label values in the double quoted strings of matchers.
+var valueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// matcherRegexp matches the complete label matchers of a stream selector.
+var matcherRegexp = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*"((?:[^"\\]|\\.)*)"`)
+
+// labelSource discovers the label names and values used to complete stream selectors.
+type labelSource interface {
+	// LabelNames returns the label names of the streams matching selector, or of all the streams if it is empty.
+	LabelNames(selector string) ([]string, error)
+	// LabelValues returns the values of a label in the streams matching selector, or in all the streams if it is empty.
+	LabelValues(name, selector string) ([]string, error)
+}
+
+// completer completes the commands of the shell and the label names and values of stream selectors.
+type completer struct {
+	labels labelSource
+}
+
+// complete completes the word before pos in line. It returns the new line and position, along with
+// the candidates of the completion, of which there is more than one when it is ambiguous.
+func (c *completer) complete(line string, pos int) (string, int, []string) {
+	prefix := line[:pos]
+
+	if strings.HasPrefix(prefix, ":") && !strings.Contains(prefix, " ") {
+		var names []string
+		for _, cmd := range commands {
+			names = append(names, cmd.name)
+		}
+		return replaceWord(line, pos, prefix, candidates(names, prefix), " ", "")
+	}
+
+	if word := strings.TrimPrefix(prefix, ":labels "); word != prefix && !strings.Contains(word, " ") {
+		names, _ := c.labels.LabelNames("")
+		return replaceWord(line, pos, word, candidates(names, word), "", "")
+	}
+
+	open := strings.LastIndex(prefix, "{")
+	if open == -1 || strings.LastIndex(prefix, "}") > open {
+		return line, pos, nil
+	}
+	inner := prefix[open+1:]
+	start := currentMatcherStart(inner)
+	word := inner[start:]
+
+	// the complete matchers before the completed word narrow down the label names and values.
+	var used []string
+	matchers := matcherRegexp.FindAllStringSubmatch(inner[:start], -1)
+	for _, m := range matchers {
+		used = append(used, m[1])
+	}
+	selector := selectorOf(matchers)
+
+	if name, op, value, ok := splitMatcher(word); ok {
+		values, err := c.labels.LabelValues(name, selector)
+		if err != nil && selector != "" {
+			values, _ = c.labels.LabelValues(name, "")
+		}
+		return replaceWord(line, pos, word, candidates(escapeValues(values), value), `"`, name+op+`"`)
+	}
+
+	names, err := c.labels.LabelNames(selector)
+	if err != nil && selector != "" {
+		names, _ = c.labels.LabelNames("")
+	}
+	return replaceWord(line, pos, word, candidates(without(names, used), word), "=", "")
+}
+
+// replaceWord replaces word, ending at pos in line, with the completion of candidates. The single
+// candidate of an unambiguous completion is followed by suffix, and all of them are preceded by prefix.
+func replaceWord(line string, pos int, word string, candidates []string, suffix, prefix string) (string, int, []string) {
+	var replacement string
+	switch len(candidates) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		replacement = prefix + candidates[0] + suffix
+	default:
+		replacement = prefix + commonPrefix(candidates)
+	}
+
+	start := pos - len(word)
+	// keep the word as is when the completion would shorten it, e.g. values of a regexp matcher.
+	if len(replacement) < len(word) {
+		return line, pos, candidates
+	}
+	return line[:start] + replacement + line[pos:], start + len(replacement), candidates
+}
+
+// currentMatcherStart returns the start in the inside of a stream selector of the matcher being typed,
+// after the last comma or space which is not quoted.
+func currentMatcherStart(inner string) int {
+	start, quoted := 0, false
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ',' || c == ' '):
+			start = i + 1
+		}
+	}
+	return start
+}
+
+// splitMatcher splits a partial matcher into its label name, operator and the prefix of its value.
+func splitMatcher(word string) (name, op, value string, ok bool) {
+	i := strings.IndexAny(word, "=!")
+	if i == -1 {
+		return "", "", "", false
+	}
+	name, op = word[:i], word[i:i+1]
+	if rest := word[i:]; len(rest) > 1 && (rest[1] == '=' || rest[1] == '~') {
+		op = rest[:2]
+	}
+	return name, op, strings.TrimPrefix(word[i+len(op):], `"`), true
+}
+
+func selectorOf(matchers [][]string) string {
+	if len(matchers) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, m[0])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// candidates returns the sorted values starting with prefix.
+func candidates(values []string, prefix string) []string {
+	var res []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			res = append(res, v)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func escapeValues(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, valueEscaper.Replace(v))
+	}
+	return res
+}
+
+func without(values, excluded []string) []string {
+	res := make([]string, 0, len(values))
+outer:
+	for _, v := range values {
+		for _, e := range excluded {
+			if v == e {
+				continue outer
+			}
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+type cachedLabels struct {
+	values  []string
+	expires time.Time
+}
+
+// clientLabels looks up the label names and values of the time range of the shell with its client,
+// caching them for labelsCacheTTL.
+type clientLabels struct {
+	client    *client.Client
+	timeRange func() (time.Time, time.Time)
+
+	mtx   sync.Mutex
+	cache map[string]cachedLabels
+}
+
+func newClientLabels(c *client.Client, timeRange func() (time.Time, time.Time)) *clientLabels {
+	return &clientLabels{
+		client:    c,
+		timeRange: timeRange,
+		cache:     map[string]cachedLabels{},
+	}
+}
+
+// reset drops the cached labels, e.g. after a change of tenant or time range.
+func (l *clientLabels) reset() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.cache = map[string]cachedLabels{}
+}
+
+// LabelNames implements labelSource.
+func (l *clientLabels) LabelNames(selector string) ([]string, error) {
+	return l.cached("names\x00"+selector, func(from, through time.Time) ([]string, error) {
+		if selector == "" {
+			resp, err := l.client.ListLabelNames(true, from, through)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Data, nil
+		}
+		return l.seriesLabels(selector, from, through, func(name, _ string) string { return name })
+	})
+}
+
+// LabelValues implements labelSource.
+func (l *clientLabels) LabelValues(name, selector string) ([]string, error) {
+	return l.cached("values\x00"+name+"\x00"+selector, func(from, through time.Time) ([]string, error) {
+		if selector == "" {
+			resp, err := l.client.ListLabelValues(name, true, from, through)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Data, nil
+		}
+		return l.seriesLabels(selector, from, through, func(n, v string) string {
+			if n != name {
+				return ""
+			}
+			return v
+		})
+	})
+}
+
+// seriesLabels returns the distinct non-empty results of f for the labels of the series matching selector.
+func (l *clientLabels) seriesLabels(selector string, from, through time.Time, f func(name, value string) string) ([]string, error) {
+	resp, err := l.client.Series([]string{selector}, from, through, true)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var res []string
+	for _, ls := range resp.Data {
+		for n, v := range ls {
+			s := f(n, v)
+			if _, ok := seen[s]; ok || s == "" {
+				continue
+			}
+			seen[s] = struct{}{}
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (l *clientLabels) cached(key string, lookup func(from, through time.Time) ([]string, error)) ([]string, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if c, ok := l.cache[key]; ok && time.Now().Before(c.expires) {
+		return c.values, nil
+	}
+
+	values, err := lookup(l.timeRange())
+	if err != nil {
+		return nil, err
+	}
+	l.cache[key] = cachedLabels{values: values, expires: time.Now().Add(labelsCacheTTL)}
+	return values, nil
+}
@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// maxHistory is the number of lines of the history file recalled at startup, which is as many
+// as the terminal keeps.
+const maxHistory = 100
+
+// loadHistory returns the last maxHistory lines of the history file, skipping the ones which
+// cannot be replayed to the terminal. A missing file is an empty history.
+func loadHistory(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.IndexFunc(line, unicode.IsControl) != -1 {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) > maxHistory {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// appendHistory appends a line to the history file.
+func appendHistory(path, line string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,484 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/logcli/output"
+	"github.com/grafana/loki/pkg/logcli/query"
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// commands of the shell, lines not starting with ":" being LogQL queries.
+var commands = []struct {
+	name, args, help string
+}{
+	{":help", "", "Show this help."},
+	{":quit", "", "Exit the shell, like Ctrl-D."},
+	{":session", "", "Show the settings of the session."},
+	{":range", "[<since> | <from> <to>]", "Query the last <since> (e.g. 15m, 1d) or from <from> to <to> (RFC3339)."},
+	{":limit", "[<n>]", "Limit on the number of entries of log queries."},
+	{":output", "[default|raw|jsonl|table|csv|promtext]", "Output mode of the queries."},
+	{":org-id", "[<id>]", "Tenant the queries are sent for."},
+	{":addr", "[<url>]", "Address of the Loki server."},
+	{":stats", "[on|off]", "Show the statistics of the queries, toggled without argument."},
+	{":instant", "<query>", "Run an instant query at the current time."},
+	{":tail", "<query>", "Tail a log query until interrupted with Ctrl-C."},
+	{":labels", "[<name>]", "List the label names, or the values of a label, of the time range."},
+	{":series", "<selector>", "List the streams matching a selector in the time range."},
+}
+
+// Shell is an interactive session running the LogQL queries typed on the terminal against Loki,
+// with the time range, tenant and output settings of the session. Label names and values of the
+// stream selectors are completed with Tab, and the lines are kept in a history file.
+type Shell struct {
+	Client *client.Client
+	// Query holds the settings of the queries run by the shell, e.g. their limit or direction.
+	Query      query.Query
+	Since      time.Duration
+	From       time.Time
+	To         time.Time
+	OutputMode string
+	// Columns are the labels printed in their own column by the csv output mode.
+	Columns     []string
+	Timezone    *time.Location
+	Statistics  bool
+	DelayFor    int
+	HistoryFile string
+
+	labels *clientLabels
+}
+
+// Run runs the commands read from stdin until it ends or :quit is entered. Line editing, history
+// and completion are only enabled when stdin is a terminal.
+func (s *Shell) Run() error {
+	s.labels = newClientLabels(s.Client, s.timeRange)
+
+	// interrupts stop the running command instead of the shell.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return s.loop(&scannerReader{scanner: bufio.NewScanner(os.Stdin)}, sigs)
+	}
+
+	r, err := s.newTerminalReader(fd)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Type :help for the commands of the shell, and Tab to complete label names and values.")
+	return s.loop(r, sigs)
+}
+
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+func (s *Shell) loop(r lineReader, sigs <-chan os.Signal) error {
+	for {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-sigs:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		quit, err := s.exec(ctx, line)
+		cancel()
+
+		if err != nil {
+			log.Println("Error:", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// exec runs a line of the shell, returning whether the shell should exit.
+func (s *Shell) exec(ctx context.Context, line string) (bool, error) {
+	if !strings.HasPrefix(line, ":") {
+		return false, s.runQuery(line, false)
+	}
+
+	fields := strings.Fields(line)
+	cmd, args := fields[0], fields[1:]
+	rest := strings.TrimSpace(strings.TrimPrefix(line, cmd))
+
+	switch cmd {
+	case ":help":
+		s.printHelp()
+	case ":quit", ":exit":
+		return true, nil
+	case ":session":
+		s.printSession()
+	case ":range":
+		return false, s.setRange(args)
+	case ":limit":
+		if len(args) == 0 {
+			fmt.Println(s.Query.Limit)
+			return false, nil
+		}
+		limit, err := strconv.Atoi(args[0])
+		if err != nil || limit <= 0 {
+			return false, fmt.Errorf("invalid limit %q", args[0])
+		}
+		s.Query.Limit = limit
+	case ":output":
+		if len(args) == 0 {
+			fmt.Println(s.OutputMode)
+			return false, nil
+		}
+		if _, err := output.NewLogOutput(args[0], &output.LogOutputOptions{}); err != nil {
+			return false, err
+		}
+		s.OutputMode = args[0]
+	case ":org-id":
+		if len(args) == 0 {
+			fmt.Println(s.Client.OrgID)
+			return false, nil
+		}
+		s.Client.OrgID = args[0]
+		s.labels.reset()
+	case ":addr":
+		if len(args) == 0 {
+			fmt.Println(s.Client.Address)
+			return false, nil
+		}
+		u, err := url.Parse(args[0])
+		if err != nil {
+			return false, err
+		}
+		s.Client.Address = args[0]
+		s.Client.TLSConfig.ServerName = u.Host
+		s.labels.reset()
+	case ":stats":
+		switch {
+		case len(args) == 0:
+			s.Statistics = !s.Statistics
+		case args[0] == "on" || args[0] == "off":
+			s.Statistics = args[0] == "on"
+		default:
+			return false, fmt.Errorf("invalid argument %q, expected on or off", args[0])
+		}
+		fmt.Println("stats:", onOff(s.Statistics))
+	case ":instant":
+		return false, s.runQuery(rest, true)
+	case ":tail":
+		return false, s.tail(ctx, rest)
+	case ":labels":
+		return false, s.printLabels(args)
+	case ":series":
+		return false, s.printSeries(rest)
+	default:
+		return false, fmt.Errorf("unknown command %s, type :help for the commands", cmd)
+	}
+	return false, nil
+}
+
+// timeRange returns the time range of the queries, the last Since unless an absolute one is set.
+func (s *Shell) timeRange() (time.Time, time.Time) {
+	if !s.From.IsZero() {
+		return s.From, s.To
+	}
+	now := time.Now()
+	return now.Add(-s.Since), now
+}
+
+func (s *Shell) setRange(args []string) error {
+	switch len(args) {
+	case 0:
+		fmt.Println(s.describeRange())
+		return nil
+	case 1:
+		since, err := model.ParseDuration(args[0])
+		if err != nil {
+			return err
+		}
+		s.Since, s.From, s.To = time.Duration(since), time.Time{}, time.Time{}
+	case 2:
+		from, err := time.Parse(time.RFC3339Nano, args[0])
+		if err != nil {
+			return err
+		}
+		to, err := time.Parse(time.RFC3339Nano, args[1])
+		if err != nil {
+			return err
+		}
+		if !from.Before(to) {
+			return fmt.Errorf("the start of the range %s is not before its end %s", args[0], args[1])
+		}
+		s.From, s.To = from, to
+	default:
+		return fmt.Errorf("expected a duration, or a start and an end time")
+	}
+
+	s.labels.reset()
+	fmt.Println("range:", s.describeRange())
+	return nil
+}
+
+func (s *Shell) describeRange() string {
+	if !s.From.IsZero() {
+		return fmt.Sprintf("%s to %s", s.From.Format(time.RFC3339Nano), s.To.Format(time.RFC3339Nano))
+	}
+	return "last " + model.Duration(s.Since).String()
+}
+
+func (s *Shell) newOutput() (output.LogOutput, error) {
+	return output.NewLogOutput(s.OutputMode, &output.LogOutputOptions{
+		Timezone: s.Timezone,
+		NoLabels: s.Query.NoLabels,
+		Columns:  s.Columns,
+	})
+}
+
+func (s *Shell) runQuery(queryString string, instant bool) error {
+	if queryString == "" {
+		return fmt.Errorf("missing query")
+	}
+
+	q := s.Query
+	q.QueryString = queryString
+	// the label columns are kept in the labels of the entries, even when common to all of them.
+	q.ShowLabelsKey = append(append([]string{}, q.ShowLabelsKey...), s.Columns...)
+	if instant {
+		q.SetInstant(time.Now())
+	} else {
+		q.Start, q.End = s.timeRange()
+	}
+
+	out, err := s.newOutput()
+	if err != nil {
+		return err
+	}
+	return q.Exec(s.Client, out, s.Statistics)
+}
+
+func (s *Shell) tail(ctx context.Context, queryString string) error {
+	if queryString == "" {
+		return fmt.Errorf("missing query")
+	}
+
+	q := s.Query
+	q.QueryString = queryString
+	q.Start, _ = s.timeRange()
+
+	out, err := s.newOutput()
+	if err != nil {
+		return err
+	}
+	return q.Tail(ctx, s.DelayFor, s.Client, out)
+}
+
+func (s *Shell) printLabels(args []string) error {
+	from, through := s.timeRange()
+
+	var (
+		resp *loghttp.LabelResponse
+		err  error
+	)
+	if len(args) == 0 {
+		resp, err = s.Client.ListLabelNames(s.Query.Quiet, from, through)
+	} else {
+		resp, err = s.Client.ListLabelValues(args[0], s.Query.Quiet, from, through)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp.Data {
+		fmt.Println(v)
+	}
+	return nil
+}
+
+func (s *Shell) printSeries(selector string) error {
+	if selector == "" {
+		return fmt.Errorf("missing selector")
+	}
+
+	from, through := s.timeRange()
+	resp, err := s.Client.Series([]string{selector}, from, through, s.Query.Quiet)
+	if err != nil {
+		return err
+	}
+
+	for _, ls := range resp.Data {
+		fmt.Println(ls)
+	}
+	return nil
+}
+
+func (s *Shell) printHelp() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "<query>\t\tRun a LogQL query over the time range.")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", cmd.name, cmd.args, cmd.help)
+	}
+	w.Flush()
+}
+
+func (s *Shell) printSession() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "addr\t%s\n", s.Client.Address)
+	fmt.Fprintf(w, "org-id\t%s\n", s.Client.OrgID)
+	fmt.Fprintf(w, "range\t%s\n", s.describeRange())
+	fmt.Fprintf(w, "limit\t%d\n", s.Query.Limit)
+	fmt.Fprintf(w, "output\t%s\n", s.OutputMode)
+	fmt.Fprintf(w, "stats\t%s\n", onOff(s.Statistics))
+	w.Flush()
+}
+
+func (s *Shell) prompt() string {
+	if s.Client.OrgID != "" {
+		return fmt.Sprintf("logcli [%s]> ", s.Client.OrgID)
+	}
+	return "logcli> "
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// scannerReader reads the lines of a non interactive input.
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *scannerReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// terminalReader reads the lines of the terminal, only putting it in raw mode while editing them
+// so that the output and the interrupts of the commands are handled as usual.
+type terminalReader struct {
+	shell *Shell
+	fd    int
+	state *terminal.State
+	term  *terminal.Terminal
+}
+
+func (s *Shell) newTerminalReader(fd int) (*terminalReader, error) {
+	state, err := terminal.GetState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := loadHistory(s.HistoryFile)
+	if err != nil {
+		log.Println("Unable to load the history:", err)
+	}
+
+	// the terminal does not allow to set its history, so the lines of the history file are replayed
+	// to it before reading from stdin, without echoing them.
+	replay := strings.Join(history, "\r")
+	if replay != "" {
+		replay += "\r"
+	}
+	out := &mutableWriter{Writer: os.Stdout, muted: true}
+	term := terminal.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{io.MultiReader(strings.NewReader(replay), os.Stdin), out}, "")
+	for range history {
+		if _, err := term.ReadLine(); err != nil {
+			return nil, err
+		}
+	}
+	out.muted = false
+
+	c := &completer{labels: s.labels}
+	term.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, candidates := c.complete(line, pos)
+		if newLine == line && len(candidates) > 1 {
+			fmt.Fprintln(term, strings.Join(candidates, "  "))
+		}
+		return newLine, newPos, true
+	}
+
+	return &terminalReader{shell: s, fd: fd, state: state, term: term}, nil
+}
+
+func (r *terminalReader) ReadLine() (string, error) {
+	if width, height, err := terminal.GetSize(r.fd); err == nil && width > 0 {
+		_ = r.term.SetSize(width, height)
+	}
+	r.term.SetPrompt(r.shell.prompt())
+
+	if _, err := terminal.MakeRaw(r.fd); err != nil {
+		return "", err
+	}
+	line, err := r.term.ReadLine()
+	if restoreErr := terminal.Restore(r.fd, r.state); restoreErr != nil && err == nil {
+		err = restoreErr
+	}
+	if err == io.EOF {
+		fmt.Println()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if line = strings.TrimSpace(line); line != "" {
+		if err := appendHistory(r.shell.HistoryFile, line); err != nil {
+			log.Println("Unable to save the history:", err)
+		}
+	}
+	return line, nil
+}
+
+// mutableWriter discards what is written to it while muted.
+type mutableWriter struct {
+	io.Writer
+	muted bool
+}
+
+func (w *mutableWriter) Write(p []byte) (int, error) {
+	if w.muted {
+		return len(p), nil
+	}
+	return w.Writer.Write(p)
+}
@@ -0,0 +1,196 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+)
+
+type fakeLabels map[string][]string
+
+func (f fakeLabels) LabelNames(selector string) ([]string, error) {
+	var names []string
+	for name := range f {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f fakeLabels) LabelValues(name, selector string) ([]string, error) {
+	return f[name], nil
+}
+
+func Test_complete(t *testing.T) {
+	c := &completer{labels: fakeLabels{
+		"app":       {"api", "frontend", `quo"te`},
+		"namespace": {"prod", "dev"},
+		"name":      {"loki"},
+	}}
+
+	for _, tc := range []struct {
+		line, expected string
+		candidates     []string
+	}{
+		{":ra", ":range ", []string{":range"}},
+		{":s", ":s", []string{":series", ":session", ":stats"}},
+		{":labels na", ":labels name", []string{"name", "namespace"}},
+		{"{a", "{app=", []string{"app"}},
+		{"{nam", "{name", []string{"name", "namespace"}},
+		{`{app="a`, `{app="api"`, []string{"api"}},
+		{`{app=`, `{app="`, []string{"api", "frontend", `quo\"te`}},
+		{`{app=~"q`, `{app=~"quo\"te"`, []string{`quo\"te`}},
+		{`{app="api", na`, `{app="api", name`, []string{"name", "namespace"}},
+		{`{app="api", namespace!="d`, `{app="api", namespace!="dev"`, []string{"dev"}},
+		{`{app="a b", a`, `{app="a b", a`, nil},
+		{`{app="api"} |= "na`, `{app="api"} |= "na`, nil},
+		{`rate({app="api"}[5m]) + rate({n`, `rate({app="api"}[5m]) + rate({name`, []string{"name", "namespace"}},
+	} {
+		t.Run(tc.line, func(t *testing.T) {
+			line, pos, candidates := c.complete(tc.line, len(tc.line))
+			require.Equal(t, tc.expected, line)
+			require.Equal(t, len(tc.expected), pos)
+			require.Equal(t, tc.candidates, candidates)
+		})
+	}
+
+	// the text after the cursor is kept
+	line, pos, _ := c.complete(`{app="fr}`, 8)
+	require.Equal(t, `{app="frontend"}`, line)
+	require.Equal(t, 15, pos)
+}
+
+func Test_clientLabels(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+" "+r.URL.Query().Get("match"))
+		switch r.URL.Path {
+		case "/loki/api/v1/labels":
+			fmt.Fprint(w, `{"status":"success","data":["app","namespace"]}`)
+		case "/loki/api/v1/label/app/values":
+			fmt.Fprint(w, `{"status":"success","data":["api","frontend"]}`)
+		case "/loki/api/v1/series":
+			fmt.Fprint(w, `{"status":"success","data":[{"app":"api","namespace":"prod"},{"app":"api","namespace":"dev"},{"app":"api"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	labels := newClientLabels(&client.Client{Address: server.URL}, func() (time.Time, time.Time) {
+		return time.Unix(0, 0), time.Unix(3600, 0)
+	})
+
+	values, err := labels.LabelValues("app", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"api", "frontend"}, values)
+
+	// values of the streams matching the selector are looked up with their series
+	values, err = labels.LabelValues("namespace", `{app="api"}`)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"prod", "dev"}, values)
+
+	names, err := labels.LabelNames(`{app="api"}`)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"app", "namespace"}, names)
+
+	// cached lookups are not requested again until reset
+	_, err = labels.LabelValues("app", "")
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+
+	labels.reset()
+	_, err = labels.LabelNames("")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/loki/api/v1/label/app/values ",
+		`/loki/api/v1/series {app="api"}`,
+		`/loki/api/v1/series {app="api"}`,
+		"/loki/api/v1/labels ",
+	}, requests)
+}
+
+func TestShell_exec(t *testing.T) {
+	s := &Shell{Client: &client.Client{}, OutputMode: "default", Since: time.Hour}
+	s.labels = newClientLabels(s.Client, s.timeRange)
+
+	exec := func(line string) error {
+		quit, err := s.exec(context.Background(), line)
+		require.False(t, quit)
+		return err
+	}
+
+	require.NoError(t, exec(":range 1d"))
+	require.Equal(t, 24*time.Hour, s.Since)
+	from, through := s.timeRange()
+	require.Equal(t, 24*time.Hour, through.Sub(from))
+
+	require.NoError(t, exec(":range 2020-06-01T00:00:00Z 2020-06-02T00:00:00Z"))
+	from, through = s.timeRange()
+	require.Equal(t, time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC), through)
+	require.Error(t, exec(":range 2020-06-02T00:00:00Z 2020-06-01T00:00:00Z"))
+
+	require.NoError(t, exec(":range 15m"))
+	require.True(t, s.From.IsZero())
+	require.Equal(t, 15*time.Minute, s.Since)
+
+	require.NoError(t, exec(":limit 100"))
+	require.Equal(t, 100, s.Query.Limit)
+	require.Error(t, exec(":limit -1"))
+
+	require.NoError(t, exec(":output jsonl"))
+	require.Equal(t, "jsonl", s.OutputMode)
+	require.Error(t, exec(":output unknown"))
+
+	require.NoError(t, exec(":org-id tenant"))
+	require.Equal(t, "tenant", s.Client.OrgID)
+	require.Equal(t, "logcli [tenant]> ", s.prompt())
+
+	require.NoError(t, exec(":addr https://loki.example.com:3100"))
+	require.Equal(t, "https://loki.example.com:3100", s.Client.Address)
+	require.Equal(t, "loki.example.com:3100", s.Client.TLSConfig.ServerName)
+
+	require.NoError(t, exec(":stats"))
+	require.True(t, s.Statistics)
+	require.NoError(t, exec(":stats off"))
+	require.False(t, s.Statistics)
+
+	require.Error(t, exec(":unknown"))
+	require.Error(t, exec(":tail"))
+
+	quit, err := s.exec(context.Background(), ":quit")
+	require.NoError(t, err)
+	require.True(t, quit)
+}
+
+func Test_history(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logcli-history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	lines, err := loadHistory(path)
+	require.NoError(t, err)
+	require.Empty(t, lines)
+
+	for i := 0; i < maxHistory+10; i++ {
+		require.NoError(t, appendHistory(path, fmt.Sprintf(`{app="%d"}`, i)))
+	}
+	require.NoError(t, appendHistory(path, "\x1b[A"))
+
+	lines, err = loadHistory(path)
+	require.NoError(t, err)
+	require.Len(t, lines, maxHistory)
+	require.Equal(t, `{app="10"}`, lines[0])
+	require.Equal(t, fmt.Sprintf(`{app="%d"}`, maxHistory+9), lines[maxHistory-1])
+}
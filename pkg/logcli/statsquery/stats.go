@@ -0,0 +1,100 @@
+package statsquery
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+)
+
+// StatsQuery contains all necessary fields to report the size of the data matched by a log query
+// without returning its lines
+type StatsQuery struct {
+	QueryString string
+	Start       time.Time
+	End         time.Time
+	Quiet       bool
+	// Exec also runs the query, counting the lines matching its filters with the query engine.
+	Exec bool
+}
+
+// DoStats prints out the stats of the streams matching the query
+func (q *StatsQuery) DoStats(c *client.Client) {
+	if err := q.printStats(c, os.Stdout); err != nil {
+		log.Fatalf("Error doing request: %+v", err)
+	}
+}
+
+func (q *StatsQuery) printStats(c *client.Client, out io.Writer) error {
+	expr, err := logql.ParseLogSelector(q.QueryString)
+	if err != nil {
+		return fmt.Errorf("stats are only reported for log queries: %w", err)
+	}
+
+	selector := Selector(expr)
+	if selector != expr.String() && !q.Exec && !q.Quiet {
+		log.Println("The filters of the query are not applied to the index stats, use --exec to count the matching lines.")
+	}
+
+	resp, err := c.IndexStats(selector, q.Start, q.End, q.Quiet)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Streams:\t%s\n", humanize.Comma(int64(resp.Data.Streams)))
+	fmt.Fprintf(w, "Chunks:\t%s\n", humanize.Comma(int64(resp.Data.Chunks)))
+	fmt.Fprintf(w, "Lines:\t%s\n", humanize.Comma(int64(resp.Data.Lines)))
+	fmt.Fprintf(w, "Bytes:\t%s\n", humanize.Bytes(resp.Data.Bytes))
+
+	if q.Exec {
+		if err := q.printExecStats(c, w); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// printExecStats counts the lines matching the query with a metric query over its whole time range,
+// so that the query is fully executed without returning its lines.
+func (q *StatsQuery) printExecStats(c *client.Client, w io.Writer) error {
+	rng := model.Duration(q.End.Sub(q.Start).Round(time.Millisecond))
+	resp, err := c.Query(fmt.Sprintf("sum(count_over_time(%s[%s]))", q.QueryString, rng), 1, q.End, logproto.BACKWARD, q.Quiet)
+	if err != nil {
+		return err
+	}
+
+	var matched int64
+	if vector, ok := resp.Data.Result.(loghttp.Vector); ok && len(vector) > 0 {
+		matched = int64(vector[0].Value)
+	}
+	stats := resp.Data.Statistics
+
+	fmt.Fprintf(w, "Matched lines:\t%s\n", humanize.Comma(matched))
+	fmt.Fprintf(w, "Processed lines:\t%s\n", humanize.Comma(stats.Summary.TotalLinesProcessed))
+	fmt.Fprintf(w, "Processed bytes:\t%s\n", humanize.Bytes(uint64(stats.Summary.TotalBytesProcessed)))
+	fmt.Fprintf(w, "Downloaded chunks:\t%s\n", humanize.Comma(stats.Store.TotalChunksDownloaded))
+	fmt.Fprintf(w, "Execution time:\t%s\n", time.Duration(stats.Summary.ExecTime*float64(time.Second)).Round(time.Millisecond))
+	return nil
+}
+
+// Selector returns the stream selector of a log query, without its filters.
+func Selector(expr logql.LogSelectorExpr) string {
+	matchers := expr.Matchers()
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, m.String())
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,65 @@
+package statsquery
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+)
+
+func TestStatsQuery_printStats(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+" "+r.URL.Query().Get("query"))
+		switch r.URL.Path {
+		case "/loki/api/v1/index/stats":
+			fmt.Fprint(w, `{"status":"success","data":{"streams":2,"chunks":12,"lines":12345,"bytes":2500000}}`)
+		case "/loki/api/v1/query":
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[3600,"42"]}],
+				"stats":{"summary":{"totalBytesProcessed":2500000,"totalLinesProcessed":12345,"execTime":1.5},"store":{"totalChunksDownloaded":12}}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	q := &StatsQuery{
+		QueryString: `{app="api", env=~"prod|dev"} |= "error"`,
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(3600, 0),
+		Quiet:       true,
+	}
+	c := &client.Client{Address: server.URL}
+
+	var out bytes.Buffer
+	require.NoError(t, q.printStats(c, &out))
+	require.Equal(t, `Streams:  2
+Chunks:   12
+Lines:    12,345
+Bytes:    2.5 MB
+`, out.String())
+
+	q.Exec = true
+	out.Reset()
+	require.NoError(t, q.printStats(c, &out))
+	require.Contains(t, out.String(), "Matched lines:      42\n")
+	require.Contains(t, out.String(), "Downloaded chunks:  12\n")
+	require.Contains(t, out.String(), "Execution time:     1.5s\n")
+
+	// only the stream selector is looked up in the index
+	require.Equal(t, []string{
+		`/loki/api/v1/index/stats {app="api",env=~"prod|dev"}`,
+		`/loki/api/v1/index/stats {app="api",env=~"prod|dev"}`,
+		`/loki/api/v1/query sum(count_over_time({app="api", env=~"prod|dev"} |= "error"[1h]))`,
+	}, queries)
+
+	// metric queries have no stats
+	q.QueryString = `rate({app="api"}[1m])`
+	require.Error(t, q.printStats(c, &out))
+}
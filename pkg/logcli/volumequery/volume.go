@@ -0,0 +1,127 @@
+package volumequery
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logcli/client"
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+// VolumeQuery contains all necessary fields to execute log volume queries and print out the results
+type VolumeQuery struct {
+	QueryString string
+	Start       time.Time
+	End         time.Time
+	Step        time.Duration
+	// By is the labels the volume is grouped by, the volume of all the streams is summed when empty.
+	By []string
+	// Type is either loghttp.VolumeTypeLines or loghttp.VolumeTypeBytes.
+	Type  string
+	Quiet bool
+}
+
+// DoVolume prints out the volume of the streams matching the query as a table, with a row per step
+// and a column per group of streams, ordered from the largest to the smallest.
+func (q *VolumeQuery) DoVolume(c *client.Client, location *time.Location) {
+	resp, err := c.Volume(q.QueryString, q.Start, q.End, q.Step, q.By, q.Type, q.Quiet)
+	if err != nil {
+		log.Fatalf("Error doing request: %+v", err)
+	}
+
+	matrix, ok := resp.Data.Result.(loghttp.Matrix)
+	if !ok {
+		log.Fatalf("Unexpected result type %s", resp.Data.ResultType)
+	}
+	if err := q.printVolume(os.Stdout, matrix, location); err != nil {
+		log.Fatalf("Error printing volume: %+v", err)
+	}
+}
+
+func (q *VolumeQuery) printVolume(out io.Writer, matrix loghttp.Matrix, location *time.Location) error {
+	totals := make([]float64, len(matrix))
+	for i, s := range matrix {
+		for _, p := range s.Values {
+			totals[i] += float64(p.Value)
+		}
+	}
+	sort.Sort(byTotal{matrix: matrix, totals: totals})
+
+	// the values of every group by timestamp, the steps without entries are absent from the matrix.
+	var timestamps []model.Time
+	values := map[model.Time][]float64{}
+	for i, s := range matrix {
+		for _, p := range s.Values {
+			row, ok := values[p.Timestamp]
+			if !ok {
+				row = make([]float64, len(matrix))
+				values[p.Timestamp] = row
+				timestamps = append(timestamps, p.Timestamp)
+			}
+			row[i] = float64(p.Value)
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	header := []string{"TIME"}
+	for _, s := range matrix {
+		header = append(header, seriesName(s.Metric))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, ts := range timestamps {
+		fmt.Fprintln(w, q.row(ts.Time().In(location).Format(time.RFC3339), values[ts]))
+	}
+	fmt.Fprintln(w, q.row("TOTAL", totals))
+	return w.Flush()
+}
+
+func (q *VolumeQuery) row(name string, values []float64) string {
+	cells := []string{name}
+	for _, v := range values {
+		cells = append(cells, q.format(v))
+	}
+	return strings.Join(cells, "\t")
+}
+
+func (q *VolumeQuery) format(v float64) string {
+	if q.Type == loghttp.VolumeTypeBytes {
+		return humanize.Bytes(uint64(v))
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func seriesName(metric model.Metric) string {
+	if len(metric) == 0 {
+		return "{}"
+	}
+	return metric.String()
+}
+
+type byTotal struct {
+	matrix loghttp.Matrix
+	totals []float64
+}
+
+func (b byTotal) Len() int { return len(b.matrix) }
+func (b byTotal) Less(i, j int) bool {
+	if b.totals[i] != b.totals[j] {
+		return b.totals[i] > b.totals[j]
+	}
+	return b.matrix[i].Metric.String() < b.matrix[j].Metric.String()
+}
+func (b byTotal) Swap(i, j int) {
+	b.matrix[i], b.matrix[j] = b.matrix[j], b.matrix[i]
+	b.totals[i], b.totals[j] = b.totals[j], b.totals[i]
+}
@@ -0,0 +1,42 @@
+package volumequery
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/loghttp"
+)
+
+func TestVolumeQuery_printVolume(t *testing.T) {
+	matrix := loghttp.Matrix{
+		{
+			Metric: model.Metric{"namespace": "dev"},
+			Values: []model.SamplePair{{Timestamp: 60000, Value: 1000}},
+		},
+		{
+			Metric: model.Metric{"namespace": "prod"},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 2000}, {Timestamp: 60000, Value: 3000}},
+		},
+	}
+
+	q := &VolumeQuery{Type: loghttp.VolumeTypeBytes}
+	var out bytes.Buffer
+	require.NoError(t, q.printVolume(&out, matrix, time.UTC))
+	require.Equal(t, `TIME                  {namespace="prod"}  {namespace="dev"}
+1970-01-01T00:00:00Z  2.0 kB              0 B
+1970-01-01T00:01:00Z  3.0 kB              1.0 kB
+TOTAL                 5.0 kB              1.0 kB
+`, out.String())
+
+	q.Type = loghttp.VolumeTypeLines
+	out.Reset()
+	require.NoError(t, q.printVolume(&out, loghttp.Matrix{{Values: []model.SamplePair{{Timestamp: 0, Value: 5}}}}, time.UTC))
+	require.Equal(t, `TIME                  {}
+1970-01-01T00:00:00Z  5
+TOTAL                 5
+`, out.String())
+}
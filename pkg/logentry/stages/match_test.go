@@ -138,6 +138,10 @@ func TestMatcher(t *testing.T) {
 		{`{foo="bar",bar=~"te.*"}`, map[string]string{"foo": "bar", "bar": "test"}, MatchActionKeep, false, true, false},
 		{`{foo="bar",bar!~"te.*"}`, map[string]string{"foo": "bar", "bar": "test"}, MatchActionKeep, false, false, false},
 		{`{foo="bar",bar!~"te.*"}`, map[string]string{"foo": "bar", "bar": "test"}, MatchActionDrop, false, false, false},
+		{`{foo="bar"} | user="bob"`, map[string]string{"foo": "bar"}, MatchActionKeep, false, false, false},
+		{`{foo="bar"} | user!="bob"`, map[string]string{"foo": "bar"}, MatchActionKeep, false, true, false},
+		{`{foo="bar"} | user="bob"`, map[string]string{"foo": "bar"}, MatchActionDrop, false, false, false},
+		{`{foo="bar"} | user=""`, map[string]string{"foo": "bar"}, MatchActionDrop, true, false, false},
 
 		{`{foo=""}`, map[string]string{}, MatchActionKeep, false, true, false},
 	}
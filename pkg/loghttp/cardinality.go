@@ -0,0 +1,58 @@
+package loghttp
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const defaultCardinalityLimit = 10
+
+// CardinalityResponse represents the http json response to a cardinality query.
+type CardinalityResponse struct {
+	Status string          `json:"status"`
+	Data   CardinalityData `json:"data"`
+}
+
+// CardinalityData is the cardinality of the streams of a tenant.
+type CardinalityData struct {
+	Streams uint32             `json:"streams"`
+	Labels  []LabelCardinality `json:"labels"`
+	Churn   []StreamChurn      `json:"churn"`
+}
+
+// LabelCardinality is the number of streams and of distinct values of a label name,
+// along with the values having the most streams.
+type LabelCardinality struct {
+	Name      string                  `json:"name"`
+	Streams   uint32                  `json:"streams"`
+	Values    uint32                  `json:"values"`
+	TopValues []LabelValueCardinality `json:"topValues"`
+}
+
+// LabelValueCardinality is the number of streams of a label value.
+type LabelValueCardinality struct {
+	Value   string `json:"value"`
+	Streams uint32 `json:"streams"`
+}
+
+// StreamChurn is the number of streams created and removed during the period starting at Start.
+type StreamChurn struct {
+	Start   time.Time `json:"start"`
+	Created uint32    `json:"created"`
+	Removed uint32    `json:"removed"`
+}
+
+// ParseCardinalityQuery parses a CardinalityRequest from an http request.
+func ParseCardinalityQuery(r *http.Request) (*logproto.CardinalityRequest, error) {
+	limit, err := parseInt(r.Form.Get("limit"), defaultCardinalityLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, errors.New("limit must be a positive value")
+	}
+	return &logproto.CardinalityRequest{Limit: uint32(limit)}, nil
+}
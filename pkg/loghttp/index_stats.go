@@ -0,0 +1,45 @@
+package loghttp
+
+import (
+	"net/http"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+)
+
+// IndexStatsResponse represents the http json response to an index stats query.
+type IndexStatsResponse struct {
+	Status string     `json:"status"`
+	Data   IndexStats `json:"data"`
+}
+
+// IndexStats is the number of streams and chunks matching a selector, along with the lines
+// and bytes of their entries.
+type IndexStats struct {
+	Streams uint64 `json:"streams"`
+	Chunks  uint64 `json:"chunks"`
+	Lines   uint64 `json:"lines"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+// ParseIndexStatsQuery parses an index stats request from an http request. The stats are
+// read from the volume of the chunks, so the request is a VolumeRequest.
+func ParseIndexStatsQuery(r *http.Request) (*logproto.VolumeRequest, error) {
+	var result logproto.VolumeRequest
+	var err error
+
+	result.Selector = query(r)
+	if _, err := logql.ParseMatchers(result.Selector); err != nil {
+		return nil, err
+	}
+
+	result.Start, result.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.End.Before(result.Start) || result.Start.Equal(result.End) {
+		return nil, errEndBeforeStart
+	}
+	return &result, nil
+}
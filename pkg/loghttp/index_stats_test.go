@@ -0,0 +1,46 @@
+package loghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestParseIndexStatsQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    *logproto.VolumeRequest
+		wantErr bool
+	}{
+		{"bad query", &http.Request{URL: mustParseURL(`?query={foo="bar"} |= "buzz"`)}, nil, true},
+		{"end before start", &http.Request{URL: mustParseURL(`?query={foo="bar"}&start=2016-06-10T21:42:24Z&end=2015-06-10T21:42:24Z`)}, nil, true},
+		{"good",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T00:00:00Z&end=2017-06-11T00:00:00Z`),
+			}, &logproto.VolumeRequest{
+				Selector: `{foo="bar"}`,
+				Start:    time.Date(2017, 06, 10, 0, 0, 0, 0, time.UTC),
+				End:      time.Date(2017, 06, 11, 0, 0, 0, 0, time.UTC),
+			}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.r.ParseForm())
+
+			got, err := ParseIndexStatsQuery(tt.r)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package loghttp
 
 import (
 	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 // QueryResponse represents the http json response to a label query
@@ -17,6 +19,7 @@ type Stream struct {
 
 // Entry represents a log entry.  It includes a log message and the time it occurred at.
 type Entry struct {
-	Timestamp time.Time `json:"ts"`
-	Line      string    `json:"line"`
+	Timestamp          time.Time            `json:"ts"`
+	Line               string               `json:"line"`
+	StructuredMetadata []logproto.LabelPair `json:"structuredMetadata,omitempty"`
 }
@@ -16,4 +16,6 @@ type DroppedEntry struct {
 type TailResponse struct {
 	Streams        []logproto.Stream `json:"streams"`
 	DroppedEntries []DroppedEntry    `json:"dropped_entries"`
+	// DroppedCounts is the number of entries dropped per stream labels since the previous response.
+	DroppedCounts map[string]int `json:"dropped_counts,omitempty"`
 }
@@ -36,6 +36,23 @@ func query(r *http.Request) string {
 	return r.Form.Get("query")
 }
 
+// dedupQuery adds to the query the dedup modifier requested by the dedup_ignoring parameter,
+// a comma separated list of labels to ignore when deduplicating entries.
+func dedupQuery(r *http.Request, query string) (string, error) {
+	value := r.Form.Get("dedup_ignoring")
+	if value == "" {
+		return query, nil
+	}
+	ignoring := strings.Split(value, ",")
+	for i, name := range ignoring {
+		ignoring[i] = strings.TrimSpace(name)
+		if !model.LabelName(ignoring[i]).IsValid() {
+			return "", fmt.Errorf("invalid label name in dedup_ignoring: %q", ignoring[i])
+		}
+	}
+	return logql.AddDedup(query, ignoring)
+}
+
 func ts(r *http.Request) (time.Time, error) {
 	return parseTimestamp(r.Form.Get("time"), time.Now())
 }
@@ -48,6 +65,10 @@ func shards(r *http.Request) []string {
 	return r.Form["shards"]
 }
 
+func stream(r *http.Request) (bool, error) {
+	return parseBool(r.Form.Get("stream"), false)
+}
+
 func bounds(r *http.Request) (time.Time, time.Time, error) {
 	now := time.Now()
 	start, err := parseTimestamp(r.Form.Get("start"), now.Add(-defaultSince))
@@ -117,6 +138,15 @@ func parseInt(value string, def int) (int, error) {
 	return strconv.Atoi(value)
 }
 
+// parseBool parses a bool from a string
+// if the value is empty it returns a default value passed as second parameter
+func parseBool(value string, def bool) (bool, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.ParseBool(value)
+}
+
 // parseUnixNano parses a ns unix timestamp from a string
 // if the value is empty it returns a default value passed as second parameter
 func parseTimestamp(value string, def time.Time) (time.Time, error) {
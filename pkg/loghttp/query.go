@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 	"unsafe"
@@ -37,6 +38,16 @@ type QueryResponse struct {
 	Data   QueryResponseData `json:"data"`
 }
 
+// QueryStreamLine is a single line of a streamed (newline delimited JSON) range query response.
+// Every line carries a stream with its entries, except the last one which carries either the
+// statistics of the query or the error which interrupted it.
+type QueryStreamLine struct {
+	Labels     LabelSet      `json:"stream,omitempty"`
+	Entries    []Entry       `json:"values,omitempty"`
+	Statistics *stats.Result `json:"stats,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
 // PushRequest models a log stream push
 type PushRequest struct {
 	Streams []*Stream `json:"streams"`
@@ -99,9 +110,11 @@ type Stream struct {
 }
 
 //Entry represents a log entry.  It includes a log message and the time it occurred at.
+// Its layout must match logproto.Entry, see Streams.ToProto.
 type Entry struct {
-	Timestamp time.Time
-	Line      string
+	Timestamp          time.Time
+	Line               string
+	StructuredMetadata []logproto.LabelPair
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -153,30 +166,65 @@ func (q *QueryResponseData) UnmarshalJSON(data []byte) error {
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+// The structured metadata, if any, are an object following the line.
 func (e *Entry) MarshalJSON() ([]byte, error) {
 	l, err := json.Marshal(e.Line)
 	if err != nil {
 		return nil, err
 	}
-	return []byte(fmt.Sprintf("[\"%d\",%s]", e.Timestamp.UnixNano(), l)), nil
+	if len(e.StructuredMetadata) == 0 {
+		return []byte(fmt.Sprintf("[\"%d\",%s]", e.Timestamp.UnixNano(), l)), nil
+	}
+	metadata := make(map[string]string, len(e.StructuredMetadata))
+	for _, m := range e.StructuredMetadata {
+		metadata[m.Name] = m.Value
+	}
+	m, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("[\"%d\",%s,%s]", e.Timestamp.UnixNano(), l, m)), nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (e *Entry) UnmarshalJSON(data []byte) error {
-	var unmarshal []string
+	var unmarshal []json.RawMessage
 
 	err := json.Unmarshal(data, &unmarshal)
 	if err != nil {
 		return err
 	}
+	if len(unmarshal) != 2 && len(unmarshal) != 3 {
+		return fmt.Errorf("invalid entry, expected a timestamp, a line and optional structured metadata, got %d values", len(unmarshal))
+	}
 
-	t, err := strconv.ParseInt(unmarshal[0], 10, 64)
+	var ts string
+	if err := json.Unmarshal(unmarshal[0], &ts); err != nil {
+		return err
+	}
+	t, err := strconv.ParseInt(ts, 10, 64)
 	if err != nil {
 		return err
 	}
-
 	e.Timestamp = time.Unix(0, t)
-	e.Line = unmarshal[1]
+
+	if err := json.Unmarshal(unmarshal[1], &e.Line); err != nil {
+		return err
+	}
+
+	e.StructuredMetadata = nil
+	if len(unmarshal) == 3 {
+		var metadata map[string]string
+		if err := json.Unmarshal(unmarshal[2], &metadata); err != nil {
+			return err
+		}
+		for name, value := range metadata {
+			e.StructuredMetadata = append(e.StructuredMetadata, logproto.LabelPair{Name: name, Value: value})
+		}
+		sort.Slice(e.StructuredMetadata, func(i, j int) bool {
+			return e.StructuredMetadata[i].Name < e.StructuredMetadata[j].Name
+		})
+	}
 
 	return nil
 }
@@ -217,6 +265,11 @@ func ParseInstantQuery(r *http.Request) (*InstantQuery, error) {
 	request := &InstantQuery{
 		Query: query(r),
 	}
+	request.Query, err = dedupQuery(r, request.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	request.Limit, err = limit(r)
 	if err != nil {
 		return nil, err
@@ -245,6 +298,9 @@ type RangeQuery struct {
 	Direction logproto.Direction
 	Limit     uint32
 	Shards    []string
+	// Stream requests the entries to be written as newline delimited JSON while they are read,
+	// instead of a single materialized response. Only supported for log queries.
+	Stream bool
 }
 
 // ParseRangeQuery parses a RangeQuery request from an http request.
@@ -252,7 +308,11 @@ func ParseRangeQuery(r *http.Request) (*RangeQuery, error) {
 	var result RangeQuery
 	var err error
 
-	result.Query = query(r)
+	result.Query, err = dedupQuery(r, query(r))
+	if err != nil {
+		return nil, err
+	}
+
 	result.Start, result.End, err = bounds(r)
 	if err != nil {
 		return nil, err
@@ -283,6 +343,11 @@ func ParseRangeQuery(r *http.Request) (*RangeQuery, error) {
 
 	result.Shards = shards(r)
 
+	result.Stream, err = stream(r)
+	if err != nil {
+		return nil, err
+	}
+
 	// For safety, limit the number of returned points per timeseries.
 	// This is sufficient for 60s resolution for a week or 1h resolution for a year.
 	if (result.End.Sub(result.Start) / result.Step) > 11000 {
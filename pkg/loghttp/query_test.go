@@ -57,6 +57,37 @@ func TestParseRangeQuery(t *testing.T) {
 				End:       time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC),
 				Limit:     1000,
 			}, false},
+		{"bad stream",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD&step=3600&stream=maybe`),
+			}, nil, true},
+		{"good stream",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=100000&direction=FORWARD&step=3600&stream=true`),
+			}, &RangeQuery{
+				Step:      time.Hour,
+				Query:     `{foo="bar"}`,
+				Direction: logproto.FORWARD,
+				Start:     time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				End:       time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:     100000,
+				Stream:    true,
+			}, false},
+		{"bad dedup",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD&step=3600&dedup_ignoring=in-stance`),
+			}, nil, true},
+		{"good dedup",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&end=2017-07-10T21:42:24.760738998Z&limit=1000&direction=BACKWARD&step=3600&dedup_ignoring=instance,%20pod`),
+			}, &RangeQuery{
+				Step:      time.Hour,
+				Query:     `{foo="bar"} dedup ignoring(instance, pod)`,
+				Direction: logproto.BACKWARD,
+				Start:     time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				End:       time.Date(2017, 07, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:     1000,
+			}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -142,7 +173,7 @@ func TestStreams_ToProto(t *testing.T) {
 				Labels: map[string]string{"foo": "bar", "lvl": "error"},
 				Entries: []Entry{
 					{Timestamp: time.Unix(0, 3), Line: "3"},
-					{Timestamp: time.Unix(0, 4), Line: "4"},
+					{Timestamp: time.Unix(0, 4), Line: "4", StructuredMetadata: []logproto.LabelPair{{Name: "trace_id", Value: "abc"}}},
 				},
 			},
 		},
@@ -158,7 +189,7 @@ func TestStreams_ToProto(t *testing.T) {
 					Labels: `{foo="bar", lvl="error"}`,
 					Entries: []logproto.Entry{
 						{Timestamp: time.Unix(0, 3), Line: "3"},
-						{Timestamp: time.Unix(0, 4), Line: "4"},
+						{Timestamp: time.Unix(0, 4), Line: "4", StructuredMetadata: []logproto.LabelPair{{Name: "trace_id", Value: "abc"}}},
 					},
 				},
 			},
@@ -172,3 +203,31 @@ func TestStreams_ToProto(t *testing.T) {
 		})
 	}
 }
+
+func TestEntry_JSON(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		entry Entry
+		json  string
+	}{
+		{"line", Entry{Timestamp: time.Unix(0, 1), Line: "foo"}, `["1","foo"]`},
+		{
+			"structured metadata",
+			Entry{Timestamp: time.Unix(0, 2), Line: "bar", StructuredMetadata: []logproto.LabelPair{{Name: "trace_id", Value: "abc"}, {Name: "user", Value: "bob"}}},
+			`["2","bar",{"trace_id":"abc","user":"bob"}]`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.entry.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, tt.json, string(b))
+
+			var e Entry
+			require.NoError(t, e.UnmarshalJSON([]byte(tt.json)))
+			require.Equal(t, tt.entry, e)
+		})
+	}
+
+	var e Entry
+	require.Error(t, e.UnmarshalJSON([]byte(`["1"]`)))
+}
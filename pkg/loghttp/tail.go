@@ -2,6 +2,7 @@ package loghttp
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net/http"
 	"strconv"
 	"time"
@@ -19,6 +20,45 @@ const (
 type TailResponse struct {
 	Streams        []Stream        `json:"streams,omitempty"`
 	DroppedStreams []DroppedStream `json:"dropped_entries,omitempty"`
+	DroppedCounts  []DroppedCount  `json:"dropped_counts,omitempty"`
+}
+
+// DroppedCount represents the number of entries of a stream dropped in tail call
+type DroppedCount struct {
+	Labels LabelSet `json:"labels"`
+	Count  int      `json:"count"`
+}
+
+// TailCursor is the position of the last entries received by a tail client, used to
+// resume tailing after a disconnection without losing or repeating entries.
+type TailCursor struct {
+	Timestamp time.Time
+	// Hashes of the lines of all the entries received at Timestamp.
+	Hashes []uint64
+}
+
+// NewTailCursor returns the cursor to resume tailing after the entry.
+func NewTailCursor(e Entry) TailCursor {
+	return TailCursor{Timestamp: e.Timestamp, Hashes: []uint64{TailCursorHash(e.Line)}}
+}
+
+// Add moves the cursor to the entry when it is newer than the cursor, and records
+// the entry when it is at the timestamp of the cursor.
+func (c *TailCursor) Add(e Entry) {
+	switch {
+	case e.Timestamp.After(c.Timestamp):
+		c.Timestamp = e.Timestamp
+		c.Hashes = []uint64{TailCursorHash(e.Line)}
+	case e.Timestamp.Equal(c.Timestamp):
+		c.Hashes = append(c.Hashes, TailCursorHash(e.Line))
+	}
+}
+
+// TailCursorHash returns the hash identifying a line at the timestamp of a cursor.
+func TailCursorHash(line string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(line))
+	return h.Sum64()
 }
 
 // DroppedStream represents a dropped stream in tail call
@@ -87,3 +127,29 @@ func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	}
 	return &req, nil
 }
+
+// ParseTailCursor parses the resume cursor of a tail request from an http request.
+// It returns nil when the request does not resume a previous tail.
+func ParseTailCursor(r *http.Request) (*TailCursor, error) {
+	value := r.Form.Get("resume_from")
+	if value == "" {
+		return nil, nil
+	}
+	ts, err := parseTimestamp(value, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	values := r.Form["resume_hash"]
+	if len(values) == 0 {
+		return nil, fmt.Errorf("missing resume_hash")
+	}
+	hashes := make([]uint64, 0, len(values))
+	for _, v := range values {
+		hash, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resume_hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return &TailCursor{Timestamp: ts, Hashes: hashes}, nil
+}
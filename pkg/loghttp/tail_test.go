@@ -55,3 +55,57 @@ func TestParseTailQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestTailCursor_Add(t *testing.T) {
+	t.Parallel()
+
+	c := NewTailCursor(Entry{Timestamp: time.Unix(1, 0), Line: "a"})
+	c.Add(Entry{Timestamp: time.Unix(1, 0), Line: "b"})
+	c.Add(Entry{Timestamp: time.Unix(0, 0), Line: "c"})
+	require.Equal(t, TailCursor{Timestamp: time.Unix(1, 0), Hashes: []uint64{TailCursorHash("a"), TailCursorHash("b")}}, c)
+
+	c.Add(Entry{Timestamp: time.Unix(2, 0), Line: "d"})
+	require.Equal(t, TailCursor{Timestamp: time.Unix(2, 0), Hashes: []uint64{TailCursorHash("d")}}, c)
+}
+
+func TestParseTailCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    *TailCursor
+		wantErr bool
+	}{
+		{"no cursor", &http.Request{URL: mustParseURL(`?query={foo="bar"}`)}, nil, false},
+		{"bad time", &http.Request{URL: mustParseURL(`?query={foo="bar"}&resume_from=t&resume_hash=1`)}, nil, true},
+		{"bad hash", &http.Request{URL: mustParseURL(`?query={foo="bar"}&resume_from=1497130944760738998&resume_hash=h`)}, nil, true},
+		{"missing hash", &http.Request{URL: mustParseURL(`?query={foo="bar"}&resume_from=1497130944760738998`)}, nil, true},
+		{"good",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&resume_from=1497130944760738998&resume_hash=42`),
+			}, &TailCursor{
+				Timestamp: time.Unix(0, 1497130944760738998),
+				Hashes:    []uint64{42},
+			}, false},
+		{"many hashes",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&resume_from=1497130944760738998&resume_hash=42&resume_hash=43`),
+			}, &TailCursor{
+				Timestamp: time.Unix(0, 1497130944760738998),
+				Hashes:    []uint64{42, 43},
+			}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.r.ParseForm())
+			got, err := ParseTailCursor(tt.r)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
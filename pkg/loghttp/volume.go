@@ -0,0 +1,99 @@
+package loghttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logql"
+)
+
+// Volume types, i.e. what is counted by a VolumeQuery.
+const (
+	VolumeTypeLines = "lines"
+	VolumeTypeBytes = "bytes"
+)
+
+var errVolumeStep = fmt.Errorf("step must be a multiple of %s", chunkenc.VolumeBucketPeriod)
+
+// VolumeQuery defines a log volume query.
+type VolumeQuery struct {
+	// Query is the stream selector of the streams counted.
+	Query string
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+	// By is the labels the volume is grouped by, the volume of all the streams is summed when empty.
+	By []string
+	// Type is either VolumeTypeLines or VolumeTypeBytes.
+	Type string
+}
+
+// ParseVolumeQuery parses a VolumeQuery request from an http request.
+func ParseVolumeQuery(r *http.Request) (*VolumeQuery, error) {
+	var result VolumeQuery
+	var err error
+
+	result.Query = query(r)
+	if _, err := logql.ParseMatchers(result.Query); err != nil {
+		return nil, err
+	}
+
+	result.Start, result.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.End.Before(result.Start) || result.Start.Equal(result.End) {
+		return nil, errEndBeforeStart
+	}
+
+	if r.Form.Get("step") == "" {
+		result.Step = time.Duration(defaultQueryRangeStep(result.Start, result.End)) * time.Second
+		if rem := result.Step % chunkenc.VolumeBucketPeriod; rem != 0 {
+			result.Step += chunkenc.VolumeBucketPeriod - rem
+		}
+	} else {
+		result.Step, err = parseSecondsOrDuration(r.Form.Get("step"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Step <= 0 {
+		return nil, errNegativeStep
+	}
+	if result.Step%chunkenc.VolumeBucketPeriod != 0 {
+		return nil, errVolumeStep
+	}
+
+	if (result.End.Sub(result.Start) / result.Step) > 11000 {
+		return nil, errStepTooSmall
+	}
+
+	if by := r.Form.Get("by"); by != "" {
+		for _, name := range strings.Split(by, ",") {
+			name = strings.TrimSpace(name)
+			if !model.LabelName(name).IsValid() {
+				return nil, fmt.Errorf("invalid label name in by: %q", name)
+			}
+			result.By = append(result.By, name)
+		}
+	}
+
+	result.Type = r.Form.Get("type")
+	switch result.Type {
+	case "":
+		result.Type = VolumeTypeLines
+	case VolumeTypeLines, VolumeTypeBytes:
+	default:
+		return nil, errors.New("type must be either lines or bytes")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,60 @@
+package loghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVolumeQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    *VolumeQuery
+		wantErr bool
+	}{
+		{"bad query", &http.Request{URL: mustParseURL(`?query={foo="bar"} |= "buzz"`)}, nil, true},
+		{"end before start", &http.Request{URL: mustParseURL(`?query={foo="bar"}&start=2016-06-10T21:42:24Z&end=2015-06-10T21:42:24Z`)}, nil, true},
+		{"step not multiple of a minute", &http.Request{URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:00:00Z&end=2017-06-10T22:00:00Z&step=90s`)}, nil, true},
+		{"bad by", &http.Request{URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:00:00Z&end=2017-06-10T22:00:00Z&by=a-b`)}, nil, true},
+		{"bad type", &http.Request{URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:00:00Z&end=2017-06-10T22:00:00Z&type=entries`)}, nil, true},
+		{"default step",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T00:00:00Z&end=2017-06-11T00:00:00Z`),
+			}, &VolumeQuery{
+				Query: `{foo="bar"}`,
+				Start: time.Date(2017, 06, 10, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2017, 06, 11, 0, 0, 0, 0, time.UTC),
+				Step:  6 * time.Minute,
+				Type:  VolumeTypeLines,
+			}, false},
+		{"good",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T00:00:00Z&end=2017-06-11T00:00:00Z&step=1h&by=level,%20app&type=bytes`),
+			}, &VolumeQuery{
+				Query: `{foo="bar"}`,
+				Start: time.Date(2017, 06, 10, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2017, 06, 11, 0, 0, 0, 0, time.UTC),
+				Step:  time.Hour,
+				By:    []string{"level", "app"},
+				Type:  VolumeTypeBytes,
+			}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.r.ParseForm())
+
+			got, err := ParseVolumeQuery(tt.r)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
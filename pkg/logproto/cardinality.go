@@ -0,0 +1,18 @@
+package logproto
+
+import "sort"
+
+// TopLabelValues sorts the values of a label by decreasing number of streams and keeps the
+// first limit ones. All the values are kept when limit is 0.
+func TopLabelValues(values []LabelValueCardinality, limit int) []LabelValueCardinality {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Streams != values[j].Streams {
+			return values[i].Streams > values[j].Streams
+		}
+		return values[i].Value < values[j].Value
+	})
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return values
+}
@@ -0,0 +1,100 @@
+package logproto
+
+import (
+	"encoding/base64"
+
+	"github.com/weaveworks/common/httpgrpc"
+)
+
+// Reject records count entries of the stream rejected for reason. Only the error of the
+// first rejection of a given reason is kept.
+func (m *StreamPushResult) Reject(reason string, count int, retryable bool, err string) {
+	if count <= 0 {
+		return
+	}
+	for i := range m.Rejections {
+		if m.Rejections[i].Reason == reason {
+			m.Rejections[i].Count += uint32(count)
+			m.Rejections[i].Retryable = m.Rejections[i].Retryable || retryable
+			return
+		}
+	}
+	m.Rejections = append(m.Rejections, PushRejection{
+		Reason:    reason,
+		Count:     uint32(count),
+		Retryable: retryable,
+		Error:     err,
+	})
+}
+
+// Rejected returns the number of rejected entries of the stream.
+func (m *StreamPushResult) Rejected() int {
+	rejected := 0
+	for _, r := range m.Rejections {
+		rejected += int(r.Count)
+	}
+	return rejected
+}
+
+// Rejection returns the first retryable rejection of the response, or its first rejection if none
+// can be retried. It returns nil if all the entries were accepted.
+func (m *PushResponse) Rejection() *PushRejection {
+	var first *PushRejection
+	for i := range m.Streams {
+		for j := range m.Streams[i].Rejections {
+			r := &m.Streams[i].Rejections[j]
+			if r.Retryable {
+				return r
+			}
+			if first == nil {
+				first = r
+			}
+		}
+	}
+	return first
+}
+
+// pushResponseHeader is the header of the http response carried by a push error, holding the
+// base64 encoded PushResponse. Clients unaware of it only see the status code and body of the error.
+const pushResponseHeader = "X-Loki-Push-Response"
+
+// ErrorWithPushResponse adds the push response to an http error returned by a push, so that the
+// rejected entries are reported along with the error. Other errors are returned unchanged.
+func ErrorWithPushResponse(err error, resp *PushResponse) error {
+	httpResp, ok := httpgrpc.HTTPResponseFromError(err)
+	if !ok || resp == nil {
+		return err
+	}
+	buf, merr := resp.Marshal()
+	if merr != nil {
+		return err
+	}
+	httpResp.Headers = append(httpResp.Headers, &httpgrpc.Header{
+		Key:    pushResponseHeader,
+		Values: []string{base64.StdEncoding.EncodeToString(buf)},
+	})
+	return httpgrpc.ErrorFromHTTPResponse(httpResp)
+}
+
+// PushResponseFromError returns the push response carried by an error built by ErrorWithPushResponse.
+func PushResponseFromError(err error) (*PushResponse, bool) {
+	httpResp, ok := httpgrpc.HTTPResponseFromError(err)
+	if !ok {
+		return nil, false
+	}
+	for _, h := range httpResp.Headers {
+		if h.Key != pushResponseHeader || len(h.Values) != 1 {
+			continue
+		}
+		buf, err := base64.StdEncoding.DecodeString(h.Values[0])
+		if err != nil {
+			return nil, false
+		}
+		var resp PushResponse
+		if err := resp.Unmarshal(buf); err != nil {
+			return nil, false
+		}
+		return &resp, true
+	}
+	return nil, false
+}
@@ -0,0 +1,60 @@
+package logproto
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+)
+
+func TestStreamPushResult_Reject(t *testing.T) {
+	var r StreamPushResult
+	r.Reject("out_of_order", 2, false, "first")
+	r.Reject("rate_limited", 0, true, "ignored")
+	r.Reject("out_of_order", 1, false, "second")
+	r.Reject("rate_limited", 3, true, "limited")
+
+	require.Equal(t, []PushRejection{
+		{Reason: "out_of_order", Count: 3, Error: "first"},
+		{Reason: "rate_limited", Count: 3, Retryable: true, Error: "limited"},
+	}, r.Rejections)
+	require.Equal(t, 6, r.Rejected())
+}
+
+func TestPushResponse_Rejection(t *testing.T) {
+	resp := PushResponse{Streams: []StreamPushResult{{Accepted: 1}}}
+	require.Nil(t, resp.Rejection())
+
+	resp.Streams = append(resp.Streams, StreamPushResult{Rejections: []PushRejection{{Reason: "out_of_order", Count: 1}}})
+	require.Equal(t, "out_of_order", resp.Rejection().Reason)
+
+	resp.Streams = append(resp.Streams, StreamPushResult{Rejections: []PushRejection{{Reason: "rate_limited", Count: 1, Retryable: true}}})
+	require.Equal(t, "rate_limited", resp.Rejection().Reason)
+}
+
+func TestErrorWithPushResponse(t *testing.T) {
+	resp := &PushResponse{Streams: []StreamPushResult{{
+		Labels:     `{foo="bar"}`,
+		Accepted:   1,
+		Rejections: []PushRejection{{Reason: "out_of_order", Count: 1, Error: "entry out of order"}},
+	}}}
+
+	err := ErrorWithPushResponse(httpgrpc.Errorf(http.StatusBadRequest, "entry out of order"), resp)
+	// the status code and body are unchanged for clients unaware of the push response.
+	httpResp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusBadRequest), httpResp.Code)
+	require.Equal(t, "entry out of order", string(httpResp.Body))
+
+	actual, ok := PushResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, resp, actual)
+
+	_, ok = PushResponseFromError(httpgrpc.Errorf(http.StatusBadRequest, "entry out of order"))
+	require.False(t, ok)
+
+	other := errors.New("failed")
+	require.Equal(t, other, ErrorWithPushResponse(other, resp))
+}
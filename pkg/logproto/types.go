@@ -14,10 +14,12 @@ type Stream struct {
 	Entries []Entry `protobuf:"bytes,2,rep,name=entries,proto3,customtype=EntryAdapter" json:"entries"`
 }
 
-// Entry is a log entry with a timestamp.
+// Entry is a log entry with a timestamp and its optional structured metadata,
+// the non-indexed key/value attributes of the entry.
 type Entry struct {
-	Timestamp time.Time `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"ts"`
-	Line      string    `protobuf:"bytes,2,opt,name=line,proto3" json:"line"`
+	Timestamp          time.Time   `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"ts"`
+	Line               string      `protobuf:"bytes,2,opt,name=line,proto3" json:"line"`
+	StructuredMetadata []LabelPair `protobuf:"bytes,3,rep,name=structured_metadata,json=structuredMetadata,proto3" json:"structuredMetadata,omitempty"`
 }
 
 func (m *Stream) Marshal() (dAtA []byte, err error) {
@@ -85,6 +87,16 @@ func (m *Entry) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Line)))
 		i += copy(dAtA[i:], m.Line)
 	}
+	for _, msg := range m.StructuredMetadata {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintLogproto(dAtA, i, uint64(msg.Size()))
+		n, err := msg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
 	return i, nil
 }
 
@@ -302,6 +314,40 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 			}
 			m.Line = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StructuredMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StructuredMetadata = append(m.StructuredMetadata, LabelPair{})
+			if err := m.StructuredMetadata[len(m.StructuredMetadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -358,6 +404,10 @@ func (m *Entry) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	for _, e := range m.StructuredMetadata {
+		l = e.Size()
+		n += 1 + l + sovLogproto(uint64(l))
+	}
 	return n
 }
 
@@ -418,5 +468,13 @@ func (m *Entry) Equal(that interface{}) bool {
 	if m.Line != that1.Line {
 		return false
 	}
+	if len(m.StructuredMetadata) != len(that1.StructuredMetadata) {
+		return false
+	}
+	for i := range m.StructuredMetadata {
+		if !m.StructuredMetadata[i].Equal(&that1.StructuredMetadata[i]) {
+			return false
+		}
+	}
 	return true
 }
@@ -93,6 +93,10 @@ type filterExpr struct {
 
 // NewFilterExpr wraps an existing Expr with a next filter expression.
 func NewFilterExpr(left LogSelectorExpr, ty labels.MatchType, match string) LogSelectorExpr {
+	// entries are filtered before being deduplicated, which returns the same entries.
+	if e, ok := left.(*dedupExpr); ok {
+		return newDedupExpr(NewFilterExpr(e.left, ty, match), e.ignoring)
+	}
 	return &filterExpr{
 		left:  left,
 		ty:    ty,
@@ -126,14 +130,12 @@ func (e *filterExpr) Filter() (LineFilter, error) {
 	if err != nil {
 		return nil, err
 	}
-	if nextExpr, ok := e.left.(*filterExpr); ok {
-		nextFilter, err := nextExpr.Filter()
-		if err != nil {
-			return nil, err
-		}
-		if nextFilter != nil {
-			f = newAndFilter(nextFilter, f)
-		}
+	nextFilter, err := e.left.Filter()
+	if err != nil {
+		return nil, err
+	}
+	if nextFilter != nil {
+		f = newAndFilter(nextFilter, f)
 	}
 
 	if f == TrueFilter {
@@ -146,6 +148,88 @@ func (e *filterExpr) Filter() (LineFilter, error) {
 // impl Expr
 func (e *filterExpr) logQLExpr() {}
 
+// metadataFilterExpr filters the entries of a log selector on their structured metadata, e.g.
+// `{app="foo"} | trace_id="abc"`.
+type metadataFilterExpr struct {
+	left    LogSelectorExpr
+	matcher *labels.Matcher
+}
+
+func newMetadataFilterExpr(left LogSelectorExpr, matcher *labels.Matcher) LogSelectorExpr {
+	if e, ok := left.(*dedupExpr); ok {
+		return newDedupExpr(newMetadataFilterExpr(e.left, matcher), e.ignoring)
+	}
+	return &metadataFilterExpr{
+		left:    left,
+		matcher: matcher,
+	}
+}
+
+func (e *metadataFilterExpr) Matchers() []*labels.Matcher {
+	return e.left.Matchers()
+}
+
+func (e *metadataFilterExpr) String() string {
+	return fmt.Sprintf("%s|%s", e.left.String(), e.matcher.String())
+}
+
+func (e *metadataFilterExpr) Filter() (LineFilter, error) {
+	f := newMetadataFilter(e.matcher)
+	nextFilter, err := e.left.Filter()
+	if err != nil {
+		return nil, err
+	}
+	if nextFilter != nil {
+		f = newAndFilter(nextFilter, f)
+	}
+	return f, nil
+}
+
+// impl Expr
+func (e *metadataFilterExpr) logQLExpr() {}
+
+// dedupExpr deduplicates the entries of a log selector across series which only differ by
+// the ignored labels, e.g. the same file shipped by several agents. It is always the outermost
+// log selector expression, so that the entries are only deduplicated once they are filtered.
+type dedupExpr struct {
+	left     LogSelectorExpr
+	ignoring []string
+}
+
+func newDedupExpr(left LogSelectorExpr, ignoring []string) LogSelectorExpr {
+	if e, ok := left.(*dedupExpr); ok {
+		left, ignoring = e.left, append(append([]string{}, e.ignoring...), ignoring...)
+	}
+	return &dedupExpr{
+		left:     left,
+		ignoring: ignoring,
+	}
+}
+
+func (e *dedupExpr) Matchers() []*labels.Matcher {
+	return e.left.Matchers()
+}
+
+func (e *dedupExpr) Filter() (LineFilter, error) {
+	return e.left.Filter()
+}
+
+func (e *dedupExpr) String() string {
+	return fmt.Sprintf("%s %s %s(%s)", e.left.String(), OpDedup, OpIgnoring, strings.Join(e.ignoring, ", "))
+}
+
+// impl Expr
+func (e *dedupExpr) logQLExpr() {}
+
+// splitDedup returns the log selector expression without its dedup modifier, and the labels
+// ignored by the modifier or nil if it has none.
+func splitDedup(expr LogSelectorExpr) (LogSelectorExpr, []string) {
+	if e, ok := expr.(*dedupExpr); ok {
+		return e.left, e.ignoring
+	}
+	return expr, nil
+}
+
 func mustNewMatcher(t labels.MatchType, n, v string) *labels.Matcher {
 	m, err := labels.NewMatcher(t, n, v)
 	if err != nil {
@@ -177,11 +261,17 @@ func newLogRange(left LogSelectorExpr, interval time.Duration) *logRange {
 }
 
 func addFilterToLogRangeExpr(left *logRange, ty labels.MatchType, match string) *logRange {
-	left.left = &filterExpr{
-		left:  left.left,
-		ty:    ty,
-		match: match,
-	}
+	left.left = NewFilterExpr(left.left, ty, match)
+	return left
+}
+
+func addMetadataFilterToLogRangeExpr(left *logRange, matcher *labels.Matcher) *logRange {
+	left.left = newMetadataFilterExpr(left.left, matcher)
+	return left
+}
+
+func addDedupToLogRangeExpr(left *logRange, ignoring []string) *logRange {
+	left.left = newDedupExpr(left.left, ignoring)
 	return left
 }
 
@@ -223,6 +313,10 @@ const (
 	OpTypeGTE   = ">="
 	OpTypeLT    = "<"
 	OpTypeLTE   = "<="
+
+	// log selector modifiers
+	OpDedup    = "dedup"
+	OpIgnoring = "ignoring"
 )
 
 func IsComparisonOperator(op string) bool {
@@ -279,6 +373,10 @@ func (e *rangeAggregationExpr) String() string {
 
 // impl SampleExpr
 func (e *rangeAggregationExpr) Operations() []string {
+	// series are deduplicated before being aggregated, which isn't shardable.
+	if _, ok := e.left.left.(*dedupExpr); ok {
+		return []string{OpDedup, e.operation}
+	}
 	return []string{e.operation}
 }
 
@@ -25,6 +25,8 @@ func Test_logSelectorExpr_String(t *testing.T) {
 		{`{foo="bar", bar!="baz"} |~ ".*"`, false},
 		{`{foo="bar", bar!="baz"} |= "" |= ""`, false},
 		{`{foo="bar", bar!="baz"} |~ "" |= "" |~ ".*"`, false},
+		{`{foo="bar"} | trace_id="abc"`, true},
+		{`{foo="bar"} |= "baz" | trace_id=~"a.+" != "flip"`, true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,171 @@
+package logql
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// dedupSeries is a series of the deduplicated iterator, split between the labels
+// identifying duplicated entries and the values of the ignored labels.
+type dedupSeries struct {
+	key     string
+	labels  labels.Labels
+	sources []string
+}
+
+type dedupEntry struct {
+	labels string
+	entry  logproto.Entry
+}
+
+// dedupIterator collapses the entries having the same timestamp and line across series which
+// only differ by the ignored labels. The entries are returned with the labels of their series
+// without the ignored labels, and report the series they were read from as structured metadata:
+// each ignored label is set to the comma separated values of these series.
+type dedupIterator struct {
+	it       iter.EntryIterator
+	ignoring []string
+	series   map[string]*dedupSeries
+
+	// whether the entry of it was read ahead, and whether it is exhausted.
+	pending bool
+	done    bool
+
+	buffer []dedupEntry
+	curr   dedupEntry
+	err    error
+}
+
+func newDedupIterator(it iter.EntryIterator, ignoring []string) iter.EntryIterator {
+	return &dedupIterator{
+		it:       it,
+		ignoring: ignoring,
+		series:   map[string]*dedupSeries{},
+	}
+}
+
+func (d *dedupIterator) Next() bool {
+	if len(d.buffer) == 0 && !d.fill() {
+		return false
+	}
+	d.curr, d.buffer = d.buffer[0], d.buffer[1:]
+	return true
+}
+
+// fill buffers the deduplicated entries having the timestamp of the next entry.
+func (d *dedupIterator) fill() bool {
+	if !d.pending {
+		if d.done || !d.it.Next() {
+			d.done = true
+			return false
+		}
+		d.pending = true
+	}
+
+	type group struct {
+		series  *dedupSeries
+		entry   logproto.Entry
+		sources [][]string
+	}
+	var (
+		groups []*group
+		index  = map[string]*group{}
+		ts     = d.it.Entry().Timestamp
+	)
+	for d.pending && d.it.Entry().Timestamp.Equal(ts) {
+		s, err := d.seriesOf(d.it.Labels())
+		if err != nil {
+			d.err = err
+			return false
+		}
+		entry := d.it.Entry()
+		key := s.key + "\xff" + entry.Line
+		g, ok := index[key]
+		if !ok {
+			g = &group{series: s, entry: entry, sources: make([][]string, len(d.ignoring))}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		for i, value := range s.sources {
+			if value != "" {
+				g.sources[i] = append(g.sources[i], value)
+			}
+		}
+		d.pending = d.it.Next()
+	}
+	d.done = !d.pending
+
+	for _, g := range groups {
+		var sources []logproto.LabelPair
+		for i, name := range d.ignoring {
+			if values := uniqueSorted(g.sources[i]); len(values) > 0 {
+				sources = append(sources, logproto.LabelPair{Name: name, Value: strings.Join(values, ",")})
+			}
+		}
+		entry := g.entry
+		if len(sources) > 0 {
+			// the metadata of the entry is shared with the underlying iterator.
+			metadata := make([]logproto.LabelPair, 0, len(entry.StructuredMetadata)+len(sources))
+			entry.StructuredMetadata = append(append(metadata, entry.StructuredMetadata...), sources...)
+		}
+		d.buffer = append(d.buffer, dedupEntry{labels: g.series.key, entry: entry})
+	}
+	return true
+}
+
+func (d *dedupIterator) seriesOf(ls string) (*dedupSeries, error) {
+	if s, ok := d.series[ls]; ok {
+		return s, nil
+	}
+	lbs, err := parser.ParseMetric(ls)
+	if err != nil {
+		return nil, err
+	}
+	s := &dedupSeries{
+		labels:  lbs.WithoutLabels(d.ignoring...),
+		sources: make([]string, len(d.ignoring)),
+	}
+	for i, name := range d.ignoring {
+		s.sources[i] = lbs.Get(name)
+	}
+	s.key = s.labels.String()
+	d.series[ls] = s
+	return s, nil
+}
+
+func (d *dedupIterator) Entry() logproto.Entry {
+	return d.curr.entry
+}
+
+func (d *dedupIterator) Labels() string {
+	return d.curr.labels
+}
+
+func (d *dedupIterator) Error() error {
+	if d.err != nil {
+		return d.err
+	}
+	return d.it.Error()
+}
+
+func (d *dedupIterator) Close() error {
+	return d.it.Close()
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	j := 0
+	for i, v := range values {
+		if i == 0 || v != values[j-1] {
+			values[j] = v
+			j++
+		}
+	}
+	return values[:j]
+}
@@ -0,0 +1,86 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestDedupIterator(t *testing.T) {
+	it := newDedupIterator(iter.NewHeapIterator(context.Background(), []iter.EntryIterator{
+		iter.NewStreamIterator(newStream(5, identity, `{app="foo", instance="a"}`)),
+		iter.NewStreamIterator(newStream(5, offset(2, identity), `{app="foo", instance="b"}`)),
+		iter.NewStreamIterator(newStream(5, offset(2, identity), `{app="foo", instance="c", pod="c"}`)),
+		iter.NewStreamIterator(newStream(2, identity, `{app="bar", instance="a"}`)),
+		iter.NewStreamIterator(newStream(2, identity, `{app="bar"}`)),
+	}, logproto.FORWARD), []string{"instance"})
+
+	type source struct {
+		ts        int64
+		instances string
+	}
+	got := map[string][]source{}
+	for it.Next() {
+		var instances string
+		for _, m := range it.Entry().StructuredMetadata {
+			if m.Name == "instance" {
+				instances = m.Value
+			}
+		}
+		got[it.Labels()] = append(got[it.Labels()], source{it.Entry().Timestamp.Unix(), instances})
+	}
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+	require.Equal(t, map[string][]source{
+		`{app="bar"}`:          {{0, "a"}, {1, "a"}},
+		`{app="foo"}`:          {{0, "a"}, {1, "a"}, {2, "a,b"}, {3, "a,b"}, {4, "a,b"}, {5, "b"}, {6, "b"}},
+		`{app="foo", pod="c"}`: {{2, "c"}, {3, "c"}, {4, "c"}, {5, "c"}, {6, "c"}},
+	}, got)
+}
+
+func TestEngine_Dedup(t *testing.T) {
+	var selectors []string
+	eng := NewEngine(EngineOpts{}, QuerierFunc(func(ctx context.Context, sp SelectParams) (iter.EntryIterator, error) {
+		selectors = append(selectors, sp.Selector)
+		return iter.NewHeapIterator(ctx, []iter.EntryIterator{
+			iter.NewStreamIterator(newStream(10, identity, `{app="foo", instance="a"}`)),
+			iter.NewStreamIterator(newStream(10, identity, `{app="foo", instance="b"}`)),
+		}, sp.Direction), nil
+	}))
+
+	res, err := eng.Query(LiteralParams{
+		qs:        `{app="foo"} dedup ignoring(instance)`,
+		start:     time.Unix(0, 0),
+		end:       time.Unix(10, 0),
+		direction: logproto.FORWARD,
+		limit:     5,
+	}).Exec(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{`{app="foo"}`}, selectors)
+	expected := newStream(5, identity, `{app="foo"}`)
+	for i := range expected.Entries {
+		expected.Entries[i].StructuredMetadata = []logproto.LabelPair{{Name: "instance", Value: "a,b"}}
+	}
+	require.Equal(t, Streams{expected}, res.Data)
+
+	selectors = nil
+	res, err = eng.Query(LiteralParams{
+		qs:        `count_over_time({app="foo"} dedup ignoring(instance) [10s])`,
+		start:     time.Unix(10, 0),
+		end:       time.Unix(10, 0),
+		direction: logproto.FORWARD,
+		limit:     5,
+	}).Exec(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{`{app="foo"}`}, selectors)
+	require.Equal(t, promql.Vector{
+		promql.Sample{Point: promql.Point{T: 10 * 1000, V: 9}, Metric: labels.Labels{{Name: "app", Value: "foo"}}},
+	}, res.Data)
+}
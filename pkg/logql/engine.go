@@ -30,6 +30,9 @@ var (
 		Buckets:   prometheus.DefBuckets,
 	}, []string{"query_type"})
 	lastEntryMinTime = time.Unix(-100, 0)
+
+	// ErrStreamingMetricQuery is returned when a metric query is executed as a streamed query.
+	ErrStreamingMetricQuery = errors.New("only log queries can be streamed")
 )
 
 // ValueTypeStreams promql.ValueType for log streams
@@ -65,6 +68,8 @@ type EngineOpts struct {
 	// MaxLookBackPeriod is the maximum amount of time to look back for log lines.
 	// only used for instant log queries.
 	MaxLookBackPeriod time.Duration `yaml:"max_look_back_period"`
+	// SlowQueryLog configures the logging of slow queries.
+	SlowQueryLog SlowQueryLogConfig `yaml:"slow_query_log"`
 }
 
 func (opts *EngineOpts) applyDefault() {
@@ -78,16 +83,18 @@ func (opts *EngineOpts) applyDefault() {
 
 // Engine is the LogQL engine.
 type Engine struct {
-	timeout   time.Duration
-	evaluator Evaluator
+	timeout      time.Duration
+	evaluator    Evaluator
+	slowQueryLog SlowQueryLogConfig
 }
 
 // NewEngine creates a new LogQL Engine.
 func NewEngine(opts EngineOpts, q Querier) *Engine {
 	opts.applyDefault()
 	return &Engine{
-		timeout:   opts.Timeout,
-		evaluator: NewDefaultEvaluator(q, opts.MaxLookBackPeriod),
+		timeout:      opts.Timeout,
+		evaluator:    NewDefaultEvaluator(q, opts.MaxLookBackPeriod),
+		slowQueryLog: opts.SlowQueryLog,
 	}
 }
 
@@ -100,10 +107,67 @@ func (ng *Engine) Query(params Params) Query {
 		parse: func(_ context.Context, query string) (Expr, error) {
 			return ParseExpr(query)
 		},
-		record: true,
+		record:       true,
+		slowQueryLog: ng.slowQueryLog,
 	}
 }
 
+// EntryWriter receives the entries of a streamed log query, in the order they are read.
+type EntryWriter func(labels string, entry logproto.Entry) error
+
+// Stream executes a log query and hands every selected entry to w as soon as it is read,
+// instead of materializing the whole result. Metric queries are rejected.
+func (ng *Engine) Stream(ctx context.Context, params Params, w EntryWriter) (stats.Result, error) {
+	log, ctx := spanlogger.New(ctx, "query.Stream")
+	defer log.Finish()
+
+	timer := prometheus.NewTimer(queryTime.WithLabelValues(string(GetRangeType(params))))
+	defer timer.ObserveDuration()
+
+	start := time.Now()
+	ctx = stats.NewContext(ctx)
+
+	err := ng.stream(ctx, params, w)
+
+	statResult := stats.Snapshot(ctx, time.Since(start))
+	statResult.Log(level.Debug(log))
+
+	status := "200"
+	if err != nil {
+		status = "500"
+		if IsParseError(err) || err == ErrStreamingMetricQuery {
+			status = "400"
+		}
+	}
+	RecordMetrics(ctx, params, status, statResult, ng.slowQueryLog)
+	recordTenantMetrics(ctx, statResult)
+
+	return statResult, err
+}
+
+func (ng *Engine) stream(ctx context.Context, params Params, w EntryWriter) error {
+	ctx, cancel := context.WithTimeout(ctx, ng.timeout)
+	defer cancel()
+
+	expr, err := ParseExpr(params.Query())
+	if err != nil {
+		return err
+	}
+
+	e, ok := expr.(LogSelectorExpr)
+	if !ok {
+		return ErrStreamingMetricQuery
+	}
+
+	iter, err := selectLogs(ctx, ng.evaluator, e, params)
+	if err != nil {
+		return err
+	}
+	defer helpers.LogErrorWithContext(ctx, "closing iterator", iter.Close)
+
+	return forEachEntry(iter, params.Limit(), params.Direction(), params.Interval(), w)
+}
+
 // Query is a LogQL query to be executed.
 type Query interface {
 	// Exec processes the query.
@@ -116,6 +180,8 @@ type query struct {
 	parse     func(context.Context, string) (Expr, error)
 	evaluator Evaluator
 	record    bool
+	// slowQueryLog is only used when the query is recorded.
+	slowQueryLog SlowQueryLogConfig
 }
 
 // Exec Implements `Query`. It handles instrumentation & defers to Eval.
@@ -146,7 +212,8 @@ func (q *query) Exec(ctx context.Context) (Result, error) {
 	}
 
 	if q.record {
-		RecordMetrics(ctx, q.params, status, statResult)
+		RecordMetrics(ctx, q.params, status, statResult, q.slowQueryLog)
+		recordTenantMetrics(ctx, statResult)
 	}
 
 	return Result{
@@ -170,7 +237,7 @@ func (q *query) Eval(ctx context.Context) (parser.Value, error) {
 		return value, err
 
 	case LogSelectorExpr:
-		iter, err := q.evaluator.Iterator(ctx, e, q.params)
+		iter, err := selectLogs(ctx, q.evaluator, e, q.params)
 		if err != nil {
 			return nil, err
 		}
@@ -183,6 +250,16 @@ func (q *query) Eval(ctx context.Context) (parser.Value, error) {
 	}
 }
 
+// selectLogs returns the entries of a log selector, deduplicated when requested by the query.
+func selectLogs(ctx context.Context, ev Evaluator, expr LogSelectorExpr, params Params) (iter.EntryIterator, error) {
+	expr, ignoring := splitDedup(expr)
+	it, err := ev.Iterator(ctx, expr, params)
+	if err != nil || ignoring == nil {
+		return it, err
+	}
+	return newDedupIterator(it, ignoring), nil
+}
+
 // evalSample evaluate a sampleExpr
 func (q *query) evalSample(ctx context.Context, expr SampleExpr) (parser.Value, error) {
 	if lit, ok := expr.(*literalExpr); ok {
@@ -284,6 +361,29 @@ func PopulateMatrixFromScalar(data promql.Scalar, params Params) promql.Matrix {
 
 func readStreams(i iter.EntryIterator, size uint32, dir logproto.Direction, interval time.Duration) (Streams, error) {
 	streams := map[string]*logproto.Stream{}
+	err := forEachEntry(i, size, dir, interval, func(labels string, entry logproto.Entry) error {
+		stream, ok := streams[labels]
+		if !ok {
+			stream = &logproto.Stream{
+				Labels: labels,
+			}
+			streams[labels] = stream
+		}
+		stream.Entries = append(stream.Entries, entry)
+		return nil
+	})
+
+	result := make(Streams, 0, len(streams))
+	for _, stream := range streams {
+		result = append(result, *stream)
+	}
+	sort.Sort(result)
+	return result, err
+}
+
+// forEachEntry calls fn for at most size entries of the iterator, skipping the entries
+// which fall within the interval of the previously selected one.
+func forEachEntry(i iter.EntryIterator, size uint32, dir logproto.Direction, interval time.Duration, fn EntryWriter) error {
 	respSize := uint32(0)
 	// lastEntry should be a really old time so that the first comparison is always true, we use a negative
 	// value here because many unit tests start at time.Unix(0,0)
@@ -298,25 +398,14 @@ func readStreams(i iter.EntryIterator, size uint32, dir logproto.Direction, inte
 		// If lastEntry.Unix < 0 this is the first pass through the loop and we should output the line.
 		// Then check to see if the entry is equal to, or past a forward or reverse step
 		if interval == 0 || lastEntry.Unix() < 0 || forwardShouldOutput || backwardShouldOutput {
-			stream, ok := streams[labels]
-			if !ok {
-				stream = &logproto.Stream{
-					Labels: labels,
-				}
-				streams[labels] = stream
+			if err := fn(labels, entry); err != nil {
+				return err
 			}
-			stream.Entries = append(stream.Entries, entry)
 			lastEntry = i.Entry().Timestamp
 			respSize++
 		}
 	}
-
-	result := make(Streams, 0, len(streams))
-	for _, stream := range streams {
-		result = append(result, *stream)
-	}
-	sort.Sort(result)
-	return result, i.Error()
+	return i.Error()
 }
 
 type groupedAggregation struct {
@@ -1431,6 +1431,65 @@ func TestEngine_Stats(t *testing.T) {
 	require.Equal(t, int64(1), r.Statistics.Store.DecompressedBytes)
 }
 
+func TestEngine_Stream(t *testing.T) {
+	eng := NewEngine(EngineOpts{}, QuerierFunc(func(ctx context.Context, sp SelectParams) (iter.EntryIterator, error) {
+		st := stats.GetChunkData(ctx)
+		st.DecompressedBytes++
+		return iter.NewHeapIterator(ctx, []iter.EntryIterator{
+			iter.NewStreamIterator(newStream(10, identity, `{app="foo"}`)),
+			iter.NewStreamIterator(newStream(10, offset(10, identity), `{app="bar"}`)),
+		}, sp.Direction), nil
+	}))
+
+	type streamed struct {
+		labels string
+		entry  logproto.Entry
+	}
+	var got []streamed
+	r, err := eng.Stream(context.Background(), LiteralParams{
+		qs:        `{app=~"foo|bar"}`,
+		start:     time.Unix(0, 0),
+		end:       time.Unix(20, 0),
+		direction: logproto.FORWARD,
+		limit:     12,
+	}, func(labels string, entry logproto.Entry) error {
+		got = append(got, streamed{labels, entry})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), r.Store.DecompressedBytes)
+	require.Len(t, got, 12)
+	for i, s := range got {
+		require.Equal(t, identity(int64(i)), s.entry)
+	}
+	require.Equal(t, `{app="foo"}`, got[9].labels)
+	require.Equal(t, `{app="bar"}`, got[10].labels)
+
+	// errors from the writer interrupt the query.
+	_, err = eng.Stream(context.Background(), LiteralParams{
+		qs:        `{app=~"foo|bar"}`,
+		start:     time.Unix(0, 0),
+		end:       time.Unix(20, 0),
+		direction: logproto.FORWARD,
+		limit:     12,
+	}, func(labels string, entry logproto.Entry) error {
+		return ErrMock
+	})
+	require.Equal(t, ErrMock, err)
+
+	_, err = eng.Stream(context.Background(), LiteralParams{
+		qs:        `count_over_time({app="foo"}[1m])`,
+		start:     time.Unix(0, 0),
+		end:       time.Unix(20, 0),
+		step:      time.Second,
+		direction: logproto.FORWARD,
+		limit:     12,
+	}, func(labels string, entry logproto.Entry) error {
+		return nil
+	})
+	require.Equal(t, ErrStreamingMetricQuery, err)
+}
+
 func TestStepEvaluator_Error(t *testing.T) {
 	tests := []struct {
 		name  string
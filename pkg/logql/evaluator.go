@@ -158,19 +158,23 @@ func (ev *DefaultEvaluator) StepEvaluator(
 	case *vectorAggregationExpr:
 		return vectorAggEvaluator(ctx, nextEv, e, q)
 	case *rangeAggregationExpr:
+		selector, ignoring := splitDedup(expr.Selector())
 		entryIter, err := ev.querier.Select(ctx, SelectParams{
 			&logproto.QueryRequest{
 				Start:     q.Start().Add(-e.left.interval),
 				End:       q.End(),
 				Limit:     0,
 				Direction: logproto.FORWARD,
-				Selector:  expr.Selector().String(),
+				Selector:  selector.String(),
 				Shards:    q.Shards(),
 			},
 		})
 		if err != nil {
 			return nil, err
 		}
+		if ignoring != nil {
+			entryIter = newDedupIterator(entryIter, ignoring)
+		}
 		return rangeAggEvaluator(entryIter, e, q)
 	case *binOpExpr:
 		return binOpStepEvaluator(ctx, nextEv, e, q)
@@ -49,12 +49,14 @@ import (
 %type <BinOpExpr>             binOpExpr
 %type <LiteralExpr>           literalExpr
 %type <BinOpModifier>         binOpModifier
+%type <str>                   labelName
+%type <Labels>                dedupModifier
 
 %token <str>      IDENTIFIER STRING NUMBER
 %token <duration> DURATION
-%token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT
+%token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE PIPE_MATCH PIPE_EXACT
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE SUM AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
-                  BYTES_OVER_TIME BYTES_RATE BOOL
+                  BYTES_OVER_TIME BYTES_RATE BOOL DEDUP IGNORING
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -84,6 +86,8 @@ metricExpr:
 logExpr:
       selector                                    { $$ = newMatcherExpr($1)}
     | logExpr filter STRING                       { $$ = NewFilterExpr( $1, $2, $3 ) }
+    | logExpr PIPE matcher                        { $$ = newMetadataFilterExpr( $1, $3 ) }
+    | logExpr dedupModifier                       { $$ = newDedupExpr( $1, $2 ) }
     | OPEN_PARENTHESIS logExpr CLOSE_PARENTHESIS  { $$ = $2 }
     | logExpr filter error
     | logExpr error
@@ -92,6 +96,8 @@ logExpr:
 logRangeExpr:
       logExpr DURATION { $$ = newLogRange($1, $2) } // <selector> <filters> <range>
     | logRangeExpr filter STRING                       { $$ = addFilterToLogRangeExpr( $1, $2, $3 ) }
+    | logRangeExpr PIPE matcher                        { $$ = addMetadataFilterToLogRangeExpr( $1, $3 ) }
+    | logRangeExpr dedupModifier                       { $$ = addDedupToLogRangeExpr( $1, $2 ) }
     | OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS  { $$ = $2 }
     | logRangeExpr filter error
     | logRangeExpr error
@@ -128,10 +134,17 @@ matchers:
     ;
 
 matcher:
-      IDENTIFIER EQ STRING             { $$ = mustNewMatcher(labels.MatchEqual, $1, $3) }
-    | IDENTIFIER NEQ STRING            { $$ = mustNewMatcher(labels.MatchNotEqual, $1, $3) }
-    | IDENTIFIER RE STRING             { $$ = mustNewMatcher(labels.MatchRegexp, $1, $3) }
-    | IDENTIFIER NRE STRING            { $$ = mustNewMatcher(labels.MatchNotRegexp, $1, $3) }
+      labelName EQ STRING              { $$ = mustNewMatcher(labels.MatchEqual, $1, $3) }
+    | labelName NEQ STRING             { $$ = mustNewMatcher(labels.MatchNotEqual, $1, $3) }
+    | labelName RE STRING              { $$ = mustNewMatcher(labels.MatchRegexp, $1, $3) }
+    | labelName NRE STRING             { $$ = mustNewMatcher(labels.MatchNotRegexp, $1, $3) }
+    ;
+
+// Labels can be named as the keywords of the dedup modifier.
+labelName:
+      IDENTIFIER                       { $$ = $1 }
+    | DEDUP                            { $$ = OpDedup }
+    | IGNORING                         { $$ = OpIgnoring }
     ;
 
 // TODO(owen-d): add (on,ignoring) clauses to binOpExpr
@@ -186,10 +199,12 @@ rangeOp:
 
 
 labels:
-      IDENTIFIER                 { $$ = []string{ $1 } }
-    | labels COMMA IDENTIFIER    { $$ = append($1, $3) }
+      labelName                  { $$ = []string{ $1 } }
+    | labels COMMA labelName     { $$ = append($1, $3) }
     ;
 
+dedupModifier: DEDUP IGNORING OPEN_PARENTHESIS labels CLOSE_PARENTHESIS { $$ = $4 };
+
 grouping:
       BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS        { $$ = &grouping{ without: false , groups: $3 } }
     | WITHOUT OPEN_PARENTHESIS labels CLOSE_PARENTHESIS   { $$ = &grouping{ without: true , groups: $3 } }
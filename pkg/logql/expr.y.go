@@ -4,7 +4,6 @@ package logql
 
 import __yyfmt__ "fmt"
 
-
 import (
 	"github.com/prometheus/prometheus/pkg/labels"
 	"time"
@@ -49,41 +48,44 @@ const OPEN_BRACKET = 57357
 const CLOSE_BRACKET = 57358
 const COMMA = 57359
 const DOT = 57360
-const PIPE_MATCH = 57361
-const PIPE_EXACT = 57362
-const OPEN_PARENTHESIS = 57363
-const CLOSE_PARENTHESIS = 57364
-const BY = 57365
-const WITHOUT = 57366
-const COUNT_OVER_TIME = 57367
-const RATE = 57368
-const SUM = 57369
-const AVG = 57370
-const MAX = 57371
-const MIN = 57372
-const COUNT = 57373
-const STDDEV = 57374
-const STDVAR = 57375
-const BOTTOMK = 57376
-const TOPK = 57377
-const BYTES_OVER_TIME = 57378
-const BYTES_RATE = 57379
-const BOOL = 57380
-const OR = 57381
-const AND = 57382
-const UNLESS = 57383
-const CMP_EQ = 57384
-const NEQ = 57385
-const LT = 57386
-const LTE = 57387
-const GT = 57388
-const GTE = 57389
-const ADD = 57390
-const SUB = 57391
-const MUL = 57392
-const DIV = 57393
-const MOD = 57394
-const POW = 57395
+const PIPE = 57361
+const PIPE_MATCH = 57362
+const PIPE_EXACT = 57363
+const OPEN_PARENTHESIS = 57364
+const CLOSE_PARENTHESIS = 57365
+const BY = 57366
+const WITHOUT = 57367
+const COUNT_OVER_TIME = 57368
+const RATE = 57369
+const SUM = 57370
+const AVG = 57371
+const MAX = 57372
+const MIN = 57373
+const COUNT = 57374
+const STDDEV = 57375
+const STDVAR = 57376
+const BOTTOMK = 57377
+const TOPK = 57378
+const BYTES_OVER_TIME = 57379
+const BYTES_RATE = 57380
+const BOOL = 57381
+const DEDUP = 57382
+const IGNORING = 57383
+const OR = 57384
+const AND = 57385
+const UNLESS = 57386
+const CMP_EQ = 57387
+const NEQ = 57388
+const LT = 57389
+const LTE = 57390
+const GT = 57391
+const GTE = 57392
+const ADD = 57393
+const SUB = 57394
+const MUL = 57395
+const DIV = 57396
+const MOD = 57397
+const POW = 57398
 
 var exprToknames = [...]string{
 	"$end",
@@ -104,6 +106,7 @@ var exprToknames = [...]string{
 	"CLOSE_BRACKET",
 	"COMMA",
 	"DOT",
+	"PIPE",
 	"PIPE_MATCH",
 	"PIPE_EXACT",
 	"OPEN_PARENTHESIS",
@@ -124,6 +127,8 @@ var exprToknames = [...]string{
 	"BYTES_OVER_TIME",
 	"BYTES_RATE",
 	"BOOL",
+	"DEDUP",
+	"IGNORING",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -140,27 +145,24 @@ var exprToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var exprStatenames = [...]string{}
 
 const exprEofCode = 1
 const exprErrCode = 2
 const exprInitialStackSize = 16
 
-
 var exprExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
 	-1, 3,
 	1, 2,
-	22, 2,
-	39, 2,
-	40, 2,
-	41, 2,
+	23, 2,
 	42, 2,
+	43, 2,
 	44, 2,
 	45, 2,
-	46, 2,
 	47, 2,
 	48, 2,
 	49, 2,
@@ -168,15 +170,15 @@ var exprExca = [...]int{
 	51, 2,
 	52, 2,
 	53, 2,
+	54, 2,
+	55, 2,
+	56, 2,
 	-2, 0,
-	-1, 52,
-	39, 2,
-	40, 2,
-	41, 2,
+	-1, 55,
 	42, 2,
+	43, 2,
 	44, 2,
 	45, 2,
-	46, 2,
 	47, 2,
 	48, 2,
 	49, 2,
@@ -184,138 +186,154 @@ var exprExca = [...]int{
 	51, 2,
 	52, 2,
 	53, 2,
+	54, 2,
+	55, 2,
+	56, 2,
 	-2, 0,
 }
 
 const exprPrivate = 57344
 
-const exprLast = 279
+const exprLast = 349
 
 var exprAct = [...]int{
-
-	60, 4, 45, 130, 56, 3, 93, 38, 51, 53,
-	2, 66, 52, 30, 31, 32, 39, 40, 43, 44,
-	41, 42, 33, 34, 35, 36, 37, 38, 14, 33,
-	34, 35, 36, 37, 38, 11, 35, 36, 37, 38,
-	89, 91, 92, 6, 61, 62, 142, 17, 18, 21,
-	22, 24, 25, 23, 26, 27, 28, 29, 19, 20,
-	59, 96, 61, 62, 94, 138, 127, 139, 11, 139,
-	15, 16, 141, 90, 140, 101, 95, 102, 103, 104,
-	105, 106, 107, 108, 109, 110, 111, 112, 113, 114,
-	115, 83, 116, 100, 99, 98, 121, 58, 128, 88,
-	129, 125, 126, 64, 132, 31, 32, 39, 40, 43,
-	44, 41, 42, 33, 34, 35, 36, 37, 38, 134,
-	63, 120, 133, 86, 46, 97, 10, 143, 136, 121,
-	137, 119, 11, 82, 49, 85, 81, 118, 87, 117,
-	6, 47, 48, 144, 17, 18, 21, 22, 24, 25,
-	23, 26, 27, 28, 29, 19, 20, 65, 55, 131,
-	57, 57, 9, 13, 8, 50, 5, 15, 16, 39,
+	66, 60, 47, 45, 4, 59, 141, 38, 3, 101,
+	90, 54, 56, 2, 61, 55, 30, 31, 32, 39,
 	40, 43, 44, 41, 42, 33, 34, 35, 36, 37,
-	38, 12, 7, 54, 1, 0, 0, 0, 0, 67,
-	68, 69, 70, 71, 72, 73, 74, 75, 76, 77,
-	78, 79, 80, 46, 0, 0, 0, 122, 124, 0,
-	0, 0, 122, 49, 0, 0, 0, 49, 0, 0,
-	47, 48, 49, 84, 47, 48, 0, 135, 46, 47,
-	48, 0, 123, 124, 0, 0, 0, 46, 49, 0,
-	0, 0, 0, 0, 50, 47, 48, 49, 50, 0,
-	0, 0, 0, 50, 47, 48, 0, 84, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 50,
-	0, 0, 0, 0, 0, 0, 0, 0, 50,
+	38, 72, 156, 14, 33, 34, 35, 36, 37, 38,
+	11, 35, 36, 37, 38, 97, 99, 100, 151, 6,
+	62, 63, 89, 17, 18, 21, 22, 24, 25, 23,
+	26, 27, 28, 29, 19, 20, 67, 68, 138, 58,
+	104, 61, 65, 102, 67, 68, 152, 152, 15, 16,
+	91, 98, 155, 154, 109, 124, 110, 111, 112, 113,
+	114, 115, 116, 117, 118, 119, 120, 121, 122, 123,
+	152, 125, 108, 107, 132, 130, 153, 62, 63, 142,
+	142, 140, 136, 137, 11, 143, 106, 64, 94, 139,
+	96, 146, 88, 103, 145, 87, 142, 70, 69, 129,
+	93, 144, 128, 95, 127, 126, 10, 147, 9, 149,
+	132, 130, 13, 8, 150, 5, 12, 7, 57, 1,
+	0, 0, 0, 0, 157, 0, 0, 158, 31, 32,
+	39, 40, 43, 44, 41, 42, 33, 34, 35, 36,
+	37, 38, 105, 0, 0, 0, 0, 0, 0, 11,
+	0, 0, 0, 0, 0, 0, 0, 71, 6, 0,
+	0, 0, 17, 18, 21, 22, 24, 25, 23, 26,
+	27, 28, 29, 19, 20, 39, 40, 43, 44, 41,
+	42, 33, 34, 35, 36, 37, 38, 15, 16, 73,
+	74, 75, 76, 77, 78, 79, 80, 81, 82, 83,
+	84, 85, 86, 48, 0, 0, 0, 0, 135, 0,
+	0, 0, 0, 51, 0, 133, 0, 0, 0, 0,
+	46, 49, 50, 0, 92, 51, 0, 0, 0, 0,
+	0, 0, 131, 49, 50, 0, 148, 0, 48, 0,
+	0, 53, 0, 135, 0, 0, 0, 52, 51, 0,
+	133, 0, 0, 53, 0, 46, 49, 50, 0, 52,
+	51, 0, 48, 0, 0, 0, 0, 131, 49, 50,
+	0, 134, 51, 0, 48, 0, 53, 0, 0, 46,
+	49, 50, 52, 92, 51, 0, 0, 0, 53, 0,
+	0, 46, 49, 50, 52, 0, 0, 0, 0, 0,
+	53, 0, 0, 0, 0, 0, 52, 0, 0, 0,
+	0, 0, 53, 0, 0, 0, 0, 0, 52,
 }
-var exprPact = [...]int{
 
-	22, -1000, -26, 122, -1000, -1000, 22, -1000, -1000, -1000,
-	-1000, 156, 76, 39, -1000, 114, 97, -1000, -1000, -1000,
+var exprPact = [...]int{
+	27, -1000, -26, 302, -1000, -1000, 27, -1000, -1000, -1000,
+	-1000, 67, 95, 50, -1000, 122, 121, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-27, -27, -27, -27, -27, -27, -27, -27, -27, -27,
-	-27, -27, -27, -27, -27, 131, -1000, -1000, -1000, -1000,
-	-1000, 69, 235, -26, 121, 85, -1000, 30, 55, 119,
-	74, 73, 72, -1000, -1000, 22, -1000, 22, 22, 22,
-	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
-	22, -1000, -1000, -1000, -1000, -1000, -1000, 157, -1000, 134,
-	132, 126, 116, 210, 226, 55, 44, 81, 22, 155,
-	155, 65, 127, 127, -14, -14, -46, -46, -46, -46,
-	-19, -19, -19, -19, -19, -19, -1000, -1000, -1000, -1000,
-	-1000, 117, -1000, -1000, -1000, 201, 205, 21, 22, 43,
-	52, -1000, 50, -1000, -1000, -1000, -1000, 24, -1000, 123,
-	-1000, -1000, 21, -1000, -1000,
+	-8, -8, -8, -8, -8, -8, -8, -8, -8, -8,
+	-8, -8, -8, -8, -8, 120, 10, -1000, -1000, -1000,
+	-1000, -1000, -1000, -31, 57, 290, -26, 116, 106, -1000,
+	35, -1000, -1000, -1000, 101, 166, 94, 81, 80, -1000,
+	-1000, 27, -1000, 27, 27, 27, 27, 27, 27, 27,
+	27, 27, 27, 27, 27, 27, 27, -1000, -1000, -1000,
+	63, -1000, -1000, -1000, -1000, 10, -1000, 130, 129, 127,
+	124, 278, 266, 101, 45, 102, 27, 10, 10, 115,
+	160, 160, -12, -12, -49, -49, -49, -49, -17, -17,
+	-17, -17, -17, -17, 10, -1000, -1000, -1000, -1000, -1000,
+	119, 10, -1000, -1000, -1000, -1000, 231, 243, 42, 27,
+	25, 83, -1000, 60, 59, -1000, -1000, -1000, -1000, -1000,
+	9, -1000, 10, -1000, -1000, -1000, 42, -1000, -1000,
 }
-var exprPgo = [...]int{
 
-	0, 184, 9, 2, 0, 3, 5, 1, 6, 4,
-	183, 182, 181, 166, 164, 163, 162, 126, 157,
+var exprPgo = [...]int{
+	0, 149, 12, 3, 0, 6, 8, 4, 9, 5,
+	148, 147, 146, 145, 143, 142, 138, 136, 187, 1,
+	2,
 }
-var exprR1 = [...]int{
 
+var exprR1 = [...]int{
 	0, 1, 2, 2, 7, 7, 7, 7, 7, 6,
-	6, 6, 6, 6, 8, 8, 8, 8, 8, 11,
-	14, 14, 14, 14, 14, 3, 3, 3, 3, 13,
-	13, 13, 10, 10, 9, 9, 9, 9, 16, 16,
+	6, 6, 6, 6, 6, 6, 8, 8, 8, 8,
+	8, 8, 8, 11, 14, 14, 14, 14, 14, 3,
+	3, 3, 3, 13, 13, 13, 10, 10, 9, 9,
+	9, 9, 19, 19, 19, 16, 16, 16, 16, 16,
 	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
-	16, 16, 16, 18, 18, 17, 17, 17, 15, 15,
-	15, 15, 15, 15, 15, 15, 15, 12, 12, 12,
-	12, 5, 5, 4, 4,
+	18, 18, 17, 17, 17, 15, 15, 15, 15, 15,
+	15, 15, 15, 15, 12, 12, 12, 12, 5, 5,
+	20, 4, 4,
 }
-var exprR2 = [...]int{
 
+var exprR2 = [...]int{
 	0, 1, 1, 1, 1, 1, 1, 1, 3, 1,
-	3, 3, 3, 2, 2, 3, 3, 3, 2, 4,
-	4, 5, 5, 6, 7, 1, 1, 1, 1, 3,
-	3, 3, 1, 3, 3, 3, 3, 3, 4, 4,
+	3, 3, 2, 3, 3, 2, 2, 3, 3, 2,
+	3, 3, 2, 4, 4, 5, 5, 6, 7, 1,
+	1, 1, 1, 3, 3, 3, 1, 3, 3, 3,
+	3, 3, 1, 1, 1, 4, 4, 4, 4, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 0, 1, 1, 2, 2, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 3, 4, 4,
+	0, 1, 1, 2, 2, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 3,
+	5, 4, 4,
 }
-var exprChk = [...]int{
 
-	-1000, -1, -2, -6, -7, -13, 21, -11, -14, -16,
-	-17, 13, -12, -15, 6, 48, 49, 25, 26, 36,
-	37, 27, 28, 31, 29, 30, 32, 33, 34, 35,
-	39, 40, 41, 48, 49, 50, 51, 52, 53, 42,
-	43, 46, 47, 44, 45, -3, 2, 19, 20, 12,
-	43, -7, -6, -2, -10, 2, -9, 4, 21, 21,
-	-4, 23, 24, 6, 6, -18, 38, -18, -18, -18,
-	-18, -18, -18, -18, -18, -18, -18, -18, -18, -18,
-	-18, 5, 2, 22, 22, 14, 2, 17, 14, 10,
-	43, 11, 12, -8, -6, 21, -7, 6, 21, 21,
-	21, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -2, -2, -2, -2, -2, -9, 5, 5, 5,
-	5, -3, 2, 22, 7, -6, -8, 22, 17, -7,
-	-5, 4, -5, 5, 2, 22, -4, -7, 22, 17,
-	22, 22, 22, 4, -4,
+var exprChk = [...]int{
+	-1000, -1, -2, -6, -7, -13, 22, -11, -14, -16,
+	-17, 13, -12, -15, 6, 51, 52, 26, 27, 37,
+	38, 28, 29, 32, 30, 31, 33, 34, 35, 36,
+	42, 43, 44, 51, 52, 53, 54, 55, 56, 45,
+	46, 49, 50, 47, 48, -3, 19, -20, 2, 20,
+	21, 12, 46, 40, -7, -6, -2, -10, 2, -9,
+	-19, 4, 40, 41, 22, 22, -4, 24, 25, 6,
+	6, -18, 39, -18, -18, -18, -18, -18, -18, -18,
+	-18, -18, -18, -18, -18, -18, -18, 5, 2, -9,
+	41, 23, 23, 14, 2, 17, 14, 10, 46, 11,
+	12, -8, -6, 22, -7, 6, 22, 22, 22, -2,
+	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
+	-2, -2, -2, -2, 22, -9, 5, 5, 5, 5,
+	-3, 19, -20, 2, 23, 7, -6, -8, 23, 17,
+	-7, -5, -19, -5, -5, 5, 2, -9, 23, -4,
+	-7, 23, 17, 23, 23, 23, 23, -19, -4,
 }
-var exprDef = [...]int{
 
+var exprDef = [...]int{
 	0, -2, 1, -2, 3, 9, 0, 4, 5, 6,
-	7, 0, 0, 0, 55, 0, 0, 67, 68, 69,
-	70, 58, 59, 60, 61, 62, 63, 64, 65, 66,
-	53, 53, 53, 53, 53, 53, 53, 53, 53, 53,
-	53, 53, 53, 53, 53, 0, 13, 25, 26, 27,
-	28, 3, -2, 0, 0, 0, 32, 0, 0, 0,
-	0, 0, 0, 56, 57, 0, 54, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 10, 12, 8, 11, 29, 30, 0, 31, 0,
-	0, 0, 0, 0, 0, 0, 3, 55, 0, 0,
-	0, 38, 39, 40, 41, 42, 43, 44, 45, 46,
-	47, 48, 49, 50, 51, 52, 33, 34, 35, 36,
-	37, 0, 18, 19, 14, 0, 0, 20, 0, 3,
-	0, 71, 0, 15, 17, 16, 22, 3, 21, 0,
-	73, 74, 23, 72, 24,
+	7, 0, 0, 0, 62, 0, 0, 74, 75, 76,
+	77, 65, 66, 67, 68, 69, 70, 71, 72, 73,
+	60, 60, 60, 60, 60, 60, 60, 60, 60, 60,
+	60, 60, 60, 60, 60, 0, 0, 12, 15, 29,
+	30, 31, 32, 0, 3, -2, 0, 0, 0, 36,
+	0, 42, 43, 44, 0, 0, 0, 0, 0, 63,
+	64, 0, 61, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 10, 14, 11,
+	0, 8, 13, 33, 34, 0, 35, 0, 0, 0,
+	0, 0, 0, 0, 3, 62, 0, 0, 0, 45,
+	46, 47, 48, 49, 50, 51, 52, 53, 54, 55,
+	56, 57, 58, 59, 0, 37, 38, 39, 40, 41,
+	0, 0, 19, 22, 23, 16, 0, 0, 24, 0,
+	3, 0, 78, 0, 0, 17, 21, 18, 20, 26,
+	3, 25, 0, 81, 82, 80, 27, 79, 28,
 }
-var exprTok1 = [...]int{
 
+var exprTok1 = [...]int{
 	1,
 }
-var exprTok2 = [...]int{
 
+var exprTok2 = [...]int{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	52, 53,
+	52, 53, 54, 55, 56,
 }
+
 var exprTok3 = [...]int{
 	0,
 }
@@ -326,7 +344,6 @@ var exprErrorMessages = [...]struct {
 	msg   string
 }{}
 
-
 /*	parser for yacc output	*/
 
 var (
@@ -707,300 +724,340 @@ exprdefault:
 			exprVAL.LogExpr = NewFilterExpr(exprDollar[1].LogExpr, exprDollar[2].Filter, exprDollar[3].str)
 		}
 	case 11:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.LogExpr = newMetadataFilterExpr(exprDollar[1].LogExpr, exprDollar[3].Matcher)
+		}
+	case 12:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.LogExpr = newDedupExpr(exprDollar[1].LogExpr, exprDollar[2].Labels)
+		}
+	case 13:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
-	case 14:
+	case 16:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = newLogRange(exprDollar[1].LogExpr, exprDollar[2].duration)
 		}
-	case 15:
+	case 17:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.LogRangeExpr = addFilterToLogRangeExpr(exprDollar[1].LogRangeExpr, exprDollar[2].Filter, exprDollar[3].str)
 		}
-	case 16:
+	case 18:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
-			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
+			exprVAL.LogRangeExpr = addMetadataFilterToLogRangeExpr(exprDollar[1].LogRangeExpr, exprDollar[3].Matcher)
 		}
 	case 19:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+		{
+			exprVAL.LogRangeExpr = addDedupToLogRangeExpr(exprDollar[1].LogRangeExpr, exprDollar[2].Labels)
+		}
+	case 20:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+		{
+			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
+		}
+	case 23:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp)
 		}
-	case 20:
+	case 24:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
 		}
-	case 21:
+	case 25:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
-	case 22:
+	case 26:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
-	case 23:
+	case 27:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
-	case 24:
+	case 28:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 25:
+	case 29:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchRegexp
 		}
-	case 26:
+	case 30:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchEqual
 		}
-	case 27:
+	case 31:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotRegexp
 		}
-	case 28:
+	case 32:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Filter = labels.MatchNotEqual
 		}
-	case 29:
+	case 33:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 30:
+	case 34:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 31:
+	case 35:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 		}
-	case 32:
+	case 36:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
 		}
-	case 33:
+	case 37:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
 		}
-	case 34:
+	case 38:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 35:
+	case 39:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 36:
+	case 40:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 37:
+	case 41:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 38:
+	case 42:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = exprDollar[1].str
+		}
+	case 43:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpDedup
+		}
+	case 44:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+		{
+			exprVAL.str = OpIgnoring
+		}
+	case 45:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 39:
+	case 46:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 40:
+	case 47:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 41:
+	case 48:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 42:
+	case 49:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 43:
+	case 50:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 44:
+	case 51:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 45:
+	case 52:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 46:
+	case 53:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 47:
+	case 54:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 48:
+	case 55:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 49:
+	case 56:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 50:
+	case 57:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 51:
+	case 58:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 52:
+	case 59:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 53:
+	case 60:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = BinOpOptions{}
 		}
-	case 54:
+	case 61:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.BinOpModifier = BinOpOptions{ReturnBool: true}
 		}
-	case 55:
+	case 62:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 56:
+	case 63:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 57:
+	case 64:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 58:
+	case 65:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 59:
+	case 66:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 60:
+	case 67:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 61:
+	case 68:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 62:
+	case 69:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 63:
+	case 70:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 64:
+	case 71:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 65:
+	case 72:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 66:
+	case 73:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 67:
+	case 74:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 68:
+	case 75:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 69:
+	case 76:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 70:
+	case 77:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 71:
+	case 78:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 72:
+	case 79:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 73:
+	case 80:
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+		{
+			exprVAL.Labels = exprDollar[4].Labels
+		}
+	case 81:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &grouping{without: false, groups: exprDollar[3].Labels}
 		}
-	case 74:
+	case 82:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
 		{
 			exprVAL.Grouping = &grouping{without: true, groups: exprDollar[3].Labels}
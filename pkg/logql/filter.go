@@ -7,6 +7,8 @@ import (
 	"regexp/syntax"
 
 	"github.com/prometheus/prometheus/pkg/labels"
+
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 // LineFilter is a interface to filter log lines.
@@ -14,6 +16,21 @@ type LineFilter interface {
 	Filter(line []byte) bool
 }
 
+// EntryFilter is a LineFilter which can also filter log entries on their structured metadata.
+type EntryFilter interface {
+	LineFilter
+	FilterEntry(line []byte, metadata []logproto.LabelPair) bool
+}
+
+// FilterEntry filters a log entry with its structured metadata, which are only used by the filters
+// implementing EntryFilter.
+func FilterEntry(f LineFilter, line []byte, metadata []logproto.LabelPair) bool {
+	if ef, ok := f.(EntryFilter); ok {
+		return ef.FilterEntry(line, metadata)
+	}
+	return f.Filter(line)
+}
+
 // LineFilterFunc is a syntax sugar for creating line filter from a function
 type LineFilterFunc func(line []byte) bool
 
@@ -72,6 +89,32 @@ func (a andFilter) Filter(line []byte) bool {
 	return a.left.Filter(line) && a.right.Filter(line)
 }
 
+func (a andFilter) FilterEntry(line []byte, metadata []logproto.LabelPair) bool {
+	return FilterEntry(a.left, line, metadata) && FilterEntry(a.right, line, metadata)
+}
+
+// metadataFilter matches the log entries whose structured metadata match a label matcher.
+// An entry without the metadata is matched as if its value was empty, like a missing label.
+type metadataFilter struct {
+	matcher *labels.Matcher
+}
+
+func newMetadataFilter(matcher *labels.Matcher) LineFilter {
+	return metadataFilter{matcher: matcher}
+}
+
+// Filter matches the lines as entries without metadata.
+func (m metadataFilter) Filter(line []byte) bool { return m.FilterEntry(line, nil) }
+
+func (m metadataFilter) FilterEntry(_ []byte, metadata []logproto.LabelPair) bool {
+	for _, l := range metadata {
+		if l.Name == m.matcher.Name {
+			return m.matcher.Matches(l.Value)
+		}
+	}
+	return m.matcher.Matches("")
+}
+
 type orFilter struct {
 	left  LineFilter
 	right LineFilter
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
 )
 
 func Test_SimplifiedRegex(t *testing.T) {
@@ -126,6 +129,36 @@ func Test_TrueFilter(t *testing.T) {
 	}
 }
 
+func Test_MetadataFilter(t *testing.T) {
+	metadata := []logproto.LabelPair{{Name: "trace_id", Value: "abc"}, {Name: "user", Value: "bob"}}
+	for _, test := range []struct {
+		name     string
+		f        LineFilter
+		line     string
+		metadata []logproto.LabelPair
+		expected bool
+	}{
+		{"equal", newMetadataFilter(mustNewMatcher(labels.MatchEqual, "trace_id", "abc")), "foo", metadata, true},
+		{"not equal", newMetadataFilter(mustNewMatcher(labels.MatchNotEqual, "trace_id", "abc")), "foo", metadata, false},
+		{"regexp", newMetadataFilter(mustNewMatcher(labels.MatchRegexp, "user", "b.*")), "foo", metadata, true},
+		{"missing", newMetadataFilter(mustNewMatcher(labels.MatchEqual, "trace_id", "abc")), "foo", nil, false},
+		{"missing empty", newMetadataFilter(mustNewMatcher(labels.MatchEqual, "trace_id", "")), "foo", nil, true},
+		{"missing not equal", newMetadataFilter(mustNewMatcher(labels.MatchNotEqual, "trace_id", "abc")), "foo", nil, true},
+		{"and line", newAndFilter(newContainsFilter([]byte("foo"), false), newMetadataFilter(mustNewMatcher(labels.MatchEqual, "user", "bob"))), "foo", metadata, true},
+		{"and line mismatch", newAndFilter(newContainsFilter([]byte("foo"), false), newMetadataFilter(mustNewMatcher(labels.MatchEqual, "user", "bob"))), "bar", metadata, false},
+		{"and metadata mismatch", newAndFilter(newContainsFilter([]byte("foo"), false), newMetadataFilter(mustNewMatcher(labels.MatchEqual, "user", "alice"))), "foo", metadata, false},
+		{"line filter", newContainsFilter([]byte("foo"), false), "foo", metadata, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, FilterEntry(test.f, []byte(test.line), test.metadata))
+			if test.metadata == nil {
+				// lines are filtered as entries without metadata.
+				require.Equal(t, test.expected, test.f.Filter([]byte(test.line)))
+			}
+		})
+	}
+}
+
 func Benchmark_LineFilter(b *testing.B) {
 	b.ReportAllocs()
 	logline := `level=bar ts=2020-02-22T14:57:59.398312973Z caller=logging.go:44 traceID=2107b6b551458908 msg="GET /buzz (200) 4.599635ms`
@@ -17,6 +17,7 @@ var tokens = map[string]int{
 	OpTypeNEQ:            NEQ,
 	"=~":                 RE,
 	"!~":                 NRE,
+	"|":                  PIPE,
 	"|=":                 PIPE_EXACT,
 	"|~":                 PIPE_MATCH,
 	"(":                  OPEN_PARENTHESIS,
@@ -24,6 +25,8 @@ var tokens = map[string]int{
 	"by":                 BY,
 	"without":            WITHOUT,
 	"bool":               BOOL,
+	OpDedup:              DEDUP,
+	OpIgnoring:           IGNORING,
 	"[":                  OPEN_BRACKET,
 	"]":                  CLOSE_BRACKET,
 	OpRangeTypeRate:      RATE,
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/stats"
 )
 
 // WriteQueryResponseJSON marshals the promql.Value to v1 loghttp JSON and then
@@ -37,6 +38,49 @@ func WriteQueryResponseJSON(v logql.Result, w io.Writer) error {
 	return json.NewEncoder(w).Encode(q)
 }
 
+// QueryStreamWriter marshals the entries of a streamed query to v1 loghttp
+// newline delimited JSON, one loghttp.QueryStreamLine per entry.
+type QueryStreamWriter struct {
+	enc    *json.Encoder
+	labels map[string]loghttp.LabelSet
+}
+
+// NewQueryStreamWriter makes a new QueryStreamWriter writing to the provided io.Writer.
+func NewQueryStreamWriter(w io.Writer) *QueryStreamWriter {
+	return &QueryStreamWriter{
+		enc:    json.NewEncoder(w),
+		labels: map[string]loghttp.LabelSet{},
+	}
+}
+
+// WriteEntry writes a single entry of the stream identified by labels.
+// It can be used as a logql.EntryWriter.
+func (s *QueryStreamWriter) WriteEntry(labels string, e logproto.Entry) error {
+	ls, ok := s.labels[labels]
+	if !ok {
+		var err error
+		ls, err = NewLabelSet(labels)
+		if err != nil {
+			return err
+		}
+		s.labels[labels] = ls
+	}
+	return s.enc.Encode(loghttp.QueryStreamLine{
+		Labels:  ls,
+		Entries: []loghttp.Entry{NewEntry(e)},
+	})
+}
+
+// WriteStats writes the statistics line ending a successful streamed query.
+func (s *QueryStreamWriter) WriteStats(r stats.Result) error {
+	return s.enc.Encode(loghttp.QueryStreamLine{Statistics: &r})
+}
+
+// WriteError writes the line ending a streamed query interrupted by err.
+func (s *QueryStreamWriter) WriteError(err error) error {
+	return s.enc.Encode(loghttp.QueryStreamLine{Error: err.Error()})
+}
+
 // WriteLabelResponseJSON marshals a logproto.LabelResponse to v1 loghttp JSON
 // and then writes it to the provided io.Writer.
 func WriteLabelResponseJSON(l logproto.LabelResponse, w io.Writer) error {
@@ -75,6 +119,43 @@ func WriteSeriesResponseJSON(r logproto.SeriesResponse, w io.Writer) error {
 	return json.NewEncoder(w).Encode(adapter)
 }
 
+// WriteCardinalityResponseJSON marshals a logproto.CardinalityResponse to v1 loghttp JSON and then
+// writes it to the provided io.Writer.
+func WriteCardinalityResponseJSON(r logproto.CardinalityResponse, w io.Writer) error {
+	resp := loghttp.CardinalityResponse{
+		Status: "success",
+		Data: loghttp.CardinalityData{
+			Streams: r.Streams,
+			Labels:  make([]loghttp.LabelCardinality, 0, len(r.Labels)),
+			Churn:   make([]loghttp.StreamChurn, 0, len(r.Churn)),
+		},
+	}
+
+	for _, l := range r.Labels {
+		label := loghttp.LabelCardinality{
+			Name:      l.Name,
+			Streams:   l.Streams,
+			Values:    l.Values,
+			TopValues: make([]loghttp.LabelValueCardinality, 0, len(l.TopValues)),
+		}
+		for _, v := range l.TopValues {
+			label.TopValues = append(label.TopValues, loghttp.LabelValueCardinality(v))
+		}
+		resp.Data.Labels = append(resp.Data.Labels, label)
+	}
+	for _, c := range r.Churn {
+		resp.Data.Churn = append(resp.Data.Churn, loghttp.StreamChurn(c))
+	}
+
+	return json.NewEncoder(w).Encode(resp)
+}
+
+// WriteIndexStatsResponseJSON marshals loghttp.IndexStats to v1 loghttp JSON and then writes it to the
+// provided io.Writer.
+func WriteIndexStatsResponseJSON(stats loghttp.IndexStats, w io.Writer) error {
+	return json.NewEncoder(w).Encode(loghttp.IndexStatsResponse{Status: "success", Data: stats})
+}
+
 // This struct exists primarily because we can't specify a repeated map in proto v3.
 // Otherwise, we'd use that + gogoproto.jsontag to avoid this layer of indirection
 type seriesResponseAdapter struct {
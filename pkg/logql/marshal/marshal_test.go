@@ -359,6 +359,44 @@ var tailTests = []struct {
 			]
 		}`,
 	},
+	{
+		legacy.TailResponse{
+			Streams: []logproto.Stream{
+				{
+					Entries: []logproto.Entry{
+						{
+							Timestamp: time.Unix(0, 123456789012345),
+							Line:      "super line",
+						},
+					},
+					Labels: "{test=\"test\"}",
+				},
+			},
+			DroppedCounts: map[string]int{
+				"{test=\"test\"}": 3,
+			},
+		},
+		`{
+			"streams": [
+				{
+					"stream": {
+						"test": "test"
+					},
+					"values":[
+						[ "123456789012345", "super line" ]
+					]
+				}
+			],
+			"dropped_counts": [
+				{
+					"labels": {
+						"test": "test"
+					},
+					"count": 3
+				}
+			]
+		}`,
+	},
 }
 
 func Test_WriteQueryResponseJSON(t *testing.T) {
@@ -371,6 +409,27 @@ func Test_WriteQueryResponseJSON(t *testing.T) {
 	}
 }
 
+func Test_QueryStreamWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewQueryStreamWriter(&b)
+	require.NoError(t, w.WriteEntry(`{test="test"}`, logproto.Entry{Timestamp: time.Unix(0, 123456789012345), Line: "super line"}))
+	require.NoError(t, w.WriteEntry(`{test="test"}`, logproto.Entry{Timestamp: time.Unix(0, 123456789012346), Line: "super line2"}))
+	require.NoError(t, w.WriteError(fmt.Errorf("interrupted")))
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	testJSONBytesEqual(t, []byte(`{"stream":{"test":"test"},"values":[["123456789012345","super line"]]}`), lines[0], "first entry")
+	testJSONBytesEqual(t, []byte(`{"stream":{"test":"test"},"values":[["123456789012346","super line2"]]}`), lines[1], "second entry")
+	testJSONBytesEqual(t, []byte(`{"error":"interrupted"}`), lines[2], "error")
+
+	var line loghttp.QueryStreamLine
+	require.NoError(t, json.Unmarshal(lines[1], &line))
+	require.Equal(t, loghttp.QueryStreamLine{
+		Labels:  loghttp.LabelSet{"test": "test"},
+		Entries: []loghttp.Entry{{Timestamp: time.Unix(0, 123456789012346), Line: "super line2"}},
+	}, line)
+}
+
 func Test_WriteLabelResponseJSON(t *testing.T) {
 	for i, labelTest := range labelTests {
 		var b bytes.Buffer
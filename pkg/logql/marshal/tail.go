@@ -1,6 +1,8 @@
 package marshal
 
 import (
+	"sort"
+
 	"github.com/grafana/loki/pkg/loghttp"
 	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 )
@@ -28,6 +30,17 @@ func NewTailResponse(r legacy.TailResponse) (loghttp.TailResponse, error) {
 		}
 	}
 
+	for labels, count := range r.DroppedCounts {
+		l, err := NewLabelSet(labels)
+		if err != nil {
+			return loghttp.TailResponse{}, err
+		}
+		ret.DroppedCounts = append(ret.DroppedCounts, loghttp.DroppedCount{Labels: l, Count: count})
+	}
+	sort.Slice(ret.DroppedCounts, func(i, j int) bool {
+		return ret.DroppedCounts[i].Labels.String() < ret.DroppedCounts[j].Labels.String()
+	})
+
 	return ret, nil
 }
 
@@ -2,12 +2,15 @@ package logql
 
 import (
 	"context"
+	"flag"
+	"strings"
 	"time"
 
 	"github.com/cortexproject/cortex/pkg/util"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/logql/stats"
 )
@@ -60,9 +63,42 @@ var (
 		Name:      "logql_querystats_ingester_sent_lines_total",
 		Help:      "Total count of lines sent from ingesters while executing LogQL queries.",
 	})
+	tenantBytesProcessed = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "loki",
+		Name:      "logql_querystats_tenant_bytes_processed",
+		Help:      "Distribution of bytes processed per LogQL query by tenant.",
+		// 1MB 4MB 16MB 64MB 256MB 1GB 4GB 16GB 64GB 256GB
+		Buckets: prometheus.ExponentialBuckets(1e6, 4, 10),
+	}, []string{"tenant"})
+	tenantLinesProcessed = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "loki",
+		Name:      "logql_querystats_tenant_lines_processed",
+		Help:      "Distribution of lines processed per LogQL query by tenant.",
+		// 1k 4k 16k 64k 256k 1M 4M 16M 64M 256M
+		Buckets: prometheus.ExponentialBuckets(1000, 4, 10),
+	}, []string{"tenant"})
 )
 
-func RecordMetrics(ctx context.Context, p Params, status string, stats stats.Result) {
+// SlowQueryLogConfig configures the logging of the queries above a latency or processed bytes threshold.
+type SlowQueryLogConfig struct {
+	LatencyThreshold time.Duration `yaml:"latency_threshold"`
+	BytesThreshold   int64         `yaml:"bytes_threshold"`
+}
+
+// RegisterFlagsWithPrefix registers the flags of the slow query log with a prefix.
+func (cfg *SlowQueryLogConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.DurationVar(&cfg.LatencyThreshold, prefix+"slow-query-log.latency-threshold", 0, "Log the queries taking longer than this duration with their statistics. 0 to disable.")
+	f.Int64Var(&cfg.BytesThreshold, prefix+"slow-query-log.bytes-threshold", 0, "Log the queries processing more than this number of bytes with their statistics. 0 to disable.")
+}
+
+func (cfg SlowQueryLogConfig) isSlow(stats stats.Result) bool {
+	return (cfg.LatencyThreshold > 0 && stats.Summary.ExecTime >= cfg.LatencyThreshold.Seconds()) ||
+		(cfg.BytesThreshold > 0 && stats.Summary.TotalBytesProcessed >= cfg.BytesThreshold)
+}
+
+// RecordMetrics logs the query with its statistics and records the query metrics. Queries above the
+// thresholds of the slow query log are logged at the warn level, with their time range, shards and full statistics.
+func RecordMetrics(ctx context.Context, p Params, status string, stats stats.Result, slowQueryLog SlowQueryLogConfig) {
 	logger := util.WithContext(ctx, util.Logger)
 	queryType, err := QueryType(p.Query())
 	if err != nil {
@@ -78,7 +114,8 @@ func RecordMetrics(ctx context.Context, p Params, status string, stats stats.Res
 	}
 
 	// we also log queries, useful for troubleshooting slow queries.
-	level.Info(logger).Log(
+	logLevel := level.Info
+	keyvals := []interface{}{
 		"latency", latencyType, // this can be used to filter log lines.
 		"query", p.Query(),
 		"query_type", queryType,
@@ -89,7 +126,17 @@ func RecordMetrics(ctx context.Context, p Params, status string, stats stats.Res
 		"status", status,
 		"throughput_mb", float64(stats.Summary.BytesProcessedPerSecond)/1e6,
 		"total_bytes_mb", float64(stats.Summary.TotalBytesProcessed)/1e6,
-	)
+	}
+	if slowQueryLog.isSlow(stats) {
+		logLevel = level.Warn
+		keyvals = append(keyvals,
+			"start", p.Start().Format(time.RFC3339Nano),
+			"end", p.End().Format(time.RFC3339Nano),
+			"shards", strings.Join(p.Shards(), ","),
+		)
+		keyvals = append(keyvals, stats.KVList()...)
+	}
+	logLevel(logger).Log(keyvals...)
 
 	bytesPerSecond.WithLabelValues(status, queryType, rt, latencyType).
 		Observe(float64(stats.Summary.BytesProcessedPerSecond))
@@ -103,18 +150,30 @@ func RecordMetrics(ctx context.Context, p Params, status string, stats stats.Res
 	ingesterLineTotal.Add(float64(stats.Ingester.TotalLinesSent))
 }
 
+// recordTenantMetrics records the bytes and lines processed by a query for its tenant. It is only called by
+// the engine of the queriers so that queries going through the frontend aren't observed twice, the split
+// and sharded queries of the frontend being observed by part.
+func recordTenantMetrics(ctx context.Context, stats stats.Result) {
+	// the tenant is empty when missing, e.g. with a failed authentication.
+	tenant, _ := user.ExtractOrgID(ctx)
+	tenantBytesProcessed.WithLabelValues(tenant).Observe(float64(stats.Summary.TotalBytesProcessed))
+	tenantLinesProcessed.WithLabelValues(tenant).Observe(float64(stats.Summary.TotalLinesProcessed))
+}
+
 func QueryType(query string) (string, error) {
 	expr, err := ParseExpr(query)
 	if err != nil {
 		return "", err
 	}
-	switch expr.(type) {
+	switch e := expr.(type) {
 	case SampleExpr:
 		return QueryTypeMetric, nil
 	case *matchersExpr:
 		return QueryTypeLimited, nil
-	case *filterExpr:
+	case *filterExpr, *metadataFilterExpr:
 		return QueryTypeFilter, nil
+	case *dedupExpr:
+		return QueryType(e.left.String())
 	default:
 		return "", nil
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,7 +70,7 @@ func TestLogSlowQuery(t *testing.T) {
 			ExecTime:                25.25,
 			TotalBytesProcessed:     100000,
 		},
-	})
+	}, SlowQueryLogConfig{})
 	require.Equal(t,
 		fmt.Sprintf(
 			"level=info org_id=foo traceID=%s latency=slow query=\"{foo=\\\"bar\\\"} |= \\\"buzz\\\"\" query_type=filter range_type=range length=1h0m0s step=1m0s duration=25.25s status=200 throughput_mb=0.1 total_bytes_mb=0.1\n",
@@ -78,3 +79,58 @@ func TestLogSlowQuery(t *testing.T) {
 		buf.String())
 	util.Logger = log.NewNopLogger()
 }
+
+func TestSlowQueryLogConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  SlowQueryLogConfig
+		slow bool
+	}{
+		{"disabled", SlowQueryLogConfig{}, false},
+		{"below latency", SlowQueryLogConfig{LatencyThreshold: 30 * time.Second}, false},
+		{"above latency", SlowQueryLogConfig{LatencyThreshold: 10 * time.Second}, true},
+		{"below bytes", SlowQueryLogConfig{BytesThreshold: 1e6}, false},
+		{"above bytes", SlowQueryLogConfig{BytesThreshold: 1e5}, true},
+		{"either", SlowQueryLogConfig{LatencyThreshold: 30 * time.Second, BytesThreshold: 1e5}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.slow, tc.cfg.isSlow(stats.Result{
+				Summary: stats.Summary{
+					ExecTime:            25.25,
+					TotalBytesProcessed: 100000,
+				},
+			}))
+		})
+	}
+}
+
+func TestLogSlowQueryStats(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	util.Logger = log.NewLogfmtLogger(buf)
+	defer func() { util.Logger = log.NewNopLogger() }()
+
+	ctx := user.InjectOrgID(context.Background(), "foo")
+	start := time.Unix(0, 0).UTC()
+	RecordMetrics(ctx, LiteralParams{
+		qs:        `{foo="bar"}`,
+		direction: logproto.BACKWARD,
+		start:     start,
+		end:       start.Add(time.Hour),
+		limit:     1000,
+		shards:    []string{"0_of_2"},
+	}, "200", stats.Result{
+		Summary: stats.Summary{
+			ExecTime:            25.25,
+			TotalBytesProcessed: 100000,
+			TotalLinesProcessed: 10,
+		},
+	}, SlowQueryLogConfig{LatencyThreshold: 10 * time.Second})
+
+	// the thresholds only change the level of the query log line and add the statistics to it.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+	require.True(t, strings.HasPrefix(lines[0],
+		`level=warn org_id=foo latency=slow query="{foo=\"bar\"}" query_type=limited range_type=range length=1h0m0s step=0s duration=25.25s status=200 throughput_mb=0 total_bytes_mb=0.1 start=1970-01-01T00:00:00Z end=1970-01-01T01:00:00Z shards=0_of_2 Ingester.TotalReached=0`,
+	), lines[0])
+	require.Contains(t, lines[0], `Summary.TotalBytesProcessed="100 kB" Summary.TotalLinesProcessed=10 Summary.ExecTime=25.25s`)
+}
@@ -45,6 +45,36 @@ func ParseExpr(input string) (expr Expr, err error) {
 	return l.expr, nil
 }
 
+// AddDedup returns the query with the entries of its log selectors deduplicated across series ignoring
+// the given labels, in addition to the labels already ignored by the dedup modifiers of the query.
+func AddDedup(query string, ignoring []string) (string, error) {
+	expr, err := ParseExpr(query)
+	if err != nil {
+		return "", err
+	}
+	switch e := expr.(type) {
+	case SampleExpr:
+		addDedupToSampleExpr(e, ignoring)
+		return e.String(), nil
+	case LogSelectorExpr:
+		return newDedupExpr(e, ignoring).String(), nil
+	default:
+		return "", fmt.Errorf("unexpected expr type (%T)", expr)
+	}
+}
+
+func addDedupToSampleExpr(expr SampleExpr, ignoring []string) {
+	switch e := expr.(type) {
+	case *rangeAggregationExpr:
+		e.left.left = newDedupExpr(e.left.left, ignoring)
+	case *vectorAggregationExpr:
+		addDedupToSampleExpr(e.left, ignoring)
+	case *binOpExpr:
+		addDedupToSampleExpr(e.SampleExpr, ignoring)
+		addDedupToSampleExpr(e.RHS, ignoring)
+	}
+}
+
 // ParseMatchers parses a string and returns labels matchers, if the expression contains
 // anything else it will return an error.
 func ParseMatchers(input string) ([]*labels.Matcher, error) {
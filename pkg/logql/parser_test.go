@@ -1162,6 +1162,146 @@ func TestParse(t *testing.T) {
 				col:  1,
 			},
 		},
+		{
+			in: `{foo="bar"} |= "dedup" dedup ignoring(instance, pod)`,
+			exp: &dedupExpr{
+				left: &filterExpr{
+					ty:    labels.MatchEqual,
+					match: "dedup",
+					left: &matchersExpr{
+						matchers: []*labels.Matcher{
+							mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+						},
+					},
+				},
+				ignoring: []string{"instance", "pod"},
+			},
+		},
+		{
+			in: `{foo="bar"} |= "baz" | trace_id="abc"`,
+			exp: &metadataFilterExpr{
+				left: &filterExpr{
+					ty:    labels.MatchEqual,
+					match: "baz",
+					left: &matchersExpr{
+						matchers: []*labels.Matcher{
+							mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+						},
+					},
+				},
+				matcher: mustNewMatcher(labels.MatchEqual, "trace_id", "abc"),
+			},
+		},
+		{
+			in: `count_over_time({foo="bar"}[5m] | user!~"bob|alice")`,
+			exp: &rangeAggregationExpr{
+				operation: "count_over_time",
+				left: &logRange{
+					left: &metadataFilterExpr{
+						left: &matchersExpr{
+							matchers: []*labels.Matcher{
+								mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+							},
+						},
+						matcher: mustNewMatcher(labels.MatchNotRegexp, "user", "bob|alice"),
+					},
+					interval: 5 * time.Minute,
+				},
+			},
+		},
+		{
+			in: `{dedup="bar"} dedup ignoring(instance)`,
+			exp: &dedupExpr{
+				left: &matchersExpr{
+					matchers: []*labels.Matcher{
+						mustNewMatcher(labels.MatchEqual, "dedup", "bar"),
+					},
+				},
+				ignoring: []string{"instance"},
+			},
+		},
+		{
+			in: `{foo="bar"} dedup ignoring(instance) |= "baz"`,
+			exp: &dedupExpr{
+				left: &filterExpr{
+					ty:    labels.MatchEqual,
+					match: "baz",
+					left: &matchersExpr{
+						matchers: []*labels.Matcher{
+							mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+						},
+					},
+				},
+				ignoring: []string{"instance"},
+			},
+		},
+		{
+			in: `{foo="bar"} dedup ignoring(instance) dedup ignoring(pod)`,
+			exp: &dedupExpr{
+				left: &matchersExpr{
+					matchers: []*labels.Matcher{
+						mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+					},
+				},
+				ignoring: []string{"instance", "pod"},
+			},
+		},
+		{
+			in: `{foo="bar"} dedup by(instance)`,
+			err: ParseError{
+				msg:  "syntax error: unexpected by, expecting ignoring",
+				line: 1,
+				col:  19,
+			},
+		},
+		{
+			in: `count_over_time({foo="bar"} dedup ignoring(instance) [5m])`,
+			exp: &rangeAggregationExpr{
+				left: &logRange{
+					left: &dedupExpr{
+						left: &matchersExpr{
+							matchers: []*labels.Matcher{
+								mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+							},
+						},
+						ignoring: []string{"instance"},
+					},
+					interval: 5 * time.Minute,
+				},
+				operation: OpRangeTypeCount,
+			},
+		},
+		{
+			in: `sum(rate({foo="bar"}[5m] |= "baz" dedup ignoring(instance)))`,
+			exp: mustNewVectorAggregationExpr(
+				&rangeAggregationExpr{
+					left: &logRange{
+						left: &dedupExpr{
+							left: &filterExpr{
+								ty:    labels.MatchEqual,
+								match: "baz",
+								left: &matchersExpr{
+									matchers: []*labels.Matcher{
+										mustNewMatcher(labels.MatchEqual, "foo", "bar"),
+									},
+								},
+							},
+							ignoring: []string{"instance"},
+						},
+						interval: 5 * time.Minute,
+					},
+					operation: OpRangeTypeRate,
+				},
+				OpTypeSum, nil, nil),
+		},
+		{
+			in: `count_over_time({foo="bar"}[5m]) dedup ignoring(instance)`,
+			err: ParseError{
+				msg:  "syntax error: unexpected dedup",
+				line: 1,
+				col:  34,
+			},
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			ast, err := ParseExpr(tc.in)
@@ -1171,6 +1311,30 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestAddDedup(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{in: `{foo="bar"}`, out: `{foo="bar"} dedup ignoring(instance)`},
+		{in: `{foo="bar"} |= "baz" dedup ignoring(pod)`, out: `{foo="bar"}|="baz" dedup ignoring(pod, instance)`},
+		{in: `rate({foo="bar"}[1m])`, out: `rate(({foo="bar"} dedup ignoring(instance))[1m])`},
+		{in: `sum(rate({foo="bar"}[1m])) / count_over_time({foo="bar"} dedup ignoring(pod) [1m])`, out: `sum(rate(({foo="bar"} dedup ignoring(instance))[1m])) / count_over_time(({foo="bar"} dedup ignoring(pod, instance))[1m])`},
+		{in: `{foo="bar"`, err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			out, err := AddDedup(tc.in, []string{"instance"})
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.out, out)
+		})
+	}
+}
+
 func TestParseMatchers(t *testing.T) {
 
 	tests := []struct {
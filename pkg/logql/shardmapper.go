@@ -129,8 +129,11 @@ func (m ShardMapper) Map(expr Expr, r *shardRecorder) (Expr, error) {
 	switch e := expr.(type) {
 	case *literalExpr:
 		return e, nil
-	case *matchersExpr, *filterExpr:
+	case *matchersExpr, *filterExpr, *metadataFilterExpr:
 		return m.mapLogSelectorExpr(e.(LogSelectorExpr), r), nil
+	case *dedupExpr:
+		// entries are deduplicated once the shards are merged.
+		return newDedupExpr(m.mapLogSelectorExpr(e.left, r), e.ignoring), nil
 	case *vectorAggregationExpr:
 		return m.mapVectorAggregationExpr(e, r)
 	case *rangeAggregationExpr:
@@ -278,6 +281,10 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *vectorAggregationExpr, r *sh
 }
 
 func (m ShardMapper) mapRangeAggregationExpr(expr *rangeAggregationExpr, r *shardRecorder) SampleExpr {
+	// the duplicated series can be in different shards.
+	if !isShardable(expr.Operations()) {
+		return expr
+	}
 	switch expr.operation {
 	case OpRangeTypeCount, OpRangeTypeRate, OpRangeTypeBytesRate, OpRangeTypeBytes:
 		// count_over_time(x) -> count_over_time(x, shard=1) ++ count_over_time(x, shard=2)...
@@ -150,6 +150,14 @@ func TestMappingStrings(t *testing.T) {
 			in:  `{foo="bar"} |= "id=123"`,
 			out: `downstream<{foo="bar"}|="id=123", shard=0_of_2> ++ downstream<{foo="bar"}|="id=123", shard=1_of_2>`,
 		},
+		{
+			in:  `{foo="bar"} dedup ignoring(instance)`,
+			out: `downstream<{foo="bar"}, shard=0_of_2> ++ downstream<{foo="bar"}, shard=1_of_2> dedup ignoring(instance)`,
+		},
+		{
+			in:  `sum(rate({foo="bar"} dedup ignoring(instance) [5m]))`,
+			out: `sum(rate(({foo="bar"} dedup ignoring(instance))[5m]))`,
+		},
 		{
 			in:  `sum by (cluster) (rate({foo="bar"} |= "id=123" [5m]))`,
 			out: `sum by(cluster)(downstream<sum by(cluster)(rate(({foo="bar"}|="id=123")[5m])), shard=0_of_2> ++ downstream<sum by(cluster)(rate(({foo="bar"}|="id=123")[5m])), shard=1_of_2>)`,
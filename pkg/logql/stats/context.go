@@ -44,7 +44,17 @@ const (
 
 // Log logs a query statistics result.
 func (r Result) Log(log log.Logger) {
-	_ = log.Log(
+	_ = log.Log(r.kvList()...)
+	r.Summary.Log(log)
+}
+
+// KVList returns all the fields of a query statistics result as key-value pairs, as logged by Log.
+func (r Result) KVList() []interface{} {
+	return append(r.kvList(), r.Summary.kvList()...)
+}
+
+func (r Result) kvList() []interface{} {
+	return []interface{}{
 		"Ingester.TotalReached", r.Ingester.TotalReached,
 		"Ingester.TotalChunksMatched", r.Ingester.TotalChunksMatched,
 		"Ingester.TotalBatches", r.Ingester.TotalBatches,
@@ -67,18 +77,21 @@ func (r Result) Log(log log.Logger) {
 		"Store.DecompressedLines", r.Store.DecompressedLines,
 		"Store.CompressedBytes", humanize.Bytes(uint64(r.Store.CompressedBytes)),
 		"Store.TotalDuplicates", r.Store.TotalDuplicates,
-	)
-	r.Summary.Log(log)
+	}
 }
 
 func (s Summary) Log(log log.Logger) {
-	_ = log.Log(
+	_ = log.Log(s.kvList()...)
+}
+
+func (s Summary) kvList() []interface{} {
+	return []interface{}{
 		"Summary.BytesProcessedPerSecond", humanize.Bytes(uint64(s.BytesProcessedPerSecond)),
 		"Summary.LinesProcessedPerSecond", s.LinesProcessedPerSecond,
 		"Summary.TotalBytesProcessed", humanize.Bytes(uint64(s.TotalBytesProcessed)),
 		"Summary.TotalLinesProcessed", s.TotalLinesProcessed,
 		"Summary.ExecTime", time.Duration(int64(s.ExecTime*float64(time.Second))),
-	)
+	}
 }
 
 // NewContext creates a new statistics context
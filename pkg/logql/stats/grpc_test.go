@@ -109,3 +109,9 @@ func (ingesterFn) Series(context.Context, *logproto.SeriesRequest) (*logproto.Se
 func (ingesterFn) TailersCount(context.Context, *logproto.TailersCountRequest) (*logproto.TailersCountResponse, error) {
 	return nil, nil
 }
+func (ingesterFn) Cardinality(context.Context, *logproto.CardinalityRequest) (*logproto.CardinalityResponse, error) {
+	return nil, nil
+}
+func (ingesterFn) Volume(context.Context, *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	return nil, nil
+}
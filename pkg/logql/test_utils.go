@@ -75,7 +75,7 @@ outer:
 		for _, s := range matched {
 			var entries []logproto.Entry
 			for _, entry := range s.Entries {
-				if filter.Filter([]byte(entry.Line)) {
+				if FilterEntry(filter, []byte(entry.Line), entry.StructuredMetadata) {
 					entries = append(entries, entry)
 				}
 			}
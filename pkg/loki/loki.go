@@ -32,6 +32,8 @@ import (
 	"github.com/grafana/loki/pkg/querier"
 	"github.com/grafana/loki/pkg/querier/queryrange"
 	"github.com/grafana/loki/pkg/storage"
+	"github.com/grafana/loki/pkg/storage/stores/local"
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway"
 	"github.com/grafana/loki/pkg/tracing"
 	serverutil "github.com/grafana/loki/pkg/util/server"
 	"github.com/grafana/loki/pkg/util/validation"
@@ -59,6 +61,8 @@ type Config struct {
 	RuntimeConfig    runtimeconfig.ManagerConfig `yaml:"runtime_config,omitempty"`
 	MemberlistKV     memberlist.KVConfig         `yaml:"memberlist"`
 	Tracing          tracing.Config              `yaml:"tracing"`
+	CompactorConfig  local.CompactorConfig       `yaml:"compactor,omitempty"`
+	IndexGateway     indexgateway.Config         `yaml:"index_gateway,omitempty"`
 }
 
 // RegisterFlags registers flag.
@@ -85,6 +89,8 @@ func (c *Config) RegisterFlags(f *flag.FlagSet) {
 	c.RuntimeConfig.RegisterFlags(f)
 	c.MemberlistKV.RegisterFlags(f, "")
 	c.Tracing.RegisterFlags(f)
+	c.CompactorConfig.RegisterFlags(f)
+	c.IndexGateway.RegisterFlags(f)
 }
 
 // Validate the config and returns an error if the validation
@@ -113,18 +119,21 @@ type Loki struct {
 	moduleManager *modules.Manager
 	serviceMap    map[string]services.Service
 
-	server        *server.Server
-	ring          *ring.Ring
-	overrides     *validation.Overrides
-	distributor   *distributor.Distributor
-	ingester      *ingester.Ingester
-	querier       *querier.Querier
-	store         storage.Store
-	tableManager  *chunk.TableManager
-	frontend      *frontend.Frontend
-	stopper       queryrange.Stopper
-	runtimeConfig *runtimeconfig.Manager
-	memberlistKV  *memberlist.KVInitService
+	server           *server.Server
+	ring             *ring.Ring
+	overrides        *validation.Overrides
+	distributor      *distributor.Distributor
+	ingester         *ingester.Ingester
+	querier          *querier.Querier
+	store            storage.Store
+	tableManager     *chunk.TableManager
+	frontend         *frontend.Frontend
+	stopper          queryrange.Stopper
+	runtimeConfig    *runtimeconfig.Manager
+	memberlistKV     *memberlist.KVInitService
+	compactor        *local.Compactor
+	indexGateway     *indexgateway.Gateway
+	indexGatewayRing *ring.Ring
 
 	httpAuthMiddleware middleware.Interface
 }
@@ -295,19 +304,25 @@ func (t *Loki) setupModuleManager() error {
 	mm.RegisterModule(Querier, t.initQuerier)
 	mm.RegisterModule(QueryFrontend, t.initQueryFrontend)
 	mm.RegisterModule(TableManager, t.initTableManager)
+	mm.RegisterModule(Compactor, t.initCompactor)
+	mm.RegisterModule(IndexGateway, t.initIndexGateway)
+	mm.RegisterModule(IndexGatewayRing, t.initIndexGatewayRing)
 	mm.RegisterModule(All, nil)
 
 	// Add dependencies
 	deps := map[string][]string{
-		Ring:          {RuntimeConfig, Server, MemberlistKV},
-		Overrides:     {RuntimeConfig},
-		Distributor:   {Ring, Server, Overrides},
-		Store:         {Overrides},
-		Ingester:      {Store, Server, MemberlistKV},
-		Querier:       {Store, Ring, Server},
-		QueryFrontend: {Server, Overrides},
-		TableManager:  {Server},
-		All:           {Querier, Ingester, Distributor, TableManager},
+		Ring:             {RuntimeConfig, Server, MemberlistKV},
+		Overrides:        {RuntimeConfig},
+		Distributor:      {Ring, Server, Overrides},
+		Store:            {Overrides, IndexGatewayRing},
+		Ingester:         {Store, Server, MemberlistKV},
+		Querier:          {Store, Ring, Server},
+		QueryFrontend:    {Server, Overrides},
+		TableManager:     {Server},
+		Compactor:        {Server},
+		IndexGateway:     {Server, RuntimeConfig, MemberlistKV},
+		IndexGatewayRing: {RuntimeConfig, Server, MemberlistKV},
+		All:              {Querier, Ingester, Distributor, TableManager},
 	}
 
 	for mod, targets := range deps {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cortexproject/cortex/pkg/chunk"
+	cortex_local "github.com/cortexproject/cortex/pkg/chunk/local"
 	"github.com/cortexproject/cortex/pkg/chunk/storage"
 	"github.com/cortexproject/cortex/pkg/cortex"
 	cortex_querier "github.com/cortexproject/cortex/pkg/querier"
@@ -35,6 +36,8 @@ import (
 	"github.com/grafana/loki/pkg/querier/queryrange"
 	loki_storage "github.com/grafana/loki/pkg/storage"
 	"github.com/grafana/loki/pkg/storage/stores/local"
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway"
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway/indexgatewaypb"
 	serverutil "github.com/grafana/loki/pkg/util/server"
 	"github.com/grafana/loki/pkg/util/validation"
 )
@@ -43,18 +46,21 @@ const maxChunkAgeForTableManager = 12 * time.Hour
 
 // The various modules that make up Loki.
 const (
-	Ring          string = "ring"
-	RuntimeConfig string = "runtime-config"
-	Overrides     string = "overrides"
-	Server        string = "server"
-	Distributor   string = "distributor"
-	Ingester      string = "ingester"
-	Querier       string = "querier"
-	QueryFrontend string = "query-frontend"
-	Store         string = "store"
-	TableManager  string = "table-manager"
-	MemberlistKV  string = "memberlist-kv"
-	All           string = "all"
+	Ring             string = "ring"
+	RuntimeConfig    string = "runtime-config"
+	Overrides        string = "overrides"
+	Server           string = "server"
+	Distributor      string = "distributor"
+	Ingester         string = "ingester"
+	Querier          string = "querier"
+	QueryFrontend    string = "query-frontend"
+	Store            string = "store"
+	TableManager     string = "table-manager"
+	MemberlistKV     string = "memberlist-kv"
+	Compactor        string = "compactor"
+	IndexGateway     string = "index-gateway"
+	IndexGatewayRing string = "index-gateway-ring"
+	All              string = "all"
 )
 
 func (t *Loki) initServer() (services.Service, error) {
@@ -163,6 +169,9 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	t.server.HTTP.Handle("/loki/api/v1/label/{name}/values", httpMiddleware.Wrap(http.HandlerFunc(t.querier.LabelHandler)))
 	t.server.HTTP.Handle("/loki/api/v1/tail", httpMiddleware.Wrap(http.HandlerFunc(t.querier.TailHandler)))
 	t.server.HTTP.Handle("/loki/api/v1/series", httpMiddleware.Wrap(http.HandlerFunc(t.querier.SeriesHandler)))
+	t.server.HTTP.Handle("/loki/api/v1/cardinality", httpMiddleware.Wrap(http.HandlerFunc(t.querier.CardinalityHandler)))
+	t.server.HTTP.Handle("/loki/api/v1/volume", httpMiddleware.Wrap(http.HandlerFunc(t.querier.VolumeHandler)))
+	t.server.HTTP.Handle("/loki/api/v1/index/stats", httpMiddleware.Wrap(http.HandlerFunc(t.querier.IndexStatsHandler)))
 
 	t.server.HTTP.Handle("/api/prom/query", httpMiddleware.Wrap(http.HandlerFunc(t.querier.LogQueryHandler)))
 	t.server.HTTP.Handle("/api/prom/label", httpMiddleware.Wrap(http.HandlerFunc(t.querier.LabelHandler)))
@@ -249,6 +258,9 @@ func (t *Loki) initStore() (_ services.Service, err error) {
 		case Querier:
 			// We do not want query to do any updates to index
 			t.cfg.StorageConfig.BoltDBShipperConfig.Mode = local.ShipperModeReadOnly
+			if t.indexGatewayRing != nil {
+				t.cfg.StorageConfig.BoltDBShipperConfig.IndexGatewayClient.Ring = t.indexGatewayRing
+			}
 		default:
 			t.cfg.StorageConfig.BoltDBShipperConfig.Mode = local.ShipperModeReadWrite
 		}
@@ -292,7 +304,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 
 	frontendHandler := middleware.Merge(
 		serverutil.RecoveryHTTPMiddleware,
-		queryrange.StatsHTTPMiddleware,
+		queryrange.NewStatsHTTPMiddleware(t.cfg.QueryRange.SlowQueryLog),
 		t.httpAuthMiddleware,
 		serverutil.NewPrepopulateMiddleware(),
 	).Wrap(t.frontend.Handler())
@@ -303,6 +315,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.server.HTTP.Handle("/loki/api/v1/labels", frontendHandler)
 	t.server.HTTP.Handle("/loki/api/v1/label/{name}/values", frontendHandler)
 	t.server.HTTP.Handle("/loki/api/v1/series", frontendHandler)
+	t.server.HTTP.Handle("/loki/api/v1/cardinality", frontendHandler)
+	t.server.HTTP.Handle("/loki/api/v1/volume", frontendHandler)
+	t.server.HTTP.Handle("/loki/api/v1/index/stats", frontendHandler)
 	t.server.HTTP.Handle("/api/prom/query", frontendHandler)
 	t.server.HTTP.Handle("/api/prom/label", frontendHandler)
 	t.server.HTTP.Handle("/api/prom/label/{name}/values", frontendHandler)
@@ -329,6 +344,66 @@ func (t *Loki) initMemberlistKV() (services.Service, error) {
 	return t.memberlistKV, nil
 }
 
+func (t *Loki) initCompactor() (services.Service, error) {
+	objectClient, err := storage.NewObjectClient(t.cfg.CompactorConfig.SharedStoreType, t.cfg.StorageConfig.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	t.compactor, err = local.NewCompactor(t.cfg.CompactorConfig, objectClient, prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.compactor, nil
+}
+
+func (t *Loki) initIndexGateway() (services.Service, error) {
+	t.cfg.IndexGateway.LifecyclerConfig.RingConfig.KVStore.Multi.ConfigProvider = multiClientRuntimeConfigChannel(t.runtimeConfig)
+	t.cfg.IndexGateway.LifecyclerConfig.RingConfig.KVStore.MemberlistKV = t.memberlistKV.GetMemberlistKV
+	t.cfg.IndexGateway.LifecyclerConfig.ListenPort = t.cfg.Server.GRPCListenPort
+
+	// the index gateway only downloads the files, like the queriers would without it.
+	shipperCfg := t.cfg.StorageConfig.BoltDBShipperConfig
+	shipperCfg.IngesterName = t.cfg.IndexGateway.LifecyclerConfig.ID
+	shipperCfg.Mode = local.ShipperModeReadOnly
+
+	objectClient, err := storage.NewObjectClient(shipperCfg.SharedStoreType, t.cfg.StorageConfig.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	indexClient, err := local.NewBoltDBIndexClientWithShipper(cortex_local.BoltDBConfig{Directory: shipperCfg.ActiveIndexDirectory}, objectClient, shipperCfg, prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	t.indexGateway, err = indexgateway.NewGateway(t.cfg.IndexGateway, indexClient, prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	indexgatewaypb.RegisterIndexGatewayServer(t.server.GRPC, t.indexGateway)
+	grpc_health_v1.RegisterHealthServer(t.server.GRPC, t.indexGateway)
+	return t.indexGateway, nil
+}
+
+func (t *Loki) initIndexGatewayRing() (_ services.Service, err error) {
+	if !t.cfg.IndexGateway.ShardingEnabled {
+		return nil, nil
+	}
+
+	t.cfg.IndexGateway.LifecyclerConfig.RingConfig.KVStore.Multi.ConfigProvider = multiClientRuntimeConfigChannel(t.runtimeConfig)
+	t.cfg.IndexGateway.LifecyclerConfig.RingConfig.KVStore.MemberlistKV = t.memberlistKV.GetMemberlistKV
+	t.indexGatewayRing, err = ring.New(t.cfg.IndexGateway.LifecyclerConfig.RingConfig, indexgateway.RingName, indexgateway.RingKey, prometheus.DefaultRegisterer)
+	if err != nil {
+		return
+	}
+	prometheus.MustRegister(t.indexGatewayRing)
+	t.server.HTTP.Handle("/index-gateway/ring", t.indexGatewayRing)
+	return t.indexGatewayRing, nil
+}
+
 // activePeriodConfig type returns index type which would be applicable to logs that would be pushed starting now
 // Note: Another periodic config can be applicable in future which can change index type
 func activePeriodConfig(cfg chunk.SchemaConfig) chunk.PeriodConfig {
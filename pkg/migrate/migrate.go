@@ -0,0 +1,397 @@
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	loki_util "github.com/grafana/loki/pkg/util"
+)
+
+// Config configures a migration of the chunks of a tenant between two stores.
+type Config struct {
+	Tenant string
+	// Selector is the stream selector of the streams migrated, all the streams are migrated when empty.
+	Selector string
+	From     time.Time
+	Through  time.Time
+	// ShardBy is the duration of the shards the time range is split in. Shards are the unit of
+	// parallelism and of checkpointing.
+	ShardBy time.Duration
+	// Parallelism is the number of shards migrated concurrently.
+	Parallelism int
+	// BatchSize is the number of chunks fetched and written at once.
+	BatchSize int
+	// Encoding re-encodes the chunks with another encoding when set.
+	Encoding string
+	// BlockSize and TargetChunkSize size the re-encoded chunks.
+	BlockSize       int
+	TargetChunkSize int
+	// ZstdLevel is the compression level of the chunks re-encoded with zstd, 0 for the default level.
+	ZstdLevel int
+	// CheckpointFile records the shards migrated, which are skipped when the migration is resumed.
+	CheckpointFile string
+	// DryRun reads the chunks without writing them nor the checkpoint.
+	DryRun bool
+}
+
+// SourceStore is the interface we need to read the chunks to migrate.
+type SourceStore interface {
+	GetChunkRefs(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) ([][]chunk.Chunk, []*chunk.Fetcher, error)
+}
+
+// DestinationStore is the interface we need to write the migrated chunks.
+type DestinationStore interface {
+	Put(ctx context.Context, chunks []chunk.Chunk) error
+}
+
+// Stats counts what a migration did.
+type Stats struct {
+	Shards        int
+	ShardsSkipped int
+	ChunksRead    int
+	ChunksWritten int
+	BytesWritten  int
+}
+
+// Migrator copies the chunks of a tenant from a store to another, e.g. from a store with a BigTable
+// index to one with a boltdb-shipper index, optionally re-encoding them.
+type Migrator struct {
+	cfg      Config
+	src      SourceStore
+	dst      DestinationStore
+	matchers []*labels.Matcher
+	encoding chunkenc.Encoding
+	logger   log.Logger
+
+	mtx        sync.Mutex
+	stats      Stats
+	checkpoint *os.File
+}
+
+type shard struct {
+	from, through model.Time
+}
+
+func (s shard) String() string {
+	return fmt.Sprintf("%s-%s", s.from.Time().UTC().Format(time.RFC3339), s.through.Time().UTC().Format(time.RFC3339))
+}
+
+// NewMigrator returns a Migrator migrating the chunks from src to dst.
+func NewMigrator(cfg Config, src SourceStore, dst DestinationStore, logger log.Logger) (*Migrator, error) {
+	if cfg.Tenant == "" {
+		return nil, fmt.Errorf("tenant is required")
+	}
+	if !cfg.From.Before(cfg.Through) {
+		return nil, fmt.Errorf("from %s must be before through %s", cfg.From, cfg.Through)
+	}
+	if cfg.ShardBy <= 0 || cfg.Parallelism <= 0 || cfg.BatchSize <= 0 {
+		return nil, fmt.Errorf("shard duration, parallelism and batch size must be positive")
+	}
+
+	m := &Migrator{
+		cfg:    cfg,
+		src:    src,
+		dst:    dst,
+		logger: logger,
+	}
+
+	if cfg.Selector != "" {
+		matchers, err := logql.ParseMatchers(cfg.Selector)
+		if err != nil {
+			return nil, err
+		}
+		m.matchers = matchers
+	}
+	m.matchers = append(m.matchers, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "logs"))
+
+	if cfg.Encoding != "" {
+		enc, err := chunkenc.ParseEncoding(cfg.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		m.encoding = enc
+	}
+	return m, nil
+}
+
+// Run migrates the shards of the time range not in the checkpoint yet, and returns what was migrated.
+// The shards migrated before an error are recorded in the checkpoint, so that Run can be resumed.
+func (m *Migrator) Run(ctx context.Context) (Stats, error) {
+	ctx = user.InjectOrgID(ctx, m.cfg.Tenant)
+
+	done, err := m.readCheckpoint()
+	if err != nil {
+		return Stats{}, err
+	}
+	if m.cfg.CheckpointFile != "" && !m.cfg.DryRun {
+		m.checkpoint, err = os.OpenFile(m.cfg.CheckpointFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return Stats{}, err
+		}
+		defer m.checkpoint.Close()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	shards := make(chan shard)
+	go func() {
+		defer close(shards)
+		for _, s := range m.shards() {
+			if _, ok := done[s.String()]; ok {
+				m.mtx.Lock()
+				m.stats.ShardsSkipped++
+				m.mtx.Unlock()
+				continue
+			}
+			select {
+			case shards <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < m.cfg.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range shards {
+				if err := m.migrateShard(ctx, s); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("migrating shard %s: %w", s, err)
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return m.stats, firstErr
+}
+
+// shards splits the time range of the migration in shards aligned on multiples of ShardBy.
+func (m *Migrator) shards() []shard {
+	var (
+		result  []shard
+		from    = model.TimeFromUnixNano(m.cfg.From.UnixNano())
+		through = model.TimeFromUnixNano(m.cfg.Through.UnixNano())
+		step    = model.Time(m.cfg.ShardBy / time.Millisecond)
+	)
+	for start := from; start < through; {
+		end := start - start%step + step
+		if end > through {
+			end = through
+		}
+		result = append(result, shard{from: start, through: end})
+		start = end
+	}
+	return result
+}
+
+// readCheckpoint returns the shards recorded in the checkpoint file.
+func (m *Migrator) readCheckpoint() (map[string]struct{}, error) {
+	done := map[string]struct{}{}
+	if m.cfg.CheckpointFile == "" {
+		return done, nil
+	}
+	f, err := os.Open(m.cfg.CheckpointFile)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			done[line] = struct{}{}
+		}
+	}
+	return done, scanner.Err()
+}
+
+// migrateShard migrates the chunks starting in the shard, and records it in the checkpoint.
+func (m *Migrator) migrateShard(ctx context.Context, s shard) error {
+	start := time.Now()
+	chunks, fetchers, err := m.src.GetChunkRefs(ctx, m.cfg.Tenant, s.from, s.through, m.matchers...)
+	if err != nil {
+		return err
+	}
+
+	var read, written, bytes int
+	migrationStart := model.TimeFromUnixNano(m.cfg.From.UnixNano())
+	// a chunk overlapping several shards is migrated by the shard it starts in, and a chunk
+	// indexed by several period configs only once.
+	seen := map[string]struct{}{}
+	for i := range chunks {
+		refs := make([]chunk.Chunk, 0, len(chunks[i]))
+		for _, c := range chunks[i] {
+			if c.Through < s.from || c.From >= s.through || (c.From < s.from && s.from > migrationStart) {
+				continue
+			}
+			key := c.ExternalKey()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			refs = append(refs, c)
+		}
+
+		for len(refs) > 0 {
+			batch := refs
+			if len(batch) > m.cfg.BatchSize {
+				batch = batch[:m.cfg.BatchSize]
+			}
+			refs = refs[len(batch):]
+
+			keys := make([]string, 0, len(batch))
+			for _, c := range batch {
+				keys = append(keys, c.ExternalKey())
+			}
+			fetched, err := fetchers[i].FetchChunks(ctx, batch, keys)
+			if err != nil {
+				return err
+			}
+			read += len(fetched)
+
+			if m.cfg.Encoding != "" {
+				fetched, err = m.reencode(ctx, fetched)
+				if err != nil {
+					return err
+				}
+			}
+			for _, c := range fetched {
+				encoded, err := c.Encoded()
+				if err != nil {
+					return err
+				}
+				bytes += len(encoded)
+			}
+			written += len(fetched)
+			if m.cfg.DryRun {
+				continue
+			}
+			if err := m.dst.Put(ctx, fetched); err != nil {
+				return err
+			}
+		}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.stats.Shards++
+	m.stats.ChunksRead += read
+	m.stats.ChunksWritten += written
+	m.stats.BytesWritten += bytes
+	if m.checkpoint != nil {
+		if _, err := fmt.Fprintln(m.checkpoint, s.String()); err != nil {
+			return err
+		}
+	}
+	level.Info(m.logger).Log("msg", "shard migrated", "shard", s, "chunks_read", read, "chunks_written", written, "bytes", bytes, "dry_run", m.cfg.DryRun, "duration", time.Since(start))
+	return nil
+}
+
+// newChunk returns a new chunk with the encoding of the migration.
+func (m *Migrator) newChunk() chunkenc.Chunk {
+	if m.encoding == chunkenc.EncZstd {
+		return chunkenc.NewZstdMemChunk(chunkenc.ZstdOptions{Level: m.cfg.ZstdLevel}, m.cfg.BlockSize, m.cfg.TargetChunkSize)
+	}
+	return chunkenc.NewMemChunk(m.encoding, m.cfg.BlockSize, m.cfg.TargetChunkSize)
+}
+
+// reencode appends the entries of each chunk to new chunks with the encoding of the migration.
+// The chunks already encoded with it are kept as is.
+func (m *Migrator) reencode(ctx context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
+	result := make([]chunk.Chunk, 0, len(chunks))
+	for _, c := range chunks {
+		facade, ok := c.Data.(*chunkenc.Facade)
+		if !ok {
+			return nil, fmt.Errorf("unexpected chunk encoding %s of chunk %s", c.Encoding, c.ExternalKey())
+		}
+		lc := facade.LokiChunk()
+		if mc, ok := lc.(*chunkenc.MemChunk); ok && mc.Encoding() == m.encoding {
+			result = append(result, c)
+			continue
+		}
+
+		it, err := lc.Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			reencoded []chunkenc.Chunk
+			cur       chunkenc.Chunk = m.newChunk()
+		)
+		for it.Next() {
+			entry := it.Entry()
+			if !cur.SpaceFor(&entry) {
+				reencoded = append(reencoded, cur)
+				cur = m.newChunk()
+			}
+			if err := cur.Append(&entry); err != nil {
+				_ = it.Close()
+				return nil, err
+			}
+		}
+		if err := it.Error(); err != nil {
+			_ = it.Close()
+			return nil, err
+		}
+		if err := it.Close(); err != nil {
+			return nil, err
+		}
+		if cur.Size() > 0 {
+			reencoded = append(reencoded, cur)
+		}
+
+		for _, r := range reencoded {
+			if err := r.Close(); err != nil {
+				return nil, err
+			}
+			firstTime, lastTime := loki_util.RoundToMilliseconds(r.Bounds())
+			ch := chunk.NewChunk(
+				c.UserID, c.Fingerprint, c.Metric,
+				chunkenc.NewFacade(r, m.cfg.BlockSize, m.cfg.TargetChunkSize),
+				firstTime,
+				lastTime,
+			)
+			if err := ch.Encode(); err != nil {
+				return nil, err
+			}
+			result = append(result, ch)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,191 @@
+package migrate
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	cortex_local "github.com/cortexproject/cortex/pkg/chunk/local"
+	cortex_storage "github.com/cortexproject/cortex/pkg/chunk/storage"
+	"github.com/cortexproject/cortex/pkg/ingester/client"
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+var start = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T, dir string) storage.Store {
+	limits, err := validation.NewOverrides(validation.Limits{}, nil)
+	require.NoError(t, err)
+
+	s, err := storage.NewStore(storage.Config{
+		Config: cortex_storage.Config{
+			BoltDBConfig: cortex_local.BoltDBConfig{Directory: filepath.Join(dir, "index")},
+			FSConfig:     cortex_local.FSConfig{Directory: filepath.Join(dir, "chunks")},
+		},
+		MaxChunkBatchSize: 10,
+	}, chunk.StoreConfig{}, chunk.SchemaConfig{
+		Configs: []chunk.PeriodConfig{
+			{
+				From:       chunk.DayTime{Time: model.TimeFromUnix(start.Add(-24 * time.Hour).Unix())},
+				IndexType:  "boltdb",
+				ObjectType: "filesystem",
+				Schema:     "v11",
+				IndexTables: chunk.PeriodicTableConfig{
+					Prefix: "index_",
+					Period: 24 * time.Hour,
+				},
+				RowShards: 4,
+			},
+		},
+	}, limits, nil)
+	require.NoError(t, err)
+	return s
+}
+
+func newTestChunk(t *testing.T, lbs string, from time.Time, count int) chunk.Chunk {
+	metric, err := parser.ParseMetric(lbs)
+	require.NoError(t, err)
+	metric = labels.NewBuilder(metric).Set(labels.MetricName, "logs").Labels()
+
+	c := chunkenc.NewMemChunk(chunkenc.EncGZIP, 256*1024, 0)
+	for i := 0; i < count; i++ {
+		require.NoError(t, c.Append(&logproto.Entry{Timestamp: from.Add(time.Duration(i) * time.Minute), Line: lbs}))
+	}
+	require.NoError(t, c.Close())
+	first, last := c.Bounds()
+	ch := chunk.NewChunk("fake", client.Fingerprint(metric), metric, chunkenc.NewFacade(c, 0, 0), model.TimeFromUnixNano(first.UnixNano()), model.TimeFromUnixNano(last.UnixNano()))
+	require.NoError(t, ch.Encode())
+	return ch
+}
+
+// storedEntries returns the entries of the chunks of the tenant fake in s, by stream and sorted.
+func storedEntries(t *testing.T, s storage.Store) map[string][]logproto.Entry {
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	chunks, err := s.Get(ctx, "fake", model.TimeFromUnix(start.Add(-time.Hour).Unix()), model.TimeFromUnix(start.Add(72*time.Hour).Unix()),
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "logs"))
+	require.NoError(t, err)
+
+	result := map[string][]logproto.Entry{}
+	for _, c := range chunks {
+		it, err := c.Data.(*chunkenc.Facade).LokiChunk().Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, nil)
+		require.NoError(t, err)
+		lbs := labels.NewBuilder(c.Metric).Del(labels.MetricName).Labels().String()
+		for it.Next() {
+			result[lbs] = append(result[lbs], it.Entry())
+		}
+		require.NoError(t, it.Close())
+	}
+	for _, entries := range result {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	}
+	return result
+}
+
+func TestMigrator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := newTestStore(t, filepath.Join(dir, "source"))
+	defer src.Stop()
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	require.NoError(t, src.Put(ctx, []chunk.Chunk{
+		newTestChunk(t, `{app="foo"}`, start.Add(time.Hour), 10),
+		// overlaps two shards.
+		newTestChunk(t, `{app="foo"}`, start.Add(23*time.Hour), 120),
+		newTestChunk(t, `{app="bar"}`, start.Add(30*time.Hour), 10),
+		// out of the migrated time range.
+		newTestChunk(t, `{app="bar"}`, start.Add(50*time.Hour), 10),
+	}))
+	expected := storedEntries(t, src)
+	expected[`{app="bar"}`] = expected[`{app="bar"}`][:10]
+
+	cfg := Config{
+		Tenant:      "fake",
+		From:        start,
+		Through:     start.Add(48 * time.Hour),
+		ShardBy:     24 * time.Hour,
+		Parallelism: 2,
+		BatchSize:   1,
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		dst := newTestStore(t, filepath.Join(dir, "dry-run"))
+		defer dst.Stop()
+
+		cfg := cfg
+		cfg.DryRun = true
+		cfg.CheckpointFile = filepath.Join(dir, "dry-run.checkpoint")
+		m, err := NewMigrator(cfg, src, dst, util.Logger)
+		require.NoError(t, err)
+		stats, err := m.Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.ChunksRead)
+		require.Equal(t, 3, stats.ChunksWritten)
+		require.Empty(t, storedEntries(t, dst))
+		_, err = os.Stat(cfg.CheckpointFile)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		dst := newTestStore(t, filepath.Join(dir, "resumed"))
+		defer dst.Stop()
+
+		cfg := cfg
+		cfg.CheckpointFile = filepath.Join(dir, "resumed.checkpoint")
+		cfg.Through = start.Add(24 * time.Hour)
+		m, err := NewMigrator(cfg, src, dst, util.Logger)
+		require.NoError(t, err)
+		stats, err := m.Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, Stats{Shards: 1, ChunksRead: 2, ChunksWritten: 2, BytesWritten: stats.BytesWritten}, stats)
+
+		// the shard of the first day is skipped.
+		cfg.Through = start.Add(48 * time.Hour)
+		m, err = NewMigrator(cfg, src, dst, util.Logger)
+		require.NoError(t, err)
+		stats, err = m.Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, Stats{Shards: 1, ShardsSkipped: 1, ChunksRead: 1, ChunksWritten: 1, BytesWritten: stats.BytesWritten}, stats)
+
+		require.Equal(t, expected, storedEntries(t, dst))
+	})
+
+	t.Run("re-encoded", func(t *testing.T) {
+		dst := newTestStore(t, filepath.Join(dir, "re-encoded"))
+		defer dst.Stop()
+
+		cfg := cfg
+		cfg.Encoding = chunkenc.EncSnappy.String()
+		cfg.BlockSize = 256 * 1024
+		m, err := NewMigrator(cfg, src, dst, util.Logger)
+		require.NoError(t, err)
+		_, err = m.Run(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, expected, storedEntries(t, dst))
+		chunks, err := dst.Get(ctx, "fake", model.TimeFromUnix(start.Unix()), model.TimeFromUnix(start.Add(48*time.Hour).Unix()),
+			labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "logs"))
+		require.NoError(t, err)
+		require.Len(t, chunks, 3)
+		for _, c := range chunks {
+			require.Equal(t, chunkenc.EncSnappy, c.Data.(*chunkenc.Facade).LokiChunk().(*chunkenc.MemChunk).Encoding())
+		}
+	})
+}
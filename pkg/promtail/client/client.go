@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"sync"
@@ -30,6 +31,8 @@ import (
 const (
 	contentType  = "application/x-protobuf"
 	maxErrMsgLen = 1024
+	// maximum size of the push responses reporting rejected entries.
+	maxPushResponseLen = 16 << 20
 
 	// Label reserved to override the tenant ID while processing
 	// pipeline stages
@@ -62,6 +65,11 @@ var (
 		Name:      "dropped_entries_total",
 		Help:      "Number of log entries dropped because failed to be sent to the ingester after all retries.",
 	}, []string{"host"})
+	droppedEntriesByReason = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "dropped_entries_by_reason_total",
+		Help:      "Number of log entries dropped because rejected by Loki, by reason of the rejection.",
+	}, []string{"host", "reason"})
 	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "promtail",
 		Name:      "request_duration_seconds",
@@ -81,6 +89,7 @@ func init() {
 	prometheus.MustRegister(droppedBytes)
 	prometheus.MustRegister(sentEntries)
 	prometheus.MustRegister(droppedEntries)
+	prometheus.MustRegister(droppedEntriesByReason)
 	prometheus.MustRegister(requestDuration)
 }
 
@@ -112,6 +121,23 @@ type entry struct {
 
 // New makes a new Client.
 func New(cfg Config, logger log.Logger) (Client, error) {
+	return NewWithTransport(cfg, logger, nil)
+}
+
+// NewWithTransport makes a new Client sending its requests through the round tripper
+// returned by wrap for the one configured, e.g. to instrument them.
+func NewWithTransport(cfg Config, logger log.Logger, wrap func(http.RoundTripper) http.RoundTripper) (Client, error) {
+	c, err := newClient(cfg, logger, wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	c.wg.Add(1)
+	go c.run()
+	return c, nil
+}
+
+func newClient(cfg Config, logger log.Logger, wrap func(http.RoundTripper) http.RoundTripper) (*client, error) {
 	if cfg.URL.URL == nil {
 		return nil, errors.New("client needs target URL")
 	}
@@ -136,15 +162,15 @@ func New(cfg Config, logger log.Logger) (Client, error) {
 	}
 
 	c.client.Timeout = cfg.Timeout
+	if wrap != nil {
+		c.client.Transport = wrap(c.client.Transport)
+	}
 
 	// Initialize counters to 0 so the metrics are exported before the first
 	// occurrence of incrementing to avoid missing metrics.
 	for _, counter := range countersWithHost {
 		counter.WithLabelValues(c.cfg.URL.Host).Add(0)
 	}
-
-	c.wg.Add(1)
-	go c.run()
 	return c, nil
 }
 
@@ -215,7 +241,8 @@ func (c *client) run() {
 }
 
 func (c *client) sendBatch(tenantID string, batch *batch) {
-	buf, entriesCount, err := batch.encode()
+	req, entriesCount := batch.createPushRequest()
+	buf, err := encodePushRequest(req)
 	if err != nil {
 		level.Error(c.logger).Log("msg", "error encoding batch", "error", err)
 		return
@@ -225,10 +252,15 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 
 	ctx := context.Background()
 	backoff := util.NewBackoff(ctx, c.cfg.BackoffConfig)
-	var status int
+	var (
+		status int
+		// reasons of the rejections of the entries being retried.
+		retryReasons map[string]int
+	)
 	for backoff.Ongoing() {
 		start := time.Now()
-		status, err = c.send(ctx, tenantID, buf)
+		var resp *logproto.PushResponse
+		status, resp, err = c.send(ctx, tenantID, buf)
 		requestDuration.WithLabelValues(strconv.Itoa(status), c.cfg.URL.Host).Observe(time.Since(start).Seconds())
 
 		if err == nil {
@@ -237,6 +269,24 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 			return
 		}
 
+		// Loki reported the entries it rejected, only the retryable ones are sent again.
+		if resp != nil && len(resp.Streams) == len(req.Streams) {
+			req, retryReasons = c.handlePushResponse(req, resp)
+			if len(req.Streams) == 0 {
+				return
+			}
+			if buf, err = encodePushRequest(req); err != nil {
+				level.Error(c.logger).Log("msg", "error encoding retried entries", "error", err)
+				return
+			}
+			bufBytes = float64(len(buf))
+			entriesCount = countEntries(req)
+
+			level.Warn(c.logger).Log("msg", "entries rejected, will retry", "status", status, "entries", entriesCount, "error", err)
+			backoff.Wait()
+			continue
+		}
+
 		// Only retry 429s, 500s and connection-level errors.
 		if status > 0 && status != 429 && status/100 != 5 {
 			break
@@ -250,18 +300,60 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 		level.Error(c.logger).Log("msg", "final error sending batch", "status", status, "error", err)
 		droppedBytes.WithLabelValues(c.cfg.URL.Host).Add(bufBytes)
 		droppedEntries.WithLabelValues(c.cfg.URL.Host).Add(float64(entriesCount))
+		for reason, count := range retryReasons {
+			droppedEntriesByReason.WithLabelValues(c.cfg.URL.Host, reason).Add(float64(count))
+		}
 	}
 }
 
-func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, error) {
+// handlePushResponse records the entries accepted and dropped by Loki. It returns the request
+// made of the entries to retry, and the reasons they were rejected for.
+func (c *client) handlePushResponse(req *logproto.PushRequest, resp *logproto.PushResponse) (*logproto.PushRequest, map[string]int) {
+	retry := &logproto.PushRequest{}
+	retryReasons := map[string]int{}
+	for i, result := range resp.Streams {
+		sentEntries.WithLabelValues(c.cfg.URL.Host).Add(float64(result.Accepted))
+		for _, r := range result.Rejections {
+			if r.Retryable {
+				retryReasons[r.Reason] += int(r.Count)
+				continue
+			}
+			level.Error(c.logger).Log("msg", "entries rejected", "reason", r.Reason, "count", r.Count, "error", r.Error)
+			droppedEntries.WithLabelValues(c.cfg.URL.Host).Add(float64(r.Count))
+			droppedEntriesByReason.WithLabelValues(c.cfg.URL.Host, r.Reason).Add(float64(r.Count))
+		}
+
+		stream := req.Streams[i]
+		if n := int(result.Retryable); n > 0 && n <= len(stream.Entries) {
+			retry.Streams = append(retry.Streams, logproto.Stream{
+				Labels:  stream.Labels,
+				Entries: stream.Entries[len(stream.Entries)-n:],
+			})
+		}
+	}
+	return retry, retryReasons
+}
+
+func countEntries(req *logproto.PushRequest) int {
+	entries := 0
+	for _, stream := range req.Streams {
+		entries += len(stream.Entries)
+	}
+	return entries
+}
+
+// send pushes the encoded request to Loki. When Loki rejects some of the entries, the
+// returned response reports the accepted and rejected entries of each stream.
+func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, *logproto.PushResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 	defer cancel()
 	req, err := http.NewRequest("POST", c.cfg.URL.String(), bytes.NewReader(buf))
 	if err != nil {
-		return -1, err
+		return -1, nil, err
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
 	req.Header.Set("User-Agent", userAgent)
 
 	// If the tenant ID is not empty promtail is running in multi-tenant mode, so
@@ -272,19 +364,39 @@ func (c *client) send(ctx context.Context, tenantID string, buf []byte) (int, er
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return -1, err
+		return -1, nil, err
 	}
 	defer helpers.LogError("closing response body", resp.Body.Close)
 
-	if resp.StatusCode/100 != 2 {
-		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+	if resp.StatusCode/100 == 2 {
+		return resp.StatusCode, nil, nil
+	}
+
+	if resp.Header.Get("Content-Type") == contentType {
+		var pushResp logproto.PushResponse
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPushResponseLen))
+		if err == nil {
+			err = pushResp.Unmarshal(body)
+		}
+		if err != nil {
+			return resp.StatusCode, nil, fmt.Errorf("server returned HTTP status %s (%d) with an invalid push response: %v", resp.Status, resp.StatusCode, err)
+		}
 		line := ""
-		if scanner.Scan() {
-			line = scanner.Text()
+		if r := pushResp.Rejection(); r != nil {
+			line = r.Error
+			if len(line) > maxErrMsgLen {
+				line = line[:maxErrMsgLen]
+			}
 		}
-		err = fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+		return resp.StatusCode, &pushResp, fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
 	}
-	return resp.StatusCode, err
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+	line := ""
+	if scanner.Scan() {
+		line = scanner.Text()
+	}
+	return resp.StatusCode, nil, fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
 }
 
 func (c *client) getTenantID(labels model.LabelSet) string {
@@ -311,6 +423,12 @@ func (c *client) Stop() {
 
 // Handle implement EntryHandler; adds a new line to the next batch; send is async.
 func (c *client) Handle(ls model.LabelSet, t time.Time, s string) error {
+	c.entries <- c.newEntry(ls, t, s)
+	return nil
+}
+
+// newEntry makes the entry of a line, with the external labels and the tenant ID of the client.
+func (c *client) newEntry(ls model.LabelSet, t time.Time, s string) entry {
 	if len(c.externalLabels) > 0 {
 		ls = c.externalLabels.Merge(ls)
 	}
@@ -324,9 +442,8 @@ func (c *client) Handle(ls model.LabelSet, t time.Time, s string) error {
 		delete(ls, ReservedLabelTenantID)
 	}
 
-	c.entries <- entry{tenantID, ls, logproto.Entry{
+	return entry{tenantID, ls, logproto.Entry{
 		Timestamp: t,
 		Line:      s,
 	}}
-	return nil
 }
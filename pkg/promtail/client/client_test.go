@@ -240,6 +240,7 @@ func TestClient_Handle(t *testing.T) {
 			// Reset metrics
 			sentEntries.Reset()
 			droppedEntries.Reset()
+			droppedEntriesByReason.Reset()
 
 			// Create a buffer channel where we do enqueue received requests
 			receivedReqsChan := make(chan receivedReq, 10)
@@ -307,6 +308,88 @@ func TestClient_Handle(t *testing.T) {
 	}
 }
 
+func TestClient_RetryRejectedEntries(t *testing.T) {
+	sentEntries.Reset()
+	droppedEntries.Reset()
+	droppedEntriesByReason.Reset()
+
+	receivedReqsChan := make(chan receivedReq, 10)
+	handler := createServerHandler(receivedReqsChan, http.StatusNoContent)
+	first := true
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !first {
+			handler(rw, req)
+			return
+		}
+		first = false
+		require.Equal(t, contentType, req.Header.Get("Accept"))
+		handler(httptest.NewRecorder(), req)
+
+		// The first entry is accepted, the second one is out of order and the last one rate limited.
+		body, err := (&logproto.PushResponse{Streams: []logproto.StreamPushResult{{
+			Labels:   "{}",
+			Accepted: 1,
+			Rejections: []logproto.PushRejection{
+				{Reason: "out_of_order", Count: 1, Error: "entry out of order"},
+				{Reason: "per_stream_rate_limit", Count: 1, Retryable: true, Error: "rate limited"},
+			},
+			Retryable: 1,
+		}}}).Marshal()
+		require.NoError(t, err)
+		rw.Header().Set("Content-Type", contentType)
+		rw.WriteHeader(http.StatusTooManyRequests)
+		_, _ = rw.Write(body)
+	}))
+	defer server.Close()
+
+	serverURL := flagext.URLValue{}
+	require.NoError(t, serverURL.Set(server.URL))
+
+	c, err := New(Config{
+		URL:            serverURL,
+		BatchWait:      10 * time.Millisecond,
+		BatchSize:      100,
+		BackoffConfig:  util.BackoffConfig{MinBackoff: 1 * time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 3},
+		ExternalLabels: lokiflag.LabelSet{},
+		Timeout:        1 * time.Second,
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	for _, logEntry := range logEntries[:3] {
+		require.NoError(t, c.Handle(logEntry.labels, logEntry.Timestamp, logEntry.Line))
+	}
+
+	deadline := time.Now().Add(1 * time.Second)
+	for len(receivedReqsChan) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.Stop()
+	close(receivedReqsChan)
+
+	var receivedReqs []receivedReq
+	for req := range receivedReqsChan {
+		receivedReqs = append(receivedReqs, req)
+	}
+	require.Equal(t, []receivedReq{
+		{pushReq: logproto.PushRequest{Streams: []logproto.Stream{{Labels: "{}", Entries: []logproto.Entry{logEntries[0].Entry, logEntries[1].Entry, logEntries[2].Entry}}}}},
+		{pushReq: logproto.PushRequest{Streams: []logproto.Stream{{Labels: "{}", Entries: []logproto.Entry{logEntries[2].Entry}}}}},
+	}, receivedReqs)
+
+	expectedMetrics := strings.Replace(`
+		# HELP promtail_sent_entries_total Number of log entries sent to the ingester.
+		# TYPE promtail_sent_entries_total counter
+		promtail_sent_entries_total{host="__HOST__"} 2.0
+		# HELP promtail_dropped_entries_total Number of log entries dropped because failed to be sent to the ingester after all retries.
+		# TYPE promtail_dropped_entries_total counter
+		promtail_dropped_entries_total{host="__HOST__"} 1.0
+		# HELP promtail_dropped_entries_by_reason_total Number of log entries dropped because rejected by Loki, by reason of the rejection.
+		# TYPE promtail_dropped_entries_by_reason_total counter
+		promtail_dropped_entries_by_reason_total{host="__HOST__",reason="out_of_order"} 1.0
+	`, "__HOST__", serverURL.Host, -1)
+	err = testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expectedMetrics), "promtail_sent_entries_total", "promtail_dropped_entries_total", "promtail_dropped_entries_by_reason_total")
+	assert.NoError(t, err)
+}
+
 func createServerHandler(receivedReqsChan chan receivedReq, status int) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		// Parse the request
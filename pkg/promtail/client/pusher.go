@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/pkg/logproto"
+	lokiutil "github.com/grafana/loki/pkg/util"
+)
+
+// Pusher pushes entries to Loki synchronously, sending each request once. It is meant for callers
+// keeping the entries until they are pushed, e.g. in an on-disk buffer, which retry them.
+type Pusher interface {
+	// Push pushes the entries of the streams, one request per tenant. It returns an error when
+	// entries could not be pushed and are worth pushing again, the entries rejected for good by
+	// Loki being dropped.
+	Push(ctx context.Context, streams []logproto.Stream) error
+}
+
+type pusher struct {
+	*client
+}
+
+// NewPusherWithTransport makes a new Pusher sending its requests through the round tripper
+// returned by wrap for the one configured.
+func NewPusherWithTransport(cfg Config, logger log.Logger, wrap func(http.RoundTripper) http.RoundTripper) (Pusher, error) {
+	c, err := newClient(cfg, logger, wrap)
+	if err != nil {
+		return nil, err
+	}
+	return pusher{c}, nil
+}
+
+// Push implements Pusher.
+func (p pusher) Push(ctx context.Context, streams []logproto.Stream) error {
+	batches := map[string]*batch{}
+	labels := map[string]model.LabelSet{}
+	for _, s := range streams {
+		ls, ok := labels[s.Labels]
+		if !ok {
+			metric, err := parser.ParseMetric(s.Labels)
+			if err != nil {
+				level.Error(p.logger).Log("msg", "dropping stream with invalid labels", "labels", s.Labels, "error", err)
+				droppedEntries.WithLabelValues(p.cfg.URL.Host).Add(float64(len(s.Entries)))
+				continue
+			}
+			ls = make(model.LabelSet, len(metric))
+			for _, l := range metric {
+				ls[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+			}
+			labels[s.Labels] = ls
+		}
+		for _, e := range s.Entries {
+			entry := p.newEntry(ls, e.Timestamp, e.Line)
+			if batch, ok := batches[entry.tenantID]; ok {
+				batch.add(entry)
+				continue
+			}
+			batches[entry.tenantID] = newBatch(entry)
+		}
+	}
+
+	var result lokiutil.MultiError
+	for tenantID, batch := range batches {
+		if err := p.pushBatch(ctx, tenantID, batch); err != nil {
+			result.Add(err)
+		}
+	}
+	return result.Err()
+}
+
+// pushBatch sends the batch once. Only the errors worth retrying are returned, the batches
+// rejected by Loki being dropped.
+func (p pusher) pushBatch(ctx context.Context, tenantID string, batch *batch) error {
+	buf, entriesCount, err := batch.encode()
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error encoding batch", "error", err)
+		return nil
+	}
+	bufBytes := float64(len(buf))
+	encodedBytes.WithLabelValues(p.cfg.URL.Host).Add(bufBytes)
+
+	start := time.Now()
+	status, _, err := p.send(ctx, tenantID, buf)
+	requestDuration.WithLabelValues(strconv.Itoa(status), p.cfg.URL.Host).Observe(time.Since(start).Seconds())
+	if err == nil {
+		sentBytes.WithLabelValues(p.cfg.URL.Host).Add(bufBytes)
+		sentEntries.WithLabelValues(p.cfg.URL.Host).Add(float64(entriesCount))
+		return nil
+	}
+
+	// Only 429s, 500s and connection-level errors are worth retrying.
+	if status <= 0 || status == 429 || status/100 == 5 {
+		return err
+	}
+	level.Error(p.logger).Log("msg", "dropping rejected batch", "status", status, "error", err)
+	droppedBytes.WithLabelValues(p.cfg.URL.Host).Add(bufBytes)
+	droppedEntries.WithLabelValues(p.cfg.URL.Host).Add(float64(entriesCount))
+	return nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestPusher_Push(t *testing.T) {
+	streams := []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{logEntries[0].Entry, logEntries[1].Entry}},
+		{Labels: `{__tenant_id__="tenant-1", app="foo"}`, Entries: []logproto.Entry{logEntries[3].Entry}},
+	}
+
+	tests := map[string]struct {
+		serverResponseStatus int
+		expectedErr          bool
+		expectedMetrics      string
+	}{
+		"pushed": {
+			serverResponseStatus: http.StatusNoContent,
+			expectedMetrics: `
+				# HELP promtail_sent_entries_total Number of log entries sent to the ingester.
+				# TYPE promtail_sent_entries_total counter
+				promtail_sent_entries_total{host="__HOST__"} 3.0
+				# HELP promtail_dropped_entries_total Number of log entries dropped because failed to be sent to the ingester after all retries.
+				# TYPE promtail_dropped_entries_total counter
+				promtail_dropped_entries_total{host="__HOST__"} 0
+			`,
+		},
+		"retryable error, left to the caller": {
+			serverResponseStatus: http.StatusInternalServerError,
+			expectedErr:          true,
+			expectedMetrics: `
+				# HELP promtail_sent_entries_total Number of log entries sent to the ingester.
+				# TYPE promtail_sent_entries_total counter
+				promtail_sent_entries_total{host="__HOST__"} 0
+				# HELP promtail_dropped_entries_total Number of log entries dropped because failed to be sent to the ingester after all retries.
+				# TYPE promtail_dropped_entries_total counter
+				promtail_dropped_entries_total{host="__HOST__"} 0
+			`,
+		},
+		"rejected, dropped": {
+			serverResponseStatus: http.StatusBadRequest,
+			expectedMetrics: `
+				# HELP promtail_sent_entries_total Number of log entries sent to the ingester.
+				# TYPE promtail_sent_entries_total counter
+				promtail_sent_entries_total{host="__HOST__"} 0
+				# HELP promtail_dropped_entries_total Number of log entries dropped because failed to be sent to the ingester after all retries.
+				# TYPE promtail_dropped_entries_total counter
+				promtail_dropped_entries_total{host="__HOST__"} 3.0
+			`,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			sentEntries.Reset()
+			droppedEntries.Reset()
+
+			receivedReqsChan := make(chan receivedReq, 10)
+			server := httptest.NewServer(createServerHandler(receivedReqsChan, testData.serverResponseStatus))
+			defer server.Close()
+
+			serverURL := flagext.URLValue{}
+			require.NoError(t, serverURL.Set(server.URL))
+
+			p, err := NewPusherWithTransport(Config{
+				URL:      serverURL,
+				Timeout:  time.Second,
+				TenantID: "default",
+			}, log.NewNopLogger(), nil)
+			require.NoError(t, err)
+
+			err = p.Push(context.Background(), streams)
+			if testData.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// one request per tenant, sent once.
+			close(receivedReqsChan)
+			received := map[string][]logproto.Stream{}
+			for req := range receivedReqsChan {
+				received[req.tenantID] = append(received[req.tenantID], req.pushReq.Streams...)
+			}
+			require.Equal(t, map[string][]logproto.Stream{
+				"default":  {{Labels: `{app="foo"}`, Entries: []logproto.Entry{logEntries[0].Entry, logEntries[1].Entry}}},
+				"tenant-1": {{Labels: `{app="foo"}`, Entries: []logproto.Entry{logEntries[3].Entry}}},
+			}, received)
+
+			expectedMetrics := strings.Replace(testData.expectedMetrics, "__HOST__", serverURL.Host, -1)
+			err = testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expectedMetrics), "promtail_sent_entries_total", "promtail_dropped_entries_total")
+			assert.NoError(t, err)
+		})
+	}
+}
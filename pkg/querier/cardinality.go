@@ -0,0 +1,118 @@
+package querier
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// Cardinality returns the cardinality of the streams of the tenant held by the ingesters.
+func (q *Querier) Cardinality(ctx context.Context, req *logproto.CardinalityRequest) (*logproto.CardinalityResponse, error) {
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(q.cfg.QueryTimeout))
+	defer cancel()
+
+	resps, err := q.forAllIngesters(ctx, func(client logproto.QuerierClient) (interface{}, error) {
+		return client.Cardinality(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cardinalities := make([]*logproto.CardinalityResponse, 0, len(resps))
+	for _, resp := range resps {
+		cardinalities = append(cardinalities, resp.response.(*logproto.CardinalityResponse))
+	}
+	return mergeCardinality(cardinalities, q.ring.ReplicationFactor(), int(req.Limit)), nil
+}
+
+// mergeCardinality merges the cardinality reported by each ingester. Every stream being held by
+// replicationFactor ingesters, the stream counts are divided by the replication factor. The number
+// of values of a label is exact when every ingester returned all of its values, otherwise it is
+// a lower bound.
+func mergeCardinality(resps []*logproto.CardinalityResponse, replicationFactor, limit int) *logproto.CardinalityResponse {
+	if replicationFactor < 1 {
+		replicationFactor = 1
+	}
+	rf := uint32(replicationFactor)
+
+	type labelCardinality struct {
+		streams   uint32
+		maxValues uint32
+		complete  bool
+		values    map[string]uint32
+	}
+	var (
+		streams uint32
+		labels  = map[string]*labelCardinality{}
+		churn   = map[time.Time]*logproto.StreamChurn{}
+	)
+	for _, resp := range resps {
+		streams += resp.Streams
+		for _, l := range resp.Labels {
+			label, ok := labels[l.Name]
+			if !ok {
+				label = &labelCardinality{complete: true, values: map[string]uint32{}}
+				labels[l.Name] = label
+			}
+			label.streams += l.Streams
+			if l.Values > label.maxValues {
+				label.maxValues = l.Values
+			}
+			label.complete = label.complete && len(l.TopValues) == int(l.Values)
+			for _, v := range l.TopValues {
+				label.values[v.Value] += v.Streams
+			}
+		}
+		for _, c := range resp.Churn {
+			bucket, ok := churn[c.Start]
+			if !ok {
+				bucket = &logproto.StreamChurn{Start: c.Start}
+				churn[c.Start] = bucket
+			}
+			bucket.Created += c.Created
+			bucket.Removed += c.Removed
+		}
+	}
+
+	result := &logproto.CardinalityResponse{
+		Streams: divideRoundUp(streams, rf),
+		Labels:  make([]logproto.LabelCardinality, 0, len(labels)),
+		Churn:   make([]logproto.StreamChurn, 0, len(churn)),
+	}
+	for name, label := range labels {
+		values := uint32(len(label.values))
+		if !label.complete && label.maxValues > values {
+			values = label.maxValues
+		}
+		topValues := make([]logproto.LabelValueCardinality, 0, len(label.values))
+		for value, streams := range label.values {
+			topValues = append(topValues, logproto.LabelValueCardinality{Value: value, Streams: divideRoundUp(streams, rf)})
+		}
+		result.Labels = append(result.Labels, logproto.LabelCardinality{
+			Name:      name,
+			Streams:   divideRoundUp(label.streams, rf),
+			Values:    values,
+			TopValues: logproto.TopLabelValues(topValues, limit),
+		})
+	}
+	sort.Slice(result.Labels, func(i, j int) bool {
+		return result.Labels[i].Name < result.Labels[j].Name
+	})
+	for _, c := range churn {
+		result.Churn = append(result.Churn, logproto.StreamChurn{
+			Start:   c.Start,
+			Created: divideRoundUp(c.Created, rf),
+			Removed: divideRoundUp(c.Removed, rf),
+		})
+	}
+	sort.Slice(result.Churn, func(i, j int) bool {
+		return result.Churn[i].Start.Before(result.Churn[j].Start)
+	})
+	return result
+}
+
+func divideRoundUp(n, d uint32) uint32 {
+	return (n + d - 1) / d
+}
@@ -0,0 +1,54 @@
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestMergeCardinality(t *testing.T) {
+	start := time.Unix(600, 0)
+	resps := []*logproto.CardinalityResponse{
+		{
+			Streams: 3,
+			Labels: []logproto.LabelCardinality{
+				{Name: "app", Streams: 3, Values: 2, TopValues: []logproto.LabelValueCardinality{{Value: "foo", Streams: 2}, {Value: "bar", Streams: 1}}},
+				{Name: "pod", Streams: 3, Values: 3, TopValues: []logproto.LabelValueCardinality{{Value: "a", Streams: 1}}},
+			},
+			Churn: []logproto.StreamChurn{{Start: start, Created: 3}},
+		},
+		{
+			Streams: 3,
+			Labels: []logproto.LabelCardinality{
+				{Name: "app", Streams: 3, Values: 2, TopValues: []logproto.LabelValueCardinality{{Value: "foo", Streams: 2}, {Value: "bar", Streams: 1}}},
+				{Name: "pod", Streams: 3, Values: 3, TopValues: []logproto.LabelValueCardinality{{Value: "b", Streams: 1}}},
+			},
+			Churn: []logproto.StreamChurn{{Start: start, Created: 2}, {Start: start.Add(10 * time.Minute), Removed: 1}},
+		},
+		{
+			Streams: 2,
+			Labels: []logproto.LabelCardinality{
+				{Name: "app", Streams: 2, Values: 1, TopValues: []logproto.LabelValueCardinality{{Value: "foo", Streams: 2}}},
+				{Name: "pod", Streams: 2, Values: 2, TopValues: []logproto.LabelValueCardinality{{Value: "a", Streams: 1}}},
+			},
+			Churn: []logproto.StreamChurn{{Start: start, Created: 1}},
+		},
+	}
+
+	require.Equal(t, &logproto.CardinalityResponse{
+		Streams: 4,
+		Labels: []logproto.LabelCardinality{
+			// every ingester returned all of the values.
+			{Name: "app", Streams: 4, Values: 2, TopValues: []logproto.LabelValueCardinality{{Value: "foo", Streams: 3}}},
+			// the values are truncated, the largest count of an ingester is a lower bound.
+			{Name: "pod", Streams: 4, Values: 3, TopValues: []logproto.LabelValueCardinality{{Value: "a", Streams: 1}}},
+		},
+		Churn: []logproto.StreamChurn{
+			{Start: start, Created: 3},
+			{Start: start.Add(10 * time.Minute), Removed: 1},
+		},
+	}, mergeCardinality(resps, 2, 1))
+}
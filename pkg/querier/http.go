@@ -13,8 +13,10 @@ import (
 	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/loghttp"
 	loghttp_legacy "github.com/grafana/loki/pkg/loghttp/legacy"
+	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/marshal"
 	marshal_legacy "github.com/grafana/loki/pkg/logql/marshal/legacy"
@@ -23,6 +25,9 @@ import (
 
 const (
 	wsPingPeriod = 1 * time.Second
+
+	// streamFlushEntries is the number of entries after which a streamed query response is flushed.
+	streamFlushEntries = 100
 )
 
 type QueryResponse struct {
@@ -42,6 +47,11 @@ func (q *Querier) RangeQueryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.Stream {
+		q.streamRangeQuery(ctx, request, w)
+		return
+	}
+
 	if err := q.validateEntriesLimits(ctx, request.Limit); err != nil {
 		serverutil.WriteError(err, w)
 		return
@@ -70,6 +80,64 @@ func (q *Querier) RangeQueryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// streamRangeQuery writes the entries of a range log query as newline delimited JSON while they are read.
+// Once the first entry has been written the status code can't be changed anymore, errors are then reported
+// in the last line of the response.
+func (q *Querier) streamRangeQuery(ctx context.Context, request *loghttp.RangeQuery, w http.ResponseWriter) {
+	logger := util.WithContext(ctx, util.Logger)
+
+	expr, err := logql.ParseExpr(request.Query)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+	if _, ok := expr.(logql.SampleExpr); ok {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, logql.ErrStreamingMetricQuery.Error()), w)
+		return
+	}
+
+	if err := q.validateStreamedEntriesLimits(ctx, request.Limit); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	params := logql.NewLiteralParams(
+		request.Query,
+		request.Start,
+		request.End,
+		request.Step,
+		request.Interval,
+		request.Direction,
+		request.Limit,
+		request.Shards,
+	)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	writer := marshal.NewQueryStreamWriter(w)
+	written := 0
+
+	statistics, err := q.engine.Stream(ctx, params, func(labels string, entry logproto.Entry) error {
+		if err := writer.WriteEntry(labels, entry); err != nil {
+			return err
+		}
+		written++
+		if flusher != nil && written%streamFlushEntries == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		level.Error(logger).Log("msg", "streamed query failed", "err", err, "written_entries", written)
+		err = writer.WriteError(err)
+	} else {
+		err = writer.WriteStats(statistics)
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "error writing streamed query response", "err", err)
+	}
+}
+
 // InstantQueryHandler is a http.HandlerFunc for instant queries.
 func (q *Querier) InstantQueryHandler(w http.ResponseWriter, r *http.Request) {
 	// Enforce the query timeout while querying backends
@@ -212,6 +280,12 @@ func (q *Querier) TailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cursor, err := loghttp.ParseTailCursor(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		level.Error(logger).Log("msg", "Error in upgrading websocket", "err", err)
@@ -224,7 +298,7 @@ func (q *Querier) TailHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	tailer, err := q.Tail(r.Context(), req)
+	tailer, err := q.Tail(r.Context(), req, cursor)
 	if err != nil {
 		if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())); err != nil {
 			level.Error(logger).Log("msg", "Error connecting to ingesters for tailing", "err", err)
@@ -326,6 +400,92 @@ func (q *Querier) SeriesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CardinalityHandler returns the number of streams of the tenant by label name and value,
+// and the recent stream churn, as seen by the ingesters.
+func (q *Querier) CardinalityHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := loghttp.ParseCardinalityQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	resp, err := q.Cardinality(r.Context(), req)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	if err := marshal.WriteCardinalityResponseJSON(*resp, w); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+}
+
+// VolumeHandler returns the number of lines or bytes of the streams matching a selector over time,
+// grouped by labels, from the volume index without fetching the chunks when possible.
+func (q *Querier) VolumeHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := loghttp.ParseVolumeQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	// the volume is counted by whole buckets.
+	req := &logproto.VolumeRequest{
+		Selector: query.Query,
+		Start:    query.Start.Truncate(chunkenc.VolumeBucketPeriod),
+		End:      query.End.Truncate(chunkenc.VolumeBucketPeriod),
+	}
+	if req.End.Before(query.End) {
+		req.End = req.End.Add(chunkenc.VolumeBucketPeriod)
+	}
+
+	resp, err := q.Volume(r.Context(), req)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	matrix, err := volumeMatrix(resp, query)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	if err := marshal.WriteQueryResponseJSON(logql.Result{Data: matrix}, w); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+}
+
+// IndexStatsHandler returns the number of streams and chunks matching a selector, with the lines and bytes
+// of their entries, from the volume index without fetching the chunks when possible.
+func (q *Querier) IndexStatsHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := loghttp.ParseIndexStatsQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	// the volume is counted by whole buckets.
+	end := req.End
+	req.Start, req.End = req.Start.Truncate(chunkenc.VolumeBucketPeriod), req.End.Truncate(chunkenc.VolumeBucketPeriod)
+	if req.End.Before(end) {
+		req.End = req.End.Add(chunkenc.VolumeBucketPeriod)
+	}
+
+	stats, err := q.IndexStats(r.Context(), req)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	if err := marshal.WriteIndexStatsResponseJSON(*stats, w); err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+}
+
 // parseRegexQuery parses regex and query querystring from httpRequest and returns the combined LogQL query.
 // This is used only to keep regexp query string support until it gets fully deprecated.
 func parseRegexQuery(httpRequest *http.Request) (string, error) {
@@ -354,3 +514,17 @@ func (q *Querier) validateEntriesLimits(ctx context.Context, limit uint32) error
 	}
 	return nil
 }
+
+func (q *Querier) validateStreamedEntriesLimits(ctx context.Context, limit uint32) error {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+
+	maxEntriesLimit := q.limits.MaxStreamedEntriesPerQuery(userID)
+	if int(limit) > maxEntriesLimit && maxEntriesLimit != 0 {
+		return httpgrpc.Errorf(http.StatusBadRequest,
+			"max streamed entries limit per query exceeded, limit > max_streamed_entries_per_query (%d > %d)", limit, maxEntriesLimit)
+	}
+	return nil
+}
@@ -41,6 +41,7 @@ const (
 type Config struct {
 	QueryTimeout                  time.Duration    `yaml:"query_timeout"`
 	TailMaxDuration               time.Duration    `yaml:"tail_max_duration"`
+	TailBackpressure              bool             `yaml:"tail_backpressure"`
 	ExtraQueryDelay               time.Duration    `yaml:"extra_query_delay,omitempty"`
 	QueryIngestersWithin          time.Duration    `yaml:"query_ingesters_within,omitempty"`
 	IngesterQueryStoreMaxLookback time.Duration    `yaml:"-"`
@@ -51,10 +52,12 @@ type Config struct {
 // RegisterFlags register flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.TailMaxDuration, "querier.tail-max-duration", 1*time.Hour, "Limit the duration for which live tailing request would be served")
+	f.BoolVar(&cfg.TailBackpressure, "querier.tail-backpressure", false, "Pause reading from ingesters when a live tailing client is slow, instead of dropping entries.")
 	f.DurationVar(&cfg.QueryTimeout, "querier.query_timeout", 1*time.Minute, "Timeout when querying backends (ingesters or storage) during the execution of a query request")
 	f.DurationVar(&cfg.ExtraQueryDelay, "distributor.extra-query-delay", 0, "Time to wait before sending more than the minimum successful query requests.")
 	f.DurationVar(&cfg.QueryIngestersWithin, "querier.query-ingesters-within", 0, "Maximum lookback beyond which queries are not sent to ingester. 0 means all queries are sent to ingester.")
 	f.IntVar(&cfg.MaxConcurrent, "querier.max-concurrent", 20, "The maximum number of concurrent queries.")
+	cfg.Engine.SlowQueryLog.RegisterFlagsWithPrefix("querier.", f)
 }
 
 // Querier handlers queries.
@@ -183,7 +186,7 @@ func (q *Querier) Select(ctx context.Context, params logql.SelectParams) (iter.E
 	// skip ingester queries only when QueryIngestersWithin is enabled (not the zero value) and
 	// the end of the query is earlier than the lookback
 	if lookback := time.Now().Add(-q.cfg.QueryIngestersWithin); q.cfg.QueryIngestersWithin != 0 && params.GetEnd().Before(lookback) {
-		return chunkStoreIter, nil
+		return newStreamShardIterator(chunkStoreIter), nil
 	}
 
 	iters, err := q.queryIngesters(ctx, params)
@@ -191,7 +194,7 @@ func (q *Querier) Select(ctx context.Context, params logql.SelectParams) (iter.E
 		return nil, err
 	}
 
-	return iter.NewHeapIterator(ctx, append(iters, chunkStoreIter), params.Direction), nil
+	return newStreamShardIterator(iter.NewHeapIterator(ctx, append(iters, chunkStoreIter), params.Direction)), nil
 }
 
 func (q *Querier) queryIngesters(ctx context.Context, params logql.SelectParams) ([]iter.EntryIterator, error) {
@@ -247,8 +250,18 @@ func (q *Querier) Label(ctx context.Context, req *logproto.LabelRequest) (*logpr
 	}
 	results = append(results, storeValues)
 
+	values := listutil.MergeStringLists(results...)
+	if !req.Values {
+		// the shard label of the streams split by the distributors is hidden.
+		for i, name := range values {
+			if name == listutil.StreamShardLabel {
+				values = append(values[:i], values[i+1:]...)
+				break
+			}
+		}
+	}
 	return &logproto.LabelResponse{
-		Values: listutil.MergeStringLists(results...),
+		Values: values,
 	}, nil
 }
 
@@ -257,12 +270,16 @@ func (*Querier) Check(_ context.Context, _ *grpc_health_v1.HealthCheckRequest) (
 	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
 }
 
-// Tail keeps getting matching logs from all ingesters for given query
-func (q *Querier) Tail(ctx context.Context, req *logproto.TailRequest) (*Tailer, error) {
+// Tail keeps getting matching logs from all ingesters for given query.
+// When a cursor is given, the tail resumes after the entry of the cursor by first sending
+// all the entries received since then, up to the max entries limit per query.
+func (q *Querier) Tail(ctx context.Context, req *logproto.TailRequest, cursor *loghttp.TailCursor) (*Tailer, error) {
 	err := q.checkTailRequestLimit(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// the ingesters wait for the querier instead of dropping entries as it stops reading them.
+	req.Backpressure = q.cfg.TailBackpressure
 
 	histReq := logql.SelectParams{
 		QueryRequest: &logproto.QueryRequest{
@@ -273,6 +290,15 @@ func (q *Querier) Tail(ctx context.Context, req *logproto.TailRequest) (*Tailer,
 			Direction: logproto.BACKWARD,
 		},
 	}
+	if cursor != nil {
+		userID, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		histReq.Start = cursor.Timestamp
+		histReq.Limit = uint32(q.limits.MaxEntriesLimitPerQuery(userID))
+		histReq.Direction = logproto.FORWARD
+	}
 
 	err = q.validateQueryRequest(ctx, histReq.QueryRequest)
 	if err != nil {
@@ -302,20 +328,26 @@ func (q *Querier) Tail(ctx context.Context, req *logproto.TailRequest) (*Tailer,
 		return nil, err
 	}
 
-	reversedIterator, err := iter.NewReversedIter(histIterators, req.Limit, true)
-	if err != nil {
-		return nil, err
+	var historicEntries iter.EntryIterator
+	if cursor != nil {
+		historicEntries = newCursorIterator(histIterators, *cursor, histReq.Limit)
+	} else {
+		historicEntries, err = iter.NewReversedIter(histIterators, req.Limit, true)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return newTailer(
 		time.Duration(req.DelayFor)*time.Second,
 		tailClients,
-		reversedIterator,
+		historicEntries,
 		func(connectedIngestersAddr []string) (map[string]logproto.Querier_TailClient, error) {
 			return q.tailDisconnectedIngesters(tailCtx, req, connectedIngestersAddr)
 		},
 		q.cfg.TailMaxDuration,
 		tailerWaitEntryThrottle,
+		q.cfg.TailBackpressure,
 	), nil
 }
 
@@ -433,7 +465,7 @@ func (q *Querier) awaitSeries(ctx context.Context, req *logproto.SeriesRequest)
 
 	deduped := make(map[string]logproto.SeriesIdentifier)
 	for _, set := range sets {
-		for _, s := range set {
+		for _, s := range removeStreamShards(set) {
 			key := loghttp.LabelSet(s.Labels).String()
 			if _, exists := deduped[key]; !exists {
 				deduped[key] = s
@@ -67,6 +67,16 @@ func (c *querierClientMock) TailersCount(ctx context.Context, in *logproto.Taile
 	return args.Get(0).(*logproto.TailersCountResponse), args.Error(1)
 }
 
+func (c *querierClientMock) Cardinality(ctx context.Context, in *logproto.CardinalityRequest, opts ...grpc.CallOption) (*logproto.CardinalityResponse, error) {
+	args := c.Called(ctx, in, opts)
+	return args.Get(0).(*logproto.CardinalityResponse), args.Error(1)
+}
+
+func (c *querierClientMock) Volume(ctx context.Context, in *logproto.VolumeRequest, opts ...grpc.CallOption) (*logproto.VolumeResponse, error) {
+	args := c.Called(ctx, in, opts)
+	return args.Get(0).(*logproto.VolumeResponse), args.Error(1)
+}
+
 func (c *querierClientMock) Context() context.Context {
 	return context.Background()
 }
@@ -261,6 +271,11 @@ func (s *storeMock) GetSeries(ctx context.Context, req logql.SelectParams) ([]lo
 	return res.([]logproto.SeriesIdentifier), args.Error(1)
 }
 
+func (s *storeMock) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	args := s.Called(ctx, req)
+	return args.Get(0).(*logproto.VolumeResponse), args.Error(1)
+}
+
 func (s *storeMock) Stop() {
 
 }
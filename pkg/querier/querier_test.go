@@ -1,15 +1,20 @@
 package querier
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/grafana/loki/pkg/logql"
 
+	json "github.com/json-iterator/go"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,6 +25,7 @@ import (
 	"github.com/cortexproject/cortex/pkg/ring"
 	"github.com/cortexproject/cortex/pkg/util/flagext"
 
+	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/util/validation"
 )
@@ -110,7 +116,7 @@ func TestQuerier_Tail_QueryTimeoutConfigFlag(t *testing.T) {
 	require.NoError(t, err)
 
 	ctx := user.InjectOrgID(context.Background(), "test")
-	_, err = q.Tail(ctx, &request)
+	_, err = q.Tail(ctx, &request, nil)
 	require.NoError(t, err)
 
 	calls := ingesterClient.GetMockedCallsByMethod("Query")
@@ -521,6 +527,81 @@ func TestQuerier_IngesterMaxQueryLookback(t *testing.T) {
 	}
 }
 
+func TestQuerier_RangeQueryHandler_Stream(t *testing.T) {
+	limitsCfg := defaultLimitsTestConfig()
+	limitsCfg.MaxEntriesLimitPerQuery = 10
+	limitsCfg.MaxStreamedEntriesPerQuery = 1000
+	limits, err := validation.NewOverrides(limitsCfg, nil)
+	require.NoError(t, err)
+
+	conf := mockQuerierConfig()
+	conf.QueryIngestersWithin = time.Minute
+
+	for _, tc := range []struct {
+		desc    string
+		query   string
+		limit   int
+		status  int
+		entries int
+	}{
+		{"streams past the entries limit", `{type="test"}`, 500, http.StatusOK, 250},
+		{"honors the requested limit", `{type="test"}`, 100, http.StatusOK, 100},
+		{"rejects limits above the streamed entries limit", `{type="test"}`, 5000, http.StatusBadRequest, 0},
+		{"rejects metric queries", `count_over_time({type="test"}[1m])`, 100, http.StatusBadRequest, 0},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			store := newStoreMock()
+			store.On("LazyQuery", mock.Anything, mock.Anything).Return(mockStreamIterator(1, 250), nil)
+
+			q, err := newQuerier(
+				conf,
+				mockIngesterClientConfig(),
+				newIngesterClientMockFactory(newQuerierClientMock()),
+				mockReadRingWithOneActiveIngester(),
+				store, limits)
+			require.NoError(t, err)
+
+			end := time.Now().Add(-time.Hour)
+			params := url.Values{
+				"query":     {tc.query},
+				"stream":    {"true"},
+				"direction": {"forward"},
+				"limit":     {fmt.Sprint(tc.limit)},
+				"start":     {fmt.Sprint(end.Add(-time.Hour).UnixNano())},
+				"end":       {fmt.Sprint(end.UnixNano())},
+			}
+			req := httptest.NewRequest("GET", "/loki/api/v1/query_range?"+params.Encode(), nil)
+			require.NoError(t, req.ParseForm())
+			req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+			w := httptest.NewRecorder()
+
+			q.RangeQueryHandler(w, req)
+
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+			if tc.status != http.StatusOK {
+				return
+			}
+			require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+			var lines []loghttp.QueryStreamLine
+			scanner := bufio.NewScanner(w.Body)
+			for scanner.Scan() {
+				var line loghttp.QueryStreamLine
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+			require.NoError(t, scanner.Err())
+			require.Len(t, lines, tc.entries+1)
+			for i, line := range lines[:tc.entries] {
+				require.Equal(t, loghttp.LabelSet{"type": "test"}, line.Labels)
+				require.Equal(t, []loghttp.Entry{{Timestamp: time.Unix(int64(i+1), 0), Line: fmt.Sprintf("line %d", i+1)}}, line.Entries)
+			}
+			require.NotNil(t, lines[tc.entries].Statistics)
+			require.Empty(t, lines[tc.entries].Error)
+		})
+	}
+}
+
 func TestQuerier_concurrentTailLimits(t *testing.T) {
 	request := logproto.TailRequest{
 		Query:    "{type=\"test\"}",
@@ -598,7 +679,7 @@ func TestQuerier_concurrentTailLimits(t *testing.T) {
 			require.NoError(t, err)
 
 			ctx := user.InjectOrgID(context.Background(), "test")
-			_, err = q.Tail(ctx, &request)
+			_, err = q.Tail(ctx, &request, nil)
 			assert.Equal(t, testData.expectedError, err)
 		})
 	}
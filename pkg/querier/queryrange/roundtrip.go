@@ -25,11 +25,14 @@ import (
 // Config is the configuration for the queryrange tripperware
 type Config struct {
 	queryrange.Config `yaml:",inline"`
+	// SlowQueryLog configures the logging of slow queries by the frontend.
+	SlowQueryLog logql.SlowQueryLogConfig `yaml:"slow_query_log"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Config.RegisterFlags(f)
+	cfg.SlowQueryLog.RegisterFlagsWithPrefix("frontend.", f)
 }
 
 // Stopper gracefully shutdown resources created
@@ -118,6 +121,11 @@ func (r roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 			if err != nil {
 				return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 			}
+			// the responses of querier workers travel back as a single message, streamed queries
+			// would be buffered whole by the frontend.
+			if rangeQuery.Stream {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "streamed queries are not supported by the query-frontend, send them to the queriers directly")
+			}
 			if err := validateLimits(req, rangeQuery.Limit, r.limits); err != nil {
 				return nil, err
 			}
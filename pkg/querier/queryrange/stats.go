@@ -13,6 +13,7 @@ import (
 	"github.com/cortexproject/cortex/pkg/util/spanlogger"
 	"github.com/go-kit/kit/log/level"
 	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/stats"
@@ -22,13 +23,12 @@ type ctxKeyType string
 
 const ctxKey ctxKeyType = "stats"
 
-var (
-	defaultMetricRecorder = metricRecorderFn(func(ctx context.Context, p logql.Params, status string, stats stats.Result) {
-		logql.RecordMetrics(ctx, p, status, stats)
-	})
-	// StatsHTTPMiddleware is an http middleware to record stats for query_range filter.
-	StatsHTTPMiddleware middleware.Interface = statsHTTPMiddleware(defaultMetricRecorder)
-)
+// NewStatsHTTPMiddleware returns an http middleware to record stats for query_range filter.
+func NewStatsHTTPMiddleware(slowQueryLog logql.SlowQueryLogConfig) middleware.Interface {
+	return statsHTTPMiddleware(metricRecorderFn(func(ctx context.Context, p logql.Params, status string, stats stats.Result) {
+		logql.RecordMetrics(ctx, p, status, stats, slowQueryLog)
+	}))
+}
 
 type metricRecorder interface {
 	Record(ctx context.Context, p logql.Params, status string, stats stats.Result)
@@ -44,6 +44,7 @@ type queryData struct {
 	params     logql.Params
 	statistics *stats.Result
 	recorded   bool
+	orgID      string
 }
 
 func statsHTTPMiddleware(recorder metricRecorder) middleware.Interface {
@@ -62,8 +63,12 @@ func statsHTTPMiddleware(recorder metricRecorder) middleware.Interface {
 				if data.statistics == nil {
 					data.statistics = &stats.Result{}
 				}
+				ctx := r.Context()
+				if data.orgID != "" {
+					ctx = user.InjectOrgID(ctx, data.orgID)
+				}
 				recorder.Record(
-					r.Context(),
+					ctx,
 					data.params,
 					strconv.Itoa(interceptor.statusCode),
 					*data.statistics,
@@ -105,6 +110,8 @@ func StatsCollectorMiddleware() queryrange.Middleware {
 				data.recorded = true
 				data.statistics = statistics
 				data.params = paramsFromRequest(req)
+				// the tenant is only known by the context of the request once authenticated.
+				data.orgID, _ = user.ExtractOrgID(ctx)
 			}
 			return resp, err
 		})
@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	listutil "github.com/grafana/loki/pkg/util"
+)
+
+// streamShardIterator removes the shard label of the streams split by the distributors,
+// so that the entries of all the shards of a stream are returned as a single stream.
+type streamShardIterator struct {
+	iter.EntryIterator
+	// labels without the shard label, by labels of the streams read.
+	labels map[string]string
+}
+
+func newStreamShardIterator(it iter.EntryIterator) iter.EntryIterator {
+	return &streamShardIterator{
+		EntryIterator: it,
+		labels:        map[string]string{},
+	}
+}
+
+func (it *streamShardIterator) Labels() string {
+	ls := it.EntryIterator.Labels()
+	unsharded, ok := it.labels[ls]
+	if !ok {
+		unsharded = listutil.RemoveStreamShard(ls)
+		it.labels[ls] = unsharded
+	}
+	return unsharded
+}
+
+// removeStreamShards removes the shard label of the series, merging the series of the shards of a stream.
+func removeStreamShards(series []logproto.SeriesIdentifier) []logproto.SeriesIdentifier {
+	for _, s := range series {
+		delete(s.Labels, listutil.StreamShardLabel)
+	}
+	return series
+}
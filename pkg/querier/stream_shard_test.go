@@ -0,0 +1,48 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestStreamShardIterator(t *testing.T) {
+	it := newStreamShardIterator(iter.NewHeapIterator(context.Background(), []iter.EntryIterator{
+		iter.NewStreamIterator(logproto.Stream{
+			Labels:  `{__stream_shard__="0", app="foo"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "1"}, {Timestamp: time.Unix(3, 0), Line: "3"}},
+		}),
+		iter.NewStreamIterator(logproto.Stream{
+			Labels:  `{__stream_shard__="1", app="foo"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(2, 0), Line: "2"}},
+		}),
+		iter.NewStreamIterator(logproto.Stream{
+			Labels:  `{app="bar"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(4, 0), Line: "4"}},
+		}),
+	}, logproto.FORWARD))
+
+	var labels, lines []string
+	for it.Next() {
+		labels = append(labels, it.Labels())
+		lines = append(lines, it.Entry().Line)
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, []string{`{app="foo"}`, `{app="foo"}`, `{app="foo"}`, `{app="bar"}`}, labels)
+	require.Equal(t, []string{"1", "2", "3", "4"}, lines)
+}
+
+func TestRemoveStreamShards(t *testing.T) {
+	require.Equal(t, []logproto.SeriesIdentifier{
+		{Labels: map[string]string{"app": "foo"}},
+		{Labels: map[string]string{"app": "bar"}},
+	}, removeStreamShards([]logproto.SeriesIdentifier{
+		{Labels: map[string]string{"app": "foo", "__stream_shard__": "1"}},
+		{Labels: map[string]string{"app": "bar"}},
+	}))
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/grafana/loki/pkg/iter"
-	loghttp "github.com/grafana/loki/pkg/loghttp/legacy"
+	"github.com/grafana/loki/pkg/loghttp"
+	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logproto"
+	listutil "github.com/grafana/loki/pkg/util"
 )
 
 const (
@@ -29,6 +31,10 @@ const (
 	// with the next successfully pushed response. Once the dropped entries memory buffer
 	// exceed this value, we start skipping dropped entries too.
 	maxDroppedEntriesPerTailResponse = 1000
+
+	// the maximum number of responses received from ingesters and not yet sent to the
+	// client before the tailer stops reading from ingesters, when backpressure is enabled
+	maxBufferedIngesterResponses = 100
 )
 
 // Tailer manages complete lifecycle of a tail request
@@ -47,7 +53,8 @@ type Tailer struct {
 
 	stopped         bool
 	delayFor        time.Duration
-	responseChan    chan *loghttp.TailResponse
+	backpressure    bool
+	responseChan    chan *legacy.TailResponse
 	closeErrChan    chan error
 	tailMaxDuration time.Duration
 
@@ -65,7 +72,7 @@ func (t *Tailer) readTailClients() {
 	}
 }
 
-// keeps sending oldest entry to responseChan. If channel is blocked drop the entry
+// keeps sending oldest entry to responseChan. If channel is blocked drop the entry, unless backpressure is enabled
 // When channel is unblocked, send details of dropped entries with current entry
 func (t *Tailer) loop() {
 	checkConnectionTicker := time.NewTicker(checkConnectionsWithIngestersPeriod)
@@ -74,7 +81,8 @@ func (t *Tailer) loop() {
 	tailMaxDurationTicker := time.NewTicker(t.tailMaxDuration)
 	defer tailMaxDurationTicker.Stop()
 
-	droppedEntries := make([]loghttp.DroppedEntry, 0)
+	droppedEntries := make([]legacy.DroppedEntry, 0)
+	droppedCounts := map[string]int{}
 
 	for !t.stopped {
 		select {
@@ -92,9 +100,16 @@ func (t *Tailer) loop() {
 		default:
 		}
 
+		// With backpressure, entries are kept until the client catches up instead of
+		// being dropped, and ingesters are not read meanwhile (see readTailClient)
+		if t.backpressure && t.isResponseChanBlocked() {
+			time.Sleep(t.waitEntryThrottle)
+			continue
+		}
+
 		// Read as much entries as we can (up to the max allowed) and populate the
 		// tail response we'll send over the response channel
-		tailResponse := new(loghttp.TailResponse)
+		tailResponse := new(legacy.TailResponse)
 		entriesCount := 0
 
 		for ; entriesCount < maxEntriesPerTailResponse && t.next(); entriesCount++ {
@@ -102,6 +117,7 @@ func (t *Tailer) loop() {
 			// to save the effort
 			if t.isResponseChanBlocked() {
 				droppedEntries = dropEntry(droppedEntries, t.currEntry.Timestamp, t.currLabels)
+				droppedCounts[t.currLabels]++
 				continue
 			}
 
@@ -146,15 +162,17 @@ func (t *Tailer) loop() {
 		// Drop the entry if the response channel buffer is full.
 		if len(droppedEntries) > 0 {
 			tailResponse.DroppedEntries = droppedEntries
+			tailResponse.DroppedCounts = droppedCounts
 		}
 
 		select {
 		case t.responseChan <- tailResponse:
 			if len(droppedEntries) > 0 {
-				droppedEntries = make([]loghttp.DroppedEntry, 0)
+				droppedEntries = make([]legacy.DroppedEntry, 0)
+				droppedCounts = map[string]int{}
 			}
 		default:
-			droppedEntries = dropEntries(droppedEntries, tailResponse.Streams)
+			droppedEntries = dropEntries(droppedEntries, droppedCounts, tailResponse.Streams)
 		}
 	}
 }
@@ -206,6 +224,12 @@ func (t *Tailer) readTailClient(addr string, querierTailClient logproto.Querier_
 			}
 			break
 		}
+		if t.backpressure && t.bufferedIngesterResponses() >= maxBufferedIngesterResponses {
+			// Stop reading until the client catches up, so that the ingester stream is paused
+			// by the grpc flow control instead of dropping entries in the querier.
+			time.Sleep(t.waitEntryThrottle)
+			continue
+		}
 		resp, err = querierTailClient.Recv()
 		if err != nil {
 			// We don't want to log error when its due to stopping the tail request
@@ -223,7 +247,17 @@ func (t *Tailer) pushTailResponseFromIngester(resp *logproto.TailResponse) {
 	t.streamMtx.Lock()
 	defer t.streamMtx.Unlock()
 
-	t.openStreamIterator.Push(iter.NewStreamIterator(*resp.Stream))
+	stream := *resp.Stream
+	stream.Labels = listutil.RemoveStreamShard(stream.Labels)
+	t.openStreamIterator.Push(iter.NewStreamIterator(stream))
+}
+
+// returns the number of responses from ingesters which have entries left to send
+func (t *Tailer) bufferedIngesterResponses() int {
+	t.streamMtx.Lock()
+	defer t.streamMtx.Unlock()
+
+	return t.openStreamIterator.Len()
 }
 
 // finds oldest entry by peeking at open stream iterator.
@@ -255,7 +289,7 @@ func (t *Tailer) isResponseChanBlocked() bool {
 	return len(t.responseChan) == cap(t.responseChan)
 }
 
-func (t *Tailer) getResponseChan() <-chan *loghttp.TailResponse {
+func (t *Tailer) getResponseChan() <-chan *legacy.TailResponse {
 	return t.responseChan
 }
 
@@ -270,16 +304,18 @@ func newTailer(
 	tailDisconnectedIngesters func([]string) (map[string]logproto.Querier_TailClient, error),
 	tailMaxDuration time.Duration,
 	waitEntryThrottle time.Duration,
+	backpressure bool,
 ) *Tailer {
 	t := Tailer{
 		openStreamIterator:        iter.NewHeapIterator(context.Background(), []iter.EntryIterator{historicEntries}, logproto.FORWARD),
 		querierTailClients:        querierTailClients,
 		delayFor:                  delayFor,
-		responseChan:              make(chan *loghttp.TailResponse, maxBufferedTailResponses),
+		responseChan:              make(chan *legacy.TailResponse, maxBufferedTailResponses),
 		closeErrChan:              make(chan error),
 		tailDisconnectedIngesters: tailDisconnectedIngesters,
 		tailMaxDuration:           tailMaxDuration,
 		waitEntryThrottle:         waitEntryThrottle,
+		backpressure:              backpressure,
 	}
 
 	t.readTailClients()
@@ -287,20 +323,55 @@ func newTailer(
 	return &t
 }
 
-func dropEntry(droppedEntries []loghttp.DroppedEntry, timestamp time.Time, labels string) []loghttp.DroppedEntry {
+func dropEntry(droppedEntries []legacy.DroppedEntry, timestamp time.Time, labels string) []legacy.DroppedEntry {
 	if len(droppedEntries) >= maxDroppedEntriesPerTailResponse {
 		return droppedEntries
 	}
 
-	return append(droppedEntries, loghttp.DroppedEntry{Timestamp: timestamp, Labels: labels})
+	return append(droppedEntries, legacy.DroppedEntry{Timestamp: timestamp, Labels: labels})
 }
 
-func dropEntries(droppedEntries []loghttp.DroppedEntry, streams []logproto.Stream) []loghttp.DroppedEntry {
+func dropEntries(droppedEntries []legacy.DroppedEntry, droppedCounts map[string]int, streams []logproto.Stream) []legacy.DroppedEntry {
 	for _, stream := range streams {
 		for _, entry := range stream.Entries {
-			droppedEntries = dropEntry(droppedEntries, entry.Timestamp, entry.Line)
+			droppedEntries = dropEntry(droppedEntries, entry.Timestamp, stream.Labels)
 		}
+		droppedCounts[stream.Labels] += len(stream.Entries)
 	}
 
 	return droppedEntries
 }
+
+// cursorIterator skips the entries already received at the timestamp a resumed tail was stopped at,
+// and stops after limit entries.
+type cursorIterator struct {
+	iter.EntryIterator
+	timestamp time.Time
+	// number of entries received per hash of their line, as identical lines can be received at the same timestamp.
+	received map[uint64]int
+	limit    uint32
+	count    uint32
+}
+
+func newCursorIterator(it iter.EntryIterator, cursor loghttp.TailCursor, limit uint32) iter.EntryIterator {
+	received := make(map[uint64]int, len(cursor.Hashes))
+	for _, h := range cursor.Hashes {
+		received[h]++
+	}
+	return &cursorIterator{EntryIterator: it, timestamp: cursor.Timestamp, received: received, limit: limit}
+}
+
+func (it *cursorIterator) Next() bool {
+	for it.count < it.limit && it.EntryIterator.Next() {
+		entry := it.Entry()
+		if entry.Timestamp.Equal(it.timestamp) {
+			if h := loghttp.TailCursorHash(entry.Line); it.received[h] > 0 {
+				it.received[h]--
+				continue
+			}
+		}
+		it.count++
+		return true
+	}
+	return false
+}
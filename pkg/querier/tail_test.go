@@ -9,7 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/pkg/iter"
-	loghttp "github.com/grafana/loki/pkg/loghttp/legacy"
+	"github.com/grafana/loki/pkg/loghttp"
+	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logproto"
 )
 
@@ -24,6 +25,7 @@ func TestTailer(t *testing.T) {
 	tests := map[string]struct {
 		historicEntries iter.EntryIterator
 		tailClient      *tailClientMock
+		backpressure    bool
 		tester          func(t *testing.T, tailer *Tailer, tailClient *tailClientMock)
 	}{
 		"tail logs from historic entries only (no tail clients provided)": {
@@ -116,6 +118,26 @@ func TestTailer(t *testing.T) {
 				require.Equal(t, 1, len(responses))
 				assert.Equal(t, 1, countEntriesInStreams(responses[0].Streams))
 				assert.Equal(t, 5, len(responses[0].DroppedEntries))
+				assert.Equal(t, map[string]int{`{type="test"}`: 5}, responses[0].DroppedCounts)
+			},
+		},
+		"do not drop entries with backpressure": {
+			historicEntries: mockStreamIterator(1, (maxEntriesPerTailResponse*maxBufferedTailResponses)+5),
+			tailClient:      newTailClientMock().mockRecvWithTrigger(mockTailResponse(mockStream(10000, 1))),
+			backpressure:    true,
+			tester: func(t *testing.T, tailer *Tailer, tailClient *tailClientMock) {
+				// Give the tailer the time to fill the response channel
+				time.Sleep(10 * throttle)
+				tailClient.triggerRecv()
+
+				responses, err := readFromTailer(tailer, (maxEntriesPerTailResponse*maxBufferedTailResponses)+6)
+				require.NoError(t, err)
+
+				assert.Equal(t, (maxEntriesPerTailResponse*maxBufferedTailResponses)+6, countEntriesInStreams(flattenStreamsFromResponses(responses)))
+				for _, response := range responses {
+					assert.Equal(t, 0, len(response.DroppedEntries))
+					assert.Equal(t, 0, len(response.DroppedCounts))
+				}
 			},
 		},
 		"honor max dropped entries per tail response": {
@@ -146,6 +168,7 @@ func TestTailer(t *testing.T) {
 				require.Equal(t, 1, len(responses))
 				assert.Equal(t, 1, countEntriesInStreams(responses[0].Streams))
 				assert.Equal(t, maxDroppedEntriesPerTailResponse, len(responses[0].DroppedEntries))
+				assert.Equal(t, map[string]int{`{type="test"}`: maxDroppedEntriesPerTailResponse + 5}, responses[0].DroppedCounts)
 			},
 		},
 	}
@@ -161,7 +184,7 @@ func TestTailer(t *testing.T) {
 				tailClients["test"] = test.tailClient
 			}
 
-			tailer := newTailer(0, tailClients, test.historicEntries, tailDisconnectedIngesters, timeout, throttle)
+			tailer := newTailer(0, tailClients, test.historicEntries, tailDisconnectedIngesters, timeout, throttle, test.backpressure)
 			defer tailer.close()
 
 			test.tester(t, tailer, test.tailClient)
@@ -169,8 +192,8 @@ func TestTailer(t *testing.T) {
 	}
 }
 
-func readFromTailer(tailer *Tailer, maxEntries int) ([]*loghttp.TailResponse, error) {
-	responses := make([]*loghttp.TailResponse, 0)
+func readFromTailer(tailer *Tailer, maxEntries int) ([]*legacy.TailResponse, error) {
+	responses := make([]*legacy.TailResponse, 0)
 	entriesCount := 0
 
 	// Ensure we do not wait indefinitely
@@ -236,7 +259,7 @@ func countEntriesInStreams(streams []logproto.Stream) int {
 // to abstract away implementation details in the Tailer when testing for the output
 // regardless how the responses have been generated (ie. multiple entries grouped
 // into the same stream)
-func flattenStreamsFromResponses(responses []*loghttp.TailResponse) []logproto.Stream {
+func flattenStreamsFromResponses(responses []*legacy.TailResponse) []logproto.Stream {
 	result := make([]logproto.Stream, 0)
 
 	for _, response := range responses {
@@ -252,3 +275,32 @@ func flattenStreamsFromResponses(responses []*loghttp.TailResponse) []logproto.S
 
 	return result
 }
+
+func TestCursorIterator(t *testing.T) {
+	t.Parallel()
+
+	stream := mockStream(1, 5)
+	// another entry at the timestamp of the cursor must not be skipped
+	stream.Entries = append(stream.Entries[:3], append([]logproto.Entry{{Timestamp: time.Unix(3, 0), Line: "other line"}}, stream.Entries[3:]...)...)
+
+	cursor := loghttp.NewTailCursor(loghttp.Entry{Timestamp: stream.Entries[2].Timestamp, Line: stream.Entries[2].Line})
+	it := newCursorIterator(iter.NewStreamIterator(stream), cursor, 3)
+
+	var lines []string
+	for it.Next() {
+		lines = append(lines, it.Entry().Line)
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, []string{"line 1", "line 2", "other line"}, lines)
+
+	// all the entries already received at the timestamp of the cursor are skipped.
+	cursor.Add(loghttp.Entry{Timestamp: time.Unix(3, 0), Line: "other line"})
+	it = newCursorIterator(iter.NewStreamIterator(stream), cursor, 3)
+
+	lines = nil
+	for it.Next() {
+		lines = append(lines, it.Entry().Line)
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, []string{"line 1", "line 2", "line 4"}, lines)
+}
@@ -0,0 +1,227 @@
+package querier
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// Volume returns the volume of the streams matching the selector of req, read from the volume index
+// of the store and from the chunks of the ingesters. Each stream of the response has a single chunk,
+// spanning the request, with the deduplicated volume of all the chunks of the stream.
+func (q *Querier) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	streams, err := q.chunkVolumes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return mergeVolumes(streams, req.Start, req.End), nil
+}
+
+// IndexStats returns the number of streams and chunks matching the selector of req, with the lines and
+// bytes of their entries, from the volume of the chunks instead of fetching them. The chunks replicated
+// across ingesters are counted as many times as they are stored, as they would be fetched by a query.
+func (q *Querier) IndexStats(ctx context.Context, req *logproto.VolumeRequest) (*loghttp.IndexStats, error) {
+	streams, err := q.chunkVolumes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &loghttp.IndexStats{}
+	for _, stream := range streams {
+		for _, c := range stream.Chunks {
+			if len(c.Buckets) > 0 {
+				stats.Chunks++
+			}
+		}
+	}
+
+	for _, stream := range mergeVolumes(streams, req.Start, req.End).Streams {
+		var lines uint64
+		for _, c := range stream.Chunks {
+			for _, b := range c.Buckets {
+				lines += b.Lines
+				stats.Bytes += b.Bytes
+			}
+		}
+		if lines > 0 {
+			stats.Streams++
+			stats.Lines += lines
+		}
+	}
+	return stats, nil
+}
+
+// chunkVolumes returns the volume of the chunks of the streams matching the selector of req, from
+// the store and the ingesters.
+func (q *Querier) chunkVolumes(ctx context.Context, req *logproto.VolumeRequest) ([]logproto.StreamVolume, error) {
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(q.cfg.QueryTimeout))
+	defer cancel()
+
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.validateQueryTimeRange(userID, &req.Start, &req.End); err != nil {
+		return nil, err
+	}
+
+	storeResp, err := q.store.Volume(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	streams := storeResp.Streams
+
+	// skip ingester queries only when QueryIngestersWithin is enabled (not the zero value) and
+	// the end of the query is earlier than the lookback
+	if lookback := time.Now().Add(-q.cfg.QueryIngestersWithin); q.cfg.QueryIngestersWithin == 0 || !req.End.Before(lookback) {
+		resps, err := q.forAllIngesters(ctx, func(client logproto.QuerierClient) (interface{}, error) {
+			return client.Volume(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, resp := range resps {
+			streams = append(streams, resp.response.(*logproto.VolumeResponse).Streams...)
+		}
+	}
+	return streams, nil
+}
+
+// mergeVolumes merges the volume of the chunks of the same streams into a single chunk
+// spanning from through through.
+func mergeVolumes(streams []logproto.StreamVolume, from, through time.Time) *logproto.VolumeResponse {
+	chunks := map[string][]logproto.ChunkVolume{}
+	for _, stream := range streams {
+		chunks[stream.Labels] = append(chunks[stream.Labels], stream.Chunks...)
+	}
+
+	resp := &logproto.VolumeResponse{Streams: make([]logproto.StreamVolume, 0, len(chunks))}
+	for lbs, cs := range chunks {
+		resp.Streams = append(resp.Streams, logproto.StreamVolume{
+			Labels: lbs,
+			Chunks: []logproto.ChunkVolume{{From: from, Through: through, Buckets: dedupeChunkVolumes(cs)}},
+		})
+	}
+	sort.Slice(resp.Streams, func(i, j int) bool { return resp.Streams[i].Labels < resp.Streams[j].Labels })
+	return resp
+}
+
+// dedupeChunkVolumes returns the volume of a stream from the volume of its chunks. Each entry of
+// the stream is in the chunks of every replica of the stream, whose boundaries differ, and entries
+// may also be in the chunks merging the chunks flushed by the ingesters until they are deleted.
+// The chunks are laid out in chains of chunks not overlapping each other, each chain covering the
+// stream at most once, and the volume of the chain with the largest volume is kept in each bucket.
+func dedupeChunkVolumes(chunks []logproto.ChunkVolume) []logproto.VolumeBucket {
+	sort.Slice(chunks, func(i, j int) bool {
+		if !chunks[i].From.Equal(chunks[j].From) {
+			return chunks[i].From.Before(chunks[j].From)
+		}
+		return chunks[i].Through.Before(chunks[j].Through)
+	})
+
+	type chain struct {
+		through time.Time
+		buckets map[int64]logproto.VolumeBucket
+	}
+	var chains []*chain
+	for _, c := range chunks {
+		var ch *chain
+		for _, candidate := range chains {
+			if candidate.through.Before(c.From) {
+				ch = candidate
+				break
+			}
+		}
+		if ch == nil {
+			ch = &chain{buckets: map[int64]logproto.VolumeBucket{}}
+			chains = append(chains, ch)
+		}
+		ch.through = c.Through
+		for _, b := range c.Buckets {
+			sum := ch.buckets[b.TimestampMs]
+			sum.TimestampMs = b.TimestampMs
+			sum.Lines += b.Lines
+			sum.Bytes += b.Bytes
+			ch.buckets[b.TimestampMs] = sum
+		}
+	}
+
+	buckets := map[int64]logproto.VolumeBucket{}
+	for _, ch := range chains {
+		for ts, b := range ch.buckets {
+			max := buckets[ts]
+			max.TimestampMs = ts
+			if b.Lines > max.Lines {
+				max.Lines = b.Lines
+			}
+			if b.Bytes > max.Bytes {
+				max.Bytes = b.Bytes
+			}
+			buckets[ts] = max
+		}
+	}
+
+	result := make([]logproto.VolumeBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TimestampMs < result[j].TimestampMs })
+	return result
+}
+
+// volumeMatrix sums the volume of the streams of resp by the labels and step of the query.
+func volumeMatrix(resp *logproto.VolumeResponse, query *loghttp.VolumeQuery) (promql.Matrix, error) {
+	step := int64(query.Step / time.Millisecond)
+	series := map[string]map[int64]float64{}
+	metrics := map[string]labels.Labels{}
+	for _, stream := range resp.Streams {
+		lbs, err := parser.ParseMetric(stream.Labels)
+		if err != nil {
+			return nil, err
+		}
+		metric := make(labels.Labels, 0, len(query.By))
+		for _, name := range query.By {
+			if value := lbs.Get(name); value != "" {
+				metric = append(metric, labels.Label{Name: name, Value: value})
+			}
+		}
+		sort.Sort(metric)
+
+		key := metric.String()
+		points, ok := series[key]
+		if !ok {
+			points = map[int64]float64{}
+			series[key] = points
+			metrics[key] = metric
+		}
+		for _, c := range stream.Chunks {
+			for _, b := range c.Buckets {
+				v := b.Lines
+				if query.Type == loghttp.VolumeTypeBytes {
+					v = b.Bytes
+				}
+				points[b.TimestampMs/step*step] += float64(v)
+			}
+		}
+	}
+
+	matrix := make(promql.Matrix, 0, len(series))
+	for key, points := range series {
+		s := promql.Series{Metric: metrics[key], Points: make([]promql.Point, 0, len(points))}
+		for t, v := range points {
+			s.Points = append(s.Points, promql.Point{T: t, V: v})
+		}
+		sort.Slice(s.Points, func(i, j int) bool { return s.Points[i].T < s.Points[j].T })
+		matrix = append(matrix, s)
+	}
+	sort.Sort(matrix)
+	return matrix, nil
+}
@@ -0,0 +1,110 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+func TestDedupeChunkVolumes(t *testing.T) {
+	at := func(m int64) time.Time { return time.Unix(m*60, 0) }
+	bucket := func(m int64, lines uint64) logproto.VolumeBucket {
+		return logproto.VolumeBucket{TimestampMs: m * 60000, Lines: lines, Bytes: 10 * lines}
+	}
+	chunks := []logproto.ChunkVolume{
+		// first replica, cut in the middle of the second minute.
+		{From: at(0), Through: at(1), Buckets: []logproto.VolumeBucket{bucket(0, 5), bucket(1, 2)}},
+		{From: at(1).Add(time.Second), Through: at(2), Buckets: []logproto.VolumeBucket{bucket(1, 3), bucket(2, 4)}},
+		// second replica, with a single chunk.
+		{From: at(0), Through: at(2), Buckets: []logproto.VolumeBucket{bucket(0, 5), bucket(1, 5), bucket(2, 4)}},
+		// third replica, missing the first entries.
+		{From: at(1), Through: at(2), Buckets: []logproto.VolumeBucket{bucket(1, 4), bucket(2, 4)}},
+		// a chunk after the others.
+		{From: at(3), Through: at(3), Buckets: []logproto.VolumeBucket{bucket(3, 1)}},
+	}
+	require.Equal(t, []logproto.VolumeBucket{
+		bucket(0, 5),
+		bucket(1, 5),
+		bucket(2, 4),
+		bucket(3, 1),
+	}, dedupeChunkVolumes(chunks))
+}
+
+func TestVolumeMatrix(t *testing.T) {
+	resp := &logproto.VolumeResponse{Streams: []logproto.StreamVolume{
+		{Labels: `{app="foo", level="info"}`, Chunks: []logproto.ChunkVolume{{Buckets: []logproto.VolumeBucket{
+			{TimestampMs: 0, Lines: 1, Bytes: 10},
+			{TimestampMs: 60000, Lines: 2, Bytes: 20},
+			{TimestampMs: 120000, Lines: 3, Bytes: 30},
+		}}}},
+		{Labels: `{app="bar", level="info"}`, Chunks: []logproto.ChunkVolume{{Buckets: []logproto.VolumeBucket{
+			{TimestampMs: 60000, Lines: 4, Bytes: 40},
+		}}}},
+		{Labels: `{app="bar"}`, Chunks: []logproto.ChunkVolume{{Buckets: []logproto.VolumeBucket{
+			{TimestampMs: 0, Lines: 5, Bytes: 50},
+		}}}},
+	}}
+
+	matrix, err := volumeMatrix(resp, &loghttp.VolumeQuery{Step: 2 * time.Minute, By: []string{"level"}, Type: loghttp.VolumeTypeLines})
+	require.NoError(t, err)
+	require.Equal(t, promql.Matrix{
+		{Metric: labels.Labels{}, Points: []promql.Point{{T: 0, V: 5}}},
+		{Metric: labels.Labels{{Name: "level", Value: "info"}}, Points: []promql.Point{{T: 0, V: 7}, {T: 120000, V: 3}}},
+	}, matrix)
+
+	matrix, err = volumeMatrix(resp, &loghttp.VolumeQuery{Step: time.Minute, Type: loghttp.VolumeTypeBytes})
+	require.NoError(t, err)
+	require.Equal(t, promql.Matrix{
+		{Metric: labels.Labels{}, Points: []promql.Point{{T: 0, V: 60}, {T: 60000, V: 60}, {T: 120000, V: 30}}},
+	}, matrix)
+}
+
+func TestQuerier_IndexStats(t *testing.T) {
+	at := func(m int64) time.Time { return time.Unix(m*60, 0) }
+	bucket := func(m int64, lines uint64) logproto.VolumeBucket {
+		return logproto.VolumeBucket{TimestampMs: m * 60000, Lines: lines, Bytes: 10 * lines}
+	}
+
+	store := newStoreMock()
+	store.On("Volume", mock.Anything, mock.Anything).Return(&logproto.VolumeResponse{Streams: []logproto.StreamVolume{
+		{Labels: `{app="foo"}`, Chunks: []logproto.ChunkVolume{
+			{From: at(0), Through: at(1), Buckets: []logproto.VolumeBucket{bucket(0, 5), bucket(1, 2)}},
+			// replica of the chunk above
+			{From: at(0), Through: at(1), Buckets: []logproto.VolumeBucket{bucket(0, 5), bucket(1, 2)}},
+		}},
+		// no entries in the range of the request
+		{Labels: `{app="baz"}`, Chunks: []logproto.ChunkVolume{{From: at(0), Through: at(1)}}},
+	}}, nil)
+
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("Volume", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.VolumeResponse{Streams: []logproto.StreamVolume{
+		{Labels: `{app="bar"}`, Chunks: []logproto.ChunkVolume{
+			{From: at(1), Through: at(2), Buckets: []logproto.VolumeBucket{bucket(1, 1), bucket(2, 3)}},
+		}},
+	}}, nil)
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	q, err := newQuerier(
+		mockQuerierConfig(),
+		mockIngesterClientConfig(),
+		newIngesterClientMockFactory(ingesterClient),
+		mockReadRingWithOneActiveIngester(),
+		store, limits)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	stats, err := q.IndexStats(ctx, &logproto.VolumeRequest{Selector: `{app=~".+"}`, Start: at(0), End: at(3)})
+	require.NoError(t, err)
+	require.Equal(t, &loghttp.IndexStats{Streams: 2, Chunks: 3, Lines: 11, Bytes: 110}, stats)
+}
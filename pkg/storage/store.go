@@ -9,16 +9,20 @@ import (
 	cortex_local "github.com/cortexproject/cortex/pkg/chunk/local"
 	"github.com/cortexproject/cortex/pkg/chunk/storage"
 	"github.com/cortexproject/cortex/pkg/querier/astmapper"
+	cortex_util "github.com/cortexproject/cortex/pkg/util"
+	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/loki/pkg/chunkenc"
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/stats"
 	"github.com/grafana/loki/pkg/storage/stores/local"
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway"
 	"github.com/grafana/loki/pkg/util"
 )
 
@@ -27,6 +31,12 @@ type Config struct {
 	storage.Config      `yaml:",inline"`
 	MaxChunkBatchSize   int                 `yaml:"max_chunk_batch_size"`
 	BoltDBShipperConfig local.ShipperConfig `yaml:"boltdb_shipper"`
+
+	// Directory of the zstd dictionaries the chunks are compressed with.
+	ChunkDictionariesDirectory string `yaml:"chunk_dictionaries_directory"`
+
+	// Write the volume of the chunks to the volume index when they are stored.
+	VolumeIndexEnabled bool `yaml:"volume_index_enabled"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
@@ -34,6 +44,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Config.RegisterFlags(f)
 	cfg.BoltDBShipperConfig.RegisterFlags(f)
 	f.IntVar(&cfg.MaxChunkBatchSize, "max-chunk-batch-size", 50, "The maximum number of chunks to fetch per batch.")
+	f.StringVar(&cfg.ChunkDictionariesDirectory, "store.chunk-dictionaries-directory", "", "Directory of the zstd dictionaries, with the .dict extension, to compress and decompress chunks with.")
+	f.BoolVar(&cfg.VolumeIndexEnabled, "store.volume-index-enabled", false, "Write the number of lines and bytes of the chunks per minute to the volume index, to answer volume queries without fetching the chunks.")
 }
 
 // Store is the Loki chunk store to retrieve and save chunks.
@@ -41,25 +53,67 @@ type Store interface {
 	chunk.Store
 	LazyQuery(ctx context.Context, req logql.SelectParams) (iter.EntryIterator, error)
 	GetSeries(ctx context.Context, req logql.SelectParams) ([]logproto.SeriesIdentifier, error)
+	Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error)
 }
 
 type store struct {
 	chunk.Store
 	cfg Config
+	// volume index of the chunks, nil if disabled.
+	volumes *volumeIndex
 }
 
 // NewStore creates a new Loki Store using configuration supplied.
 func NewStore(cfg Config, storeCfg chunk.StoreConfig, schemaCfg chunk.SchemaConfig, limits storage.StoreLimits, registerer prometheus.Registerer) (Store, error) {
+	if cfg.ChunkDictionariesDirectory != "" {
+		if err := chunkenc.LoadDictionaries(cfg.ChunkDictionariesDirectory); err != nil {
+			return nil, err
+		}
+	}
 	s, err := storage.NewStore(cfg.Config, storeCfg, schemaCfg, limits, registerer, nil)
 	if err != nil {
 		return nil, err
 	}
+	var volumes *volumeIndex
+	if cfg.VolumeIndexEnabled {
+		volumes, err = newVolumeIndex(cfg, schemaCfg, registerer)
+		if err != nil {
+			s.Stop()
+			return nil, err
+		}
+	}
 	return &store{
-		Store: s,
-		cfg:   cfg,
+		Store:   s,
+		cfg:     cfg,
+		volumes: volumes,
 	}, nil
 }
 
+// Put stores the chunks, and writes their volume to the volume index when enabled. The volume of the
+// chunks is computed by the ingesters when flushing them. Failing to write the volume doesn't fail the
+// chunks, they are fetched to answer volume queries instead.
+func (s *store) Put(ctx context.Context, chunks []chunk.Chunk) error {
+	if err := s.Store.Put(ctx, chunks); err != nil {
+		return err
+	}
+	if s.volumes == nil {
+		return nil
+	}
+	if err := s.volumes.write(ctx, chunks); err != nil {
+		s.volumes.writeFailures.Add(float64(len(chunks)))
+		level.Warn(cortex_util.WithContext(ctx, cortex_util.Logger)).Log("msg", "failed to write the volume of chunks", "err", err)
+	}
+	return nil
+}
+
+// Stop stops the store.
+func (s *store) Stop() {
+	s.Store.Stop()
+	if s.volumes != nil {
+		s.volumes.Stop()
+	}
+}
+
 // NewTableClient creates a TableClient for managing tables for index/chunk store.
 // ToDo: Add support in Cortex for registering custom table client like index client.
 func NewTableClient(name string, cfg Config) (chunk.TableClient, error) {
@@ -270,6 +324,17 @@ func RegisterCustomIndexClients(cfg Config, registerer prometheus.Registerer) {
 			return boltDBIndexClientWithShipper, nil
 		}
 
+		// queriers query the index gateways instead of downloading the files when they are configured.
+		if cfg.BoltDBShipperConfig.Mode == local.ShipperModeReadOnly && cfg.BoltDBShipperConfig.IndexGatewayClient.Enabled() {
+			gatewayClient, err := indexgateway.NewGatewayClient(cfg.BoltDBShipperConfig.IndexGatewayClient, registerer)
+			if err != nil {
+				return nil, err
+			}
+
+			boltDBIndexClientWithShipper = gatewayClient
+			return boltDBIndexClientWithShipper, nil
+		}
+
 		objectClient, err := storage.NewObjectClient(cfg.BoltDBShipperConfig.SharedStoreType, cfg.Config)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,377 @@
+package local
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/local"
+	chunk_util "github.com/cortexproject/cortex/pkg/chunk/util"
+	pkg_util "github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/services"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/loki/pkg/storage/stores/util"
+)
+
+const (
+	// compactedFilePrefix is the prefix of the name of the files uploaded by the compactor, which
+	// supersede the files of the other uploaders they were merged from.
+	compactedFilePrefix = "compactor-"
+
+	// compactionMinFileAge is how long the files of a table must not have been updated for the table to be compacted.
+	// Ingesters upload their updated files every ShipperFileUploadInterval, so the tables not written to anymore
+	// are compacted while the active ones are left alone.
+	compactionMinFileAge = 2 * ShipperFileUploadInterval
+)
+
+var (
+	indexBucketName = []byte("index")
+	// sourcesBucketName is the bucket of the compacted files recording the modification time of the
+	// file of each uploader merged in it.
+	sourcesBucketName = []byte("sources")
+)
+
+type CompactorConfig struct {
+	WorkingDirectory   string        `yaml:"working_directory"`
+	SharedStoreType    string        `yaml:"shared_store"`
+	CompactionInterval time.Duration `yaml:"compaction_interval"`
+}
+
+// RegisterFlags registers flags.
+func (cfg *CompactorConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&cfg.WorkingDirectory, "boltdb.shipper.compactor.working-directory", "", "Directory where files can be downloaded for compaction.")
+	f.StringVar(&cfg.SharedStoreType, "boltdb.shipper.compactor.shared-store", "", "Shared store used for storing boltdb files. Supported types: gcs, s3, azure, filesystem")
+	f.DurationVar(&cfg.CompactionInterval, "boltdb.shipper.compactor.compaction-interval", 2*time.Hour, "Interval at which the files of each table are merged into a single file.")
+}
+
+// Compactor periodically merges the files uploaded by the ingesters for each table into a single
+// deduplicated file, so that queriers download and open one file per table instead of one per ingester.
+type Compactor struct {
+	services.Service
+
+	cfg           CompactorConfig
+	storageClient chunk.ObjectClient
+	minFileAge    time.Duration
+	metrics       *compactorMetrics
+}
+
+func NewCompactor(cfg CompactorConfig, storageClient chunk.ObjectClient, registerer prometheus.Registerer) (*Compactor, error) {
+	if err := chunk_util.EnsureDirectory(cfg.WorkingDirectory); err != nil {
+		return nil, err
+	}
+
+	compactor := Compactor{
+		cfg:           cfg,
+		storageClient: util.NewPrefixedObjectClient(storageClient, storageKeyPrefix),
+		minFileAge:    compactionMinFileAge,
+		metrics:       newCompactorMetrics(registerer),
+	}
+
+	compactor.Service = services.NewTimerService(cfg.CompactionInterval, nil, compactor.run, func(_ error) error {
+		compactor.storageClient.Stop()
+		return nil
+	})
+	return &compactor, nil
+}
+
+// run compacts all the tables. Failures are only logged so that the compactor keeps running.
+func (c *Compactor) run(ctx context.Context) error {
+	err := c.compactTables(ctx)
+	if err != nil {
+		level.Error(pkg_util.Logger).Log("msg", "failed to compact tables", "err", err)
+	}
+	return nil
+}
+
+func (c *Compactor) compactTables(ctx context.Context) (err error) {
+	startTime := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusFailure
+		}
+		c.metrics.compactTablesOperationTotal.WithLabelValues(status).Inc()
+		c.metrics.compactTablesOperationDurationSeconds.Set(time.Since(startTime).Seconds())
+	}()
+
+	_, dirs, err := c.storageClient.List(ctx, "")
+	if err != nil {
+		return
+	}
+
+	for _, dir := range dirs {
+		table := strings.TrimSuffix(string(dir), "/")
+		if err = c.compactTable(ctx, table); err != nil {
+			return fmt.Errorf("compacting table %s: %w", table, err)
+		}
+	}
+	return
+}
+
+// compactTable merges the files of a table not superseded by its newest compacted file, along with
+// that compacted file, into a new compacted file. The merged and superseded files are then deleted.
+func (c *Compactor) compactTable(ctx context.Context, table string) error {
+	objects, _, err := c.storageClient.List(ctx, table+"/")
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if !isCompactedFile(getUploaderFromObjectKey(object.Key)) && time.Since(object.ModifiedAt) < c.minFileAge {
+			level.Debug(pkg_util.Logger).Log("msg", fmt.Sprintf("skipping compaction of table %s which is still being written to", table))
+			return nil
+		}
+	}
+
+	workingDir := path.Join(c.cfg.WorkingDirectory, table)
+	if err := chunk_util.EnsureDirectory(workingDir); err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(workingDir); err != nil {
+			level.Error(pkg_util.Logger).Log("msg", "failed to remove compaction working directory", "path", workingDir, "err", err)
+		}
+	}()
+
+	var dbs []*bbolt.DB
+	defer func() {
+		for _, db := range dbs {
+			if err := db.Close(); err != nil {
+				level.Error(pkg_util.Logger).Log("msg", "failed to close boltdb file", "path", db.Path(), "err", err)
+			}
+		}
+	}()
+	download := func(object chunk.StorageObject) (*bbolt.DB, error) {
+		filePath := path.Join(workingDir, getUploaderFromObjectKey(object.Key))
+		if err := getFileFromStorage(ctx, c.storageClient, object.Key, filePath); err != nil {
+			return nil, err
+		}
+		db, err := local.OpenBoltdbFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+		return db, nil
+	}
+
+	var (
+		sources       = map[string]time.Time{}
+		toMerge       = objects
+		previousDB    *bbolt.DB
+		compacted, ok = newestCompactedObject(objects)
+	)
+	if ok {
+		if previousDB, err = download(compacted); err != nil {
+			return err
+		}
+		if sources, err = readCompactedSources(previousDB); err != nil {
+			return err
+		}
+		toMerge = filterSuperseded(objects, compacted, sources)
+	}
+
+	// nothing to merge, only cleanup the files superseded by the newest compacted file.
+	if len(toMerge) < 2 {
+		if len(toMerge) < len(objects) {
+			return c.deleteObjects(ctx, table, objects, toMerge)
+		}
+		return nil
+	}
+
+	start := time.Now()
+	compactedDB, err := bbolt.Open(path.Join(workingDir, fmt.Sprintf("%s%d", compactedFilePrefix, start.UnixNano())), 0666, nil)
+	if err != nil {
+		return err
+	}
+	dbs = append(dbs, compactedDB)
+
+	for _, object := range toMerge {
+		db := previousDB
+		if !ok || object != compacted {
+			if db, err = download(object); err != nil {
+				return err
+			}
+			sources[getUploaderFromObjectKey(object.Key)] = object.ModifiedAt
+		}
+		if err := mergeBoltdbFile(compactedDB, db); err != nil {
+			return err
+		}
+	}
+	if err := writeCompactedSources(compactedDB, sources); err != nil {
+		return err
+	}
+
+	if err := c.uploadCompactedFile(ctx, table, compactedDB); err != nil {
+		return err
+	}
+	level.Info(pkg_util.Logger).Log("msg", "compacted table", "table", table, "files", len(toMerge), "duration", time.Since(start))
+
+	return c.deleteObjects(ctx, table, objects, nil)
+}
+
+// uploadCompactedFile uploads the compacted file of a table, named after the compactor and the compaction time.
+func (c *Compactor) uploadCompactedFile(ctx context.Context, table string, db *bbolt.DB) error {
+	if err := db.Sync(); err != nil {
+		return err
+	}
+	f, err := os.Open(db.Path())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			level.Error(pkg_util.Logger).Log("msg", "failed to close compacted file", "path", db.Path(), "err", err)
+		}
+	}()
+
+	objectKey := fmt.Sprintf("%s/%s", table, path.Base(db.Path()))
+	return c.storageClient.PutObject(ctx, objectKey, f)
+}
+
+// deleteObjects deletes the objects of a table which were merged into its compacted file, except the kept ones.
+// The objects updated since they were listed, i.e. uploaded again by their ingester during the compaction, are
+// kept as well: their new entries are merged by the next compaction.
+func (c *Compactor) deleteObjects(ctx context.Context, table string, objects, keep []chunk.StorageObject) error {
+	current, _, err := c.storageClient.List(ctx, table+"/")
+	if err != nil {
+		return err
+	}
+	modifiedAt := make(map[string]time.Time, len(current))
+	for _, object := range current {
+		modifiedAt[object.Key] = object.ModifiedAt
+	}
+
+	kept := make(map[string]struct{}, len(keep))
+	for _, object := range keep {
+		kept[object.Key] = struct{}{}
+	}
+	for _, object := range objects {
+		if _, ok := kept[object.Key]; ok {
+			continue
+		}
+		mtime, ok := modifiedAt[object.Key]
+		if !ok {
+			continue
+		}
+		if !mtime.Equal(object.ModifiedAt) {
+			level.Info(pkg_util.Logger).Log("msg", "not deleting file updated during compaction", "file", object.Key)
+			continue
+		}
+		if err := c.storageClient.DeleteObject(ctx, object.Key); err != nil && err != chunk.ErrStorageObjectNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeBoltdbFile copies the index entries of src into dst. The entries present in both are only kept once.
+func mergeBoltdbFile(dst, src *bbolt.DB) error {
+	return dst.Update(func(dtx *bbolt.Tx) error {
+		dstBucket, err := dtx.CreateBucketIfNotExists(indexBucketName)
+		if err != nil {
+			return err
+		}
+		return src.View(func(stx *bbolt.Tx) error {
+			srcBucket := stx.Bucket(indexBucketName)
+			if srcBucket == nil {
+				return nil
+			}
+			// keys and values are only valid for the life of the source transaction.
+			return srcBucket.ForEach(func(k, v []byte) error {
+				return dstBucket.Put(append([]byte(nil), k...), append([]byte(nil), v...))
+			})
+		})
+	})
+}
+
+// isCompactedFile returns whether the file of an uploader was uploaded by the compactor.
+func isCompactedFile(uploader string) bool {
+	return strings.HasPrefix(uploader, compactedFilePrefix)
+}
+
+// newestCompactedObject returns the most recently compacted file of a table.
+func newestCompactedObject(objects []chunk.StorageObject) (chunk.StorageObject, bool) {
+	var (
+		newest   chunk.StorageObject
+		newestTs int64
+		found    bool
+	)
+	for _, object := range objects {
+		uploader := getUploaderFromObjectKey(object.Key)
+		if !isCompactedFile(uploader) {
+			continue
+		}
+		ts, err := strconv.ParseInt(strings.TrimPrefix(uploader, compactedFilePrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		if !found || ts > newestTs {
+			newest, newestTs, found = object, ts, true
+		}
+	}
+	return newest, found
+}
+
+// filterSuperseded returns the objects of a table not superseded by its compacted file, which are
+// the compacted file itself and the files of the uploaders updated after they were merged into it.
+func filterSuperseded(objects []chunk.StorageObject, compacted chunk.StorageObject, sources map[string]time.Time) []chunk.StorageObject {
+	filtered := make([]chunk.StorageObject, 0, len(objects))
+	for _, object := range objects {
+		uploader := getUploaderFromObjectKey(object.Key)
+		if isCompactedFile(uploader) {
+			if object == compacted {
+				filtered = append(filtered, object)
+			}
+			continue
+		}
+		if mergedAt, ok := sources[uploader]; ok && !object.ModifiedAt.After(mergedAt) {
+			continue
+		}
+		filtered = append(filtered, object)
+	}
+	return filtered
+}
+
+// readCompactedSources returns the modification time of the file of each uploader merged into a compacted file.
+func readCompactedSources(db *bbolt.DB) (map[string]time.Time, error) {
+	sources := map[string]time.Time{}
+	err := db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(sourcesBucketName)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			ts, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid modification time of %s in %s: %w", k, db.Path(), err)
+			}
+			sources[string(k)] = time.Unix(0, ts)
+			return nil
+		})
+	})
+	return sources, err
+}
+
+func writeCompactedSources(db *bbolt.DB, sources map[string]time.Time) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(sourcesBucketName)
+		if err != nil {
+			return err
+		}
+		for uploader, mtime := range sources {
+			if err := b.Put([]byte(uploader), []byte(strconv.FormatInt(mtime.UnixNano(), 10))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,170 @@
+package local
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/local"
+	"github.com/stretchr/testify/require"
+)
+
+func listTestTableFiles(t *testing.T, localStoreLocation, table string) []string {
+	files, err := ioutil.ReadDir(filepath.Join(localStoreLocation, storageKeyPrefix, table))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	return names
+}
+
+func TestCompactor(t *testing.T) {
+	tempDirForTests, err := ioutil.TempDir("", "test-dir")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDirForTests))
+	}()
+
+	localStoreLocation, err := ioutil.TempDir(tempDirForTests, "local-store")
+	require.NoError(t, err)
+
+	boltDBWithShipper1 := createTestBoltDBWithShipper(t, tempDirForTests, "ingester1", localStoreLocation)
+	boltDBWithShipper2 := createTestBoltDBWithShipper(t, tempDirForTests, "ingester2", localStoreLocation)
+	querier := createTestBoltDBWithShipper(t, tempDirForTests, "querier", localStoreLocation)
+
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: localStoreLocation})
+	require.NoError(t, err)
+	compactor, err := NewCompactor(CompactorConfig{WorkingDirectory: filepath.Join(tempDirForTests, "compactor")}, objectClient, nil)
+	require.NoError(t, err)
+	compactor.minFileAge = 0
+
+	// add overlapping data to the same table from both the ingesters and upload it
+	writeTestData(t, boltDBWithShipper1, "1", 10, 0)
+	writeTestData(t, boltDBWithShipper2, "1", 10, 5)
+	require.NoError(t, boltDBWithShipper1.shipper.uploadFiles(context.Background()))
+	require.NoError(t, boltDBWithShipper2.shipper.uploadFiles(context.Background()))
+	require.Len(t, listTestTableFiles(t, localStoreLocation, "1"), 2)
+
+	// files still being written to are not compacted
+	compactor.minFileAge = time.Hour
+	require.NoError(t, compactor.compactTables(context.Background()))
+	require.Len(t, listTestTableFiles(t, localStoreLocation, "1"), 2)
+	compactor.minFileAge = 0
+
+	// the files of both the ingesters are merged into a single compacted file
+	require.NoError(t, compactor.compactTables(context.Background()))
+	files := listTestTableFiles(t, localStoreLocation, "1")
+	require.Len(t, files, 1)
+	require.True(t, isCompactedFile(files[0]))
+
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, querier, chunk.IndexQuery{TableName: "1"}), 15, 0)
+
+	// add more data from ingester1, its updated file is downloaded along with the compacted file
+	writeTestData(t, boltDBWithShipper1, "1", 10, 15)
+	require.NoError(t, boltDBWithShipper1.shipper.uploadFiles(context.Background()))
+	require.Len(t, listTestTableFiles(t, localStoreLocation, "1"), 2)
+
+	require.NoError(t, querier.shipper.syncLocalWithStorage(context.Background()))
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, querier, chunk.IndexQuery{TableName: "1"}), 25, 0)
+
+	// the updated file of ingester1 and the new compacted file are merged, superseding the previous one
+	require.NoError(t, compactor.compactTables(context.Background()))
+	files = listTestTableFiles(t, localStoreLocation, "1")
+	require.Len(t, files, 1)
+	require.True(t, isCompactedFile(files[0]))
+
+	require.NoError(t, querier.shipper.syncLocalWithStorage(context.Background()))
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, querier, chunk.IndexQuery{TableName: "1"}), 25, 0)
+
+	// the querier only keeps the compacted file
+	fc := querier.shipper.downloadedPeriods["1"]
+	require.Len(t, fc.files, 1)
+	_, ok := fc.files[files[0]]
+	require.True(t, ok)
+
+	// a new querier only downloads the compacted file
+	newQuerier := createTestBoltDBWithShipper(t, tempDirForTests, "new-querier", localStoreLocation)
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, newQuerier, chunk.IndexQuery{TableName: "1"}), 25, 0)
+	require.Len(t, newQuerier.shipper.downloadedPeriods["1"].files, 1)
+
+	for _, s := range []*BoltdbIndexClientWithShipper{boltDBWithShipper1, boltDBWithShipper2, querier, newQuerier} {
+		s.Stop()
+	}
+	compactor.storageClient.Stop()
+}
+
+// beforePutObjectClient calls beforePut before putting an object.
+type beforePutObjectClient struct {
+	chunk.ObjectClient
+	beforePut func()
+}
+
+func (c beforePutObjectClient) PutObject(ctx context.Context, objectKey string, object io.ReadSeeker) error {
+	if c.beforePut != nil {
+		c.beforePut()
+	}
+	return c.ObjectClient.PutObject(ctx, objectKey, object)
+}
+
+func TestCompactorKeepsFilesUploadedDuringCompaction(t *testing.T) {
+	tempDirForTests, err := ioutil.TempDir("", "test-dir")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempDirForTests))
+	}()
+
+	localStoreLocation, err := ioutil.TempDir(tempDirForTests, "local-store")
+	require.NoError(t, err)
+
+	boltDBWithShipper1 := createTestBoltDBWithShipper(t, tempDirForTests, "ingester1", localStoreLocation)
+	boltDBWithShipper2 := createTestBoltDBWithShipper(t, tempDirForTests, "ingester2", localStoreLocation)
+	querier := createTestBoltDBWithShipper(t, tempDirForTests, "querier", localStoreLocation)
+
+	fsObjectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: localStoreLocation})
+	require.NoError(t, err)
+	objectClient := &beforePutObjectClient{ObjectClient: fsObjectClient}
+	compactor, err := NewCompactor(CompactorConfig{WorkingDirectory: filepath.Join(tempDirForTests, "compactor")}, objectClient, nil)
+	require.NoError(t, err)
+	compactor.minFileAge = 0
+
+	writeTestData(t, boltDBWithShipper1, "1", 10, 0)
+	writeTestData(t, boltDBWithShipper2, "1", 10, 10)
+	require.NoError(t, boltDBWithShipper1.shipper.uploadFiles(context.Background()))
+	require.NoError(t, boltDBWithShipper2.shipper.uploadFiles(context.Background()))
+
+	// ingester1 uploads its file again once the compactor downloaded it.
+	objectClient.beforePut = func() {
+		objectClient.beforePut = nil
+		writeTestData(t, boltDBWithShipper1, "1", 10, 20)
+		require.NoError(t, boltDBWithShipper1.shipper.uploadFiles(context.Background()))
+	}
+
+	// the file of ingester2 is deleted, the one of ingester1 is kept along with the compacted file.
+	require.NoError(t, compactor.compactTables(context.Background()))
+	files := listTestTableFiles(t, localStoreLocation, "1")
+	require.Len(t, files, 2)
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, querier, chunk.IndexQuery{TableName: "1"}), 30, 0)
+
+	// and merged by the next compaction.
+	require.NoError(t, compactor.compactTables(context.Background()))
+	files = listTestTableFiles(t, localStoreLocation, "1")
+	require.Len(t, files, 1)
+	require.True(t, isCompactedFile(files[0]))
+
+	require.NoError(t, querier.shipper.syncLocalWithStorage(context.Background()))
+	checkExpectedKVsInBoltdbResp(t, queryTestBoltdb(t, querier, chunk.IndexQuery{TableName: "1"}), 30, 0)
+
+	for _, s := range []*BoltdbIndexClientWithShipper{boltDBWithShipper1, boltDBWithShipper2, querier} {
+		s.Stop()
+	}
+	compactor.storageClient.Stop()
+}
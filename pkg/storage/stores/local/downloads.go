@@ -25,6 +25,33 @@ func (fc *FilesCollection) checkStorageForUpdates(ctx context.Context) (toDownlo
 		return
 	}
 
+	// the compacted file is synced first to know which of the other files it supersedes, those are then
+	// not downloaded, or deleted if they already were.
+	if compacted, ok := newestCompactedObject(objects); ok {
+		uploader := getUploaderFromObjectKey(compacted.Key)
+
+		fc.mtx.RLock()
+		df, isDownloaded := fc.files[uploader]
+		fc.mtx.RUnlock()
+
+		if !isDownloaded || df.mtime != compacted.ModifiedAt {
+			err = fc.downloadFile(ctx, compacted)
+			if err != nil {
+				return
+			}
+		}
+
+		var sources map[string]time.Time
+		fc.mtx.RLock()
+		sources, err = readCompactedSources(fc.files[uploader].boltdb)
+		fc.mtx.RUnlock()
+		if err != nil {
+			return
+		}
+
+		objects = filterSuperseded(objects, compacted, sources)
+	}
+
 	listedUploaders := make(map[string]struct{}, len(objects))
 
 	fc.mtx.RLock()
@@ -54,6 +81,11 @@ func (fc *FilesCollection) checkStorageForUpdates(ctx context.Context) (toDownlo
 func (fc *FilesCollection) Sync(ctx context.Context) error {
 	level.Debug(util.Logger).Log("msg", fmt.Sprintf("syncing files for period %s", fc.period))
 
+	startTime := time.Now()
+	defer func() {
+		fc.metrics.tableSyncDurationSeconds.Observe(time.Since(startTime).Seconds())
+	}()
+
 	toDownload, toDelete, err := fc.checkStorageForUpdates(ctx)
 	if err != nil {
 		return err
@@ -88,7 +120,7 @@ func (fc *FilesCollection) downloadFile(ctx context.Context, storageObject chunk
 	// download the file temporarily with some other name to allow boltdb client to close the existing file first if it exists
 	tempFilePath := path.Join(folderPath, fmt.Sprintf("%s.%s", uploader, "temp"))
 
-	err := fc.getFileFromStorage(ctx, storageObject.Key, tempFilePath)
+	err := getFileFromStorage(ctx, fc.storageClient, storageObject.Key, tempFilePath)
 	if err != nil {
 		return err
 	}
@@ -123,15 +155,15 @@ func (fc *FilesCollection) downloadFile(ctx context.Context, storageObject chunk
 }
 
 // getFileFromStorage downloads a file from storage to given location.
-func (fc *FilesCollection) getFileFromStorage(ctx context.Context, objectKey, destination string) error {
-	readCloser, err := fc.storageClient.GetObject(ctx, objectKey)
+func getFileFromStorage(ctx context.Context, storageClient chunk.ObjectClient, objectKey, destination string) error {
+	readCloser, err := storageClient.GetObject(ctx, objectKey)
 	if err != nil {
 		return err
 	}
 
 	defer func() {
 		if err := readCloser.Close(); err != nil {
-			level.Error(util.Logger)
+			level.Error(util.Logger).Log("msg", "failed to close read closer", "err", err)
 		}
 	}()
 
@@ -140,6 +172,12 @@ func (fc *FilesCollection) getFileFromStorage(ctx context.Context, objectKey, de
 		return err
 	}
 
+	defer func() {
+		if err := f.Close(); err != nil {
+			level.Warn(util.Logger).Log("msg", "failed to close file", "file", destination)
+		}
+	}()
+
 	_, err = io.Copy(f, readCloser)
 	if err != nil {
 		return err
@@ -184,32 +222,35 @@ func (fc *FilesCollection) downloadAllFilesForPeriod(ctx context.Context) (err e
 		return
 	}
 
-	for _, object := range objects {
-		uploader := getUploaderFromObjectKey(object.Key)
-
-		filePath := path.Join(folderPath, uploader)
-		df := downloadedFile{}
-
-		err = fc.getFileFromStorage(ctx, object.Key, filePath)
+	// only the files not superseded by the newest compacted file are downloaded.
+	compacted, hasCompacted := newestCompactedObject(objects)
+	if hasCompacted {
+		var size int64
+		size, err = fc.downloadObject(ctx, folderPath, compacted)
 		if err != nil {
 			return
 		}
+		totalFilesSize += size
 
-		df.mtime = object.ModifiedAt
-		df.boltdb, err = local.OpenBoltdbFile(filePath)
+		var sources map[string]time.Time
+		sources, err = readCompactedSources(fc.files[getUploaderFromObjectKey(compacted.Key)].boltdb)
 		if err != nil {
 			return
 		}
+		objects = filterSuperseded(objects, compacted, sources)
+	}
 
-		var stat os.FileInfo
-		stat, err = os.Stat(filePath)
+	for _, object := range objects {
+		if hasCompacted && object == compacted {
+			continue
+		}
+
+		var size int64
+		size, err = fc.downloadObject(ctx, folderPath, object)
 		if err != nil {
 			return
 		}
-
-		totalFilesSize += stat.Size()
-
-		fc.files[uploader] = &df
+		totalFilesSize += size
 	}
 
 	duration := time.Since(startTime).Seconds()
@@ -219,6 +260,31 @@ func (fc *FilesCollection) downloadAllFilesForPeriod(ctx context.Context) (err e
 	return
 }
 
+// downloadObject downloads the file of an uploader to the folder of the period and opens it, returning its size.
+func (fc *FilesCollection) downloadObject(ctx context.Context, folderPath string, object chunk.StorageObject) (int64, error) {
+	uploader := getUploaderFromObjectKey(object.Key)
+	filePath := path.Join(folderPath, uploader)
+
+	err := getFileFromStorage(ctx, fc.storageClient, object.Key, filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	df := downloadedFile{mtime: object.ModifiedAt}
+	df.boltdb, err = local.OpenBoltdbFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+	fc.files[uploader] = &df
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	return stat.Size(), nil
+}
+
 func (fc *FilesCollection) getFolderPathForPeriod(ensureExists bool) (string, error) {
 	folderPath := path.Join(fc.cacheLocation, fc.period)
 
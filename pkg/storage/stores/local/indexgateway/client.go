@@ -0,0 +1,222 @@
+package indexgateway
+
+import (
+	"context"
+	"flag"
+	"hash/fnv"
+	"io"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	cortex_grpc "github.com/cortexproject/cortex/pkg/chunk/grpc"
+	chunk_util "github.com/cortexproject/cortex/pkg/chunk/util"
+	"github.com/cortexproject/cortex/pkg/ring"
+	ring_client "github.com/cortexproject/cortex/pkg/ring/client"
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/grpcclient"
+	"github.com/cortexproject/cortex/pkg/util/services"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway/indexgatewaypb"
+)
+
+// errReadOnly is returned on writes, the index gateways only serve the queries of the queriers.
+var errReadOnly = errors.New("the index gateway client does not support writes")
+
+// ClientConfig for a client of the index gateways.
+type ClientConfig struct {
+	Address          string            `yaml:"server_address"`
+	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config"`
+
+	// Ring of the index gateways, set when they shard the tables. Address is then unused.
+	Ring ring.ReadRing `yaml:"-"`
+}
+
+// RegisterFlagsWithPrefix registers flags with prefix.
+func (cfg *ClientConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.GRPCClientConfig.RegisterFlagsWithPrefix(prefix, f)
+
+	f.StringVar(&cfg.Address, prefix+".server-address", "", "Address of the index gateway the queriers send their index queries to, instead of downloading the boltdb files. Unused when the index gateways shard the tables.")
+}
+
+// Enabled returns whether the queriers should query the index gateways.
+func (cfg *ClientConfig) Enabled() bool {
+	return cfg.Address != "" || cfg.Ring != nil
+}
+
+type gatewayClient struct {
+	indexgatewaypb.IndexGatewayClient
+	grpc_health_v1.HealthClient
+	io.Closer
+}
+
+// GatewayClient is a read-only chunk.IndexClient querying the index gateways, either the one at
+// the configured address or the ones owning the queried tables in the ring.
+type GatewayClient struct {
+	cfg ClientConfig
+
+	// client of the configured address, when not using the ring.
+	client *gatewayClient
+	pool   *ring_client.Pool
+}
+
+// NewGatewayClient makes a new client of the index gateways.
+func NewGatewayClient(cfg ClientConfig, registerer prometheus.Registerer) (*GatewayClient, error) {
+	requestDuration := promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "loki",
+		Name:      "index_gateway_client_request_duration_seconds",
+		Help:      "Time (in seconds) spent executing requests to the index gateways.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 7),
+	}, []string{"operation", "status_code"})
+
+	dial := func(addr string) (*gatewayClient, error) {
+		opts := []grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithDefaultCallOptions(cfg.GRPCClientConfig.CallOptions()...),
+		}
+		opts = append(opts, cfg.GRPCClientConfig.DialOption(grpcclient.Instrument(requestDuration))...)
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to dial index gateway %s", addr)
+		}
+		return &gatewayClient{
+			IndexGatewayClient: indexgatewaypb.NewIndexGatewayClient(conn),
+			HealthClient:       grpc_health_v1.NewHealthClient(conn),
+			Closer:             conn,
+		}, nil
+	}
+
+	c := &GatewayClient{cfg: cfg}
+	if cfg.Ring == nil {
+		var err error
+		c.client, err = dial(cfg.Address)
+		return c, err
+	}
+
+	c.pool = ring_client.NewPool("index-gateway", ring_client.PoolConfig{
+		CheckInterval:      15 * time.Second,
+		HealthCheckEnabled: true,
+		HealthCheckTimeout: time.Second,
+	}, ring_client.NewRingServiceDiscovery(cfg.Ring), func(addr string) (ring_client.PoolClient, error) {
+		return dial(addr)
+	}, promauto.With(registerer).NewGauge(prometheus.GaugeOpts{
+		Namespace: "loki",
+		Name:      "index_gateway_clients",
+		Help:      "The current number of index gateway clients in the pool.",
+	}), util.Logger)
+	if err := services.StartAndAwaitRunning(context.Background(), c.pool); err != nil {
+		return nil, errors.Wrap(err, "index gateway client pool")
+	}
+	return c, nil
+}
+
+// Stop implements chunk.IndexClient.
+func (c *GatewayClient) Stop() {
+	if c.pool != nil {
+		if err := services.StopAndAwaitTerminated(context.Background(), c.pool); err != nil {
+			level.Error(util.Logger).Log("msg", "failed to stop the index gateway client pool", "err", err)
+		}
+		return
+	}
+	if err := c.client.Close(); err != nil {
+		level.Error(util.Logger).Log("msg", "failed to close the index gateway client", "err", err)
+	}
+}
+
+// NewWriteBatch implements chunk.IndexClient.
+func (c *GatewayClient) NewWriteBatch() chunk.WriteBatch {
+	return &cortex_grpc.WriteBatch{}
+}
+
+// BatchWrite implements chunk.IndexClient.
+func (c *GatewayClient) BatchWrite(context.Context, chunk.WriteBatch) error {
+	return errReadOnly
+}
+
+// QueryPages implements chunk.IndexClient.
+func (c *GatewayClient) QueryPages(ctx context.Context, queries []chunk.IndexQuery, callback func(chunk.IndexQuery, chunk.ReadBatch) (shouldContinue bool)) error {
+	return chunk_util.DoParallelQueries(ctx, c.query, queries, callback)
+}
+
+// query sends a query to the index gateways serving its table in turn, until one of them answers.
+// A gateway failing after having sent some rows is not retried, to not pass those rows twice to the callback.
+func (c *GatewayClient) query(ctx context.Context, query chunk.IndexQuery, callback chunk_util.Callback) error {
+	clients, err := c.clientsFor(query.TableName)
+	if err != nil {
+		return err
+	}
+
+	for i, client := range clients {
+		var received bool
+		received, err = c.queryGateway(ctx, client, query, callback)
+		if err == nil || received || ctx.Err() != nil {
+			return err
+		}
+		if i < len(clients)-1 {
+			level.Warn(util.Logger).Log("msg", "failed to query index gateway, trying the next one", "table", query.TableName, "err", err)
+		}
+	}
+	return err
+}
+
+func (c *GatewayClient) queryGateway(ctx context.Context, client indexgatewaypb.IndexGatewayClient, query chunk.IndexQuery, callback chunk_util.Callback) (received bool, err error) {
+	stream, err := client.QueryIndex(ctx, &cortex_grpc.QueryIndexRequest{
+		TableName:        query.TableName,
+		HashValue:        query.HashValue,
+		RangeValuePrefix: query.RangeValuePrefix,
+		RangeValueStart:  query.RangeValueStart,
+		ValueEqual:       query.ValueEqual,
+		Immutable:        query.Immutable,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+		if !callback(query, resp) {
+			return received, nil
+		}
+	}
+}
+
+// clientsFor returns the clients of the index gateways serving a table.
+func (c *GatewayClient) clientsFor(tableName string) ([]indexgatewaypb.IndexGatewayClient, error) {
+	if c.pool == nil {
+		return []indexgatewaypb.IndexGatewayClient{c.client}, nil
+	}
+
+	rs, err := c.cfg.Ring.Get(tableToken(tableName), ring.Read, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]indexgatewaypb.IndexGatewayClient, 0, len(rs.Ingesters))
+	for _, gateway := range rs.Ingesters {
+		client, err := c.pool.GetClientFor(gateway.Addr)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client.(indexgatewaypb.IndexGatewayClient))
+	}
+	return clients, nil
+}
+
+// tableToken returns the token of a table in the ring of the index gateways.
+func tableToken(tableName string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tableName))
+	return h.Sum32()
+}
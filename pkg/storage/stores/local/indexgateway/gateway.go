@@ -0,0 +1,136 @@
+package indexgateway
+
+import (
+	"context"
+	"flag"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	cortex_grpc "github.com/cortexproject/cortex/pkg/chunk/grpc"
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/util/services"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway/indexgatewaypb"
+)
+
+const (
+	// RingKey is the key under which the index gateways are registered in the ring.
+	RingKey = "index-gateway"
+
+	// RingName is the name of the ring of the index gateways.
+	RingName = "index-gateway"
+)
+
+// Config for an index gateway.
+type Config struct {
+	ShardingEnabled  bool                  `yaml:"sharding_enabled"`
+	LifecyclerConfig ring.LifecyclerConfig `yaml:"ring,omitempty"`
+}
+
+// RegisterFlags registers flags.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.LifecyclerConfig.RegisterFlagsWithPrefix("index-gateway.", f)
+
+	f.BoolVar(&cfg.ShardingEnabled, "index-gateway.sharding-enabled", false, "Shard the tables across the index gateways registered in a ring, instead of each index gateway serving all the tables.")
+}
+
+// Gateway serves the index queries of the queriers from the boltdb-shipper files it downloads,
+// so that the queriers do not have to keep their own copy of them.
+type Gateway struct {
+	services.Service
+
+	indexClient chunk.IndexClient
+
+	// lifecycler registers the gateway in the ring when sharding is enabled.
+	lifecycler         *ring.Lifecycler
+	subservicesWatcher *services.FailureWatcher
+}
+
+// NewGateway makes a new index gateway answering queries with indexClient, which is stopped along with it.
+func NewGateway(cfg Config, indexClient chunk.IndexClient, registerer prometheus.Registerer) (*Gateway, error) {
+	g := &Gateway{
+		indexClient:        indexClient,
+		subservicesWatcher: services.NewFailureWatcher(),
+	}
+
+	if cfg.ShardingEnabled {
+		var err error
+		g.lifecycler, err = ring.NewLifecycler(cfg.LifecyclerConfig, nil, RingName, RingKey, false, registerer)
+		if err != nil {
+			return nil, errors.Wrap(err, "index gateway lifecycler")
+		}
+	}
+
+	g.Service = services.NewBasicService(g.starting, g.running, g.stopping)
+	return g, nil
+}
+
+func (g *Gateway) starting(ctx context.Context) error {
+	if g.lifecycler == nil {
+		return nil
+	}
+
+	g.subservicesWatcher.WatchService(g.lifecycler)
+	return services.StartAndAwaitRunning(ctx, g.lifecycler)
+}
+
+func (g *Gateway) running(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-g.subservicesWatcher.Chan():
+		return errors.Wrap(err, "index gateway subservice failed")
+	}
+}
+
+func (g *Gateway) stopping(_ error) error {
+	defer g.indexClient.Stop()
+
+	if g.lifecycler == nil {
+		return nil
+	}
+	return services.StopAndAwaitTerminated(context.Background(), g.lifecycler)
+}
+
+// QueryIndex implements indexgatewaypb.IndexGatewayServer.
+func (g *Gateway) QueryIndex(req *cortex_grpc.QueryIndexRequest, server indexgatewaypb.IndexGateway_QueryIndexServer) error {
+	query := chunk.IndexQuery{
+		TableName:        req.TableName,
+		HashValue:        req.HashValue,
+		RangeValuePrefix: req.RangeValuePrefix,
+		RangeValueStart:  req.RangeValueStart,
+		ValueEqual:       req.ValueEqual,
+		Immutable:        req.Immutable,
+	}
+
+	var sendErr error
+	err := g.indexClient.QueryPages(server.Context(), []chunk.IndexQuery{query}, func(_ chunk.IndexQuery, batch chunk.ReadBatch) bool {
+		resp := &cortex_grpc.QueryIndexResponse{}
+		itr := batch.Iterator()
+		for itr.Next() {
+			// the rows are sent before the batch is released, no need to copy them.
+			resp.Rows = append(resp.Rows, &cortex_grpc.Row{RangeValue: itr.RangeValue(), Value: itr.Value()})
+		}
+
+		if sendErr = server.Send(resp); sendErr != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// Check implements grpc_health_v1.HealthCheck.
+func (*Gateway) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// Watch implements grpc_health_v1.HealthCheck.
+func (*Gateway) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	return nil
+}
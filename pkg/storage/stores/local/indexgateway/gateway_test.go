@@ -0,0 +1,113 @@
+package indexgateway
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/cortexproject/cortex/pkg/util/services"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway/indexgatewaypb"
+)
+
+// startTestGateway serves the index of indexClient on a local port, returning its address.
+func startTestGateway(t *testing.T, indexClient chunk.IndexClient) string {
+	g, err := NewGateway(Config{}, indexClient, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), g))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	indexgatewaypb.RegisterIndexGatewayServer(server, g)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+
+	t.Cleanup(func() {
+		server.Stop()
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), g))
+	})
+	return lis.Addr().String()
+}
+
+func newTestIndex(t *testing.T, ctx context.Context) chunk.IndexClient {
+	index := chunk.NewMockStorage()
+	require.NoError(t, index.CreateTable(ctx, chunk.TableDesc{Name: "table"}))
+
+	batch := index.NewWriteBatch()
+	batch.Add("table", "hash", []byte("range1"), []byte("value1"))
+	batch.Add("table", "hash", []byte("range2"), []byte("value2"))
+	batch.Add("table", "other", []byte("range3"), []byte("value3"))
+	require.NoError(t, index.BatchWrite(ctx, batch))
+	return index
+}
+
+func queryTestIndex(t *testing.T, ctx context.Context, client chunk.IndexClient) map[string]string {
+	rows := map[string]string{}
+	require.NoError(t, client.QueryPages(ctx, []chunk.IndexQuery{{TableName: "table", HashValue: "hash"}}, func(_ chunk.IndexQuery, batch chunk.ReadBatch) bool {
+		itr := batch.Iterator()
+		for itr.Next() {
+			rows[string(itr.RangeValue())] = string(itr.Value())
+		}
+		return true
+	}))
+	return rows
+}
+
+func newTestClientConfig(address string, r ring.ReadRing) ClientConfig {
+	cfg := ClientConfig{Address: address, Ring: r}
+	flagext.DefaultValues(&cfg.GRPCClientConfig)
+	return cfg
+}
+
+func TestGatewayClient(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	expected := map[string]string{"range1": "value1", "range2": "value2"}
+
+	client, err := NewGatewayClient(newTestClientConfig(startTestGateway(t, newTestIndex(t, ctx)), nil), nil)
+	require.NoError(t, err)
+	defer client.Stop()
+
+	require.Equal(t, expected, queryTestIndex(t, ctx, client))
+	require.Equal(t, errReadOnly, client.BatchWrite(ctx, client.NewWriteBatch()))
+}
+
+// mockRing returns the same gateways for all the tables.
+type mockRing struct {
+	ring.ReadRing
+	gateways []ring.IngesterDesc
+}
+
+func (r mockRing) Get(uint32, ring.Operation, []ring.IngesterDesc) (ring.ReplicationSet, error) {
+	return ring.ReplicationSet{Ingesters: r.gateways}, nil
+}
+
+func (r mockRing) GetAll(ring.Operation) (ring.ReplicationSet, error) {
+	return ring.ReplicationSet{Ingesters: r.gateways}, nil
+}
+
+func TestGatewayClient_Ring(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	expected := map[string]string{"range1": "value1", "range2": "value2"}
+
+	// nothing listens anymore on the address of the first gateway, the second one answers instead.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, lis.Close())
+
+	client, err := NewGatewayClient(newTestClientConfig("", mockRing{gateways: []ring.IngesterDesc{
+		{Addr: lis.Addr().String()},
+		{Addr: startTestGateway(t, newTestIndex(t, ctx))},
+	}}), nil)
+	require.NoError(t, err)
+	defer client.Stop()
+
+	require.Equal(t, expected, queryTestIndex(t, ctx, client))
+}
@@ -57,6 +57,9 @@ type downloaderMetrics struct {
 	filesDownloadSizeBytes       *downloadPeriodBytesMetric
 
 	filesDownloadOperationTotal *prometheus.CounterVec
+
+	// duration in seconds spent in syncing the files of a period with the store
+	tableSyncDurationSeconds prometheus.Histogram
 }
 
 type boltDBShipperMetrics struct {
@@ -90,6 +93,12 @@ func newBoltDBShipperMetrics(r prometheus.Registerer) *boltDBShipperMetrics {
 				Name:      "files_download_operation_total",
 				Help:      "Total number of download operations done by status",
 			}, []string{"status"}),
+			tableSyncDurationSeconds: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+				Namespace: "loki_boltdb_shipper",
+				Name:      "table_sync_duration_seconds",
+				Help:      "Time (in seconds) spent in syncing the files of a table with the store",
+				Buckets:   instrument.DefBuckets,
+			}),
 		},
 		requestDurationSeconds: promauto.With(r).NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: "loki_boltdb_shipper",
@@ -106,3 +115,23 @@ func newBoltDBShipperMetrics(r prometheus.Registerer) *boltDBShipperMetrics {
 
 	return m
 }
+
+type compactorMetrics struct {
+	compactTablesOperationTotal           *prometheus.CounterVec
+	compactTablesOperationDurationSeconds prometheus.Gauge
+}
+
+func newCompactorMetrics(r prometheus.Registerer) *compactorMetrics {
+	return &compactorMetrics{
+		compactTablesOperationTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki_boltdb_shipper",
+			Name:      "compact_tables_operation_total",
+			Help:      "Total number of tables compaction done by status",
+		}, []string{"status"}),
+		compactTablesOperationDurationSeconds: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki_boltdb_shipper",
+			Name:      "compact_tables_operation_duration_seconds",
+			Help:      "Time (in seconds) spent in compacting all the tables",
+		}),
+	}
+}
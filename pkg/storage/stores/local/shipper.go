@@ -17,6 +17,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.etcd.io/bbolt"
 
+	"github.com/grafana/loki/pkg/storage/stores/local/indexgateway"
 	"github.com/grafana/loki/pkg/storage/stores/util"
 )
 
@@ -53,6 +54,9 @@ type ShipperConfig struct {
 	ResyncInterval       time.Duration `yaml:"resync_interval"`
 	IngesterName         string        `yaml:"-"`
 	Mode                 int           `yaml:"-"`
+
+	// IndexGatewayClient configures the queriers to query the index gateways instead of downloading the files.
+	IndexGatewayClient indexgateway.ClientConfig `yaml:"index_gateway_client"`
 }
 
 // RegisterFlags registers flags.
@@ -62,6 +66,7 @@ func (cfg *ShipperConfig) RegisterFlags(f *flag.FlagSet) {
 	f.StringVar(&cfg.CacheLocation, "boltdb.shipper.cache-location", "", "Cache location for restoring boltDB files for queries")
 	f.DurationVar(&cfg.CacheTTL, "boltdb.shipper.cache-ttl", 24*time.Hour, "TTL for boltDB files restored in cache for queries")
 	f.DurationVar(&cfg.ResyncInterval, "boltdb.shipper.resync-interval", 5*time.Minute, "Resync downloaded files with the storage")
+	cfg.IndexGatewayClient.RegisterFlagsWithPrefix("boltdb.shipper.index-gateway-client", f)
 }
 
 type Shipper struct {
@@ -0,0 +1,368 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/storage/stores/local"
+)
+
+const (
+	secondsInDay = int64(24 * time.Hour / time.Second)
+
+	// suffix of the directory of the boltdb volume index, next to the boltdb chunk index
+	// whose files are locked by the chunk store.
+	volumeIndexDirectorySuffix = "-volume"
+
+	// number of rows the volume of the chunks of a tenant is spread over each day, by stream,
+	// so that a tenant with many streams doesn't write a single hot row.
+	volumeIndexShards = 16
+)
+
+var errInvalidVolumeEntry = errors.New("invalid volume index entry")
+
+// volumeIndex stores the volume of each chunk, by bucket of chunkenc.VolumeBucketPeriod, in the
+// index of the period it belongs to, so the volume of the streams can be computed without fetching
+// their chunks. The volume of a chunk is written in a row per tenant, day and shard of the streams,
+// keyed by the external key of the chunk, along with the labels of its stream.
+type volumeIndex struct {
+	schemaCfg chunk.SchemaConfig
+	// index client of each period config.
+	clients []chunk.IndexClient
+	// index clients created for the volume index, and not shared with the chunk store.
+	owned []chunk.IndexClient
+
+	writeFailures prometheus.Counter
+}
+
+func newVolumeIndex(cfg Config, schemaCfg chunk.SchemaConfig, registerer prometheus.Registerer) (*volumeIndex, error) {
+	v := &volumeIndex{
+		schemaCfg: schemaCfg,
+		writeFailures: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "store_volume_index_write_failures_total",
+			Help:      "Total number of chunks whose volume failed to be written to the volume index.",
+		}),
+	}
+
+	clientCfg := cfg.Config
+	if clientCfg.BoltDBConfig.Directory != "" {
+		clientCfg.BoltDBConfig.Directory = filepath.Clean(clientCfg.BoltDBConfig.Directory) + volumeIndexDirectorySuffix
+	}
+	byType := map[string]chunk.IndexClient{}
+	for _, p := range schemaCfg.Configs {
+		client, ok := byType[p.IndexType]
+		if !ok {
+			var err error
+			client, err = storage.NewIndexClient(p.IndexType, clientCfg, schemaCfg)
+			if err != nil {
+				v.Stop()
+				return nil, err
+			}
+			byType[p.IndexType] = client
+			// the boltdb shipper index client is a singleton shared with the chunk store.
+			if p.IndexType != local.BoltDBShipperType {
+				v.owned = append(v.owned, client)
+			}
+		}
+		v.clients = append(v.clients, client)
+	}
+	return v, nil
+}
+
+// Stop stops the index clients of the volume index.
+func (v *volumeIndex) Stop() {
+	for _, client := range v.owned {
+		client.Stop()
+	}
+}
+
+// periodFor returns the index table and client of the period config t belongs to.
+func (v *volumeIndex) periodFor(t model.Time) (string, chunk.IndexClient, error) {
+	for i := len(v.schemaCfg.Configs) - 1; i >= 0; i-- {
+		if p := v.schemaCfg.Configs[i]; t >= p.From.Time {
+			return p.IndexTables.TableFor(t), v.clients[i], nil
+		}
+	}
+	return "", nil, fmt.Errorf("no index table found for time %v", t)
+}
+
+// write writes the volume of the chunks to the index. The chunks whose volume is unknown, i.e. not
+// given to chunkenc.NewFacadeWithVolume, are skipped: they are fetched to answer volume queries.
+func (v *volumeIndex) write(ctx context.Context, chunks []chunk.Chunk) error {
+	batches := map[chunk.IndexClient]chunk.WriteBatch{}
+	for _, c := range chunks {
+		facade, ok := c.Data.(*chunkenc.Facade)
+		if !ok || facade.Volume() == nil {
+			continue
+		}
+		buckets := facade.Volume()
+
+		lbs := streamLabels(c.Metric)
+		for len(buckets) > 0 {
+			day := buckets[0].TimestampMs / 1000 / secondsInDay
+			n := 1
+			for n < len(buckets) && buckets[n].TimestampMs/1000/secondsInDay == day {
+				n++
+			}
+
+			table, client, err := v.periodFor(model.TimeFromUnix(day * secondsInDay))
+			if err != nil {
+				return err
+			}
+			batch, ok := batches[client]
+			if !ok {
+				batch = client.NewWriteBatch()
+				batches[client] = batch
+			}
+			batch.Add(table, volumeHashValue(c.UserID, day, uint64(c.Fingerprint)%volumeIndexShards), []byte(c.ExternalKey()), encodeVolume(lbs, buckets[:n]))
+			buckets = buckets[n:]
+		}
+	}
+
+	for client, batch := range batches {
+		if err := client.BatchWrite(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexedVolume is the volume of a chunk read from the index.
+type indexedVolume struct {
+	labels  string
+	buckets []logproto.VolumeBucket
+}
+
+// read returns the volume of the chunks of the tenant between from and through from the index,
+// by chunk external key. Only the buckets between from and through are returned.
+func (v *volumeIndex) read(ctx context.Context, userID string, from, through model.Time) (map[string]*indexedVolume, error) {
+	queries := map[chunk.IndexClient][]chunk.IndexQuery{}
+	for day := int64(from) / 1000 / secondsInDay; day <= int64(through)/1000/secondsInDay; day++ {
+		table, client, err := v.periodFor(model.TimeFromUnix(day * secondsInDay))
+		if err != nil {
+			continue
+		}
+		for shard := uint64(0); shard < volumeIndexShards; shard++ {
+			queries[client] = append(queries[client], chunk.IndexQuery{
+				TableName: table,
+				HashValue: volumeHashValue(userID, day, shard),
+			})
+		}
+	}
+
+	minBucket := int64(from.Time().Truncate(chunkenc.VolumeBucketPeriod).UnixNano() / int64(time.Millisecond))
+	volumes := map[string]*indexedVolume{}
+	for client, qs := range queries {
+		var decodeErr error
+		err := client.QueryPages(ctx, qs, func(_ chunk.IndexQuery, batch chunk.ReadBatch) bool {
+			for it := batch.Iterator(); it.Next(); {
+				lbs, buckets, err := decodeVolume(it.Value())
+				if err != nil {
+					decodeErr = err
+					return false
+				}
+				key := string(it.RangeValue())
+				vol, ok := volumes[key]
+				if !ok {
+					vol = &indexedVolume{labels: lbs}
+					volumes[key] = vol
+				}
+				for _, b := range buckets {
+					if b.TimestampMs >= minBucket && b.TimestampMs < int64(through) {
+						vol.buckets = append(vol.buckets, b)
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+	}
+	return volumes, nil
+}
+
+func volumeHashValue(userID string, day int64, shard uint64) string {
+	return fmt.Sprintf("%02d:%s:d%d:volume", shard, userID, day)
+}
+
+// encodeVolume encodes the labels of a stream and the volume of one of its chunks during a day.
+// The buckets are encoded as their distance in buckets to the previous one, starting at the epoch,
+// followed by their lines and bytes.
+func encodeVolume(lbs string, buckets []logproto.VolumeBucket) []byte {
+	buf := make([]byte, 0, binary.MaxVarintLen64*(2+3*len(buckets))+len(lbs))
+	tmp := make([]byte, binary.MaxVarintLen64)
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(tmp, v)
+		buf = append(buf, tmp[:n]...)
+	}
+
+	putUvarint(uint64(len(lbs)))
+	buf = append(buf, lbs...)
+	putUvarint(uint64(len(buckets)))
+	prev := int64(0)
+	period := int64(chunkenc.VolumeBucketPeriod / time.Millisecond)
+	for _, b := range buckets {
+		putUvarint(uint64((b.TimestampMs - prev) / period))
+		putUvarint(b.Lines)
+		putUvarint(b.Bytes)
+		prev = b.TimestampMs
+	}
+	return buf
+}
+
+// decodeVolume decodes a volume encoded by encodeVolume.
+func decodeVolume(buf []byte) (string, []logproto.VolumeBucket, error) {
+	var err error
+	uvarint := func() uint64 {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			err = errInvalidVolumeEntry
+			return 0
+		}
+		buf = buf[n:]
+		return v
+	}
+
+	l := uvarint()
+	if err != nil || uint64(len(buf)) < l {
+		return "", nil, errInvalidVolumeEntry
+	}
+	lbs := string(buf[:l])
+	buf = buf[l:]
+
+	count := uvarint()
+	if err != nil || count > uint64(len(buf)) {
+		return "", nil, errInvalidVolumeEntry
+	}
+	buckets := make([]logproto.VolumeBucket, 0, count)
+	ts := int64(0)
+	period := int64(chunkenc.VolumeBucketPeriod / time.Millisecond)
+	for i := uint64(0); i < count; i++ {
+		ts += int64(uvarint()) * period
+		b := logproto.VolumeBucket{TimestampMs: ts, Lines: uvarint(), Bytes: uvarint()}
+		if err != nil {
+			return "", nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return lbs, buckets, nil
+}
+
+// streamLabels returns the labels of the stream of a chunk.
+func streamLabels(metric labels.Labels) string {
+	return labels.NewBuilder(metric).Del(labels.MetricName).Labels().String()
+}
+
+// Volume returns the volume of the chunks of each stream matching the selector of req, with the
+// buckets between req.Start and req.End. The volume of the chunks is read from the volume index,
+// and the chunks missing from it, e.g. those flushed before it was enabled, are fetched and counted.
+func (s *store) Volume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matchers, _, from, through, err := decodeReq(logql.SelectParams{QueryRequest: &logproto.QueryRequest{
+		Selector: req.Selector,
+		Start:    req.Start,
+		End:      req.End,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	lazyChunks, err := s.lazyChunks(ctx, matchers, from, through)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexed map[string]*indexedVolume
+	if s.volumes != nil {
+		indexed, err = s.volumes.read(ctx, userID, from, through)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	streams := map[string]*logproto.StreamVolume{}
+	add := func(lbs string, c chunk.Chunk, buckets []logproto.VolumeBucket) {
+		stream, ok := streams[lbs]
+		if !ok {
+			stream = &logproto.StreamVolume{Labels: lbs}
+			streams[lbs] = stream
+		}
+		stream.Chunks = append(stream.Chunks, logproto.ChunkVolume{
+			From:    c.From.Time(),
+			Through: c.Through.Time(),
+			Buckets: buckets,
+		})
+	}
+
+	// a chunk overlapping two period configs is indexed in both of them.
+	seen := make(map[string]struct{}, len(lazyChunks))
+	var missing []*LazyChunk
+	for _, c := range lazyChunks {
+		key := c.Chunk.ExternalKey()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if vol, ok := indexed[key]; ok {
+			add(vol.labels, c.Chunk, vol.buckets)
+			continue
+		}
+		missing = append(missing, c)
+	}
+
+	for len(missing) > 0 {
+		batch := missing
+		if len(batch) > s.cfg.MaxChunkBatchSize {
+			batch = batch[:s.cfg.MaxChunkBatchSize]
+		}
+		missing = missing[len(batch):]
+
+		if err := fetchLazyChunks(ctx, batch); err != nil {
+			return nil, err
+		}
+		for _, c := range batch {
+			facade, ok := c.Chunk.Data.(*chunkenc.Facade)
+			if !ok {
+				continue
+			}
+			buckets, err := chunkenc.Volume(ctx, facade.LokiChunk(), req.Start, req.End)
+			if err != nil {
+				return nil, err
+			}
+			add(streamLabels(c.Chunk.Metric), c.Chunk, buckets)
+			// release the chunk data, only its volume is needed.
+			c.Chunk.Data = nil
+		}
+	}
+
+	resp := &logproto.VolumeResponse{Streams: make([]logproto.StreamVolume, 0, len(streams))}
+	for _, stream := range streams {
+		resp.Streams = append(resp.Streams, *stream)
+	}
+	return resp, nil
+}
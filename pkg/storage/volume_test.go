@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/stats"
+)
+
+func Test_encodeVolume(t *testing.T) {
+	buckets := []logproto.VolumeBucket{
+		{TimestampMs: 1585699200000, Lines: 1, Bytes: 10},
+		{TimestampMs: 1585699260000, Lines: 300, Bytes: 123456},
+		{TimestampMs: 1585785540000, Lines: 2, Bytes: 0},
+	}
+	lbs, decoded, err := decodeVolume(encodeVolume(`{foo="bar"}`, buckets))
+	require.NoError(t, err)
+	require.Equal(t, `{foo="bar"}`, lbs)
+	require.Equal(t, buckets, decoded)
+
+	_, _, err = decodeVolume(encodeVolume(`{foo="bar"}`, buckets)[:15])
+	require.Equal(t, errInvalidVolumeEntry, err)
+}
+
+func Test_store_Volume(t *testing.T) {
+	// 2020-04-01T23:59:00Z
+	dayEnd := time.Unix(1585785540, 0)
+	streams := []*logproto.Stream{
+		{
+			Labels: `{foo="bar"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: dayEnd.Add(-time.Minute), Line: "1"},
+				{Timestamp: dayEnd.Add(10 * time.Second), Line: "22"},
+				{Timestamp: dayEnd.Add(70 * time.Second), Line: "333"},
+			},
+		},
+		{
+			Labels: `{foo="baz"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: dayEnd, Line: "4444"},
+				{Timestamp: dayEnd.Add(20 * time.Second), Line: "55555"},
+			},
+		},
+	}
+	req := &logproto.VolumeRequest{
+		Selector: `{foo=~"ba.*"}`,
+		Start:    dayEnd.Add(-time.Hour),
+		End:      dayEnd.Add(time.Hour),
+	}
+	bucket := func(t time.Time, lines, bytes uint64) logproto.VolumeBucket {
+		return logproto.VolumeBucket{TimestampMs: t.UnixNano() / int64(time.Millisecond), Lines: lines, Bytes: bytes}
+	}
+	expected := map[string][]logproto.VolumeBucket{
+		`{foo="bar"}`: {
+			bucket(dayEnd.Add(-time.Minute), 1, 1),
+			bucket(dayEnd, 1, 2),
+			bucket(dayEnd.Add(time.Minute), 1, 3),
+		},
+		`{foo="baz"}`: {
+			bucket(dayEnd, 2, 9),
+		},
+	}
+	assertVolume := func(t *testing.T, resp *logproto.VolumeResponse) {
+		actual := map[string][]logproto.VolumeBucket{}
+		for _, s := range resp.Streams {
+			require.Len(t, s.Chunks, 1)
+			actual[s.Labels] = s.Chunks[0].Buckets
+		}
+		require.Equal(t, expected, actual)
+	}
+
+	chunkStore := newMockChunkStore(streams)
+	// the chunks of the mock store belong to the tenant fake.
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	t.Run("without volume index", func(t *testing.T) {
+		ctx := stats.NewContext(ctx)
+		s := &store{Store: chunkStore, cfg: Config{MaxChunkBatchSize: 1}}
+
+		resp, err := s.Volume(ctx, req)
+		require.NoError(t, err)
+		assertVolume(t, resp)
+		require.Equal(t, int64(2), stats.GetStoreData(ctx).TotalChunksDownloaded)
+	})
+
+	t.Run("with volume index", func(t *testing.T) {
+		ctx := stats.NewContext(ctx)
+		index := chunk.NewMockStorage()
+		for _, table := range []string{"index_18353", "index_18354"} {
+			require.NoError(t, index.CreateTable(ctx, chunk.TableDesc{Name: table}))
+		}
+		volumes := &volumeIndex{
+			schemaCfg: chunk.SchemaConfig{Configs: []chunk.PeriodConfig{{
+				IndexTables: chunk.PeriodicTableConfig{Prefix: "index_", Period: 24 * time.Hour},
+			}}},
+			clients:       []chunk.IndexClient{index},
+			writeFailures: prometheus.NewCounter(prometheus.CounterOpts{}),
+		}
+		// only the first chunk, whose volume is given by the ingester, is indexed. The other one is fetched.
+		indexed := chunkStore.chunks[0]
+		lc := indexed.Data.(*chunkenc.Facade).LokiChunk()
+		from, through := lc.Bounds()
+		volume, err := chunkenc.Volume(ctx, lc, from, through.Add(time.Nanosecond))
+		require.NoError(t, err)
+		indexed.Data = chunkenc.NewFacadeWithVolume(lc, 0, 0, volume)
+		require.NoError(t, volumes.write(ctx, []chunk.Chunk{indexed, chunkStore.chunks[1]}))
+
+		// the volume is written in the row of the shard of the stream.
+		var rows int
+		shard := uint64(indexed.Fingerprint) % volumeIndexShards
+		require.NoError(t, index.QueryPages(ctx, []chunk.IndexQuery{
+			{TableName: "index_18353", HashValue: volumeHashValue("fake", 18353, shard)},
+			{TableName: "index_18354", HashValue: volumeHashValue("fake", 18354, shard)},
+		}, func(_ chunk.IndexQuery, batch chunk.ReadBatch) bool {
+			for it := batch.Iterator(); it.Next(); {
+				rows++
+			}
+			return true
+		}))
+		require.NotZero(t, rows)
+
+		s := &store{Store: chunkStore, cfg: Config{MaxChunkBatchSize: 1}, volumes: volumes}
+		resp, err := s.Volume(ctx, req)
+		require.NoError(t, err)
+		assertVolume(t, resp)
+		require.Equal(t, int64(1), stats.GetStoreData(ctx).TotalChunksDownloaded)
+	})
+}
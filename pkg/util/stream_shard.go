@@ -0,0 +1,34 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// StreamShardLabel is the label added by the distributors to the shards of a stream
+// split for exceeding the desired rate. It is removed from the streams returned by queries and tails.
+const StreamShardLabel = "__stream_shard__"
+
+// RemoveStreamShard returns the labels of a stream without its shard label.
+func RemoveStreamShard(stream string) string {
+	if !strings.Contains(stream, StreamShardLabel) {
+		return stream
+	}
+	ls, err := parser.ParseMetric(stream)
+	if err != nil {
+		return stream
+	}
+	return ls.WithoutLabels(StreamShardLabel).String()
+}
+
+// AddStreamShard returns the labels of the given shard of a stream.
+func AddStreamShard(stream string, shard int) (string, error) {
+	ls, err := parser.ParseMetric(stream)
+	if err != nil {
+		return "", err
+	}
+	return labels.NewBuilder(ls).Set(StreamShardLabel, strconv.Itoa(shard)).Labels().String(), nil
+}
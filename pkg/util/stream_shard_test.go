@@ -0,0 +1,20 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamShard(t *testing.T) {
+	sharded, err := AddStreamShard(`{foo="bar", app="baz"}`, 3)
+	require.NoError(t, err)
+	require.Equal(t, `{__stream_shard__="3", app="baz", foo="bar"}`, sharded)
+	require.Equal(t, `{app="baz", foo="bar"}`, RemoveStreamShard(sharded))
+
+	// labels without shard are returned as is.
+	require.Equal(t, `{foo="bar", app="baz"}`, RemoveStreamShard(`{foo="bar", app="baz"}`))
+
+	_, err = AddStreamShard(`{foo="bar"`, 0)
+	require.Error(t, err)
+}
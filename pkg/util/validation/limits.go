@@ -21,21 +21,26 @@ const (
 // limits via flags, or per-user limits via yaml config.
 type Limits struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy  string           `yaml:"ingestion_rate_strategy"`
-	IngestionRateMB        float64          `yaml:"ingestion_rate_mb"`
-	IngestionBurstSizeMB   float64          `yaml:"ingestion_burst_size_mb"`
-	MaxLabelNameLength     int              `yaml:"max_label_name_length"`
-	MaxLabelValueLength    int              `yaml:"max_label_value_length"`
-	MaxLabelNamesPerSeries int              `yaml:"max_label_names_per_series"`
-	RejectOldSamples       bool             `yaml:"reject_old_samples"`
-	RejectOldSamplesMaxAge time.Duration    `yaml:"reject_old_samples_max_age"`
-	CreationGracePeriod    time.Duration    `yaml:"creation_grace_period"`
-	EnforceMetricName      bool             `yaml:"enforce_metric_name"`
-	MaxLineSize            flagext.ByteSize `yaml:"max_line_size"`
+	IngestionRateStrategy   string           `yaml:"ingestion_rate_strategy"`
+	IngestionRateMB         float64          `yaml:"ingestion_rate_mb"`
+	IngestionBurstSizeMB    float64          `yaml:"ingestion_burst_size_mb"`
+	MaxLabelNameLength      int              `yaml:"max_label_name_length"`
+	MaxLabelValueLength     int              `yaml:"max_label_value_length"`
+	MaxLabelNamesPerSeries  int              `yaml:"max_label_names_per_series"`
+	RejectOldSamples        bool             `yaml:"reject_old_samples"`
+	RejectOldSamplesMaxAge  time.Duration    `yaml:"reject_old_samples_max_age"`
+	CreationGracePeriod     time.Duration    `yaml:"creation_grace_period"`
+	EnforceMetricName       bool             `yaml:"enforce_metric_name"`
+	MaxLineSize             flagext.ByteSize `yaml:"max_line_size"`
+	ShardStreamsDesiredRate flagext.ByteSize `yaml:"shard_streams_desired_rate"`
 
 	// Ingester enforced limits.
-	MaxLocalStreamsPerUser  int `yaml:"max_streams_per_user"`
-	MaxGlobalStreamsPerUser int `yaml:"max_global_streams_per_user"`
+	MaxLocalStreamsPerUser     int              `yaml:"max_streams_per_user"`
+	MaxGlobalStreamsPerUser    int              `yaml:"max_global_streams_per_user"`
+	PerStreamRateLimit         flagext.ByteSize `yaml:"per_stream_rate_limit"`
+	PerStreamRateLimitBurst    flagext.ByteSize `yaml:"per_stream_rate_limit_burst"`
+	MaxLabelValuesPerLabelName int              `yaml:"max_label_values_per_label_name"`
+	ChunkDictionaryID          int              `yaml:"chunk_dictionary_id"`
 
 	// Querier enforced limits.
 	MaxChunksPerQuery          int           `yaml:"max_chunks_per_query"`
@@ -45,6 +50,7 @@ type Limits struct {
 	MaxStreamsMatchersPerQuery int           `yaml:"max_streams_matchers_per_query"`
 	MaxConcurrentTailRequests  int           `yaml:"max_concurrent_tail_requests"`
 	MaxEntriesLimitPerQuery    int           `yaml:"max_entries_limit_per_query"`
+	MaxStreamedEntriesPerQuery int           `yaml:"max_streamed_entries_per_query"`
 	MaxCacheFreshness          time.Duration `yaml:"max_cache_freshness_per_query"`
 
 	// Query frontend enforced limits. The default is actually parameterized by the queryrange config.
@@ -61,6 +67,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Float64Var(&l.IngestionRateMB, "distributor.ingestion-rate-limit-mb", 4, "Per-user ingestion rate limit in sample size per second. Units in MB.")
 	f.Float64Var(&l.IngestionBurstSizeMB, "distributor.ingestion-burst-size-mb", 6, "Per-user allowed ingestion burst size (in sample size). Units in MB.")
 	f.Var(&l.MaxLineSize, "distributor.max-line-size", "maximum line length allowed, i.e. 100mb. Default (0) means unlimited.")
+	f.Var(&l.ShardStreamsDesiredRate, "distributor.shard-streams.desired-rate", "Rate of a stream above which the distributor splits it into shards identified by the __stream_shard__ label, i.e. 1mb. Default (0) means streams are never sharded.")
 	f.IntVar(&l.MaxLabelNameLength, "validation.max-length-label-name", 1024, "Maximum length accepted for label names")
 	f.IntVar(&l.MaxLabelValueLength, "validation.max-length-label-value", 2048, "Maximum length accepted for label value. This setting also applies to the metric name")
 	f.IntVar(&l.MaxLabelNamesPerSeries, "validation.max-label-names-per-series", 30, "Maximum number of label names per series.")
@@ -69,9 +76,14 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&l.CreationGracePeriod, "validation.create-grace-period", 10*time.Minute, "Duration which table will be created/deleted before/after it's needed; we won't accept sample from before this time.")
 	f.BoolVar(&l.EnforceMetricName, "validation.enforce-metric-name", true, "Enforce every sample has a metric name.")
 	f.IntVar(&l.MaxEntriesLimitPerQuery, "validation.max-entries-limit", 5000, "Per-user entries limit per query")
+	f.IntVar(&l.MaxStreamedEntriesPerQuery, "validation.max-streamed-entries-limit", 1e6, "Per-user entries limit per streamed query. 0 to disable.")
 
 	f.IntVar(&l.MaxLocalStreamsPerUser, "ingester.max-streams-per-user", 10e3, "Maximum number of active streams per user, per ingester. 0 to disable.")
 	f.IntVar(&l.MaxGlobalStreamsPerUser, "ingester.max-global-streams-per-user", 0, "Maximum number of active streams per user, across the cluster. 0 to disable.")
+	f.Var(&l.PerStreamRateLimit, "ingester.per-stream-rate-limit", "Maximum byte rate per second per stream, i.e. 3mb. Default (0) means unlimited.")
+	f.Var(&l.PerStreamRateLimitBurst, "ingester.per-stream-rate-limit-burst", "Maximum burst bytes per stream, i.e. 15mb. Default (0) means the per stream rate limit.")
+	f.IntVar(&l.MaxLabelValuesPerLabelName, "ingester.max-label-values-per-label-name", 0, "Maximum number of distinct values of a label name across the active streams of a user, per ingester. 0 to disable.")
+	f.IntVar(&l.ChunkDictionaryID, "ingester.chunk-dictionary-id", 0, "ID of the zstd dictionary to compress the chunks of a user with, when the chunk encoding is zstd. The dictionary must be loaded from the chunk dictionaries directory. 0 to disable.")
 
 	f.IntVar(&l.MaxChunksPerQuery, "store.query-chunk-limit", 2e6, "Maximum number of chunks that can be fetched in a single query.")
 	f.DurationVar(&l.MaxQueryLength, "store.max-query-length", 0, "Limit to length of chunk store queries, 0 to disable.")
@@ -194,6 +206,32 @@ func (o *Overrides) MaxGlobalStreamsPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxGlobalStreamsPerUser
 }
 
+// PerStreamRateLimit returns the maximum byte rate per second and the burst size of a single stream.
+func (o *Overrides) PerStreamRateLimit(userID string) (limit, burst int) {
+	l := o.getOverridesForUser(userID)
+	limit, burst = l.PerStreamRateLimit.Val(), l.PerStreamRateLimitBurst.Val()
+	if burst == 0 {
+		burst = limit
+	}
+	return limit, burst
+}
+
+// MaxLabelValuesPerLabelName returns the maximum number of distinct values of a single label name
+// across the streams a user is allowed to store in a single ingester.
+func (o *Overrides) MaxLabelValuesPerLabelName(userID string) int {
+	return o.getOverridesForUser(userID).MaxLabelValuesPerLabelName
+}
+
+// ChunkDictionaryID returns the ID of the zstd dictionary to compress the chunks of a user with, 0 for none.
+func (o *Overrides) ChunkDictionaryID(userID string) uint32 {
+	return uint32(o.getOverridesForUser(userID).ChunkDictionaryID)
+}
+
+// ShardStreamsDesiredRate returns the rate of a stream above which the distributor shards it.
+func (o *Overrides) ShardStreamsDesiredRate(userID string) int {
+	return o.getOverridesForUser(userID).ShardStreamsDesiredRate.Val()
+}
+
 // MaxChunksPerQuery returns the maximum number of chunks allowed per query.
 func (o *Overrides) MaxChunksPerQuery(userID string) int {
 	return o.getOverridesForUser(userID).MaxChunksPerQuery
@@ -245,6 +283,11 @@ func (o *Overrides) MaxEntriesLimitPerQuery(userID string) int {
 	return o.getOverridesForUser(userID).MaxEntriesLimitPerQuery
 }
 
+// MaxStreamedEntriesPerQuery returns the limit to number of entries the querier should stream per query.
+func (o *Overrides) MaxStreamedEntriesPerQuery(userID string) int {
+	return o.getOverridesForUser(userID).MaxStreamedEntriesPerQuery
+}
+
 func (o *Overrides) MaxCacheFreshness(userID string) time.Duration {
 	return o.getOverridesForUser(userID).MaxCacheFreshness
 }
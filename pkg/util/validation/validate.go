@@ -14,6 +14,13 @@ const (
 	// Declared here to avoid duplication in ingester and distributor.
 	RateLimited       = "rate_limited"
 	rateLimitErrorMsg = "Ingestion rate limit exceeded (limit: %d bytes/sec) while attempting to ingest '%d' lines totaling '%d' bytes, reduce log volume or contact your Loki administrator to see if the limit can be increased"
+	// StreamRateLimit is a reason for discarding lines when the rate limit of a single stream is exceeded.
+	StreamRateLimit         = "per_stream_rate_limit"
+	streamRateLimitErrorMsg = "Per stream rate limit exceeded (limit: %d bytes/sec) for stream '%s' while attempting to ingest '%d' lines totaling '%d' bytes, reduce log volume or contact your Loki administrator to see if the limit can be increased"
+	// StreamBurstExceeded is a reason for discarding lines larger than the burst size of the rate limit of
+	// their stream, which the rate limit never allows.
+	StreamBurstExceeded         = "per_stream_burst_exceeded"
+	streamBurstExceededErrorMsg = "Per stream rate limit burst exceeded (burst: %d bytes) for stream '%s' by '%d' lines larger than the burst, reduce the size of the lines or contact your Loki administrator to see if the burst can be increased"
 	// LineTooLong is a reason for discarding too long log lines.
 	LineTooLong         = "line_too_long"
 	lineTooLongErrorMsg = "Max entry size '%d' bytes exceeded for stream '%s' while adding an entry with length '%d' bytes"
@@ -21,6 +28,14 @@ const (
 	// because the limit of active streams has been reached.
 	StreamLimit         = "stream_limit"
 	streamLimitErrorMsg = "Maximum active stream limit exceeded, reduce the number of active streams (reduce labels or reduce label values), or contact your Loki administrator to see if the limit can be increased"
+	// LabelValuesLimit is a reason for discarding lines when we can't create a new stream
+	// because one of its label names already has the maximum number of distinct values.
+	LabelValuesLimit         = "label_values_limit"
+	labelValuesLimitErrorMsg = "Maximum number of values per label name exceeded for label '%s' of stream '%s' (limit: %d), reduce the number of values of this label, or contact your Loki administrator to see if the limit can be increased"
+	// OutOfOrder is a reason for rejecting log lines older than the last line of their stream.
+	OutOfOrder = "out_of_order"
+	// InvalidLabels is a reason for rejecting log lines whose stream labels can't be parsed.
+	InvalidLabels = "invalid_labels"
 	// GreaterThanMaxSampleAge is a reason for discarding log lines which are older than the current time - `reject_old_samples_max_age`
 	GreaterThanMaxSampleAge         = "greater_than_max_sample_age"
 	greaterThanMaxSampleAgeErrorMsg = "entry for stream '%s' has timestamp too old: %v"
@@ -70,6 +85,16 @@ func RateLimitedErrorMsg(limit, lines, bytes int) string {
 	return fmt.Sprintf(rateLimitErrorMsg, limit, lines, bytes)
 }
 
+// StreamRateLimitedErrorMsg returns an error string for lines rejected by the rate limit of their stream
+func StreamRateLimitedErrorMsg(limit int, stream string, lines, bytes int) string {
+	return fmt.Sprintf(streamRateLimitErrorMsg, limit, stream, lines, bytes)
+}
+
+// StreamBurstExceededErrorMsg returns an error string for lines larger than the burst size of the rate limit of their stream
+func StreamBurstExceededErrorMsg(burst int, stream string, lines int) string {
+	return fmt.Sprintf(streamBurstExceededErrorMsg, burst, stream, lines)
+}
+
 // LineTooLongErrorMsg returns an error string for a line which is too long
 func LineTooLongErrorMsg(maxLength, entryLength int, stream string) string {
 	return fmt.Sprintf(lineTooLongErrorMsg, maxLength, stream, entryLength)
@@ -80,6 +105,11 @@ func StreamLimitErrorMsg() string {
 	return fmt.Sprint(streamLimitErrorMsg)
 }
 
+// LabelValuesLimitErrorMsg returns an error string for streams refused for exceeding the label values limit
+func LabelValuesLimitErrorMsg(label, stream string, limit int) string {
+	return fmt.Sprintf(labelValuesLimitErrorMsg, label, stream, limit)
+}
+
 // GreaterThanMaxSampleAgeErrorMsg returns an error string for a line with a timestamp too old
 func GreaterThanMaxSampleAgeErrorMsg(stream string, timestamp time.Time) string {
 	return fmt.Sprintf(greaterThanMaxSampleAgeErrorMsg, stream, timestamp)